@@ -38,6 +38,8 @@ var (
 	healthProbeBindAddress  string
 	gracefulShutdownTimeout time.Duration
 	cacheSyncTimeout        time.Duration
+	enforcementMode         string
+	enforcementOverrides    string
 )
 
 func main() {
@@ -46,12 +48,26 @@ func main() {
 	flag.StringVar(&healthProbeBindAddress, "health-probe-bind-addr", fmt.Sprintf(":%d", configuration.HealthProbePort), "The address the healthz & readyz probes bind to.")
 	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", configuration.GracefulShutdownTimeout, "The duration of time to wait while shutting down for all controllers to stop.")
 	flag.DurationVar(&cacheSyncTimeout, "cache-sync-timeout", configuration.CacheSyncTimeout, "The duration of time to wait while informers synchronize.")
+	flag.StringVar(&enforcementMode, "enforcement-mode", string(webhook.DenyMode), "What to do with requests which would cause drift from the declared state: deny them, or allow them and warn the requester or only record them. One of deny, warn or audit.")
+	flag.StringVar(&enforcementOverrides, "enforcement-mode-overrides", "", "Comma-separated per-kind enforcement modes overriding --enforcement-mode, formatted as Kind.group=mode, for example Deployment.apps=warn,ConfigMap=audit.")
 
 	log.Setup()
 
 	profiler.Service()
 	ctrl.SetLogger(klogr.New())
 
+	mode, err := webhook.ParseEnforcementMode(enforcementMode)
+	if err != nil {
+		setupLog.Error(err, "parsing --enforcement-mode")
+		os.Exit(1)
+	}
+	overrides, err := webhook.ParseEnforcementOverrides(enforcementOverrides)
+	if err != nil {
+		setupLog.Error(err, "parsing --enforcement-mode-overrides")
+		os.Exit(1)
+	}
+	enforcement := webhook.Enforcement{Mode: mode, Overrides: overrides}
+
 	setupLog.Info("starting manager")
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Port:    configuration.ContainerPort,
@@ -107,7 +123,7 @@ func main() {
 		<-certDone
 
 		setupLog.Info("registering validator for webhook")
-		if err := webhook.AddValidator(mgr); err != nil {
+		if err := webhook.AddValidator(mgr, enforcement); err != nil {
 			setupLog.Error(err, "unable to register validator for webhook")
 			os.Exit(1)
 		}
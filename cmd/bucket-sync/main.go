@@ -0,0 +1,127 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2/klogr"
+	"kpt.dev/configsync/pkg/bucket"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/util"
+	utillog "kpt.dev/configsync/pkg/util/log"
+)
+
+var flEndpoint = flag.String("endpoint", util.EnvString(reconcilermanager.BucketSyncEndpoint, ""),
+	"the URL of the S3-compatible API")
+var flBucket = flag.String("bucket", util.EnvString(reconcilermanager.BucketSyncBucket, ""),
+	"the name of the bucket holding the source")
+var flPrefix = flag.String("prefix", util.EnvString(reconcilermanager.BucketSyncPrefix, ""),
+	"the prefix of the keys of the objects of the source")
+var flRegion = flag.String("region", util.EnvString(reconcilermanager.BucketSyncRegion, bucket.DefaultRegion),
+	"the region of the bucket used to sign the requests")
+var flRoot = flag.String("root", util.EnvString("BUCKET_SYNC_ROOT", util.EnvString("HOME", "")+"/bucket"),
+	"the root directory for bucket-sync operations, under which --dest will be created")
+var flDest = flag.String("dest", util.EnvString("BUCKET_SYNC_DEST", "rev"),
+	"the path (absolute or relative to --root) at which to create a symlink to the directory holding the retrieved files")
+var flErrorFile = flag.String("error-file", util.EnvString("BUCKET_SYNC_ERROR_FILE", ""),
+	"the name of a file into which errors will be written under --root (defaults to \"\", disabling error reporting)")
+var flWait = flag.Float64("wait", util.EnvFloat(reconcilermanager.BucketSyncWait, 1),
+	"the number of seconds between syncs")
+var flSyncTimeout = flag.Int("timeout", util.EnvInt("BUCKET_SYNC_TIMEOUT", 120),
+	"the max number of seconds allowed for a complete sync")
+var flOneTime = flag.Bool("one-time", util.EnvBool("BUCKET_SYNC_ONE_TIME", false),
+	"exit after the first sync")
+var flMaxSyncFailures = flag.Int("max-sync-failures", util.EnvInt("BUCKET_SYNC_MAX_SYNC_FAILURES", 0),
+	"the number of consecutive failures allowed before aborting (the first sync must succeed, -1 will retry forever after the initial sync)")
+
+func main() {
+	utillog.Setup()
+	log := utillog.NewLogger(klogr.New(), *flRoot, *flErrorFile)
+
+	log.Info("fetching bucket with arguments", "--endpoint", *flEndpoint,
+		"--bucket", *flBucket, "--prefix", *flPrefix, "--region", *flRegion,
+		"--root", *flRoot, "--dest", *flDest, "--wait", *flWait,
+		"--error-file", *flErrorFile, "--timeout", *flSyncTimeout,
+		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures)
+
+	if *flEndpoint == "" {
+		utillog.HandleError(log, true, "ERROR: --endpoint must be specified")
+	}
+
+	if *flBucket == "" {
+		utillog.HandleError(log, true, "ERROR: --bucket must be specified")
+	}
+
+	if *flRoot == "" {
+		utillog.HandleError(log, true, "ERROR: --root must be specified")
+	}
+
+	if *flWait < 0 {
+		utillog.HandleError(log, true, "ERROR: --wait must be greater than or equal to 0")
+	}
+
+	if *flSyncTimeout < 0 {
+		utillog.HandleError(log, true, "ERROR: --timeout must be greater than 0")
+	}
+
+	// The access key is only read from the environment, populated from the
+	// auth Secret, so it never shows up in the arguments of the process.
+	c, err := bucket.NewClient(*flEndpoint, *flRegion, bucket.Credentials{
+		AccessKeyID:     os.Getenv(reconcilermanager.BucketSyncAccessKeyID),
+		SecretAccessKey: os.Getenv(reconcilermanager.BucketSyncSecretAccessKey),
+		SessionToken:    os.Getenv(reconcilermanager.BucketSyncSessionToken),
+	})
+	if err != nil {
+		utillog.HandleError(log, true, "ERROR: %v", err)
+	}
+
+	initialSync := true
+	failCount := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(*flSyncTimeout))
+		if err := bucket.Fetch(ctx, c, *flBucket, *flPrefix, *flRoot, *flDest); err != nil {
+			if *flMaxSyncFailures != -1 && failCount >= *flMaxSyncFailures {
+				// Exit after too many retries, maybe the error is not recoverable.
+				log.Error(err, "too many failures, aborting", "failCount", failCount)
+				os.Exit(1)
+			}
+
+			failCount++
+			log.Error(err, "unexpected error fetching the bucket, will retry")
+			log.Info("waiting before retrying", "waitTime", util.WaitTime(*flWait))
+			cancel()
+			time.Sleep(util.WaitTime(*flWait))
+			continue
+		}
+
+		if initialSync {
+			if *flOneTime {
+				log.DeleteErrorFile()
+				os.Exit(0)
+			}
+			initialSync = false
+		}
+
+		failCount = 0
+		log.DeleteErrorFile()
+		log.Info("next sync", "wait_time", util.WaitTime(*flWait))
+		cancel()
+		time.Sleep(util.WaitTime(*flWait))
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	"google.golang.org/protobuf/proto"
+)
+
+// releases are the Kubernetes releases whose OpenAPI schemas nomos bundles,
+// one per supported minor version.
+// Add the latest patch release of a minor version to support it.
+var releases = []string{
+	"1.21.14",
+	"1.22.17",
+	"1.23.17",
+	"1.24.17",
+}
+
+// moduleURL is the URL of the source of a Kubernetes release on the Go module
+// proxy.
+const moduleURL = "https://proxy.golang.org/k8s.io/kubernetes/@v/v%s.zip"
+
+const prefix = `// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by cmd/gen-openapi-schemas/main.go. DO NOT EDIT
+
+package vet
+
+// bundledSchemas maps the supported Kubernetes versions to the file of their
+// bundled OpenAPI schema.
+var bundledSchemas = map[string]string{
+`
+
+func main() {
+	sb := strings.Builder{}
+	sb.WriteString(prefix)
+
+	for _, release := range releases {
+		swagger, err := fetchSwagger(release)
+		if err != nil {
+			panic(err)
+		}
+		doc, err := openapi_v2.ParseDocument(swagger)
+		if err != nil {
+			panic(fmt.Errorf("failed to parse the OpenAPI schema of Kubernetes %s: %w", release, err))
+		}
+		pb, err := proto.Marshal(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		minor := release[:strings.LastIndex(release, ".")]
+		file := fmt.Sprintf("schemas/v%s.pb.gz", release)
+		if err := writeGzip(filepath.Join("pkg/vet", file), pb); err != nil {
+			panic(err)
+		}
+		sb.WriteString(fmt.Sprintf("\t%q: %q,\n", minor, file))
+	}
+
+	sb.WriteString("}\n")
+
+	err := ioutil.WriteFile("pkg/vet/schemas.generated.go", []byte(sb.String()), 0644)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Don't forget to remove the schemas of the releases no longer bundled from pkg/vet/schemas!")
+}
+
+// fetchSwagger returns the OpenAPI schema of the Kubernetes release, read from
+// its source.
+func fetchSwagger(release string) ([]byte, error) {
+	resp, err := http.Get(fmt.Sprintf(moduleURL, release))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download Kubernetes %s: %s", release, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("k8s.io/kubernetes@v%s/api/openapi-spec/swagger.json", release)
+	for _, f := range archive.File {
+		if f.Name != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = r.Close()
+		}()
+		return ioutil.ReadAll(r)
+	}
+	return nil, fmt.Errorf("%s not found in Kubernetes %s", name, release)
+}
+
+func writeGzip(path string, data []byte) error {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
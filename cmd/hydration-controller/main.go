@@ -30,6 +30,7 @@ import (
 	"kpt.dev/configsync/pkg/profiler"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
+	"kpt.dev/configsync/pkg/tracing"
 	"kpt.dev/configsync/pkg/util/log"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -70,6 +71,9 @@ var (
 
 	reconcilerName = flag.String("reconciler-name", os.Getenv(reconcilermanager.ReconcilerNameKey),
 		"Name of the reconciler Deployment.")
+
+	tracingEndpoint = flag.String("tracing-endpoint", os.Getenv(reconcilermanager.TracingEndpointKey),
+		"Address of the OC Agent compatible endpoint to export trace spans to. Tracing is disabled if empty.")
 )
 
 func main() {
@@ -94,6 +98,19 @@ func main() {
 		}
 	}()
 
+	// Register the OC Agent trace exporter
+	tracingExporter, err := tracing.RegisterOCAgentExporter(reconcilermanager.HydrationController, *tracingEndpoint)
+	if err != nil {
+		klog.Fatalf("Failed to register the OC Agent trace exporter: %v", err)
+	}
+	if tracingExporter != nil {
+		defer func() {
+			if err := tracingExporter.Stop(); err != nil {
+				klog.Errorf("Unable to stop the OC Agent trace exporter: %v", err)
+			}
+		}()
+	}
+
 	absRepoRootDir, err := cmpath.AbsoluteOS(*repoRootDir)
 	if err != nil {
 		klog.Fatalf("--repo-root must be an absolute path: %v", err)
@@ -0,0 +1,155 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2/klogr"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/local"
+	"kpt.dev/configsync/pkg/reconcilermanager"
+	"kpt.dev/configsync/pkg/util"
+	utillog "kpt.dev/configsync/pkg/util/log"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var flNamespace = flag.String("namespace", util.EnvString(reconcilermanager.LocalSyncNamespace, ""),
+	"the namespace of the ConfigMap or Secret holding the source")
+var flConfigMap = flag.String("configmap", util.EnvString(reconcilermanager.LocalSyncConfigMap, ""),
+	"the name of the ConfigMap holding a tarball of the source")
+var flSecret = flag.String("secret", util.EnvString(reconcilermanager.LocalSyncSecret, ""),
+	"the name of the Secret holding a tarball of the source")
+var flKey = flag.String("key", util.EnvString(reconcilermanager.LocalSyncKey, ""),
+	"the key of the ConfigMap or Secret holding the tarball")
+var flDir = flag.String("dir", util.EnvString(reconcilermanager.LocalSyncDir, ""),
+	"the directory holding the source, typically a mounted PersistentVolumeClaim")
+var flRoot = flag.String("root", util.EnvString("LOCAL_SYNC_ROOT", util.EnvString("HOME", "")+"/local"),
+	"the root directory for local-sync operations, under which --dest will be created")
+var flDest = flag.String("dest", util.EnvString("LOCAL_SYNC_DEST", "rev"),
+	"the path (absolute or relative to --root) at which to create a symlink to the directory holding the retrieved files")
+var flErrorFile = flag.String("error-file", util.EnvString("LOCAL_SYNC_ERROR_FILE", ""),
+	"the name of a file into which errors will be written under --root (defaults to \"\", disabling error reporting)")
+var flWait = flag.Float64("wait", util.EnvFloat(reconcilermanager.LocalSyncWait, 1),
+	"the number of seconds between syncs")
+var flSyncTimeout = flag.Int("timeout", util.EnvInt("LOCAL_SYNC_TIMEOUT", 120),
+	"the max number of seconds allowed for a complete sync")
+var flOneTime = flag.Bool("one-time", util.EnvBool("LOCAL_SYNC_ONE_TIME", false),
+	"exit after the first sync")
+var flMaxSyncFailures = flag.Int("max-sync-failures", util.EnvInt("LOCAL_SYNC_MAX_SYNC_FAILURES", 0),
+	"the number of consecutive failures allowed before aborting (the first sync must succeed, -1 will retry forever after the initial sync)")
+
+func main() {
+	utillog.Setup()
+	log := utillog.NewLogger(klogr.New(), *flRoot, *flErrorFile)
+
+	log.Info("fetching local source with arguments", "--namespace", *flNamespace,
+		"--configmap", *flConfigMap, "--secret", *flSecret, "--key", *flKey, "--dir", *flDir,
+		"--root", *flRoot, "--dest", *flDest, "--wait", *flWait,
+		"--error-file", *flErrorFile, "--timeout", *flSyncTimeout,
+		"--one-time", *flOneTime, "--max-sync-failures", *flMaxSyncFailures)
+
+	sources := 0
+	for _, s := range []string{*flConfigMap, *flSecret, *flDir} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		utillog.HandleError(log, true, "ERROR: exactly one of --configmap, --secret and --dir must be specified")
+	}
+
+	if *flDir == "" && (*flNamespace == "" || *flKey == "") {
+		utillog.HandleError(log, true, "ERROR: --namespace and --key must be specified with --configmap or --secret")
+	}
+
+	if *flRoot == "" {
+		utillog.HandleError(log, true, "ERROR: --root must be specified")
+	}
+
+	if *flWait < 0 {
+		utillog.HandleError(log, true, "ERROR: --wait must be greater than or equal to 0")
+	}
+
+	if *flSyncTimeout < 0 {
+		utillog.HandleError(log, true, "ERROR: --timeout must be greater than 0")
+	}
+
+	var fetch func(ctx context.Context) error
+	if *flDir != "" {
+		fetch = func(context.Context) error {
+			return local.FetchDir(*flDir, *flRoot, *flDest)
+		}
+	} else {
+		c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: core.Scheme})
+		if err != nil {
+			utillog.HandleError(log, false, "ERROR: failed to create client: %v", err)
+		}
+		key := client.ObjectKey{Namespace: *flNamespace}
+		fetch = func(ctx context.Context) error {
+			var data []byte
+			var err error
+			if *flConfigMap != "" {
+				key.Name = *flConfigMap
+				data, err = local.ConfigMapData(ctx, c, key, *flKey)
+			} else {
+				key.Name = *flSecret
+				data, err = local.SecretData(ctx, c, key, *flKey)
+			}
+			if err != nil {
+				return err
+			}
+			return local.FetchTarball(data, *flRoot, *flDest)
+		}
+	}
+
+	initialSync := true
+	failCount := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(*flSyncTimeout))
+		if err := fetch(ctx); err != nil {
+			if *flMaxSyncFailures != -1 && failCount >= *flMaxSyncFailures {
+				// Exit after too many retries, maybe the error is not recoverable.
+				log.Error(err, "too many failures, aborting", "failCount", failCount)
+				os.Exit(1)
+			}
+
+			failCount++
+			log.Error(err, "unexpected error fetching the local source, will retry")
+			log.Info("waiting before retrying", "waitTime", util.WaitTime(*flWait))
+			cancel()
+			time.Sleep(util.WaitTime(*flWait))
+			continue
+		}
+
+		if initialSync {
+			if *flOneTime {
+				log.DeleteErrorFile()
+				os.Exit(0)
+			}
+			initialSync = false
+		}
+
+		failCount = 0
+		log.DeleteErrorFile()
+		log.Info("next sync", "wait_time", util.WaitTime(*flWait))
+		cancel()
+		time.Sleep(util.WaitTime(*flWait))
+	}
+}
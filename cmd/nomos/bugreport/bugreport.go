@@ -17,6 +17,8 @@ package bugreport
 import (
 	"flag"
 	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -27,19 +29,76 @@ import (
 	"kpt.dev/configsync/pkg/client/restconfig"
 )
 
+var (
+	rootSyncs      []string
+	repoSyncs      []string
+	logsSince      time.Duration
+	logsSinceTime  string
+	redactPatterns []string
+)
+
 func init() {
 	Cmd.Flags().DurationVar(&flags.ClientTimeout, "timeout", flags.DefaultClusterClientTimeout, "Timeout for connecting to the cluster")
+	Cmd.Flags().StringSliceVar(&rootSyncs, "root-syncs", nil,
+		"Accepts a comma-separated list of RootSync names. Limits the RootSyncs, RepoSyncs, ResourceGroups and reconciler logs in the report to those of the listed syncs.")
+	Cmd.Flags().StringSliceVar(&repoSyncs, "repo-syncs", nil,
+		"Accepts a comma-separated list of RepoSyncs formatted as <namespace>/<name>. Limits the RootSyncs, RepoSyncs, ResourceGroups and reconciler logs in the report to those of the listed syncs.")
+	Cmd.Flags().DurationVar(&logsSince, "since", 0,
+		"Only collect logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs.")
+	Cmd.Flags().StringVar(&logsSinceTime, "since-time", "",
+		"Only collect logs after a date (RFC3339). Only one of --since or --since-time may be used.")
+	Cmd.Flags().StringArrayVar(&redactPatterns, "redact", nil,
+		"A regular expression whose matches are redacted from the report, in addition to the built-in redaction of Secret data, token-like values and credentials in URLs. May be repeated.")
+}
+
+// options returns the bug report options set by the flags.
+func options() (bugreport.Options, error) {
+	opts := bugreport.Options{
+		RootSyncs: rootSyncs,
+		LogsSince: logsSince,
+	}
+	for _, rs := range repoSyncs {
+		nn, err := bugreport.ParseRepoSync(rs)
+		if err != nil {
+			return opts, err
+		}
+		opts.RepoSyncs = append(opts.RepoSyncs, nn)
+	}
+	if logsSinceTime != "" {
+		if logsSince > 0 {
+			return opts, errors.New("only one of --since or --since-time may be used")
+		}
+		t, err := time.Parse(time.RFC3339, logsSinceTime)
+		if err != nil {
+			return opts, errors.Wrapf(err, "invalid --since-time")
+		}
+		opts.LogsSinceTime = t
+	}
+	for _, p := range redactPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return opts, errors.Wrapf(err, "invalid --redact pattern %q", p)
+		}
+		opts.RedactPatterns = append(opts.RedactPatterns, re)
+	}
+	return opts, nil
 }
 
 // Cmd retrieves readers for all relevant nomos container logs and cluster state commands and writes them to a zip file
 var Cmd = &cobra.Command{
 	Use:   "bugreport",
 	Short: fmt.Sprintf("Generates a zip file of relevant %v debug information.", configmanagement.CLIName),
-	Long:  "Generates a zip file in your current directory containing an aggregate of the logs and cluster state for debugging purposes.",
+	Long: "Generates a zip file in your current directory containing an aggregate of the logs and cluster state for debugging purposes. " +
+		"Secret data, token-like values and credentials in URLs are redacted, and a manifest.yaml file lists what was collected and redacted.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Don't show usage on error, as argument validation passed.
 		cmd.SilenceUsage = true
 
+		opts, err := options()
+		if err != nil {
+			return err
+		}
+
 		// hack to set the hidden variable in klog to also print info statements
 		// cobra does not expose core golang-style flags
 		if err := flag.CommandLine.Parse([]string{"--stderrthreshold=0"}); err != nil {
@@ -51,7 +110,7 @@ var Cmd = &cobra.Command{
 			return errors.Wrapf(err, "failed to create rest config")
 		}
 
-		report, err := bugreport.New(cmd.Context(), cfg)
+		report, err := bugreport.New(cmd.Context(), cfg, opts)
 		if err != nil {
 			return errors.Wrap(err, "failed to initialize bug reporter")
 		}
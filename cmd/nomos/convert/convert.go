@@ -0,0 +1,146 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	nomosparse "kpt.dev/configsync/cmd/nomos/parse"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/hydrate"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/importer/reader"
+)
+
+const (
+	// toUnstructured converts a hierarchy repository into an unstructured one.
+	toUnstructured = "unstructured"
+
+	defaultOutput = "unstructured"
+)
+
+var (
+	to      string
+	outPath string
+)
+
+func init() {
+	flags.AddPath(Cmd)
+	flags.AddSkipAPIServerCheck(Cmd)
+	flags.AddAPIServerTimeout(Cmd)
+	Cmd.Flags().StringVar(&to, "to", toUnstructured,
+		fmt.Sprintf("Format of the converted repository. Only %q is supported.", toUnstructured))
+	Cmd.Flags().StringVar(&outPath, "output", defaultOutput,
+		"Directory to write the converted repository to. It must not exist or be empty.")
+}
+
+// Cmd is the Cobra object representing the convert command.
+var Cmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Converts a hierarchy repository into an equivalent unstructured repository.",
+	Long: `Converts a hierarchy repository into an equivalent unstructured repository.
+
+The repository is hydrated for each declared Cluster the same way as it is synced: objects in
+abstract namespaces are copied into each inheriting Namespace, NamespaceSelectors are evaluated,
+and every namespaced object gets an explicit namespace. Objects which are only synced to some
+clusters keep their cluster selection. Comments are preserved for the objects which hydration
+only assigned a namespace to.
+
+The semantics which the unstructured repository can't preserve exactly are reported.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Don't show usage on error, as argument validation passed.
+		cmd.SilenceUsage = true
+
+		if to != toUnstructured {
+			return fmt.Errorf("unsupported --to %q, only %q is supported", to, toUnstructured)
+		}
+		if err := checkOutput(outPath); err != nil {
+			return err
+		}
+
+		rootDir, needsHydrate, err := hydrate.ValidateHydrateFlags(filesystem.SourceFormatHierarchy)
+		if err != nil {
+			return err
+		}
+		if needsHydrate {
+			return errors.New("repositories with a kustomization file can't be converted, since they are already unstructured")
+		}
+
+		files, err := nomosparse.FindFiles(rootDir)
+		if err != nil {
+			return err
+		}
+		files = filesystem.FilterHierarchyFiles(rootDir, files)
+
+		options, err := hydrate.ValidateOptions(cmd.Context(), rootDir, flags.APIServerTimeout)
+		if err != nil {
+			return err
+		}
+
+		filePaths := reader.FilePaths{
+			RootDir:   rootDir,
+			PolicyDir: cmpath.RelativeOS(rootDir.OSPath()),
+			Files:     files,
+		}
+		converted, notes, errs := hydrate.ConvertToUnstructured(filesystem.NewParser(&reader.File{}), options, filePaths)
+		if errs != nil {
+			util.PrintErrOrDie(errs)
+			return errors.New("the repository has errors, fix them before converting it")
+		}
+
+		for _, f := range converted {
+			p := filepath.Join(outPath, filepath.FromSlash(f.SlashPath))
+			if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(p, []byte(f.Content), 0644); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Converted repository written to %s. Sync it with sourceFormat: %s.\n", outPath, filesystem.SourceFormatUnstructured)
+
+		if len(notes) > 0 {
+			fmt.Printf("\nThe following semantics of the hierarchy repository are not preserved exactly:\n")
+			for _, n := range notes {
+				fmt.Printf("%s- %s\n", util.Indent, n)
+			}
+		}
+		return nil
+	},
+}
+
+// checkOutput returns an error if the output directory exists and isn't empty,
+// so that a conversion never overwrites files.
+func checkOutput(output string) error {
+	entries, err := ioutil.ReadDir(output)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("output directory %q is not empty", output)
+	}
+	return nil
+}
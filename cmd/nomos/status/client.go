@@ -44,6 +44,8 @@ import (
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/client/restconfig"
 	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/resourcegroup"
+	"kpt.dev/configsync/pkg/shard"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
@@ -133,6 +135,17 @@ func (c *ClusterClient) resourceGroups(ctx context.Context, ns string, nsAndName
 		localRG := rg
 		resourceGroups = append(resourceGroups, &localRG)
 	}
+	// Large inventories are spread across multiple ResourceGroups.
+	resourceGroups, err := resourcegroup.MergeChunks(resourceGroups)
+	if err != nil {
+		return nil, err
+	}
+	// The objects of sharded RootSyncs are tracked in a ResourceGroup per
+	// shard.
+	resourceGroups, err = shard.MergeResourceGroups(resourceGroups)
+	if err != nil {
+		return nil, err
+	}
 	return consistentOrder(nsAndNames, resourceGroups), nil
 }
 
@@ -77,6 +77,8 @@ type RepoState struct {
 	git               *v1beta1.Git
 	oci               *v1beta1.Oci
 	helm              *v1beta1.HelmBase
+	local             string
+	bucket            string
 	status            string
 	commit            string
 	lastSyncTimestamp metav1.Time
@@ -87,7 +89,7 @@ type RepoState struct {
 }
 
 func (r *RepoState) printRows(writer io.Writer) {
-	fmt.Fprintf(writer, "%s%s:%s\t%s\t\n", util.Indent, r.scope, r.syncName, sourceString(r.sourceType, r.git, r.oci, r.helm))
+	fmt.Fprintf(writer, "%s%s:%s\t%s\t\n", util.Indent, r.scope, r.syncName, sourceString(r.sourceType, r.git, r.oci, r.helm, r.local, r.bucket))
 	if r.status == syncedMsg {
 		fmt.Fprintf(writer, "%s%s @ %v\t%s\t\n", util.Indent, r.status, r.lastSyncTimestamp, r.commit)
 	} else {
@@ -131,8 +133,12 @@ func (r *RepoState) printRows(writer io.Writer) {
 	}
 }
 
-func sourceString(sourceType v1beta1.SourceType, git *v1beta1.Git, oci *v1beta1.Oci, helm *v1beta1.HelmBase) string {
+func sourceString(sourceType v1beta1.SourceType, git *v1beta1.Git, oci *v1beta1.Oci, helm *v1beta1.HelmBase, local, bucket string) string {
 	switch sourceType {
+	case v1beta1.LocalSource:
+		return locationString(local)
+	case v1beta1.BucketSource:
+		return locationString(bucket)
 	case v1beta1.OciSource:
 		return ociString(oci)
 	case v1beta1.HelmSource:
@@ -180,6 +186,14 @@ func ociString(oci *v1beta1.Oci) string {
 	return ociStr
 }
 
+// locationString returns the location of a local or bucket source.
+func locationString(location string) string {
+	if location == "" {
+		return "N/A"
+	}
+	return location
+}
+
 func helmString(helm *v1beta1.HelmBase) string {
 	var helmStr string
 	if helm == nil {
@@ -309,6 +323,8 @@ func namespaceRepoStatus(rs *v1beta1.RepoSync, rg *unstructured.Unstructured, sy
 		git:        rs.Spec.Git,
 		oci:        rs.Spec.Oci,
 		helm:       reposync.GetHelmBase(rs.Spec.Helm),
+		local:      v1beta1.GetLocalSource(rs.Spec.Local, rs.Namespace),
+		bucket:     v1beta1.GetBucketSource(rs.Spec.Bucket),
 		commit:     emptyCommit,
 	}
 
@@ -411,6 +427,8 @@ func RootRepoStatus(rs *v1beta1.RootSync, rg *unstructured.Unstructured, syncing
 		git:        rs.Spec.Git,
 		oci:        rs.Spec.Oci,
 		helm:       rootsync.GetHelmBase(rs.Spec.Helm),
+		local:      v1beta1.GetLocalSource(rs.Spec.Local, rs.Namespace),
+		bucket:     v1beta1.GetBucketSource(rs.Spec.Bucket),
 		commit:     emptyCommit,
 	}
 	stalledCondition := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled)
@@ -0,0 +1,184 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// outputText prints the status as tab-aligned text.
+	outputText = "text"
+
+	// StatusOutputVersion is the version of the schema of the structured
+	// `nomos status` output. Fields may be added within a version, but they
+	// are never removed or renamed.
+	StatusOutputVersion = "v1"
+)
+
+// StatusOutput is the structured `nomos status` output.
+type StatusOutput struct {
+	// Version is the version of the schema, see StatusOutputVersion.
+	Version string `json:"version"`
+	// Clusters is the status of each cluster, sorted by name.
+	Clusters []ClusterOutput `json:"clusters"`
+}
+
+// ClusterOutput is the sync status of all the RootSyncs and RepoSyncs on a
+// cluster.
+type ClusterOutput struct {
+	// Name is the name of the cluster context.
+	Name string `json:"name"`
+	// Current is true for the current context of the kubeconfig.
+	Current bool `json:"current,omitempty"`
+	// Status is the status of Config Sync on the cluster, if it is not
+	// healthy, e.g. UNINSTALLED or N/A.
+	Status string `json:"status,omitempty"`
+	// Error is the error of Config Sync on the cluster, if any.
+	Error string `json:"error,omitempty"`
+	// Syncs is the status of each RootSync and RepoSync.
+	Syncs []SyncOutput `json:"syncs,omitempty"`
+}
+
+// SyncOutput is the sync status of a single RootSync or RepoSync.
+type SyncOutput struct {
+	// Scope is `<root>` for RootSyncs, otherwise the Namespace of the RepoSync.
+	Scope string `json:"scope"`
+	// Name is the name of the RootSync or RepoSync.
+	Name string `json:"name,omitempty"`
+	// SourceType is the type of the source, e.g. git, oci or helm.
+	SourceType v1beta1.SourceType `json:"sourceType,omitempty"`
+	// Source is the location of the source, e.g. `<repo>/<dir>@<branch>`.
+	Source string `json:"source"`
+	// Commit is the commit being synced.
+	Commit string `json:"commit"`
+	// Status is the sync status, e.g. SYNCED, PENDING or ERROR.
+	Status string `json:"status"`
+	// LastSyncTimestamp is the last time the source was synced, if synced.
+	LastSyncTimestamp *metav1.Time `json:"lastSyncTimestamp,omitempty"`
+	// Errors are the errors reported by the sync.
+	Errors []string `json:"errors,omitempty"`
+	// ErrorSummary summarizes the errors.
+	ErrorSummary *v1beta1.ErrorSummary `json:"errorSummary,omitempty"`
+	// Resources is the status of each managed resource.
+	Resources []resourceState `json:"resources,omitempty"`
+	// Conflicts are the managed resources which are also managed by another
+	// RootSync or RepoSync, formatted as `<namespace>/<kind>.<group>/<name>`.
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// validateOutputFormat returns an error if the format is not supported.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, flags.OutputJSON, flags.OutputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of %q, %q or %q",
+			format, outputText, flags.OutputJSON, flags.OutputYAML)
+	}
+}
+
+// statusOutput converts the states of the clusters into the structured output.
+func statusOutput(stateMap map[string]*ClusterState, names []string, currentContext string) StatusOutput {
+	out := StatusOutput{
+		Version:  StatusOutputVersion,
+		Clusters: []ClusterOutput{},
+	}
+	for _, name := range names {
+		state := stateMap[name]
+		cluster := ClusterOutput{
+			Name:    name,
+			Current: name == currentContext,
+			Status:  state.status,
+			Error:   state.Error,
+		}
+		for _, repo := range state.repos {
+			cluster.Syncs = append(cluster.Syncs, repo.output())
+		}
+		out.Clusters = append(out.Clusters, cluster)
+	}
+	return out
+}
+
+func (r *RepoState) output() SyncOutput {
+	out := SyncOutput{
+		Scope:        r.scope,
+		Name:         r.syncName,
+		SourceType:   r.sourceType,
+		Source:       sourceString(r.sourceType, r.git, r.oci, r.helm, r.local, r.bucket),
+		Commit:       r.commit,
+		Status:       r.status,
+		Errors:       r.errors,
+		ErrorSummary: r.errorSummary,
+	}
+	if r.status == syncedMsg && !r.lastSyncTimestamp.IsZero() {
+		timestamp := r.lastSyncTimestamp
+		out.LastSyncTimestamp = &timestamp
+	}
+	if resourceStatus && len(r.resources) > 0 {
+		out.Resources = make([]resourceState, len(r.resources))
+		copy(out.Resources, r.resources)
+		sort.Sort(byNamespaceAndType(out.Resources))
+		for _, res := range out.Resources {
+			if res.Status == conflictStatus {
+				out.Conflicts = append(out.Conflicts, res.Namespace+"/"+res.String())
+			}
+		}
+	}
+	return out
+}
+
+// notSynced returns the names of the syncs which are not synced, formatted as
+// `<cluster>:<scope>:<name>`, and of the clusters whose status is unknown.
+func (o StatusOutput) notSynced() []string {
+	var names []string
+	for _, cluster := range o.Clusters {
+		if cluster.Status != "" || cluster.Error != "" {
+			names = append(names, cluster.Name)
+		}
+		for _, sync := range cluster.Syncs {
+			if sync.Status != syncedMsg {
+				names = append(names, fmt.Sprintf("%s:%s:%s", cluster.Name, sync.Scope, sync.Name))
+			}
+		}
+	}
+	return names
+}
+
+// print writes the structured output in the specified format.
+func (o StatusOutput) print(writer io.Writer, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case flags.OutputJSON:
+		data, err = json.MarshalIndent(o, "", "  ")
+		data = append(data, '\n')
+	default:
+		data, err = yaml.Marshal(o)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal the status: %w", err)
+	}
+	_, err = writer.Write(data)
+	return err
+}
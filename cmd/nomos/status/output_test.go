@@ -0,0 +1,157 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/cmd/nomos/util"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStatusOutput(t *testing.T) {
+	stateMap := map[string]*ClusterState{
+		"cluster-1": {
+			Ref: "cluster-1",
+			repos: []*RepoState{
+				{
+					scope:             "<root>",
+					syncName:          "root-sync",
+					sourceType:        v1beta1.GitSource,
+					git:               git,
+					status:            syncedMsg,
+					commit:            "abcd123",
+					lastSyncTimestamp: lastSyncTimestamp,
+					resources:         exampleResources("abcd123"),
+				},
+				{
+					scope:        "bookstore",
+					syncName:     "repo-sync",
+					sourceType:   v1beta1.OciSource,
+					oci:          oci,
+					status:       util.ErrorMsg,
+					commit:       "efgh456",
+					errors:       []string{"KNV1021: No CustomResourceDefinition is defined"},
+					errorSummary: errorSummayWithOneError,
+				},
+			},
+		},
+		"cluster-2": unavailableCluster("cluster-2"),
+	}
+
+	got := statusOutput(stateMap, []string{"cluster-1", "cluster-2"}, "cluster-1")
+
+	want := StatusOutput{
+		Version: StatusOutputVersion,
+		Clusters: []ClusterOutput{
+			{
+				Name:    "cluster-1",
+				Current: true,
+				Syncs: []SyncOutput{
+					{
+						Scope:             "<root>",
+						Name:              "root-sync",
+						SourceType:        v1beta1.GitSource,
+						Source:            "git@github.com:tester/sample/admin@v1",
+						Commit:            "abcd123",
+						Status:            syncedMsg,
+						LastSyncTimestamp: &lastSyncTimestamp,
+						Resources:         exampleResources("abcd123"),
+						Conflicts:         []string{"bookstore/service/test2"},
+					},
+					{
+						Scope:        "bookstore",
+						Name:         "repo-sync",
+						SourceType:   v1beta1.OciSource,
+						Source:       "us-docker.pkg.dev/test-project/test-ar-repo/sample/test",
+						Commit:       "efgh456",
+						Status:       util.ErrorMsg,
+						Errors:       []string{"KNV1021: No CustomResourceDefinition is defined"},
+						ErrorSummary: errorSummayWithOneError,
+					},
+				},
+			},
+			{
+				Name:   "cluster-2",
+				Status: "N/A",
+				Error:  "Failed to connect to cluster",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("statusOutput() diff (-want +got):\n%s", diff)
+	}
+
+	wantNotSynced := []string{"cluster-1:bookstore:repo-sync", "cluster-2"}
+	if diff := cmp.Diff(wantNotSynced, got.notSynced()); diff != "" {
+		t.Errorf("notSynced() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatusOutput_Print(t *testing.T) {
+	out := StatusOutput{
+		Version: StatusOutputVersion,
+		Clusters: []ClusterOutput{{
+			Name: "cluster-1",
+			Syncs: []SyncOutput{{
+				Scope:      "<root>",
+				Name:       "root-sync",
+				SourceType: v1beta1.GitSource,
+				Source:     "git@github.com:tester/sample@main",
+				Commit:     "abcd123",
+				Status:     syncedMsg,
+			}},
+		}},
+	}
+
+	testCases := []struct {
+		format    string
+		unmarshal func([]byte, interface{}) error
+	}{
+		{format: flags.OutputJSON, unmarshal: json.Unmarshal},
+		{format: flags.OutputYAML, unmarshal: func(data []byte, v interface{}) error { return yaml.Unmarshal(data, v) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := out.print(&buffer, tc.format); err != nil {
+				t.Fatal(err)
+			}
+			var got StatusOutput
+			if err := tc.unmarshal(buffer.Bytes(), &got); err != nil {
+				t.Fatalf("failed to parse the %s output: %v\n%s", tc.format, err, buffer.String())
+			}
+			if diff := cmp.Diff(out, got); diff != "" {
+				t.Errorf("round trip diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, f := range []string{outputText, flags.OutputJSON, flags.OutputYAML} {
+		if err := validateOutputFormat(f); err != nil {
+			t.Errorf("validateOutputFormat(%q) got error %v, want nil", f, err)
+		}
+	}
+	if err := validateOutputFormat("table"); err == nil {
+		t.Error("validateOutputFormat(\"table\") got nil, want error")
+	}
+}
@@ -23,6 +23,10 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// conflictStatus is the status of a resource which is also managed by another
+// RootSync or RepoSync.
+const conflictStatus = "Conflict"
+
 type resourceState struct {
 	Namespace  string      `json:"namespace"`
 	Name       string      `json:"name"`
@@ -111,7 +115,7 @@ func checkConflict(states []resourceState) []resourceState {
 	for i, s := range states {
 		for _, c := range s.Conditions {
 			if c.Type == "OwnershipOverlap" && c.Status == "True" {
-				states[i].Status = "Conflict"
+				states[i].Status = conflictStatus
 			}
 		}
 	}
@@ -23,6 +23,7 @@ import (
 	"os/exec"
 	"runtime"
 	"sort"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -45,6 +46,8 @@ var (
 	pollingInterval time.Duration
 	namespace       string
 	resourceStatus  bool
+	format          string
+	exitCode        bool
 )
 
 func init() {
@@ -53,6 +56,10 @@ func init() {
 	Cmd.Flags().DurationVar(&pollingInterval, "poll", 0*time.Second, "Polling interval (leave unset to run once)")
 	Cmd.Flags().StringVar(&namespace, "namespace", "", "Namespace repo to get status for (multi-repo only, leave unset to get all repos)")
 	Cmd.Flags().BoolVar(&resourceStatus, "resources", true, "show resource level status for Namespace repo (multi-repo only)")
+	Cmd.Flags().StringVar(&format, "format", outputText,
+		fmt.Sprintf("Output format. Accepts '%s', '%s' and '%s'. The '%s' and '%s' formats follow the %s schema and cannot be polled.",
+			outputText, flags.OutputJSON, flags.OutputYAML, flags.OutputJSON, flags.OutputYAML, StatusOutputVersion))
+	Cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with a non-zero status if any RootSync or RepoSync is not synced, or any cluster is unavailable (ignored when polling)")
 }
 
 // SaveToTempFile writes the `nomos status` output into a temporary file, and
@@ -97,7 +104,19 @@ var Cmd = &cobra.Command{
 		// Don't show usage on error, as argument validation passed.
 		cmd.SilenceUsage = true
 
-		fmt.Println("Connecting to clusters...")
+		if err := validateOutputFormat(format); err != nil {
+			return err
+		}
+		if format != outputText && pollingInterval > 0 {
+			return fmt.Errorf("--poll is not supported with --format=%s", format)
+		}
+
+		if format == outputText {
+			fmt.Println("Connecting to clusters...")
+		} else {
+			// Keep stdout parseable.
+			fmt.Fprintln(os.Stderr, "Connecting to clusters...")
+		}
 
 		clientMap, err := ClusterClients(cmd.Context(), flags.Contexts)
 		if err != nil {
@@ -115,19 +134,34 @@ var Cmd = &cobra.Command{
 		// Use a sorted order of names to avoid shuffling in the output.
 		names := clusterNames(clientMap)
 
+		if format != outputText {
+			out := structuredStatus(cmd.Context(), clientMap, names)
+			if err := out.print(os.Stdout, format); err != nil {
+				return err
+			}
+			return checkSynced(out.notSynced())
+		}
+
 		writer := util.NewWriter(os.Stdout)
 		if pollingInterval > 0 {
 			for {
 				printStatus(cmd.Context(), writer, clientMap, names)
 				time.Sleep(pollingInterval)
 			}
-		} else {
-			printStatus(cmd.Context(), writer, clientMap, names)
 		}
-		return nil
+		return checkSynced(printStatus(cmd.Context(), writer, clientMap, names))
 	},
 }
 
+// checkSynced returns an error listing the syncs which are not synced, if the
+// --exit-code flag is set.
+func checkSynced(notSynced []string) error {
+	if !exitCode || len(notSynced) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d sync(s) or cluster(s) not synced: %s", len(notSynced), strings.Join(notSynced, ", "))
+}
+
 // clusterNames returns a sorted list of names from the given clientMap.
 func clusterNames(clientMap map[string]*ClusterClient) []string {
 	var names []string
@@ -157,11 +191,23 @@ func clusterStates(ctx context.Context, clientMap map[string]*ClusterClient) (ma
 	return stateMap, monoRepoClusters
 }
 
+// structuredStatus fetches the status of each cluster in the given map, and
+// returns it in the structured output schema.
+func structuredStatus(ctx context.Context, clientMap map[string]*ClusterClient, names []string) StatusOutput {
+	stateMap, _ := clusterStates(ctx, clientMap)
+	currentContext, err := restconfig.CurrentContextName()
+	if err != nil {
+		klog.Warningf("Failed to get current context name with err: %v", errors.Cause(err))
+	}
+	return statusOutput(stateMap, names, currentContext)
+}
+
 // printStatus fetches ConfigManagementStatus and/or RepoStatus from each cluster in the given map
 // and then prints a formatted status row for each one. If there are any errors reported by either
 // object, those are printed in a second table under the status table.
+// It returns the syncs which are not synced, see StatusOutput.notSynced.
 // nolint:errcheck
-func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[string]*ClusterClient, names []string) {
+func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[string]*ClusterClient, names []string) []string {
 	// First build up a map of all the states to display.
 	stateMap, monoRepoClusters := clusterStates(ctx, clientMap)
 
@@ -192,6 +238,7 @@ func printStatus(ctx context.Context, writer *tabwriter.Writer, clientMap map[st
 	}
 
 	writer.Flush()
+	return statusOutput(stateMap, names, currentContext).notSynced()
 }
 
 // clearTerminal executes an OS-specific command to clear all output on the terminal.
@@ -0,0 +1,314 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vet
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/status"
+)
+
+const (
+	// outputText prints the errors as human-readable text.
+	outputText = "text"
+	// outputSARIF prints the errors as a SARIF 2.1.0 log, which code scanning
+	// tools use to annotate pull requests.
+	outputSARIF = "sarif"
+	// outputJUnit prints the errors as a JUnit XML report, with a test suite
+	// for each cluster.
+	outputJUnit = "junit"
+
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	errorsURL    = "https://g.co/cloud/acm-errors"
+)
+
+// vetResult is a single validation error found by nomos vet.
+type vetResult struct {
+	// Code is the KNV code of the error, e.g. KNV1010.
+	Code string `json:"code"`
+	// Message is the body of the error.
+	Message string `json:"message"`
+	// Cluster is the cluster the error was found for, if the repository
+	// declares clusters.
+	Cluster string `json:"cluster,omitempty"`
+	// Paths are the repo-relative slash paths of the files which caused the
+	// error, if any.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// vetOutput is the structured nomos vet output.
+type vetOutput struct {
+	// Clusters are the clusters which were validated.
+	Clusters []string `json:"clusters"`
+	// Results are the validation errors, if any.
+	Results []vetResult `json:"results"`
+}
+
+// validateOutputFormat returns an error if the format is not supported.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, flags.OutputJSON, outputSARIF, outputJUnit:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of %q, %q, %q or %q",
+			format, outputText, flags.OutputJSON, outputSARIF, outputJUnit)
+	}
+}
+
+// vetResults converts the errors found for the cluster into results. The
+// source paths of the errors are made relative to rootDir, so they match the
+// paths of the files in the repository.
+func vetResults(rootDir cmpath.Absolute, clusterName string, errs status.MultiError) []vetResult {
+	if clusterName == defaultCluster {
+		clusterName = ""
+	}
+	var results []vetResult
+	for _, err := range errs.Errors() {
+		result := vetResult{
+			Code:    "KNV" + err.Code(),
+			Message: err.Body(),
+			Cluster: clusterName,
+		}
+		for _, r := range err.ToCSE().Resources {
+			path := relativePath(rootDir, r.SourcePath)
+			if path != "" && !containsString(result.Paths, path) {
+				result.Paths = append(result.Paths, path)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// relativePath returns the slash path of the source file relative to rootDir,
+// or the path unchanged if it is already relative or outside of rootDir.
+func relativePath(rootDir cmpath.Absolute, sourcePath string) string {
+	if sourcePath == "" || !filepath.IsAbs(filepath.FromSlash(sourcePath)) {
+		return sourcePath
+	}
+	rel, err := filepath.Rel(rootDir.OSPath(), filepath.FromSlash(sourcePath))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return sourcePath
+	}
+	return filepath.ToSlash(rel)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// print writes the results in the specified structured format.
+func (o vetOutput) print(writer io.Writer, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case outputSARIF:
+		data, err = json.MarshalIndent(o.sarif(), "", "  ")
+		data = append(data, '\n')
+	case outputJUnit:
+		data, err = xml.MarshalIndent(o.junit(), "", "  ")
+		data = append([]byte(xml.Header), append(data, '\n')...)
+	default:
+		if o.Results == nil {
+			o.Results = []vetResult{}
+		}
+		data, err = json.MarshalIndent(o, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal the validation results: %w", err)
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func (o vetOutput) sarif() sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "nomos",
+			InformationURI: errorsURL,
+		}},
+		Results: []sarifResult{},
+	}
+	rules := make(map[string]bool)
+	for _, r := range o.Results {
+		if !rules[r.Code] {
+			rules[r.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:      r.Code,
+				HelpURI: errorsURL + "#" + strings.ToLower(r.Code),
+			})
+		}
+		result := sarifResult{
+			RuleID:  r.Code,
+			Level:   "error",
+			Message: sarifMessage{Text: r.Message},
+		}
+		for _, p := range r.Paths {
+			result.Locations = append(result.Locations, sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: p}},
+			})
+		}
+		if r.Cluster != "" {
+			result.Properties = map[string]string{"cluster": r.Cluster}
+		}
+		run.Results = append(run.Results, result)
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+	return sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junit returns a test suite for each cluster, with a failed test case for
+// each error found for the cluster, or a single passed test case if the
+// cluster has no errors.
+func (o vetOutput) junit() junitTestSuites {
+	suites := junitTestSuites{Name: "nomos vet"}
+	for _, cluster := range o.Clusters {
+		name := cluster
+		if name == defaultCluster {
+			name = ""
+		}
+		suite := junitTestSuite{Name: cluster}
+		for _, r := range o.Results {
+			if r.Cluster != name {
+				continue
+			}
+			tc := junitTestCase{
+				Name:      r.Code,
+				ClassName: cluster,
+				Failure: &junitFailure{
+					Message: firstLine(r.Message),
+					Type:    r.Code,
+					Text:    r.Message,
+				},
+			}
+			if len(r.Paths) > 0 {
+				tc.Name = r.Code + " " + strings.Join(r.Paths, ", ")
+				tc.File = r.Paths[0]
+			}
+			suite.Cases = append(suite.Cases, tc)
+			suite.Failures++
+		}
+		if len(suite.Cases) == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{Name: "validation", ClassName: cluster})
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
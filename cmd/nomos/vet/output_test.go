@@ -0,0 +1,223 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vet
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/cmd/nomos/flags"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestVetResults(t *testing.T) {
+	rootDir, err := cmpath.AbsoluteSlash("/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := fake.RoleAtPath("namespaces/foo/role.yaml",
+		core.Annotation(metadata.SourcePathAnnotationKey, "/repo/namespaces/foo/role.yaml"))
+	roleDup := fake.RoleAtPath("namespaces/foo/role-dup.yaml",
+		core.Annotation(metadata.SourcePathAnnotationKey, "namespaces/foo/role-dup.yaml"))
+	collision := nonhierarchical.NamespaceMetadataNameCollisionError(
+		role.GroupVersionKind().GroupKind(), "foo", role.GetName(), role, roleDup)
+
+	testCases := []struct {
+		name    string
+		cluster string
+		errs    status.MultiError
+		want    []vetResult
+	}{
+		{
+			name:    "default cluster",
+			cluster: defaultCluster,
+			errs:    collision,
+			want: []vetResult{{
+				Code:    "KNV" + nonhierarchical.NameCollisionErrorCode,
+				Message: collision.Body(),
+				Paths:   []string{"namespaces/foo/role.yaml", "namespaces/foo/role-dup.yaml"},
+			}},
+		},
+		{
+			name:    "path error",
+			cluster: defaultCluster,
+			errs:    status.PathWrapError(errors.New("boom"), "/repo/cluster/bad.yaml"),
+			want: []vetResult{{
+				Code:    "KNV" + status.PathErrorCode,
+				Message: status.PathWrapError(errors.New("boom"), "/repo/cluster/bad.yaml").Body(),
+				Paths:   []string{"cluster/bad.yaml"},
+			}},
+		},
+		{
+			name:    "declared cluster",
+			cluster: "prod",
+			errs:    status.InternalError("boom"),
+			want: []vetResult{{
+				Code:    "KNV" + status.InternalErrorCode,
+				Message: status.InternalError("boom").Body(),
+				Cluster: "prod",
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := vetResults(rootDir, tc.cluster, tc.errs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestVetOutput_Print(t *testing.T) {
+	output := vetOutput{
+		Clusters: []string{"dev", "prod"},
+		Results: []vetResult{{
+			Code:    "KNV1029",
+			Message: "name collision\ndetails",
+			Cluster: "prod",
+			Paths:   []string{"namespaces/foo/role.yaml"},
+		}},
+	}
+
+	testCases := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, out []byte)
+	}{
+		{
+			name:   "json",
+			format: flags.OutputJSON,
+			check: func(t *testing.T, out []byte) {
+				got := vetOutput{}
+				if err := json.Unmarshal(out, &got); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(output, got); diff != "" {
+					t.Error(diff)
+				}
+			},
+		},
+		{
+			name:   "sarif",
+			format: outputSARIF,
+			check: func(t *testing.T, out []byte) {
+				got := sarifLog{}
+				if err := json.Unmarshal(out, &got); err != nil {
+					t.Fatal(err)
+				}
+				want := sarifLog{
+					Version: sarifVersion,
+					Schema:  sarifSchema,
+					Runs: []sarifRun{{
+						Tool: sarifTool{Driver: sarifDriver{
+							Name:           "nomos",
+							InformationURI: errorsURL,
+							Rules:          []sarifRule{{ID: "KNV1029", HelpURI: errorsURL + "#knv1029"}},
+						}},
+						Results: []sarifResult{{
+							RuleID:  "KNV1029",
+							Level:   "error",
+							Message: sarifMessage{Text: "name collision\ndetails"},
+							Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+								ArtifactLocation: sarifArtifactLocation{URI: "namespaces/foo/role.yaml"},
+							}}},
+							Properties: map[string]string{"cluster": "prod"},
+						}},
+					}},
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Error(diff)
+				}
+			},
+		},
+		{
+			name:   "junit",
+			format: outputJUnit,
+			check: func(t *testing.T, out []byte) {
+				if !strings.HasPrefix(string(out), xml.Header) {
+					t.Errorf("got output without the XML header:\n%s", out)
+				}
+				got := junitTestSuites{}
+				if err := xml.Unmarshal(out, &got); err != nil {
+					t.Fatal(err)
+				}
+				want := junitTestSuites{
+					XMLName:  xml.Name{Local: "testsuites"},
+					Name:     "nomos vet",
+					Tests:    2,
+					Failures: 1,
+					Suites: []junitTestSuite{
+						{
+							Name:  "dev",
+							Tests: 1,
+							Cases: []junitTestCase{{Name: "validation", ClassName: "dev"}},
+						},
+						{
+							Name:     "prod",
+							Tests:    1,
+							Failures: 1,
+							Cases: []junitTestCase{{
+								Name:      "KNV1029 namespaces/foo/role.yaml",
+								ClassName: "prod",
+								File:      "namespaces/foo/role.yaml",
+								Failure: &junitFailure{
+									Message: "name collision",
+									Type:    "KNV1029",
+									Text:    "name collision\ndetails",
+								},
+							}},
+						},
+					},
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Error(diff)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := output.print(out, tc.format); err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, out.Bytes())
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{outputText, flags.OutputJSON, outputSARIF, outputJUnit} {
+		if err := validateOutputFormat(format); err != nil {
+			t.Errorf("validateOutputFormat(%q) = %v, want nil", format, err)
+		}
+	}
+	if err := validateOutputFormat(flags.OutputYAML); err == nil {
+		t.Errorf("validateOutputFormat(%q) = nil, want error", flags.OutputYAML)
+	}
+}
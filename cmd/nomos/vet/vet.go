@@ -16,16 +16,22 @@ package vet
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"kpt.dev/configsync/cmd/nomos/flags"
 	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/vet"
 )
 
 var (
 	namespaceValue string
 	keepOutput     bool
 	outPath        string
+	outputFormat   string
+
+	schemaValidation  bool
+	kubernetesVersion string
 )
 
 func init() {
@@ -45,6 +51,21 @@ func init() {
 
 	Cmd.Flags().StringVar(&outPath, "output", flags.DefaultHydrationOutput,
 		`Location of the hydrated output`)
+
+	Cmd.Flags().BoolVar(&schemaValidation, "schema-validation", false,
+		`If enabled, validate the objects against the bundled OpenAPI schemas of --kubernetes-version `+
+			`and the schemas of the CustomResourceDefinitions in the repository, without contacting the API server. `+
+			`Useful together with --no-api-server-check.`)
+
+	Cmd.Flags().StringVar(&kubernetesVersion, "kubernetes-version", vet.DefaultKubernetesVersion,
+		fmt.Sprintf("Kubernetes version of the OpenAPI schemas used by --schema-validation. "+
+			"The schemas of Kubernetes %s are bundled.",
+			strings.Join(vet.SupportedKubernetesVersions(), ", ")))
+
+	Cmd.Flags().StringVar(&outputFormat, "output-format", outputText,
+		fmt.Sprintf(`Output format of the validation errors. Accepts %q, %q, %q and %q. `+
+			`Structured formats are printed to STDOUT, e.g. for annotating pull requests in CI.`,
+			outputText, flags.OutputJSON, outputSARIF, outputJUnit))
 }
 
 // Cmd is the Cobra object representing the nomos vet command.
@@ -58,12 +79,13 @@ returns a non-zero error code if any issues are found.
 `,
 	Example: `  nomos vet
   nomos vet --path=my/directory
-  nomos vet --path=/path/to/my/directory`,
+  nomos vet --path=/path/to/my/directory
+  nomos vet --output-format=sarif > nomos-vet.sarif`,
 	Args: cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Don't show usage on error, as argument validation passed.
 		cmd.SilenceUsage = true
 
-		return runVet(cmd.Context(), namespaceValue, filesystem.SourceFormat(flags.SourceFormat), flags.APIServerTimeout)
+		return runVet(cmd.Context(), namespaceValue, filesystem.SourceFormat(flags.SourceFormat), flags.APIServerTimeout, outputFormat)
 	},
 }
@@ -32,8 +32,10 @@ import (
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/parse"
+	"kpt.dev/configsync/pkg/policy"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/vet"
 )
 
 // vet runs nomos vet with the specified options.
@@ -48,7 +50,12 @@ import (
 // allClusters is whether we are implicitly vetting every cluster.
 // clusters is the set of clusters we are checking.
 //   Only used if allClusters is false.
-func runVet(ctx context.Context, namespace string, sourceFormat filesystem.SourceFormat, apiServerTimeout time.Duration) error {
+// outputFormat is whether the errors are printed as text, or as json, sarif
+//   or junit to the standard output.
+func runVet(ctx context.Context, namespace string, sourceFormat filesystem.SourceFormat, apiServerTimeout time.Duration, outputFormat string) error {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
 	if sourceFormat == "" {
 		if namespace == "" {
 			// Default to hierarchical if --namespace is not provided.
@@ -107,6 +114,17 @@ func runVet(ctx context.Context, namespace string, sourceFormat filesystem.Sourc
 		return fmt.Errorf("unknown %s value %q", reconcilermanager.SourceFormat, sourceFormat)
 	}
 
+	if schemaValidation {
+		visitor, err := vet.SchemaVisitor(kubernetesVersion)
+		if err != nil {
+			return err
+		}
+		options.Visitors = append(options.Visitors, visitor)
+	}
+	// Only the policy rules declared in the repository apply, since vet may
+	// not have access to the cluster.
+	options.Visitors = append(options.Visitors, policy.Visitor(nil))
+
 	filePaths := reader.FilePaths{
 		RootDir:   rootDir,
 		PolicyDir: cmpath.RelativeOS(rootDir.OSPath()),
@@ -116,6 +134,7 @@ func runVet(ctx context.Context, namespace string, sourceFormat filesystem.Sourc
 	// Track per-cluster vet errors.
 	var allObjects []ast.FileObject
 	var vetErrs []string
+	output := vetOutput{}
 	numClusters := 0
 	hydrate.ForEachCluster(parser, options, sourceFormat, filePaths, func(clusterName string, fileObjects []ast.FileObject, err status.MultiError) {
 		clusterEnabled := flags.AllClusters()
@@ -128,11 +147,13 @@ func runVet(ctx context.Context, namespace string, sourceFormat filesystem.Sourc
 			return
 		}
 		numClusters++
+		if clusterName == "" {
+			clusterName = nomosparse.UnregisteredCluster
+		}
+		output.Clusters = append(output.Clusters, clusterName)
 
 		if err != nil {
-			if clusterName == "" {
-				clusterName = nomosparse.UnregisteredCluster
-			}
+			output.Results = append(output.Results, vetResults(rootDir, clusterName, err)...)
 			vetErrs = append(vetErrs, clusterErrors{
 				name:       clusterName,
 				MultiError: err,
@@ -150,6 +171,15 @@ func runVet(ctx context.Context, namespace string, sourceFormat filesystem.Sourc
 			_ = util.PrintErr(err)
 		}
 	}
+	if outputFormat != outputText {
+		if err := output.print(os.Stdout, outputFormat); err != nil {
+			return err
+		}
+		if len(output.Results) > 0 {
+			return errors.Errorf("found %d validation issues", len(output.Results))
+		}
+		return nil
+	}
 	if len(vetErrs) > 0 {
 		return errors.New(strings.Join(vetErrs, "\n\n"))
 	}
@@ -158,6 +188,10 @@ func runVet(ctx context.Context, namespace string, sourceFormat filesystem.Sourc
 	return nil
 }
 
+// defaultCluster is the name ForEachCluster uses when the repository
+// declares no clusters.
+const defaultCluster = "defaultcluster"
+
 // clusterErrors is the set of vet errors for a specific Cluster.
 type clusterErrors struct {
 	name string
@@ -165,7 +199,7 @@ type clusterErrors struct {
 }
 
 func (e clusterErrors) Error() string {
-	if e.name == "defaultcluster" {
+	if e.name == defaultCluster {
 		return e.MultiError.Error()
 	}
 	return fmt.Sprintf("errors for cluster %q:\n%v\n", e.name, e.MultiError.Error())
@@ -23,6 +23,7 @@ import (
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	ft "kpt.dev/configsync/pkg/importer/filesystem/filesystemtest"
+	"kpt.dev/configsync/pkg/vet"
 )
 
 func resetFlags() {
@@ -38,6 +39,9 @@ func resetFlags() {
 	keepOutput = false
 	outPath = flags.DefaultHydrationOutput
 	flags.OutputFormat = flags.OutputYAML
+	outputFormat = outputText
+	schemaValidation = false
+	kubernetesVersion = vet.DefaultKubernetesVersion
 }
 
 var examplesDir = cmpath.RelativeSlash("../../../examples")
@@ -40,6 +40,8 @@ import (
 	"kpt.dev/configsync/pkg/kinds"
 	csmetadata "kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/parse"
+	"kpt.dev/configsync/pkg/policy"
+	"kpt.dev/configsync/pkg/reposyncpolicy"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/syncer/client"
 	"kpt.dev/configsync/pkg/syncer/reconcile"
@@ -319,6 +321,17 @@ func Generate() AllExamples {
 	// 1068
 	result.add(status.HydrationError(status.ActionableHydrationErrorCode, errors.New("user actionable rendering error")))
 
+	// 1069
+	result.add(policy.InvalidPolicyRuleError(fake.ConfigMapObject(), "default/policy/limits",
+		errors.New("message must be set")))
+
+	// 1070
+	result.add(vet.SchemaValidationError(fake.Role(),
+		[]string{`Role.rules[0]: unknown field "verb" in io.k8s.api.rbac.v1.PolicyRule`}))
+
+	// 1071
+	result.add(reposyncpolicy.ViolationError("restricted", "kind Role.rbac.authorization.k8s.io is denied", fake.Role()))
+
 	// 2001
 	result.add(status.PathWrapError(errors.New("error creating directory"), "namespaces/foo"))
 
@@ -332,7 +345,7 @@ func Generate() AllExamples {
 	result.add(status.SourceError.Sprint("unable to connect to Git repository").Build())
 
 	// 2005
-	result.add(reconcile.FightWarning(9.5, fake.NamespaceObject("gatekeeper-system")))
+	result.add(reconcile.FightWarning(9.5, fake.NamespaceObject("gatekeeper-system"), "kubectl-edit", []string{".metadata.labels.team"}))
 
 	// 2006
 	result.add(status.EmptySourceError(10, "namespaces"))
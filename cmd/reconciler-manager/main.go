@@ -33,6 +33,7 @@ import (
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -48,6 +49,9 @@ var (
 		controllers.PollingPeriod(reconcilermanager.HydrationPollingPeriod, configsync.DefaultHydrationPollingPeriod),
 		"Period of time between checking the filesystem for source updates to render.")
 
+	tracingEndpoint = flag.String("tracing-endpoint", os.Getenv(reconcilermanager.TracingEndpointKey),
+		"Address of the OC Agent compatible endpoint the reconcilers export trace spans to. Tracing is disabled if empty.")
+
 	setupLog = ctrl.Log.WithName("setup")
 )
 
@@ -60,7 +64,7 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
-	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the Prometheus metrics endpoint binds to. Set to 0 to disable the endpoint.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -70,7 +74,8 @@ func main() {
 	ctrl.SetLogger(klogr.New())
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: core.Scheme,
+		Scheme:             core.Scheme,
+		MetricsBindAddress: metricsAddr,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -83,7 +88,7 @@ func main() {
 	}
 	watchFleetMembership := fleetMembershipCRDExists(dynamicClient, mgr.GetRESTMapper())
 
-	repoSync := controllers.NewRepoSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, mgr.GetClient(), dynamicClient,
+	repoSync := controllers.NewRepoSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, *tracingEndpoint, mgr.GetClient(), dynamicClient,
 		ctrl.Log.WithName("controllers").WithName(configsync.RepoSyncKind),
 		mgr.GetScheme())
 	if err := repoSync.SetupWithManager(mgr, watchFleetMembership); err != nil {
@@ -91,7 +96,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	rootSync := controllers.NewRootSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, mgr.GetClient(), dynamicClient,
+	rootSync := controllers.NewRootSyncReconciler(*clusterName, *reconcilerPollingPeriod, *hydrationPollingPeriod, *tracingEndpoint, mgr.GetClient(), dynamicClient,
 		ctrl.Log.WithName("controllers").WithName(configsync.RootSyncKind),
 		mgr.GetScheme())
 	if err := rootSync.SetupWithManager(mgr, watchFleetMembership); err != nil {
@@ -107,11 +112,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	notification := controllers.NewNotificationReconciler(mgr.GetClient(),
+		ctrl.Log.WithName("controllers").WithName(configsync.NotificationKind),
+		mgr.GetScheme())
+	if err := notification.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", configsync.NotificationKind)
+		os.Exit(1)
+	}
+
 	// Register the OpenCensus views
 	if err := metrics.RegisterReconcilerManagerMetricsViews(); err != nil {
 		setupLog.Error(err, "failed to register OpenCensus views")
 	}
 
+	// Expose the OpenCensus views on the Prometheus metrics endpoint
+	if err := ctrlmetrics.Registry.Register(metrics.NewPrometheusCollector(metrics.ReconcilerManagerViews...)); err != nil {
+		setupLog.Error(err, "failed to register the Prometheus collector")
+		os.Exit(1)
+	}
+
 	// Register the OC Agent exporter
 	oce, err := metrics.RegisterOCAgentExporter(reconcilermanager.ManagerName)
 	if err != nil {
@@ -31,9 +31,13 @@ import (
 	"kpt.dev/configsync/pkg/reconciler"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/reconcilermanager/controllers"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
+	"kpt.dev/configsync/pkg/util"
 	"kpt.dev/configsync/pkg/util/log"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
@@ -82,12 +86,32 @@ var (
 	// Root-Repo-only flags. If set for a Namespace-scoped Reconciler, causes the Reconciler to fail immediately.
 	sourceFormat = flag.String(flags.sourceFormat, os.Getenv(filesystem.SourceFormatKey),
 		"The format of the repository.")
+	shardIndex = flag.Int("shard-index", util.EnvInt(reconcilermanager.ShardIndexKey, 0),
+		"The index of the shard of the RootSync objects handled by the reconciler.")
+	shardCount = flag.Int("shard-count", util.EnvInt(reconcilermanager.ShardCountKey, 1),
+		"The number of shards the RootSync objects are split across.")
+	shardKey = flag.String("shard-key", os.Getenv(reconcilermanager.ShardKeyKey),
+		"The key which the RootSync objects are assigned to shards by, either namespace or groupKind.")
+
+	// Namespace-Repo-only flags. Ignored by a Root Reconciler.
+	impersonateServiceAccount = flag.String("impersonate-service-account", os.Getenv(reconcilermanager.ImpersonateServiceAccountKey),
+		"The name of the ServiceAccount in the namespace of the RepoSync to impersonate when managing the objects in the source of truth.")
+
+	// Applier flag, what to do with the objects which already exist on the cluster
+	adoptionPolicy = flag.String("adoption-policy", os.Getenv(reconcilermanager.AdoptionPolicyKey),
+		"What the applier does with the objects which already exist on the cluster but are not in its inventory: adopt, adopt-if-unmanaged or fail.")
 	// Applier flag, Make the reconcile/prune timeout configurable
 	reconcileTimeout = flag.String(flags.reconcileTimeout, os.Getenv(reconcilermanager.ReconcileTimeout), "The timeout of applier reconcile and prune tasks")
 	// Enable the applier to inject actuation status data into the ResourceGroup object
 	statusMode = flag.String(flags.statusMode, os.Getenv(reconcilermanager.StatusMode),
 		"When the value is enabled or empty, the applier injects actuation status data into the ResourceGroup object")
 
+	tracingEndpoint = flag.String("tracing-endpoint", os.Getenv(reconcilermanager.TracingEndpointKey),
+		"Address of the OC Agent compatible endpoint to export trace spans to. Tracing is disabled if empty.")
+
+	metricsAddr = flag.String("metrics-addr", ":8080",
+		"The address the Prometheus metrics endpoint binds to. Set to 0 to disable the endpoint.")
+
 	apiServerTimeout = flag.String("api-server-timeout", os.Getenv(reconcilermanager.APIServerTimeout), "The client-side timeout for requests to the API server")
 
 	debug = flag.Bool("debug", false,
@@ -127,6 +151,11 @@ func main() {
 		klog.Fatalf("Failed to register OpenCensus views: %v", err)
 	}
 
+	// Expose the OpenCensus views on the Prometheus metrics endpoint
+	if err := ctrlmetrics.Registry.Register(ocmetrics.NewPrometheusCollector(ocmetrics.ReconcilerViews...)); err != nil {
+		klog.Fatalf("Failed to register the Prometheus collector: %v", err)
+	}
+
 	// Register the OC Agent exporter
 	oce, err := ocmetrics.RegisterOCAgentExporter(reconcilermanager.Reconciler)
 	if err != nil {
@@ -139,6 +168,19 @@ func main() {
 		}
 	}()
 
+	// Register the OC Agent trace exporter
+	tracingExporter, err := tracing.RegisterOCAgentExporter(reconcilermanager.Reconciler, *tracingEndpoint)
+	if err != nil {
+		klog.Fatalf("Failed to register the OC Agent trace exporter: %v", err)
+	}
+	if tracingExporter != nil {
+		defer func() {
+			if err := tracingExporter.Stop(); err != nil {
+				klog.Errorf("Unable to stop the OC Agent trace exporter: %v", err)
+			}
+		}()
+	}
+
 	absRepoRoot, err := cmpath.AbsoluteOS(*repoRootDir)
 	if err != nil {
 		klog.Fatalf("%s must be an absolute path: %v", flags.repoRootDir, err)
@@ -182,7 +224,9 @@ func main() {
 		ReconcilerName:          *reconcilerName,
 		StatusMode:              *statusMode,
 		ReconcileTimeout:        *reconcileTimeout,
+		AdoptionPolicy:          v1beta1.AdoptionPolicy(*adoptionPolicy),
 		APIServerTimeout:        *apiServerTimeout,
+		MetricsAddr:             *metricsAddr,
 	}
 
 	if declared.Scope(*scope) == declared.RootReconciler {
@@ -192,12 +236,19 @@ func main() {
 			format = filesystem.SourceFormatHierarchy
 		}
 
+		s, err := shard.New(*shardIndex, *shardCount, v1beta1.ShardingKey(*shardKey))
+		if err != nil {
+			klog.Fatalf("Invalid shard: %v", err)
+		}
+
 		klog.Info("Starting reconciler for: root")
 		opts.RootOptions = &reconciler.RootOptions{
 			SourceFormat: format,
+			Shard:        s,
 		}
 	} else {
 		klog.Infof("Starting reconciler for: %s", *scope)
+		opts.ImpersonateServiceAccount = *impersonateServiceAccount
 
 		if *sourceFormat != "" {
 			klog.Fatalf("Flag %s and Environment variable%q must not be passed to a Namespace reconciler",
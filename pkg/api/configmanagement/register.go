@@ -30,6 +30,12 @@ const (
 	// ControllerNamespace is the Namespace used for Nomos controllers
 	ControllerNamespace = "config-management-system"
 
+	// ClusterLabelsConfigMapName is the name of the ConfigMap in the
+	// ControllerNamespace whose data holds the labels of the current cluster.
+	// ClusterSelectors are evaluated against these labels in addition to the
+	// labels of the matching Cluster object declared in the repo.
+	ClusterLabelsConfigMapName = "cluster-labels"
+
 	// OperatorKind is the Kind of the Operator config object.
 	OperatorKind = "ConfigManagement"
 
@@ -268,8 +268,25 @@ type NamespaceSelectorSpec struct {
 	// This field is NOT optional and follows standard label selector semantics. An empty selector
 	// matches all namespaces.
 	Selector metav1.LabelSelector `json:"selector"`
+
+	// Mode specifies which namespaces the selector is evaluated against.
+	// "static" (the default) only selects Namespaces declared in the repo.
+	// "dynamic" also selects Namespaces that exist on the cluster, and objects
+	// are re-synced when the labels of those Namespaces change. The dynamic
+	// mode is only supported by the root reconciler in unstructured format.
+	// +optional
+	Mode string `json:"mode,omitempty"`
 }
 
+const (
+	// NSSelectorStaticMode indicates the NamespaceSelector only selects
+	// Namespaces declared in the repo.
+	NSSelectorStaticMode = "static"
+	// NSSelectorDynamicMode indicates the NamespaceSelector selects both
+	// declared Namespaces and Namespaces on the cluster.
+	NSSelectorDynamicMode = "dynamic"
+)
+
 // +kubebuilder:object:root=true
 
 // NamespaceSelectorList holds a list of NamespaceSelector resources.
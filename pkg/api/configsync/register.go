@@ -40,6 +40,12 @@ const (
 	RepoSyncKind = "RepoSync"
 	// RootSyncKind is the kind of the RepoSync resource.
 	RootSyncKind = "RootSync"
+	// NotificationKind is the kind of the Notification resource.
+	NotificationKind = "Notification"
+	// RepoSyncPolicyKind is the kind of the RepoSyncPolicy resource.
+	RepoSyncPolicyKind = "RepoSyncPolicy"
+	// DriftExemptionKind is the kind of the DriftExemption resource.
+	DriftExemptionKind = "DriftExemption"
 )
 
 const (
@@ -102,4 +108,7 @@ const (
 	// AuthGCPServiceAccount indicates using a GCP service account to authenticate to
 	// Git or OCI or Helm, when GKE Workload Identity or Fleet Workload Identity is enabled.
 	AuthGCPServiceAccount AuthType = "gcpserviceaccount"
+	// AuthAccessKey indicates using an access key ID and a secret access key to
+	// authenticate to an S3-compatible bucket. It only applies to buckets.
+	AuthAccessKey AuthType = "accesskey"
 )
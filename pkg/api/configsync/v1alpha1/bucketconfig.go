@@ -0,0 +1,74 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+)
+
+// Bucket contains configuration specific to importing resources from a bucket
+// of an S3-compatible object storage, such as Amazon S3 or MinIO.
+type Bucket struct {
+	// endpoint is the URL of the S3-compatible API, for example
+	// `https://s3.us-east-1.amazonaws.com` or `http://minio.minio:9000`.
+	// Required.
+	Endpoint string `json:"endpoint"`
+
+	// bucket is the name of the bucket. Required.
+	Bucket string `json:"bucket"`
+
+	// prefix is the directory of the bucket holding the source, with or
+	// without a trailing slash. It is stripped from the keys to get the paths
+	// of the files of the source. Default: all the objects of the bucket.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// region is the region of the bucket used to sign the requests.
+	// Default: us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// dir is the absolute path of the directory that contains
+	// the local resources.  Default: the root directory of the source.
+	// +optional
+	Dir string `json:"dir,omitempty"`
+
+	// period is the time duration between consecutive syncs. Default: 15s.
+	// The objects are only downloaded again if the ETag of any of them, or the
+	// set of objects, changed since the last sync.
+	// Note to developers that customers specify this value using
+	// string (https://golang.org/pkg/time/#Duration.String) like "3s"
+	// in their Custom Resource YAML. However, time.Duration is at a nanosecond
+	// granularity, and it is easy to introduce a bug where it looks like the
+	// code is dealing with seconds but its actually nanoseconds (or vice versa).
+	// +optional
+	Period metav1.Duration `json:"period,omitempty"`
+
+	// auth specifies the type to authenticate to the bucket.
+	// Must be one of accesskey or none.
+	// The validation of this is case-sensitive. Required.
+	// +kubebuilder:validation:Enum=none;accesskey
+	Auth configsync.AuthType `json:"auth"`
+
+	// secretRef holds the authentication secret for accessing the bucket,
+	// with the access key ID in the `access_key_id` key and the secret access
+	// key in the `secret_access_key` key. The session token of temporary
+	// security credentials is read from the optional `session_token` key.
+	// Note: The field is used when spec.bucket.auth: accesskey.
+	// +nullable
+	// +optional
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Local contains configuration specific to importing resources from a source
+// stored on the cluster, for clusters without access to a Git repository, an
+// OCI registry or a Helm repository.
+//
+// Exactly one of configMapRef, secretRef and persistentVolumeClaimRef must be
+// specified.
+type Local struct {
+	// configMapRef specifies the key of a ConfigMap holding a tarball of the
+	// source, in binaryData or data. The ConfigMap must be in the namespace of
+	// the RootSync or RepoSync.
+	// +optional
+	ConfigMapRef *LocalObjectKeyReference `json:"configMapRef,omitempty"`
+
+	// secretRef specifies the key of a Secret holding a tarball of the source.
+	// The Secret must be in the namespace of the RootSync or RepoSync.
+	// +optional
+	SecretRef *LocalObjectKeyReference `json:"secretRef,omitempty"`
+
+	// persistentVolumeClaimRef specifies a PersistentVolumeClaim holding the
+	// source as a directory tree. The PersistentVolumeClaim is mounted
+	// read-only into the reconciler Pod, so it must be in the
+	// config-management-system namespace. Only supported by RootSyncs.
+	// +optional
+	PersistentVolumeClaimRef *PersistentVolumeClaimReference `json:"persistentVolumeClaimRef,omitempty"`
+
+	// dir is the absolute path of the directory that contains
+	// the local resources.  Default: the root directory of the source.
+	// +optional
+	Dir string `json:"dir,omitempty"`
+
+	// period is the time duration between consecutive syncs. Default: 15s.
+	// Note to developers that customers specify this value using
+	// string (https://golang.org/pkg/time/#Duration.String) like "3s"
+	// in their Custom Resource YAML. However, time.Duration is at a nanosecond
+	// granularity, and it is easy to introduce a bug where it looks like the
+	// code is dealing with seconds but its actually nanoseconds (or vice versa).
+	// +optional
+	Period metav1.Duration `json:"period,omitempty"`
+}
+
+// LocalObjectKeyReference selects a key of a ConfigMap or Secret.
+type LocalObjectKeyReference struct {
+	// name is the name of the ConfigMap or Secret.
+	Name string `json:"name"`
+
+	// key is the key holding the tarball. The tarball may be gzip-compressed.
+	Key string `json:"key"`
+}
+
+// PersistentVolumeClaimReference specifies a PersistentVolumeClaim.
+type PersistentVolumeClaimReference struct {
+	// name is the name of the PersistentVolumeClaim.
+	Name string `json:"name"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+// AdoptionPolicy determines what the reconciler does with the objects in the
+// source of truth which already exist on the cluster, but are not in the
+// inventory of the RootSync or RepoSync.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAdopt takes over pre-existing objects, even if they are
+	// managed by another RootSync or RepoSync, and annotates them with the
+	// inventory of the RootSync or RepoSync.
+	AdoptionPolicyAdopt = AdoptionPolicy("adopt")
+	// AdoptionPolicyAdoptIfUnmanaged takes over pre-existing objects only if
+	// they are not managed by another RootSync or RepoSync.
+	AdoptionPolicyAdoptIfUnmanaged = AdoptionPolicy("adopt-if-unmanaged")
+	// AdoptionPolicyFail takes over no pre-existing objects, and reports a
+	// management conflict for each of them without modifying it.
+	AdoptionPolicyFail = AdoptionPolicy("fail")
+)
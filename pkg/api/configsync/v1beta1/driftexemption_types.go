@@ -0,0 +1,118 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".spec.expires"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DriftExemption is the Schema for the driftexemptions API.
+//
+// A DriftExemption is a break-glass exemption from drift prevention. Until it
+// expires, the admission webhook allows the selected users, groups and
+// ServiceAccounts to modify the selected managed objects, and the reconcilers
+// do not revert the drift of the selected objects. Every use is recorded as
+// an Event on the DriftExemption.
+type DriftExemption struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DriftExemptionSpec `json:"spec"`
+}
+
+// DriftExemptionSpec defines who may modify which managed objects, and until
+// when.
+type DriftExemptionSpec struct {
+	// reason describes why the exemption was granted, for example an
+	// incident number.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// users specifies the names of the exempted users.
+	// At least one of users, groups and serviceAccounts must be specified.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// groups specifies the names of the exempted groups.
+	// At least one of users, groups and serviceAccounts must be specified.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// serviceAccounts specifies the exempted ServiceAccounts.
+	// At least one of users, groups and serviceAccounts must be specified.
+	// +optional
+	ServiceAccounts []DriftExemptionServiceAccount `json:"serviceAccounts,omitempty"`
+
+	// objects selects the managed objects which may be modified.
+	// +kubebuilder:validation:MinItems=1
+	Objects []DriftExemptionSelector `json:"objects"`
+
+	// expires is when the exemption stops being honored, formatted as an
+	// RFC 3339 date and time, for example `2022-06-01T18:00:00Z`.
+	// +kubebuilder:validation:Format=date-time
+	Expires metav1.Time `json:"expires"`
+}
+
+// DriftExemptionServiceAccount identifies a ServiceAccount.
+type DriftExemptionServiceAccount struct {
+	// namespace is the namespace of the ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// name is the name of the ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// DriftExemptionSelector selects managed objects. The fields not specified
+// match every object.
+type DriftExemptionSelector struct {
+	// group is the API group of the objects.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind is the kind of the objects.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// namespace is the namespace of the objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the objects.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// labelSelector selects the objects by their labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DriftExemptionList contains a list of DriftExemption
+type DriftExemptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DriftExemption `json:"items"`
+}
@@ -15,6 +15,9 @@
 package v1beta1
 
 import (
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/client/restconfig"
@@ -72,3 +75,48 @@ func GetAPIServerTimeout(d *metav1.Duration) string {
 	}
 	return d.Duration.String()
 }
+
+// GetShards returns the number of shards of the RootSync, defaulting to 1 if
+// sharding is not configured.
+func (rs *RootSyncSpec) GetShards() int {
+	if rs.Sharding == nil || rs.Sharding.Shards < 1 {
+		return 1
+	}
+	return rs.Sharding.Shards
+}
+
+// GetShardingKey returns the sharding key of the RootSync, defaulting to
+// namespace if empty.
+func (rs *RootSyncSpec) GetShardingKey() ShardingKey {
+	if rs.Sharding == nil || rs.Sharding.Key == "" {
+		return ShardByNamespace
+	}
+	return rs.Sharding.Key
+}
+
+// GetLocalSource returns the location of the local source of a RootSync or
+// RepoSync in the namespace, which is reported in place of a repository URL.
+// e.g. configmap://config-management-system/my-source/source.tar.gz
+func GetLocalSource(local *Local, namespace string) string {
+	switch {
+	case local == nil:
+		return ""
+	case local.ConfigMapRef != nil:
+		return fmt.Sprintf("configmap://%s/%s/%s", namespace, local.ConfigMapRef.Name, local.ConfigMapRef.Key)
+	case local.SecretRef != nil:
+		return fmt.Sprintf("secret://%s/%s/%s", namespace, local.SecretRef.Name, local.SecretRef.Key)
+	case local.PersistentVolumeClaimRef != nil:
+		return fmt.Sprintf("persistentvolumeclaim://%s/%s", namespace, local.PersistentVolumeClaimRef.Name)
+	}
+	return ""
+}
+
+// GetBucketSource returns the location of the objects of a bucket source,
+// which is reported in place of a repository URL.
+// e.g. http://minio.minio:9000/my-bucket/my-prefix/
+func GetBucketSource(bucket *Bucket) string {
+	if bucket == nil {
+		return ""
+	}
+	return strings.TrimSuffix(bucket.Endpoint, "/") + "/" + bucket.Bucket + "/" + strings.TrimPrefix(bucket.Prefix, "/")
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.endpoint.type"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Notification is the Schema for the notifications API.
+//
+// A Notification posts a message to an HTTP endpoint when a RootSync or
+// RepoSync in the same namespace transitions between sync states.
+type Notification struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec NotificationSpec `json:"spec,omitempty"`
+	// +optional
+	Status NotificationStatus `json:"status,omitempty"`
+}
+
+// NotificationSpec defines the desired state of Notification
+type NotificationSpec struct {
+	// syncKind specifies the kind of the RootSyncs or RepoSyncs to watch.
+	//
+	// Must be one of RootSync, RepoSync. Optional. Both kinds are watched if
+	// not specified.
+	// +kubebuilder:validation:Pattern=^(RootSync|RepoSync|)$
+	// +optional
+	SyncKind string `json:"syncKind,omitempty"`
+
+	// syncNames specifies the names of the RootSyncs or RepoSyncs to watch.
+	// Optional. All the RootSyncs and RepoSyncs in the namespace of the
+	// Notification are watched if not specified.
+	// +optional
+	SyncNames []string `json:"syncNames,omitempty"`
+
+	// triggers specifies the sync state transitions to notify about.
+	//
+	// Each must be one of Stalled, SyncFailed, Recovered. Optional. All the
+	// transitions are notified if not specified.
+	// +optional
+	Triggers []NotificationTrigger `json:"triggers,omitempty"`
+
+	// endpoint specifies where to post the notifications.
+	Endpoint NotificationEndpoint `json:"endpoint"`
+
+	// template is a Go text/template used to render the body of the
+	// notifications. Optional. The default template of the endpoint type is
+	// used if not specified.
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// NotificationTrigger is an enum of the sync state transitions which can
+// trigger a notification.
+// +kubebuilder:validation:Enum=Stalled;SyncFailed;Recovered
+type NotificationTrigger string
+
+const (
+	// NotificationStalled triggers a notification when a RootSync or RepoSync
+	// becomes Stalled.
+	NotificationStalled NotificationTrigger = "Stalled"
+	// NotificationSyncFailed triggers a notification when a RootSync or
+	// RepoSync starts failing to sync.
+	NotificationSyncFailed NotificationTrigger = "SyncFailed"
+	// NotificationRecovered triggers a notification when a Stalled or failing
+	// RootSync or RepoSync syncs successfully again.
+	NotificationRecovered NotificationTrigger = "Recovered"
+)
+
+// NotificationEndpoint specifies an HTTP endpoint to post notifications to.
+type NotificationEndpoint struct {
+	// type specifies the format of the default payload.
+	//
+	// Must be one of slack, teams, generic. Optional. Set to generic if not
+	// specified.
+	// +kubebuilder:validation:Pattern=^(slack|teams|generic|)$
+	// +kubebuilder:default:=generic
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// secretRef is the Secret in the namespace of the Notification holding
+	// the endpoint URL in the `url` key, and optionally a bearer token in the
+	// `token` key.
+	SecretRef SecretReference `json:"secretRef"`
+}
+
+// NotificationStatus defines the observed state of Notification
+type NotificationStatus struct {
+	// observedGeneration is the most recent generation observed for the
+	// Notification.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// syncs records the last sync state notified about for each watched
+	// RootSync or RepoSync. It is used to only notify on transitions.
+	// +optional
+	Syncs []NotificationSyncStatus `json:"syncs,omitempty"`
+}
+
+// NotificationSyncStatus records the last sync state of a RootSync or RepoSync
+// the Notification knows about.
+type NotificationSyncStatus struct {
+	// kind is the kind of the RootSync or RepoSync.
+	Kind string `json:"kind"`
+	// name is the name of the RootSync or RepoSync.
+	Name string `json:"name"`
+	// state is the last sync state observed, one of Synced, Stalled,
+	// SyncFailed.
+	State string `json:"state"`
+	// commit is the commit of the last sync state observed.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+	// lastTransitionTime is the last time the state changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// lastDeliveryError is the error of the last failed attempt to post a
+	// notification about this RootSync or RepoSync.
+	// +optional
+	LastDeliveryError string `json:"lastDeliveryError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationList contains a list of Notification
+type NotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Notification `json:"items"`
+}
@@ -38,8 +38,14 @@ var (
 
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&DriftExemption{},
+		&DriftExemptionList{},
+		&Notification{},
+		&NotificationList{},
 		&RepoSync{},
 		&RepoSyncList{},
+		&RepoSyncPolicy{},
+		&RepoSyncPolicyList{},
 		&RootSync{},
 		&RootSyncList{},
 	)
@@ -55,8 +55,8 @@ type RepoSyncSpec struct {
 
 	// sourceType specifies the type of the source of truth.
 	//
-	// Must be one of git, oci, helm. Optional. Set to git if not specified.
-	// +kubebuilder:validation:Pattern=^(git|oci|helm)$
+	// Must be one of git, oci, helm, local, bucket. Optional. Set to git if not specified.
+	// +kubebuilder:validation:Pattern=^(git|oci|helm|local|bucket)$
 	// +kubebuilder:default:=git
 	// +optional
 	SourceType string `json:"sourceType,omitempty"`
@@ -73,10 +73,46 @@ type RepoSyncSpec struct {
 	// +optional
 	Helm *HelmRepoSync `json:"helm,omitempty"`
 
+	// local contains configuration specific to importing resources from a
+	// ConfigMap, Secret or PersistentVolumeClaim on the cluster.
+	// +optional
+	Local *Local `json:"local,omitempty"`
+
+	// bucket contains configuration specific to importing resources from a
+	// bucket of an S3-compatible object storage.
+	// +optional
+	Bucket *Bucket `json:"bucket,omitempty"`
+
 	// override allows to override the settings for a namespace reconciler.
 	// +nullable
 	// +optional
 	Override *OverrideSpec `json:"override,omitempty"`
+
+	// serviceAccountName is the name of a ServiceAccount in the namespace of
+	// the RepoSync, which the reconciler impersonates to apply, prune and
+	// remediate the objects in the source of truth. The ServiceAccount must be
+	// granted the permissions to manage these objects.
+	//
+	// Optional. If not specified, the reconciler uses its own ServiceAccount,
+	// which is bound to the configsync.gke.io:ns-reconciler ClusterRole.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// adoptionPolicy determines what the reconciler does with the objects in
+	// the source of truth which already exist on the cluster, but are not
+	// managed by this RepoSync yet.
+	//
+	// Must be one of adopt, adopt-if-unmanaged, fail. adopt takes over the
+	// objects, even if another RootSync or RepoSync manages them.
+	// adopt-if-unmanaged only takes over the objects no other RootSync or
+	// RepoSync manages. fail reports a management conflict for each of them
+	// without modifying it, together with the list of objects adopt would take
+	// over, which previews the adoption when onboarding an existing cluster.
+	//
+	// Optional. Set to adopt-if-unmanaged if not specified.
+	// +kubebuilder:validation:Pattern=^(adopt|adopt-if-unmanaged|fail|)$
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
 }
 
 // RepoSyncStatus defines the observed state of a RepoSync.
@@ -0,0 +1,112 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// RepoSyncPolicy is the Schema for the reposyncpolicies API.
+//
+// A RepoSyncPolicy restricts what the RepoSyncs in the selected namespaces may
+// sync. Namespace reconcilers refuse to sync objects violating any policy
+// which applies to their namespace, and report the violations as errors in the
+// status of their RepoSync. The admission webhook denies the requests of
+// namespace reconcilers violating them.
+type RepoSyncPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec RepoSyncPolicySpec `json:"spec,omitempty"`
+}
+
+// RepoSyncPolicySpec defines the restrictions of a RepoSyncPolicy
+type RepoSyncPolicySpec struct {
+	// namespaces specifies the namespaces of the RepoSyncs the policy applies
+	// to. Optional. The policy applies to all the RepoSyncs if not specified.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// allowedKinds specifies the only kinds of objects the RepoSyncs may sync.
+	// Optional. All the kinds not denied are allowed if not specified.
+	// +optional
+	AllowedKinds []RepoSyncPolicyKind `json:"allowedKinds,omitempty"`
+
+	// deniedKinds specifies the kinds of objects the RepoSyncs may not sync.
+	// +optional
+	DeniedKinds []RepoSyncPolicyKind `json:"deniedKinds,omitempty"`
+
+	// maxObjects is the maximum number of objects each RepoSync may sync.
+	// Optional. The number of objects is not limited if not specified.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxObjects int64 `json:"maxObjects,omitempty"`
+
+	// forbiddenFields specifies the fields which the objects synced by the
+	// RepoSyncs may not set.
+	// +optional
+	ForbiddenFields []RepoSyncPolicyField `json:"forbiddenFields,omitempty"`
+
+	// allowedSources specifies the prefixes of the Git repositories, OCI
+	// images and Helm repositories the RepoSyncs may sync from, for example
+	// `https://github.com/acme/` or `us-docker.pkg.dev/acme/`. A source is
+	// allowed if it is equal to a prefix or under it, on a path segment
+	// boundary. The case of the scheme and host, trailing slashes and `.git`
+	// suffixes are ignored.
+	// Optional. All the sources are allowed if not specified.
+	// +optional
+	AllowedSources []string `json:"allowedSources,omitempty"`
+}
+
+// RepoSyncPolicyKind selects objects by group and kind.
+type RepoSyncPolicyKind struct {
+	// group is the API group of the objects. Optional. Selects the core group
+	// if not specified.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind is the kind of the objects, or `*` to select every kind of the
+	// group.
+	Kind string `json:"kind"`
+}
+
+// RepoSyncPolicyField specifies a field which objects may not set.
+type RepoSyncPolicyField struct {
+	// kinds specifies the kinds of objects the field is forbidden in.
+	// Optional. The field is forbidden in every object if not specified.
+	// +optional
+	Kinds []RepoSyncPolicyKind `json:"kinds,omitempty"`
+
+	// path is a JSONPath expression selecting the field, for example
+	// `$..hostNetwork`. An object sets the field if the expression selects a
+	// value other than null, false, zero or empty.
+	Path string `json:"path"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepoSyncPolicyList contains a list of RepoSyncPolicy
+type RepoSyncPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepoSyncPolicy `json:"items"`
+}
@@ -15,6 +15,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -66,6 +67,41 @@ type OverrideSpec struct {
 	// support pulling remote bases from public repositories.
 	// +optional
 	EnableShellInRendering *bool `json:"enableShellInRendering,omitempty"`
+
+	// nodeSelector allows one to override the node selector of a reconciler pod,
+	// so that it is only scheduled on the matching nodes.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// tolerations allows one to override the tolerations of a reconciler pod,
+	// so that it can be scheduled on tainted nodes.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// affinity allows one to override the node, pod and pod anti-affinity
+	// scheduling constraints of a reconciler pod.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// priorityClassName allows one to override the priority class of a reconciler pod.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// topologySpreadConstraints allows one to override how the reconciler pods
+	// are spread across topology domains.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// podAnnotations allows one to add annotations to a reconciler pod.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// podLabels allows one to add labels to a reconciler pod.
+	// The labels set by Config Sync take precedence.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
 }
 
 // ContainerResourcesSpec allows to override the resource requirements for a container
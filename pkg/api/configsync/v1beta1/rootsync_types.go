@@ -55,8 +55,8 @@ type RootSyncSpec struct {
 
 	// sourceType specifies the type of the source of truth.
 	//
-	// Must be one of git, oci, helm. Optional. Set to git if not specified.
-	// +kubebuilder:validation:Pattern=^(git|oci|helm)$
+	// Must be one of git, oci, helm, local, bucket. Optional. Set to git if not specified.
+	// +kubebuilder:validation:Pattern=^(git|oci|helm|local|bucket)$
 	// +kubebuilder:default:=git
 	// +optional
 	SourceType string `json:"sourceType,omitempty"`
@@ -73,10 +73,42 @@ type RootSyncSpec struct {
 	// +optional
 	Helm *HelmRootSync `json:"helm,omitempty"`
 
+	// local contains configuration specific to importing resources from a
+	// ConfigMap, Secret or PersistentVolumeClaim on the cluster.
+	// +optional
+	Local *Local `json:"local,omitempty"`
+
+	// bucket contains configuration specific to importing resources from a
+	// bucket of an S3-compatible object storage.
+	// +optional
+	Bucket *Bucket `json:"bucket,omitempty"`
+
 	// override allows to override the settings for a root reconciler.
 	// +nullable
 	// +optional
 	Override *OverrideSpec `json:"override,omitempty"`
+
+	// sharding splits the objects of the RootSync across multiple
+	// reconcilers, to sync very large sets of objects.
+	// +nullable
+	// +optional
+	Sharding *Sharding `json:"sharding,omitempty"`
+
+	// adoptionPolicy determines what the reconciler does with the objects in
+	// the source of truth which already exist on the cluster, but are not
+	// managed by this RootSync yet.
+	//
+	// Must be one of adopt, adopt-if-unmanaged, fail. adopt takes over the
+	// objects, even if another RootSync or RepoSync manages them.
+	// adopt-if-unmanaged only takes over the objects no other RootSync or
+	// RepoSync manages. fail reports a management conflict for each of them
+	// without modifying it, together with the list of objects adopt would take
+	// over, which previews the adoption when onboarding an existing cluster.
+	//
+	// Optional. Set to adopt if not specified.
+	// +kubebuilder:validation:Pattern=^(adopt|adopt-if-unmanaged|fail|)$
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
 }
 
 // RootSyncStatus defines the observed state of RootSync
@@ -87,6 +119,11 @@ type RootSyncStatus struct {
 	// current state.
 	// +optional
 	Conditions []RootSyncCondition `json:"conditions,omitempty"`
+
+	// shards reports the sync status of each shard of a sharded RootSync.
+	// The sync status of the RootSync aggregates them.
+	// +optional
+	Shards []ShardStatus `json:"shards,omitempty"`
 }
 
 // RootSyncConditionType is an enum of types of conditions for RootSyncs.
@@ -0,0 +1,86 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ShardingKey determines which shard of a RootSync an object is assigned to.
+type ShardingKey string
+
+const (
+	// ShardByNamespace assigns objects to shards by the hash of their
+	// namespace. Namespaces are assigned to the same shard as the objects in
+	// them, and all the other cluster-scoped objects to the same shard.
+	ShardByNamespace = ShardingKey("namespace")
+	// ShardByGroupKind assigns objects to shards by the hash of their group and
+	// kind.
+	ShardByGroupKind = ShardingKey("groupKind")
+)
+
+// Sharding splits the objects of a RootSync across multiple reconcilers.
+type Sharding struct {
+	// shards is the number of reconcilers the objects of the RootSync are
+	// split across. Each shard has its own reconciler Deployment, inventory
+	// ResourceGroup and remediator. Optional. Defaults to 1, which disables
+	// sharding.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	Shards int `json:"shards,omitempty"`
+
+	// key determines which shard an object is assigned to.
+	//
+	// Must be one of namespace, groupKind. Optional. Set to namespace if not
+	// specified.
+	// +kubebuilder:validation:Pattern=^(namespace|groupKind|)$
+	// +optional
+	Key ShardingKey `json:"key,omitempty"`
+}
+
+// ShardStatus describes the sync status of a shard of a RootSync.
+type ShardStatus struct {
+	// index of the shard, from 0 to shards-1.
+	Index int `json:"index"`
+
+	// reconciler is the name of the reconciler Deployment of the shard.
+	// +optional
+	Reconciler string `json:"reconciler,omitempty"`
+
+	// commit is the hash of the source of truth synced by the shard.
+	// +optional
+	Commit string `json:"commit,omitempty"`
+
+	// syncing is true while the shard is applying the commit.
+	// +optional
+	Syncing bool `json:"syncing,omitempty"`
+
+	// lastUpdate is the timestamp of when this status was last updated by the
+	// reconciler of the shard.
+	// +nullable
+	// +optional
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+
+	// errors is a list of any errors that occurred while applying the objects
+	// of the shard.
+	// +optional
+	Errors []ConfigSyncError `json:"errors,omitempty"`
+
+	// errorSummary summarizes the errors encountered while applying the objects
+	// of the shard.
+	// +optional
+	ErrorSummary *ErrorSummary `json:"errorSummary,omitempty"`
+}
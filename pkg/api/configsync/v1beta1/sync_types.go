@@ -261,4 +261,12 @@ const (
 
 	// HelmSource represents the source type is Helm repository.
 	HelmSource SourceType = "helm"
+
+	// LocalSource represents the source type is a ConfigMap, Secret or
+	// PersistentVolumeClaim on the cluster.
+	LocalSource SourceType = "local"
+
+	// BucketSource represents the source type is a bucket of an S3-compatible
+	// object storage.
+	BucketSource SourceType = "bucket"
 )
@@ -0,0 +1,178 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	syncevents "kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/metadata"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxReportedObjects is the maximum number of objects listed in the message
+// of an adoption Event, to keep it under the size limit of Events.
+const maxReportedObjects = 10
+
+// inventoryPolicy returns the inventory policy implementing the adoption
+// policy, or defaultPolicy if the adoption policy is not specified.
+func inventoryPolicy(p v1beta1.AdoptionPolicy, defaultPolicy inventory.Policy) (inventory.Policy, error) {
+	switch p {
+	case "":
+		return defaultPolicy, nil
+	case v1beta1.AdoptionPolicyAdopt:
+		return inventory.PolicyAdoptAll, nil
+	case v1beta1.AdoptionPolicyAdoptIfUnmanaged:
+		return inventory.PolicyAdoptIfNoInventory, nil
+	case v1beta1.AdoptionPolicyFail:
+		return inventory.PolicyMustMatch, nil
+	default:
+		return defaultPolicy, fmt.Errorf("invalid adoption policy %q, must be one of %s, %s, %s",
+			p, v1beta1.AdoptionPolicyAdopt, v1beta1.AdoptionPolicyAdoptIfUnmanaged, v1beta1.AdoptionPolicyFail)
+	}
+}
+
+// adoptionReport lists the objects which already exist on the cluster but are
+// not in the inventory yet.
+type adoptionReport struct {
+	// adopted are the objects the adoption policy lets the applier take over.
+	adopted []core.ID
+	// prevented are the objects the adoption policy prevents the applier from
+	// taking over, but the adopt policy would take over.
+	prevented []core.ID
+}
+
+// reportAdoption dry-runs the adoption of the objects before applying them,
+// and reports which pre-existing objects are adopted, or would be adopted with
+// the adopt policy, in the logs and as Events on the RSync object.
+//
+// The adoption is only reported once per commit, since the apply of a commit is
+// retried until it succeeds. Failing to build the report does not prevent
+// applying the objects.
+func (a *supervisor) reportAdoption(ctx context.Context, objs []client.Object) {
+	commit := commitOf(objs)
+	if commit != "" && commit == a.adoptionReportCommit {
+		return
+	}
+	report, err := a.dryRunAdoption(ctx, objs)
+	if err != nil {
+		klog.Warningf("Failed to dry-run the adoption of the pre-existing objects: %v", err)
+		return
+	}
+	a.adoptionReportCommit = commit
+	if len(report.adopted) > 0 {
+		klog.Infof("Adopting %d pre-existing objects with the adoption policy %s: %v", len(report.adopted), a.adoptionPolicy, report.adopted)
+		a.recorder.Eventf(ctx, corev1.EventTypeNormal, syncevents.ReasonAdopting,
+			"Adopting %d pre-existing objects with the adoption policy %s: %s", len(report.adopted), a.adoptionPolicy, idList(report.adopted))
+	}
+	if len(report.prevented) > 0 {
+		klog.Infof("The adoption policy %s prevents adopting %d pre-existing objects, which the adoption policy %s would adopt: %v",
+			a.adoptionPolicy, len(report.prevented), v1beta1.AdoptionPolicyAdopt, report.prevented)
+		a.recorder.Eventf(ctx, corev1.EventTypeWarning, syncevents.ReasonAdoptionDryRun,
+			"The adoption policy %s would adopt %d pre-existing objects: %s", v1beta1.AdoptionPolicyAdopt, len(report.prevented), idList(report.prevented))
+	}
+}
+
+// dryRunAdoption returns which of the objects the applier adopts into the
+// inventory, and which it is prevented from adopting, without modifying them.
+func (a *supervisor) dryRunAdoption(ctx context.Context, objs []client.Object) (adoptionReport, error) {
+	var report adoptionReport
+	inInventory, err := a.clientSet.InvClient.GetClusterObjs(a.inventory)
+	if err != nil {
+		return report, err
+	}
+	for _, obj := range objs {
+		id := ObjMetaFromObject(obj)
+		if inInventory.Contains(id) {
+			continue
+		}
+		liveObj, err := a.getLiveObject(ctx, id)
+		if err != nil {
+			return report, err
+		}
+		if liveObj == nil || inventory.IDMatch(a.inventory, liveObj) == inventory.Match {
+			continue
+		}
+		// CanApply only returns an error describing why the object cannot be
+		// adopted.
+		if ok, _ := inventory.CanApply(a.inventory, liveObj, a.policy); ok {
+			report.adopted = append(report.adopted, core.IDOf(obj))
+		} else {
+			report.prevented = append(report.prevented, core.IDOf(obj))
+		}
+	}
+	return report, nil
+}
+
+// commitOf returns the commit the objects are declared at, or "" if it is
+// unknown.
+func commitOf(objs []client.Object) string {
+	for _, obj := range objs {
+		if commit := core.GetAnnotation(obj, metadata.SyncTokenAnnotationKey); commit != "" {
+			return commit
+		}
+	}
+	return ""
+}
+
+// getLiveObject returns the object with the metadata from the cluster, or nil
+// if it does not exist.
+func (a *supervisor) getLiveObject(ctx context.Context, id object.ObjMetadata) (*unstructured.Unstructured, error) {
+	mapping, err := a.clientSet.Mapper.RESTMapping(id.GroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// The type of the object is declared in the same apply, so the
+			// object does not exist yet.
+			return nil, nil
+		}
+		return nil, err
+	}
+	u, err := a.clientSet.DynamicClient.Resource(mapping.Resource).
+		Namespace(id.Namespace).
+		Get(ctx, id.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// idList formats the IDs for an Event message, listing at most
+// maxReportedObjects of them.
+func idList(ids []core.ID) string {
+	var names []string
+	for i, id := range ids {
+		if i == maxReportedObjects {
+			names = append(names, fmt.Sprintf("and %d more", len(ids)-maxReportedObjects))
+			break
+		}
+		names = append(names, id.String())
+	}
+	return strings.Join(names, ", ")
+}
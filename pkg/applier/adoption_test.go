@@ -0,0 +1,199 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestInventoryPolicy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  v1beta1.AdoptionPolicy
+		want    inventory.Policy
+		wantErr bool
+	}{
+		{
+			name:   "default",
+			policy: "",
+			want:   inventory.PolicyAdoptIfNoInventory,
+		},
+		{
+			name:   "adopt",
+			policy: v1beta1.AdoptionPolicyAdopt,
+			want:   inventory.PolicyAdoptAll,
+		},
+		{
+			name:   "adopt-if-unmanaged",
+			policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged,
+			want:   inventory.PolicyAdoptIfNoInventory,
+		},
+		{
+			name:   "fail",
+			policy: v1beta1.AdoptionPolicyFail,
+			want:   inventory.PolicyMustMatch,
+		},
+		{
+			name:    "invalid",
+			policy:  "takeover",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := inventoryPolicy(tc.policy, inventory.PolicyAdoptIfNoInventory)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got inventoryPolicy(%q) = %v, want error", tc.policy, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got inventoryPolicy(%q) = %v, want %v", tc.policy, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDryRunAdoption(t *testing.T) {
+	invID := InventoryID("rs", "test-namespace")
+	newCM := configMap("new")
+	unmanagedCM := configMap("unmanaged")
+	otherCM := configMap("other", core.Annotation(inventory.OwningInventoryKey, "other-inventory"))
+	ownedCM := configMap("owned", core.Annotation(inventory.OwningInventoryKey, invID))
+	inventoryCM := configMap("in-inventory", core.Annotation(inventory.OwningInventoryKey, invID))
+	objs := []client.Object{newCM, unmanagedCM, otherCM, ownedCM, inventoryCM}
+
+	testCases := []struct {
+		name          string
+		policy        v1beta1.AdoptionPolicy
+		wantAdopted   []core.ID
+		wantPrevented []core.ID
+	}{
+		{
+			name:        "adopt",
+			policy:      v1beta1.AdoptionPolicyAdopt,
+			wantAdopted: []core.ID{core.IDOf(unmanagedCM), core.IDOf(otherCM)},
+		},
+		{
+			name:          "adopt-if-unmanaged",
+			policy:        v1beta1.AdoptionPolicyAdoptIfUnmanaged,
+			wantAdopted:   []core.ID{core.IDOf(unmanagedCM)},
+			wantPrevented: []core.ID{core.IDOf(otherCM)},
+		},
+		{
+			name:          "fail",
+			policy:        v1beta1.AdoptionPolicyFail,
+			wantPrevented: []core.ID{core.IDOf(unmanagedCM), core.IDOf(otherCM)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{kinds.ConfigMap().GroupVersion()})
+			mapper.Add(kinds.ConfigMap(), meta.RESTScopeNamespace)
+			cs := &ClientSet{
+				InvClient:     inventory.NewFakeClient(object.ObjMetadataSet{ObjMetaFromObject(inventoryCM)}),
+				DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), unmanagedCM, otherCM, ownedCM, inventoryCM),
+				Mapper:        mapper,
+			}
+			policy, err := inventoryPolicy(tc.policy, inventory.PolicyAdoptIfNoInventory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inv, err := wrapInventoryObj(newInventoryUnstructured(configsync.RepoSyncKind, "rs", "test-namespace", StatusEnabled))
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := &supervisor{
+				inventory:      inv,
+				clientSet:      cs,
+				policy:         policy,
+				adoptionPolicy: tc.policy,
+			}
+
+			report, err := a.dryRunAdoption(context.Background(), objs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantAdopted, report.adopted); diff != "" {
+				t.Errorf("adopted: %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPrevented, report.prevented); diff != "" {
+				t.Errorf("prevented: %s", diff)
+			}
+		})
+	}
+}
+
+func TestReportAdoptionOncePerCommit(t *testing.T) {
+	unmanagedCM := configMap("unmanaged")
+	atCommit := func(commit string) []client.Object {
+		cm := configMap("unmanaged", core.Annotation(metadata.SyncTokenAnnotationKey, commit))
+		return []client.Object{cm}
+	}
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{kinds.ConfigMap().GroupVersion()})
+	mapper.Add(kinds.ConfigMap(), meta.RESTScopeNamespace)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), unmanagedCM)
+	inv, err := wrapInventoryObj(newInventoryUnstructured(configsync.RepoSyncKind, "rs", "test-namespace", StatusEnabled))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &supervisor{
+		inventory: inv,
+		clientSet: &ClientSet{
+			InvClient:     inventory.NewFakeClient(object.ObjMetadataSet{}),
+			DynamicClient: dynamicClient,
+			Mapper:        mapper,
+		},
+		policy:         inventory.PolicyAdoptIfNoInventory,
+		adoptionPolicy: v1beta1.AdoptionPolicyAdoptIfUnmanaged,
+	}
+
+	ctx := context.Background()
+	for _, commit := range []string{"abc123", "abc123", "def456"} {
+		a.reportAdoption(ctx, atCommit(commit))
+	}
+	// The retry of commit abc123 does not dry-run the adoption again.
+	if got := len(dynamicClient.Actions()); got != 2 {
+		t.Errorf("got %d requests to dry-run the adoption, want 2", got)
+	}
+}
+
+func configMap(name string, opts ...core.MetaMutator) *unstructured.Unstructured {
+	return fake.UnstructuredObject(kinds.ConfigMap(), append(opts, core.Namespace("test-namespace"), core.Name(name))...)
+}
@@ -22,24 +22,32 @@ import (
 	"time"
 
 	"github.com/GoogleContainerTools/kpt/pkg/live"
+	"go.opencensus.io/trace"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configmanagement"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier/stats"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	syncevents "kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
 	m "kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/resourcegroup"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/syncer/differ"
 	"kpt.dev/configsync/pkg/syncer/metrics"
+	"kpt.dev/configsync/pkg/tracing"
 	"kpt.dev/configsync/pkg/util"
 	nomosutil "kpt.dev/configsync/pkg/util"
 	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
@@ -111,6 +119,9 @@ type Supervisor interface {
 type supervisor struct {
 	// inventory policy for configuring the inventory status
 	policy inventory.Policy
+	// adoptionPolicy is the adoption policy the inventory policy is derived
+	// from, if explicitly specified by the RSync object.
+	adoptionPolicy v1beta1.AdoptionPolicy
 	// inventory ResourceGroup used to track managed objects
 	inventory *live.InventoryResourceGroup
 	// clientSet wraps multiple API server clients
@@ -119,10 +130,23 @@ type supervisor struct {
 	syncKind string
 	// syncName is the name of RSync object
 	syncName string
+	// inventoryName is the name of the ResourceGroup inventory object
+	inventoryName string
+	// shard is the shard of the RootSync objects handled by the supervisor
+	shard shard.Shard
 	// syncNamespace is the namespace of RSync object
 	syncNamespace string
 	// reconcileTimeout controls the reconcile and prune timeout
 	reconcileTimeout time.Duration
+	// recorder emits Events about the apply progress on the RSync object
+	recorder *syncevents.Recorder
+	// exemptions lists the drift exemptions, whose selected objects keep the
+	// current values of the fields Config Sync manages instead of their
+	// declared values.
+	exemptions *exemption.Lister
+	// adoptionReportCommit is the commit whose adoption was last reported, so
+	// that the retries of an apply do not dry-run the adoption again.
+	adoptionReportCommit string
 
 	// execMux prevents concurrent Apply/Destroy calls
 	execMux sync.Mutex
@@ -138,18 +162,30 @@ var _ Destroyer = &supervisor{}
 var _ Supervisor = &supervisor{}
 
 // NewSupervisor constructs either a cluster-level or namespace-level Supervisor,
-// based on the specified scope.
-func NewSupervisor(cs *ClientSet, scope declared.Scope, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+// based on the specified scope. Only cluster-level Supervisors are sharded.
+//
+// The adoptionPolicy determines what the Supervisor does with the objects which
+// already exist on the cluster but are not in its inventory. If empty, the
+// Supervisor adopts all of them at the cluster level, and only the ones not
+// managed by another inventory at the namespace level.
+//
+// The objects selected by the exemptions are applied in their current state
+// instead of their declared state.
+func NewSupervisor(cs *ClientSet, scope declared.Scope, syncName string, s shard.Shard, reconcileTimeout time.Duration, adoptionPolicy v1beta1.AdoptionPolicy, recorder *syncevents.Recorder, exemptions *exemption.Lister) (Supervisor, error) {
 	if scope == declared.RootReconciler {
-		return NewRootSupervisor(cs, syncName, reconcileTimeout)
+		return NewRootSupervisor(cs, syncName, s, reconcileTimeout, adoptionPolicy, recorder, exemptions)
 	}
-	return NewNamespaceSupervisor(cs, scope, syncName, reconcileTimeout)
+	return NewNamespaceSupervisor(cs, scope, syncName, reconcileTimeout, adoptionPolicy, recorder, exemptions)
 }
 
 // NewNamespaceSupervisor constructs a Supervisor that can manage resource
 // objects in a single namespace.
-func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName string, reconcileTimeout time.Duration, adoptionPolicy v1beta1.AdoptionPolicy, recorder *syncevents.Recorder, exemptions *exemption.Lister) (Supervisor, error) {
 	syncKind := configsync.RepoSyncKind
+	policy, err := inventoryPolicy(adoptionPolicy, inventory.PolicyAdoptIfNoInventory)
+	if err != nil {
+		return nil, err
+	}
 	invObj := newInventoryUnstructured(syncKind, syncName, string(namespace), cs.StatusMode)
 	// If the ResourceGroup object exists, annotate the status mode on the
 	// existing object.
@@ -165,11 +201,15 @@ func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName st
 	a := &supervisor{
 		inventory:        inv,
 		clientSet:        cs,
-		policy:           inventory.PolicyAdoptIfNoInventory,
+		policy:           policy,
+		adoptionPolicy:   adoptionPolicy,
 		syncKind:         syncKind,
 		syncName:         syncName,
+		inventoryName:    syncName,
 		syncNamespace:    string(namespace),
 		reconcileTimeout: reconcileTimeout,
+		recorder:         recorder,
+		exemptions:       exemptions,
 	}
 	klog.V(4).Infof("Namespace Supervisor %s/%s is initialized", namespace, syncName)
 	return a, nil
@@ -177,9 +217,18 @@ func NewNamespaceSupervisor(cs *ClientSet, namespace declared.Scope, syncName st
 
 // NewRootSupervisor constructs a Supervisor that can manage both cluster-level
 // and namespace-level resource objects in a single cluster.
-func NewRootSupervisor(cs *ClientSet, syncName string, reconcileTimeout time.Duration) (Supervisor, error) {
+//
+// The objects of each shard of a sharded RootSync are tracked in a separate
+// ResourceGroup inventory.
+func NewRootSupervisor(cs *ClientSet, syncName string, s shard.Shard, reconcileTimeout time.Duration, adoptionPolicy v1beta1.AdoptionPolicy, recorder *syncevents.Recorder, exemptions *exemption.Lister) (Supervisor, error) {
 	syncKind := configsync.RootSyncKind
-	u := newInventoryUnstructured(syncKind, syncName, configmanagement.ControllerNamespace, cs.StatusMode)
+	policy, err := inventoryPolicy(adoptionPolicy, inventory.PolicyAdoptAll)
+	if err != nil {
+		return nil, err
+	}
+	inventoryName := shard.Name(syncName, s.Index)
+	u := newInventoryUnstructured(syncKind, inventoryName, configmanagement.ControllerNamespace, cs.StatusMode)
+	core.SetLabel(u, metadata.SyncNameLabel, syncName)
 	// If the ResourceGroup object exists, annotate the status mode on the
 	// existing object.
 	if err := annotateStatusMode(context.TODO(), cs.Client, u, cs.StatusMode); err != nil {
@@ -194,13 +243,18 @@ func NewRootSupervisor(cs *ClientSet, syncName string, reconcileTimeout time.Dur
 	a := &supervisor{
 		inventory:        inv,
 		clientSet:        cs,
-		policy:           inventory.PolicyAdoptAll,
+		policy:           policy,
+		adoptionPolicy:   adoptionPolicy,
 		syncKind:         syncKind,
 		syncName:         syncName,
+		inventoryName:    inventoryName,
+		shard:            s,
 		syncNamespace:    string(configmanagement.ControllerNamespace),
 		reconcileTimeout: reconcileTimeout,
+		recorder:         recorder,
+		exemptions:       exemptions,
 	}
-	klog.V(4).Infof("Root Supervisor %s is initialized and synced with the API server", syncName)
+	klog.V(4).Infof("Root Supervisor %s is initialized and synced with the API server", inventoryName)
 	return a, nil
 }
 
@@ -450,27 +504,50 @@ func handleMetrics(ctx context.Context, operation string, err error, gvk schema.
 }
 
 // checkInventoryObjectSize checks the inventory object size limit.
-// If it is close to the size limit 1M, log a warning.
+// If it is close to the size limit 1M, log a warning. Large inventories are
+// spread across multiple ResourceGroup objects, so this only happens if the
+// ResourceGroup controller reports very long conditions.
 func (a *supervisor) checkInventoryObjectSize(ctx context.Context, c client.Client) {
-	u := newInventoryUnstructured(a.syncKind, a.syncName, a.syncNamespace, a.clientSet.StatusMode)
-	err := c.Get(ctx, client.ObjectKey{Namespace: a.syncNamespace, Name: a.syncName}, u)
+	u := newInventoryUnstructured(a.syncKind, a.inventoryName, a.syncNamespace, a.clientSet.StatusMode)
+	err := c.Get(ctx, client.ObjectKey{Namespace: a.syncNamespace, Name: a.inventoryName}, u)
 	if err == nil {
 		size, err := getObjectSize(u)
 		if err != nil {
-			klog.Warningf("Failed to marshal ResourceGroup %s/%s to get its size: %s", a.syncNamespace, a.syncName, err)
+			klog.Warningf("Failed to marshal ResourceGroup %s/%s to get its size: %s", a.syncNamespace, a.inventoryName, err)
 		}
 		if int64(size) > maxRequestBytes/2 {
 			klog.Warningf("ResourceGroup %s/%s is close to the maximum object size limit (size: %d, max: %s). "+
 				"There are too many resources being synced than Config Sync can handle! Please split your repo into smaller repos "+
-				"to avoid future failure.", a.syncNamespace, a.syncName, size, maxRequestBytesStr)
+				"to avoid future failure.", a.syncNamespace, a.inventoryName, size, maxRequestBytesStr)
 		}
 	}
 }
 
 // applyInner triggers a kpt live apply library call to apply a set of resources.
 func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[schema.GroupVersionKind]struct{}, status.MultiError) {
+	ctx, span := tracing.StartSpan(ctx, "applier.applyInner",
+		trace.Int64Attribute("configsync.objects", int64(len(objs))))
+	defer func() {
+		tracing.EndSpan(span, a.Errors())
+	}()
+
 	a.checkInventoryObjectSize(ctx, a.clientSet.Client)
 
+	if a.shard.Sharded() {
+		// Release the objects assigned to other shards from the inventory,
+		// without deleting them, so that their shards can adopt them.
+		var others []client.Object
+		objs, others = a.shard.Partition(objs)
+		if err := a.removeFromInventory(a.inventory, others); err != nil {
+			if nomosutil.IsRequestTooLargeError(err) {
+				a.addError(largeResourceGroupError(err, idFromInventory(a.inventory)))
+			} else {
+				a.addError(Error(err))
+			}
+			return nil, a.Errors()
+		}
+	}
+
 	s := stats.NewSyncStats()
 	objStatusMap := make(ObjectStatusMap)
 	// disabledObjs are objects for which the management are disabled
@@ -489,6 +566,18 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 		}
 	}
 	klog.Infof("%v objects to be applied: %v", len(enabledObjs), core.GKNNs(enabledObjs))
+	if a.adoptionPolicy != "" {
+		a.reportAdoption(ctx, enabledObjs)
+	}
+	enabledObjs, exemptionErrs := a.honorExemptions(ctx, enabledObjs)
+	if exemptionErrs != nil {
+		for _, err := range exemptionErrs.Errors() {
+			a.addError(err)
+		}
+		return nil, a.Errors()
+	}
+	a.recorder.Eventf(ctx, corev1.EventTypeNormal, syncevents.ReasonApplyStarted,
+		"Applying %d objects", len(enabledObjs))
 	resources, err := toUnstructured(enabledObjs)
 	if err != nil {
 		a.addError(err)
@@ -523,6 +612,7 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 	// This allows for picking up CRD changes.
 	meta.MaybeResetRESTMapper(a.clientSet.Mapper)
 
+	groupSpans := make(map[string]*trace.Span)
 	events := a.clientSet.KptApplier.Run(ctx, a.inventory, object.UnstructuredSet(resources), options)
 	for e := range events {
 		switch e.Type {
@@ -532,6 +622,7 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 			}
 		case event.ActionGroupType:
 			klog.Info(e.ActionGroupEvent)
+			traceActionGroup(ctx, groupSpans, e.ActionGroupEvent)
 		case event.ErrorType:
 			klog.Info(e.ErrorEvent)
 			if util.IsRequestTooLargeError(e.ErrorEvent.Err) {
@@ -568,6 +659,10 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 			klog.Infof("Unhandled event (%s): %v", e.Type, e)
 		}
 	}
+	// End the spans of the action groups interrupted by an error.
+	for _, groupSpan := range groupSpans {
+		groupSpan.End()
+	}
 
 	gvks := make(map[schema.GroupVersionKind]struct{})
 	for _, resource := range objs {
@@ -588,9 +683,46 @@ func (a *supervisor) applyInner(ctx context.Context, objs []client.Object) (map[
 		klog.Infof("Applier made new progress: %s", s.String())
 		objStatusMap.Log(klog.V(0))
 	}
+	a.recordApplyEvents(ctx, s, errs)
 	return gvks, errs
 }
 
+// traceActionGroup starts a span when the applier starts a group of actions,
+// like applying, pruning or waiting for a set of objects, and ends it when the
+// group is finished.
+func traceActionGroup(ctx context.Context, spans map[string]*trace.Span, e event.ActionGroupEvent) {
+	switch e.Status {
+	case event.Started:
+		_, span := tracing.StartSpan(ctx, "applier."+e.Action.String(),
+			trace.StringAttribute("configsync.action_group", e.GroupName))
+		spans[e.GroupName] = span
+	case event.Finished:
+		if span, found := spans[e.GroupName]; found {
+			span.End()
+			delete(spans, e.GroupName)
+		}
+	}
+}
+
+// recordApplyEvents emits Events summarizing the result of an apply.
+func (a *supervisor) recordApplyEvents(ctx context.Context, s *stats.SyncStats, errs status.MultiError) {
+	summary := "no new progress"
+	if !s.Empty() {
+		summary = s.String()
+	}
+	if errs != nil {
+		a.recorder.Eventf(ctx, corev1.EventTypeWarning, syncevents.ReasonApplyFailed,
+			"Apply finished with %d errors: %s", len(errs.Errors()), summary)
+	} else {
+		a.recorder.Eventf(ctx, corev1.EventTypeNormal, syncevents.ReasonApplySucceeded,
+			"Apply finished: %s", summary)
+	}
+	if !s.PruneEvent.Empty() {
+		a.recorder.Eventf(ctx, corev1.EventTypeNormal, syncevents.ReasonPruned,
+			"Pruned objects no longer declared in the source: %s", s.PruneEvent.String())
+	}
+}
+
 // Errors returns the errors encountered during the last apply or current apply
 // if still running.
 // Errors implements the Applier and Destroyer interfaces.
@@ -682,6 +814,12 @@ func (a *supervisor) destroyInner(ctx context.Context) status.MultiError {
 		}
 	}
 
+	if a.Errors() == nil {
+		// The destroyer only deletes the primary ResourceGroup.
+		if err := deleteInventoryChunks(ctx, a.clientSet.Client, a.syncNamespace, a.inventoryName); err != nil {
+			a.addError(Error(err))
+		}
+	}
 	errs := a.Errors()
 	if errs == nil {
 		klog.V(4).Infof("Destroy completed without error: all resources are deleted.")
@@ -781,15 +919,15 @@ func (a *supervisor) removeFromInventory(rg *live.InventoryResourceGroup, objs [
 		// If inventory does not exist, there is nothing to remove
 		return nil
 	}
-	wrappedInv, err := wrapInventoryObj(clusterInv)
-	if err != nil {
-		return err
-	}
-	oldObjs, err := wrappedInv.Load()
+	oldObjs, err := a.clientSet.InvClient.GetClusterObjs(rg)
 	if err != nil {
 		return err
 	}
 	newObjs := removeFrom(oldObjs, objs)
+	if len(newObjs) == len(oldObjs) {
+		// None of the objects are in the inventory.
+		return nil
+	}
 	err = rg.Store(newObjs, nil)
 	if err != nil {
 		return err
@@ -820,7 +958,15 @@ func (a *supervisor) disableObject(ctx context.Context, obj client.Object) error
 			return nil
 		}
 		uObj.SetManagedFields(nil)
-		return a.clientSet.Client.Patch(ctx, uObj, client.Apply, client.FieldOwner(configsync.FieldManager), client.ForceOwnership)
+		data, err := uObj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		force := true
+		_, err = a.clientSet.DynamicClient.Resource(mapping.Resource).
+			Namespace(meta.Namespace).
+			Patch(ctx, meta.Name, types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: configsync.FieldManager, Force: &force})
+		return err
 	}
 	return nil
 }
@@ -251,7 +251,7 @@ func TestApply(t *testing.T) {
 				// TODO: Add tests to cover disabling objects
 				// TODO: Add tests to cover status mode
 			}
-			applier, err := NewNamespaceSupervisor(cs, "test-namespace", "rs", 5*time.Minute)
+			applier, err := NewNamespaceSupervisor(cs, "test-namespace", "rs", 5*time.Minute, "", nil, nil)
 			require.NoError(t, err)
 
 			gvks, errs := applier.Apply(context.Background(), objs)
@@ -45,19 +45,27 @@ type KptDestroyer interface {
 // ClientSet wraps the various Kubernetes clients required for building a
 // Config Sync applier.Applier.
 type ClientSet struct {
-	KptApplier    KptApplier
-	KptDestroyer  KptDestroyer
-	InvClient     inventory.Client
-	Client        client.Client
+	KptApplier   KptApplier
+	KptDestroyer KptDestroyer
+	InvClient    inventory.Client
+	Client       client.Client
+	// DynamicClient and Mapper manage the objects in the inventory, and may
+	// impersonate another user than Client.
 	DynamicClient dynamic.Interface
 	Mapper        meta.RESTMapper
 	StatusMode    string
 }
 
 // NewClientSet constructs a new ClientSet.
-func NewClientSet(c client.Client, configFlags *genericclioptions.ConfigFlags, statusMode string) (*ClientSet, error) {
-	matchVersionKubeConfigFlags := util.NewMatchVersionFlags(configFlags)
-	f := util.NewFactory(matchVersionKubeConfigFlags)
+//
+// The inventory is managed with the configFlags, and the objects in the
+// inventory with the objectsConfigFlags, which may impersonate another user.
+func NewClientSet(c client.Client, configFlags, objectsConfigFlags *genericclioptions.ConfigFlags, statusMode string) (*ClientSet, error) {
+	f := util.NewFactory(util.NewMatchVersionFlags(configFlags))
+	objectsFactory := f
+	if objectsConfigFlags != configFlags {
+		objectsFactory = util.NewFactory(util.NewMatchVersionFlags(objectsConfigFlags))
+	}
 
 	var statusPolicy inventory.StatusPolicy
 	if statusMode == StatusEnabled {
@@ -67,7 +75,16 @@ func NewClientSet(c client.Client, configFlags *genericclioptions.ConfigFlags, s
 		klog.Infof("Disabled status reporting")
 		statusPolicy = inventory.StatusPolicyNone
 	}
-	invClient, err := inventory.NewClient(f, live.WrapInventoryObj,
+	invDynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	invMapper, err := f.ToRESTMapper()
+	if err != nil {
+		return nil, err
+	}
+
+	invClient, err := inventory.NewClient(f, chunkedInventoryFactory(invDynamicClient, invMapper),
 		live.InvToUnstructuredFunc, statusPolicy, live.ResourceGroupGVK)
 	if err != nil {
 		return nil, err
@@ -75,7 +92,7 @@ func NewClientSet(c client.Client, configFlags *genericclioptions.ConfigFlags, s
 
 	applier, err := apply.NewApplierBuilder().
 		WithInventoryClient(invClient).
-		WithFactory(f).
+		WithFactory(objectsFactory).
 		Build()
 	if err != nil {
 		return nil, err
@@ -83,17 +100,17 @@ func NewClientSet(c client.Client, configFlags *genericclioptions.ConfigFlags, s
 
 	destroyer, err := apply.NewDestroyerBuilder().
 		WithInventoryClient(invClient).
-		WithFactory(f).
+		WithFactory(objectsFactory).
 		Build()
 	if err != nil {
 		return nil, err
 	}
 
-	dynamicClient, err := f.DynamicClient()
+	dynamicClient, err := objectsFactory.DynamicClient()
 	if err != nil {
 		return nil, err
 	}
-	mapper, err := f.ToRESTMapper()
+	mapper, err := objectsFactory.ToRESTMapper()
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +194,7 @@ func TestDestroy(t *testing.T) {
 				// TODO: Add tests to cover disabling objects
 				// TODO: Add tests to cover status mode
 			}
-			destroyer, err := NewNamespaceSupervisor(cs, "test-namespace", "rs", 5*time.Minute)
+			destroyer, err := NewNamespaceSupervisor(cs, "test-namespace", "rs", 5*time.Minute, "", nil, nil)
 			require.NoError(t, err)
 
 			errs := destroyer.Destroy(context.Background())
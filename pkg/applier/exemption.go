@@ -0,0 +1,203 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/core"
+	syncevents "kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// honorExemptions replaces the declarations of the objects selected by an
+// active drift exemption with the fields Config Sync manages, set to their
+// current values on the cluster, so that applying them keeps the drift the
+// exemption allows. The exempted objects stay in the inventory, and the fields
+// managed by other managers are left to them. The exempted objects which do
+// not exist are not applied, so they are only created once the exemption
+// expires.
+//
+// The objects whose type is unknown are left alone, so that the applier
+// reports them.
+func (a *supervisor) honorExemptions(ctx context.Context, objs []client.Object) ([]client.Object, status.MultiError) {
+	if a.exemptions == nil {
+		return objs, nil
+	}
+	var result []client.Object
+	var errs status.MultiError
+	for _, obj := range objs {
+		e, found := a.exemptions.ForObject(ctx, obj)
+		if !found {
+			result = append(result, obj)
+			continue
+		}
+		gknn := core.GKNN(obj)
+		current, err := a.currentState(ctx, obj)
+		switch {
+		case err != nil:
+			errs = status.Append(errs, status.APIServerError(err, "failed to get the current state of an object selected by a drift exemption", obj))
+		case current == nil:
+			klog.V(3).Infof("Drift exemption %q prevented the applier from creating object %v", e.Name, gknn)
+			a.exemptions.RecordUseOnce(e, obj, syncevents.ReasonDeclarationNotApplied, "the applier did not create %q", gknn)
+		default:
+			klog.V(3).Infof("Drift exemption %q made the applier keep the current state of object %v", e.Name, gknn)
+			a.exemptions.RecordUseOnce(e, obj, syncevents.ReasonDeclarationNotApplied, "the applier kept the current state of %q instead of applying its declaration", gknn)
+			result = append(result, current)
+		}
+	}
+	return result, errs
+}
+
+// currentState returns the fields of the object Config Sync manages, with their
+// values on the cluster, or nil if the object does not exist. The declaration
+// is returned if its type is unknown.
+func (a *supervisor) currentState(ctx context.Context, obj client.Object) (client.Object, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	mapping, err := a.clientSet.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return obj, nil
+	}
+	u, err := a.clientSet.DynamicClient.Resource(mapping.Resource).
+		Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return managedState(u)
+}
+
+// managedState returns the fields of the object Config Sync manages according
+// to its managedFields, with their values on the object. Applying them keeps
+// them as they are, without taking over the fields of other managers.
+func managedState(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	managed := &fieldpath.Set{}
+	for _, entry := range u.GetManagedFields() {
+		if entry.Manager != configsync.FieldManager || entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to parse the managed fields of %s: %w", core.GKNN(u), err)
+		}
+		managed = managed.Union(fields)
+	}
+
+	result := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	managed.Leaves().Iterate(func(p fieldpath.Path) {
+		result.Object = copyPath(result.Object, u.Object, p).(map[string]interface{})
+	})
+	result.SetGroupVersionKind(u.GroupVersionKind())
+	result.SetNamespace(u.GetNamespace())
+	result.SetName(u.GetName())
+	return result, nil
+}
+
+// copyPath copies the value at the path of src into dst, along with the keys of
+// the list items on the path, and returns dst.
+func copyPath(dst, src interface{}, p fieldpath.Path) interface{} {
+	if len(p) == 0 {
+		return runtime.DeepCopyJSONValue(src)
+	}
+	pe := p[0]
+	if pe.FieldName != nil {
+		srcMap, ok := src.(map[string]interface{})
+		if !ok {
+			return dst
+		}
+		v, found := srcMap[*pe.FieldName]
+		if !found {
+			return dst
+		}
+		dstMap, ok := dst.(map[string]interface{})
+		if !ok {
+			dstMap = map[string]interface{}{}
+		}
+		dstMap[*pe.FieldName] = copyPath(dstMap[*pe.FieldName], v, p[1:])
+		return dstMap
+	}
+
+	srcList, ok := src.([]interface{})
+	if !ok {
+		return dst
+	}
+	if pe.Index != nil {
+		// The items of lists without keys can only be set all together.
+		return runtime.DeepCopyJSONValue(srcList)
+	}
+	i := indexOf(srcList, pe)
+	if i < 0 {
+		return dst
+	}
+	dstList, _ := dst.([]interface{})
+	j := indexOf(dstList, pe)
+	if j < 0 {
+		var item interface{} = runtime.DeepCopyJSONValue(srcList[i])
+		if pe.Key != nil {
+			// Start with the keys, which identify the item.
+			keys := map[string]interface{}{}
+			for _, f := range *pe.Key {
+				keys[f.Name] = f.Value.Unstructured()
+			}
+			item = keys
+		}
+		dstList = append(dstList, item)
+		j = len(dstList) - 1
+	}
+	dstList[j] = copyPath(dstList[j], srcList[i], p[1:])
+	return dstList
+}
+
+// indexOf returns the index of the list item the path element identifies by
+// its keys or its value, or -1 if it is not in the list.
+func indexOf(list []interface{}, pe fieldpath.PathElement) int {
+	for i, item := range list {
+		switch {
+		case pe.Key != nil:
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			matches := true
+			for _, f := range *pe.Key {
+				if v, found := m[f.Name]; !found || !value.Equals(value.NewValueInterface(v), f.Value) {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				return i
+			}
+		case pe.Value != nil:
+			if value.Equals(value.NewValueInterface(item), *pe.Value) {
+				return i
+			}
+		}
+	}
+	return -1
+}
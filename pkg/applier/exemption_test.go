@@ -0,0 +1,219 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/exemption"
+	"kpt.dev/configsync/pkg/kinds"
+	testingfake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestHonorExemptions(t *testing.T) {
+	declared := func(name string) *unstructured.Unstructured {
+		cm := configMap(name, core.Label("exempted", "true"))
+		_ = unstructured.SetNestedField(cm.Object, "declared", "data", "key")
+		return cm
+	}
+	drifted := declared("drifted")
+	current := drifted.DeepCopy()
+	_ = unstructured.SetNestedField(current.Object, "drifted", "data", "key")
+	// The other field is managed by another controller.
+	_ = unstructured.SetNestedField(current.Object, "other", "data", "other")
+	current.SetUID("1")
+	current.SetResourceVersion("2")
+	current.SetCreationTimestamp(metav1.Now())
+	current.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:    configsync.FieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}},"f:metadata":{"f:labels":{"f:exempted":{}}}}`)},
+		},
+		{
+			Manager:    "other-controller",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:other":{}}}`)},
+		},
+	})
+	notExempted := configMap("not-exempted")
+	missing := declared("missing")
+
+	// Only the fields managed by Config Sync are applied, with their current
+	// values.
+	wantCurrent := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "drifted",
+			"namespace": "test-namespace",
+			"labels":    map[string]interface{}{"exempted": "true"},
+		},
+		"data": map[string]interface{}{"key": "drifted"},
+	}}
+
+	testCases := []struct {
+		name       string
+		expires    time.Time
+		want       []client.Object
+		wantEvents int
+	}{
+		{
+			name:    "active exemption",
+			expires: time.Now().Add(time.Hour),
+			want:    []client.Object{wantCurrent, notExempted},
+			// The uses are only recorded the first time.
+			wantEvents: 2,
+		},
+		{
+			name:    "expired exemption",
+			expires: time.Now().Add(-time.Hour),
+			want:    []client.Object{drifted, notExempted, missing},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			de := &v1beta1.DriftExemption{Spec: v1beta1.DriftExemptionSpec{
+				Users: []string{"alice@acme.com"},
+				Objects: []v1beta1.DriftExemptionSelector{{
+					Kind:          "ConfigMap",
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"exempted": "true"}},
+				}},
+				Expires: metav1.NewTime(tc.expires),
+			}}
+			de.Name = "outage-1234"
+			recorder := record.NewFakeRecorder(10)
+
+			mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{kinds.ConfigMap().GroupVersion()})
+			mapper.Add(kinds.ConfigMap(), meta.RESTScopeNamespace)
+			a := &supervisor{
+				clientSet: &ClientSet{
+					DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), current),
+					Mapper:        mapper,
+				},
+				exemptions: exemption.NewLister(testingfake.NewClient(t, core.Scheme, de), recorder),
+			}
+
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				got, errs := a.honorExemptions(ctx, []client.Object{drifted, notExempted, missing})
+				if errs != nil {
+					t.Fatal(errs)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("honorExemptions() diff (- want, + got):\n%s", diff)
+				}
+				// Neither the current state nor the declaration take over the
+				// field of the other controller, so it keeps it during the
+				// exemption and after it expires.
+				for _, obj := range got {
+					if _, found, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "data", "other"); found {
+						t.Errorf("honorExemptions() applies the field of another manager of %s", core.GKNN(obj))
+					}
+				}
+			}
+
+			if got := len(recorder.Events); got != tc.wantEvents {
+				t.Errorf("got %d Events, want %d", got, tc.wantEvents)
+			}
+		})
+	}
+}
+
+func TestManagedState(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":              "frontend",
+			"namespace":         "bookstore",
+			"uid":               "1",
+			"creationTimestamp": "2022-06-01T18:00:00Z",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(5),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v2", "imagePullPolicy": "Always"},
+						map[string]interface{}{"name": "sidecar", "image": "sidecar:v1"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"replicas": int64(5)},
+	}}
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   configsync.FieldManager,
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{` +
+				`"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`)},
+		},
+		{
+			Manager:   "kube-controller-manager",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:     configsync.FieldManager,
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicas":{}}}`)},
+		},
+	})
+
+	want := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "frontend",
+			"namespace": "bookstore",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v2"},
+					},
+				},
+			},
+		},
+	}}
+	got, err := managedState(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("managedState() diff (- want, + got):\n%s", diff)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/GoogleContainerTools/kpt/pkg/live"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/resourcegroup"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/common"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// inventoryChunkBytes is the maximum encoded size of the objects stored in a
+// single ResourceGroup object. Larger inventories are spread across additional
+// ResourceGroup chunks, to keep every ResourceGroup object well below the
+// maximum request size of the API server, leaving room for the conditions the
+// ResourceGroup controller adds to the statuses of the objects.
+var inventoryChunkBytes = 256 * 1024
+
+// inventoryChunkSize is the maximum number of objects stored in a single
+// ResourceGroup object, however short their names.
+var inventoryChunkSize = 2000
+
+// chunkedInventory is an inventory.Storage which spreads the inventory across
+// the primary ResourceGroup and as many ResourceGroup chunks as needed.
+//
+// The primary ResourceGroup is the one known to the cli-utils inventory
+// client. Chunks are loaded, applied and deleted along with it, so that
+// prune logic sees a single inventory.
+type chunkedInventory struct {
+	// inv is the primary ResourceGroup object.
+	inv *unstructured.Unstructured
+	// client is used to load the chunks.
+	client dynamic.Interface
+	// mapper is used to load the chunks.
+	mapper meta.RESTMapper
+
+	objMetas  object.ObjMetadataSet
+	objStatus []actuation.ObjectStatus
+}
+
+var _ inventory.Storage = &chunkedInventory{}
+
+// chunkedInventoryFactory returns an inventory.StorageFactoryFunc which wraps
+// ResourceGroup objects in a chunkedInventory.
+func chunkedInventoryFactory(dc dynamic.Interface, mapper meta.RESTMapper) inventory.StorageFactoryFunc {
+	return func(obj *unstructured.Unstructured) inventory.Storage {
+		return &chunkedInventory{inv: obj, client: dc, mapper: mapper}
+	}
+}
+
+// Load implements inventory.Storage. It returns the objects stored in the
+// primary ResourceGroup and all its chunks. Missing chunks are skipped.
+func (c *chunkedInventory) Load() (object.ObjMetadataSet, error) {
+	objs, err := live.WrapInventoryObj(c.inv).Load()
+	if err != nil {
+		return objs, err
+	}
+	count := resourcegroup.ChunkCount(c.inv)
+	if count == 0 {
+		return objs, nil
+	}
+	rgClient, err := resourceGroupClient(c.client, c.mapper, c.inv.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= count; i++ {
+		name := resourcegroup.ChunkName(c.inv.GetName(), i)
+		chunk, err := rgClient.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				klog.Warningf("ResourceGroup chunk %s/%s of inventory %s is missing", c.inv.GetNamespace(), name, c.inv.GetName())
+				continue
+			}
+			return nil, err
+		}
+		chunkObjs, err := live.WrapInventoryObj(chunk).Load()
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, chunkObjs...)
+	}
+	return objs, nil
+}
+
+// Store implements inventory.Storage. Actual storing happens in Apply.
+func (c *chunkedInventory) Store(objs object.ObjMetadataSet, status []actuation.ObjectStatus) error {
+	c.objMetas = objs
+	c.objStatus = status
+	return nil
+}
+
+// GetObject implements inventory.Storage. It returns the primary
+// ResourceGroup, with the objects which do not fit in it left out.
+func (c *chunkedInventory) GetObject() (*unstructured.Unstructured, error) {
+	primary, _ := c.split()
+	return primary.GetObject()
+}
+
+// Apply implements inventory.Storage. It applies the chunks, then the primary
+// ResourceGroup, and finally deletes the chunks which are no longer needed.
+func (c *chunkedInventory) Apply(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy inventory.StatusPolicy) error {
+	rgClient, err := resourceGroupClient(dc, mapper, c.inv.GetNamespace())
+	if err != nil {
+		return err
+	}
+	oldCount := resourcegroup.ChunkCount(c.inv)
+	primary, chunks := c.split()
+	for i, objs := range chunks {
+		chunk, err := c.chunk(rgClient, i+1)
+		if err != nil {
+			return err
+		}
+		wrapped := live.WrapInventoryObj(chunk)
+		if err := wrapped.Store(objs, c.objStatus); err != nil {
+			return err
+		}
+		if err := wrapped.Apply(dc, mapper, statusPolicy); err != nil {
+			return err
+		}
+	}
+	if err := primary.Apply(dc, mapper, statusPolicy); err != nil {
+		return err
+	}
+	for i := len(chunks) + 1; i <= oldCount; i++ {
+		name := resourcegroup.ChunkName(c.inv.GetName(), i)
+		if err := rgClient.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyWithPrune implements inventory.Storage. Chunks are created as needed,
+// so it is the same as Apply.
+func (c *chunkedInventory) ApplyWithPrune(dc dynamic.Interface, mapper meta.RESTMapper, statusPolicy inventory.StatusPolicy, _ object.ObjMetadataSet) error {
+	return c.Apply(dc, mapper, statusPolicy)
+}
+
+// split returns the primary ResourceGroup storing the first objects, annotated
+// with the number of chunks, and the objects of each chunk. Every
+// ResourceGroup stores at most inventoryChunkSize objects, whose encoded size
+// is at most inventoryChunkBytes.
+func (c *chunkedInventory) split() (inventory.Storage, []object.ObjMetadataSet) {
+	statuses := make(map[object.ObjMetadata]actuation.ObjectStatus, len(c.objStatus))
+	for _, s := range c.objStatus {
+		statuses[inventory.ObjMetadataFromObjectReference(s.ObjectReference)] = s
+	}
+	var groups []object.ObjMetadataSet
+	start, size := 0, 0
+	for i, id := range c.objMetas {
+		n := encodedSize(id, statuses)
+		if i > start && (i-start >= inventoryChunkSize || size+n > inventoryChunkBytes) {
+			groups = append(groups, c.objMetas[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	if start < len(c.objMetas) {
+		groups = append(groups, c.objMetas[start:])
+	}
+	var objs object.ObjMetadataSet
+	var chunks []object.ObjMetadataSet
+	if len(groups) > 0 {
+		objs, chunks = groups[0], groups[1:]
+	}
+
+	inv := c.inv.DeepCopy()
+	if len(chunks) > 0 {
+		core.SetAnnotation(inv, metadata.InventoryChunksAnnotationKey, strconv.Itoa(len(chunks)))
+	} else {
+		core.RemoveAnnotations(inv, metadata.InventoryChunksAnnotationKey)
+	}
+	primary := live.WrapInventoryObj(inv)
+	// Storing never fails.
+	_ = primary.Store(objs, c.objStatus)
+	return primary, chunks
+}
+
+// chunk returns the ResourceGroup chunk with the index, from the cluster if it
+// exists, with the labels and annotations of the primary ResourceGroup.
+func (c *chunkedInventory) chunk(rgClient dynamic.ResourceInterface, index int) (*unstructured.Unstructured, error) {
+	name := resourcegroup.ChunkName(c.inv.GetName(), index)
+	ns := c.inv.GetNamespace()
+	chunk, err := rgClient.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		chunk = resourcegroup.Unstructured(name, ns, InventoryID(name, ns))
+	}
+	for k, v := range c.inv.GetLabels() {
+		core.SetLabel(chunk, k, v)
+	}
+	for k, v := range c.inv.GetAnnotations() {
+		core.SetAnnotation(chunk, k, v)
+	}
+	core.RemoveAnnotations(chunk, metadata.InventoryChunksAnnotationKey)
+	core.SetLabel(chunk, common.InventoryLabel, InventoryID(name, ns))
+	core.SetLabel(chunk, metadata.InventoryChunkOfLabel, c.inv.GetName())
+	return chunk, nil
+}
+
+// encodedSize returns the size of the entries the ResourceGroup stores for the
+// object in its spec and status, encoded like the inventory client does.
+func encodedSize(id object.ObjMetadata, statuses map[object.ObjMetadata]actuation.ObjectStatus) int {
+	entry := map[string]interface{}{
+		"group":     id.GroupKind.Group,
+		"kind":      id.GroupKind.Kind,
+		"namespace": id.Namespace,
+		"name":      id.Name,
+	}
+	// Encoding strings never fails.
+	spec, _ := json.Marshal(entry)
+	size := len(spec)
+	if s, found := statuses[id]; found {
+		entry["status"] = "Unknown"
+		entry["strategy"] = s.Strategy.String()
+		entry["actuation"] = s.Actuation.String()
+		entry["reconcile"] = s.Reconcile.String()
+		status, _ := json.Marshal(entry)
+		size += len(status)
+	}
+	return size
+}
+
+// resourceGroupClient returns a client for ResourceGroups in the namespace.
+func resourceGroupClient(dc dynamic.Interface, mapper meta.RESTMapper, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := mapper.RESTMapping(live.ResourceGroupGVK.GroupKind(), live.ResourceGroupGVK.Version)
+	if err != nil {
+		return nil, err
+	}
+	return dc.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// deleteInventoryChunks deletes the ResourceGroup chunks of the inventory
+// with the name, if any.
+func deleteInventoryChunks(ctx context.Context, c client.Client, namespace, name string) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(live.ResourceGroupGVK)
+	err := c.DeleteAllOf(ctx, u, client.InNamespace(namespace),
+		client.MatchingLabels{metadata.InventoryChunkOfLabel: name})
+	if err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/kpt/pkg/live"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/resourcegroup"
+	"sigs.k8s.io/cli-utils/pkg/apis/actuation"
+	"sigs.k8s.io/cli-utils/pkg/inventory"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+func TestChunkedInventory(t *testing.T) {
+	defer func(size int) {
+		inventoryChunkSize = size
+	}(inventoryChunkSize)
+	inventoryChunkSize = 2
+
+	rgGVR := schema.GroupVersionResource{
+		Group:    live.ResourceGroupGVK.Group,
+		Version:  live.ResourceGroupGVK.Version,
+		Resource: "resourcegroups",
+	}
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{rgGVR: "ResourceGroupList"})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(live.ResourceGroupGVK, meta.RESTScopeNamespace)
+	rgClient := dc.Resource(rgGVR).Namespace(configmanagement.ControllerNamespace)
+	factory := chunkedInventoryFactory(dc, mapper)
+
+	testCases := []struct {
+		name       string
+		objects    int
+		wantChunks []int
+	}{
+		{
+			name:       "create chunks",
+			objects:    5,
+			wantChunks: []int{2, 2, 1},
+		},
+		{
+			name:       "delete surplus chunks",
+			objects:    3,
+			wantChunks: []int{2, 1},
+		},
+		{
+			name:       "fits in the primary ResourceGroup",
+			objects:    1,
+			wantChunks: []int{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Start from the inventory in the cluster, if any, like the
+			// inventory client does.
+			inv := newInventoryUnstructured(configsync.RootSyncKind, "root-sync", configmanagement.ControllerNamespace, StatusEnabled)
+			if clusterInv, err := rgClient.Get(context.Background(), inv.GetName(), metav1.GetOptions{}); err == nil {
+				inv = clusterInv
+			}
+			var objs object.ObjMetadataSet
+			for i := 0; i < tc.objects; i++ {
+				objs = append(objs, object.ObjMetadata{
+					GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+					Namespace: "foo",
+					Name:      fmt.Sprintf("cm-%d", i),
+				})
+			}
+			storage := factory(inv)
+			if err := storage.Store(objs, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := storage.Apply(dc, mapper, inventory.StatusPolicyAll); err != nil {
+				t.Fatal(err)
+			}
+
+			primary, err := rgClient.Get(context.Background(), inv.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := resourcegroup.ChunkCount(primary), len(tc.wantChunks)-1; got != want {
+				t.Errorf("got %d chunks, want %d", got, want)
+			}
+			for i, want := range tc.wantChunks {
+				rg := primary
+				if i > 0 {
+					rg, err = rgClient.Get(context.Background(), resourcegroup.ChunkName(inv.GetName(), i), metav1.GetOptions{})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got := rg.GetLabels()[metadata.InventoryChunkOfLabel]; got != inv.GetName() {
+						t.Errorf("got chunk %d of %q, want %q", i, got, inv.GetName())
+					}
+				}
+				rgObjs, err := live.WrapInventoryObj(rg).Load()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(rgObjs) != want {
+					t.Errorf("got %d objects in ResourceGroup %s, want %d", len(rgObjs), rg.GetName(), want)
+				}
+			}
+			_, err = rgClient.Get(context.Background(), resourcegroup.ChunkName(inv.GetName(), len(tc.wantChunks)), metav1.GetOptions{})
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("got error %v getting surplus chunk, want NotFound", err)
+			}
+
+			got, err := factory(primary).Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(objs) {
+				t.Errorf("Load() = %v, want %v", got, objs)
+			}
+		})
+	}
+}
+
+func TestChunkedInventorySplitBySize(t *testing.T) {
+	defer func(size int) {
+		inventoryChunkBytes = size
+	}(inventoryChunkBytes)
+
+	var objs object.ObjMetadataSet
+	var statuses []actuation.ObjectStatus
+	for i := 0; i < 7; i++ {
+		id := object.ObjMetadata{
+			GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+			Namespace: "foo",
+			Name:      fmt.Sprintf("%s-%d", strings.Repeat("a", 240), i),
+		}
+		objs = append(objs, id)
+		statuses = append(statuses, actuation.ObjectStatus{
+			ObjectReference: inventory.ObjectReferenceFromObjMetadata(id),
+			Strategy:        actuation.ActuationStrategyApply,
+			Actuation:       actuation.ActuationSucceeded,
+			Reconcile:       actuation.ReconcileSucceeded,
+		})
+	}
+	inv := newInventoryUnstructured(configsync.RootSyncKind, "root-sync", configmanagement.ControllerNamespace, StatusEnabled)
+	c := &chunkedInventory{inv: inv}
+	if err := c.Store(objs, statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	// The size of the spec and status entries of three objects.
+	primary, _ := c.split()
+	rg, err := primary.GetObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := 0
+	for _, field := range []string{"spec.resources", "status.resourceStatuses"} {
+		entries, _, err := unstructured.NestedSlice(rg.Object, strings.Split(field, ".")...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries[:3] {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			limit += len(data)
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		bytes      int
+		wantChunks []int
+	}{
+		{
+			name:       "three objects at the limit",
+			bytes:      limit,
+			wantChunks: []int{3, 3, 1},
+		},
+		{
+			name:       "three objects over the limit",
+			bytes:      limit - 1,
+			wantChunks: []int{2, 2, 2, 1},
+		},
+		{
+			name:       "objects larger than the limit",
+			bytes:      1,
+			wantChunks: []int{1, 1, 1, 1, 1, 1, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inventoryChunkBytes = tc.bytes
+			primary, chunks := c.split()
+			rg, err := primary.GetObject()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rgObjs, err := live.WrapInventoryObj(rg).Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []int{len(rgObjs)}
+			for _, chunk := range chunks {
+				got = append(got, len(chunk))
+			}
+			if diff := cmp.Diff(tc.wantChunks, got); diff != "" {
+				t.Errorf("got objects per ResourceGroup diff (-want +got):\n%s", diff)
+			}
+			if want := strconv.Itoa(len(tc.wantChunks) - 1); rg.GetAnnotations()[metadata.InventoryChunksAnnotationKey] != want {
+				t.Errorf("got chunks annotation %q, want %q", rg.GetAnnotations()[metadata.InventoryChunksAnnotationKey], want)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bucket fetches the sources stored in buckets of S3-compatible object
+// storages, such as Amazon S3 or MinIO, for the bucket-sync container.
+//
+// Like the other sync containers, it writes every version of the source into
+// a directory under the root directory named after its digest, and points the
+// rev symlink to it. The digest is calculated from the keys and ETags of the
+// objects, so the objects are only downloaded again when any of them changed.
+package bucket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is the region used to sign the requests when none is
+	// specified. S3-compatible storages like MinIO accept it by default.
+	DefaultRegion = "us-east-1"
+
+	// emptyPayloadHash is the sha256 digest of the empty payload of the GET
+	// requests.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	amzDateFormat = "20060102T150405Z"
+
+	// dialTimeout is the timeout of establishing the connections.
+	dialTimeout = 30 * time.Second
+	// tlsHandshakeTimeout is the timeout of the TLS handshakes.
+	tlsHandshakeTimeout = 10 * time.Second
+	// responseHeaderTimeout is the timeout of waiting for the response headers
+	// once the request is sent. Downloading the response bodies is only bounded
+	// by the timeout of the fetch.
+	responseHeaderTimeout = 30 * time.Second
+	// idleConnTimeout is the time idle connections are kept open for reuse.
+	idleConnTimeout = 90 * time.Second
+)
+
+// Credentials are the credentials signing the requests. They are either an
+// access key, or the temporary security credentials of a session, which also
+// have a session token.
+type Credentials struct {
+	// AccessKeyID is the access key ID. Requests are anonymous if it is empty.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+	// SessionToken is the session token of temporary security credentials.
+	SessionToken string
+}
+
+// Object is an object of a bucket.
+type Object struct {
+	// Key is the key of the object.
+	Key string
+	// ETag identifies the content of the object.
+	ETag string
+	// Size is the size of the object in bytes.
+	Size int64
+}
+
+// Client is a minimal client of the S3 API, which lists and gets the objects
+// of buckets with path-style requests.
+type Client struct {
+	endpoint    *url.URL
+	region      string
+	credentials Credentials
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewClient returns a client of the S3 API at the endpoint. The requests are
+// signed with AWS Signature Version 4 if the access key is specified, and
+// anonymous otherwise.
+func NewClient(endpoint, region string, credentials Credentials) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: must be an http or https URL", endpoint)
+	}
+	if region == "" {
+		region = DefaultRegion
+	}
+	return &Client{
+		endpoint:    u,
+		region:      region,
+		credentials: credentials,
+		httpClient:  newHTTPClient(),
+		now:         time.Now,
+	}, nil
+}
+
+// newHTTPClient returns an HTTP client with its own transport, so that a
+// stalled connection times out instead of blocking the fetch until its
+// timeout, and the client is not affected by changes to the default one.
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   dialTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       idleConnTimeout,
+			TLSHandshakeTimeout:   tlsHandshakeTimeout,
+			ResponseHeaderTimeout: responseHeaderTimeout,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
+// listBucketResult is the response of ListObjectsV2.
+type listBucketResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// errorResponse is the response of the failed requests.
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// ListObjects returns the objects of the bucket whose key starts with the
+// prefix, sorted by key.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.get(ctx, bucket, "", query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the objects of the bucket %q: %w", bucket, err)
+		}
+		result := &listBucketResult{}
+		err = xml.NewDecoder(resp.Body).Decode(result)
+		closeBody(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the objects of the bucket %q: %w", bucket, err)
+		}
+		for _, o := range result.Contents {
+			objects = append(objects, Object{Key: o.Key, ETag: strings.Trim(o.ETag, `"`), Size: o.Size})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	return objects, nil
+}
+
+// GetObject returns the content of the object of the bucket. The caller must
+// close it.
+func (c *Client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, bucket, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the object %q of the bucket %q: %w", key, bucket, err)
+	}
+	return resp.Body, nil
+}
+
+// get sends a GET request for the object of the bucket, or the bucket itself
+// if the key is empty, and returns the response if it succeeded.
+func (c *Client) get(ctx context.Context, bucket, key string, query url.Values) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.credentials.AccessKeyID != "" {
+		c.sign(req, c.now())
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer closeBody(resp)
+		errResp := &errorResponse{}
+		if err := xml.NewDecoder(resp.Body).Decode(errResp); err != nil || errResp.Code == "" {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return nil, fmt.Errorf("unexpected status %s: %s: %s", resp.Status, errResp.Code, errResp.Message)
+	}
+	return resp, nil
+}
+
+// sign signs the GET request with AWS Signature Version 4. The session token
+// of temporary security credentials is sent and signed in the
+// x-amz-security-token header.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (c *Client) sign(req *http.Request, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", emptyPayloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	// The canonical headers are sorted by name.
+	headers := []string{
+		"host:" + host,
+		"x-amz-content-sha256:" + emptyPayloadHash,
+		"x-amz-date:" + amzDate,
+	}
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	if token := c.credentials.SessionToken; token != "" {
+		req.Header.Set("x-amz-security-token", token)
+		headers = append(headers, "x-amz-security-token:"+token)
+		signedHeaders += ";x-amz-security-token"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		strings.Join(headers, "\n"),
+		"",
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, c.region, "s3", "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery returns the query string with the parameters sorted by name,
+// and the names and values URI-encoded as required by Signature Version 4.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			params = append(params, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent-encodes every byte of the string except the unreserved
+// characters, and the slashes unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// closeBody drains and closes the body of the response, so the connection can
+// be reused.
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/util"
+)
+
+// Fetch downloads the objects of the bucket under the prefix into a new
+// directory under the root directory, and points the rev symlink to it. The
+// prefix is a directory, with or without a trailing slash. The objects are not
+// downloaded again if none of them changed since the last fetch.
+//
+// It is an error if no object is under the prefix, which is most likely a
+// mistake in the prefix.
+func Fetch(ctx context.Context, c *Client, bucket, prefix, root, rev string) error {
+	prefix = dirPrefix(prefix)
+	objects, err := c.ListObjects(ctx, bucket, prefix)
+	if err != nil {
+		return err
+	}
+	files := sourceFiles(objects, prefix)
+	if len(files) == 0 {
+		return fmt.Errorf("no objects of the bucket %q are under the prefix %q", bucket, prefix)
+	}
+	digest := filesDigest(files)
+	destDir := filepath.Join(root, digest)
+
+	linkPath := filepath.Join(root, rev)
+	oldDir, err := filepath.EvalSymlinks(linkPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to evaluate the symbolic path %q to the source: %w", linkPath, err)
+	}
+	if oldDir == destDir {
+		klog.V(4).Infof("no update required with the same digest %q", digest)
+		return nil
+	}
+
+	// Remove the leftovers of a previous attempt which failed halfway.
+	if err := os.RemoveAll(destDir); err != nil {
+		return fmt.Errorf("failed to clean up the directory %q: %w", destDir, err)
+	}
+	if err := os.MkdirAll(destDir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", destDir, err)
+	}
+	for _, f := range files {
+		if err := download(ctx, c, bucket, f, destDir); err != nil {
+			return err
+		}
+	}
+
+	klog.Infof("fetched %d objects of the bucket %q with digest %q", len(files), bucket, digest)
+	return util.UpdateSymlink(root, linkPath, destDir, oldDir)
+}
+
+// file is an object of the bucket which is a file of the source.
+type file struct {
+	Object
+	// path is the path of the file relative to the root of the source.
+	path string
+}
+
+// dirPrefix returns the prefix with a trailing slash, so that it only matches
+// the objects under the directory and not the ones whose name starts with it.
+func dirPrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
+
+// sourceFiles returns the files of the source among the objects under the
+// prefix, which ends with a slash unless it is empty, skipping the objects which
+// only mark directories.
+func sourceFiles(objects []Object, prefix string) []file {
+	var files []file
+	for _, o := range objects {
+		if !strings.HasPrefix(o.Key, prefix) {
+			continue
+		}
+		path := strings.TrimPrefix(o.Key, prefix)
+		if path == "" || strings.HasSuffix(path, "/") {
+			continue
+		}
+		files = append(files, file{Object: o, path: path})
+	}
+	return files
+}
+
+// filesDigest returns the sha256 digest of the paths and ETags of the files, which
+// changes whenever a file is added, removed or modified.
+func filesDigest(files []file) string {
+	h := sha256.New()
+	for _, f := range files {
+		_, _ = fmt.Fprintf(h, "%s %s\n", f.path, f.ETag)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// download writes the content of the file into the directory.
+func download(ctx context.Context, c *Client, bucket string, f file, dir string) error {
+	path := filepath.Join(dir, filepath.FromSlash(f.path))
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return fmt.Errorf("illegal key %q in the bucket %q", f.Key, bucket)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	body, err := c.GetObject(ctx, bucket, f.Key)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := body.Close(); err != nil {
+			klog.Warningf("failed to close the object %q: %v", f.Key, err)
+		}
+	}()
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, body); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to download the object %q of the bucket %q: %w", f.Key, bucket, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bucket
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// readTree returns the contents of the regular files under the directory.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	result := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		result[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func revDir(t *testing.T, root string) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, "rev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFetch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		objects map[string]string
+		prefix  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "whole bucket",
+			objects: map[string]string{
+				"namespaces/bookstore/ns.yaml": "kind: Namespace",
+				"cluster/cr.yaml":              "kind: ClusterRole",
+			},
+			want: map[string]string{
+				"namespaces/bookstore/ns.yaml": "kind: Namespace",
+				"cluster/cr.yaml":              "kind: ClusterRole",
+			},
+		},
+		{
+			name: "prefix with trailing slash",
+			objects: map[string]string{
+				"prod/ns.yaml":    "kind: Namespace",
+				"staging/ns.yaml": "staging",
+			},
+			prefix: "prod/",
+			want:   map[string]string{"ns.yaml": "kind: Namespace"},
+		},
+		{
+			name: "prefix without trailing slash",
+			objects: map[string]string{
+				"prod/ns.yaml":       "kind: Namespace",
+				"production/ns.yaml": "production",
+			},
+			prefix: "prod",
+			want:   map[string]string{"ns.yaml": "kind: Namespace"},
+		},
+		{
+			name: "directory markers",
+			objects: map[string]string{
+				"prod/":        "",
+				"prod/a/":      "",
+				"prod/ns.yaml": "kind: Namespace",
+			},
+			prefix: "prod/",
+			want:   map[string]string{"ns.yaml": "kind: Namespace"},
+		},
+		{
+			name:    "empty prefix",
+			objects: map[string]string{"prod/ns.yaml": "kind: Namespace"},
+			prefix:  "staging/",
+			wantErr: true,
+		},
+		{
+			name:    "path traversal",
+			objects: map[string]string{"../escape.yaml": "kind: Namespace"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, server := newFakeS3(t, "configs", tc.objects)
+			c, err := NewClient(server.URL, "", Credentials{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := t.TempDir()
+			err = Fetch(context.Background(), c, "configs", tc.prefix, root, "rev")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Fetch() got no error, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, readTree(t, revDir(t, root))); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFetchUpdates(t *testing.T) {
+	fake, server := newFakeS3(t, "configs", map[string]string{"ns.yaml": "v1"})
+	c, err := NewClient(server.URL, "", Credentials{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	ctx := context.Background()
+
+	if err := Fetch(ctx, c, "configs", "", root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	first := revDir(t, root)
+
+	// Nothing is downloaded again while the ETags don't change.
+	if err := Fetch(ctx, c, "configs", "", root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	if got := revDir(t, root); got != first {
+		t.Errorf("got rev %q after fetching unchanged objects, want %q", got, first)
+	}
+	if diff := cmp.Diff([]string{"/configs/ns.yaml"}, fake.gets()); diff != "" {
+		t.Errorf("unexpected GetObject requests: %s", diff)
+	}
+
+	fake.set("ns.yaml", "v2")
+	if err := Fetch(ctx, c, "configs", "", root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	second := revDir(t, root)
+	if second == first {
+		t.Fatalf("got the same rev %q after an object changed", second)
+	}
+	if diff := cmp.Diff(map[string]string{"ns.yaml": "v2"}, readTree(t, second)); diff != "" {
+		t.Error(diff)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("got the previous source %q still present, error %v", first, err)
+	}
+
+	fake.set("cr.yaml", "kind: ClusterRole")
+	if err := Fetch(ctx, c, "configs", "", root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	if got := revDir(t, root); got == second {
+		t.Errorf("got the same rev %q after an object was added", got)
+	}
+}
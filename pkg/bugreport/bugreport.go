@@ -21,7 +21,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -53,6 +52,7 @@ import (
 	"kpt.dev/configsync/cmd/nomos/version"
 	"kpt.dev/configsync/pkg/api/configmanagement"
 	"kpt.dev/configsync/pkg/client/restconfig"
+	"kpt.dev/configsync/pkg/kinds"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
@@ -90,12 +90,15 @@ type BugReporter struct {
 	outFile       *os.File
 	writer        *zip.Writer
 	name          string
+	opts          Options
+	redactor      *Redactor
+	manifest      *Manifest
 	ErrorList     []error
 	WritingErrors []error
 }
 
 // New creates a new BugReport
-func New(ctx context.Context, cfg *rest.Config) (*BugReporter, error) {
+func New(ctx context.Context, cfg *rest.Config, opts Options) (*BugReporter, error) {
 	cs, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		return nil, err
@@ -136,11 +139,15 @@ func New(ctx context.Context, cfg *rest.Config) (*BugReporter, error) {
 		util.MonoRepoNotice(os.Stdout, currentk8sContext)
 	}
 
+	redactor := NewRedactor(opts.RedactPatterns)
 	return &BugReporter{
 		client:        c,
 		clientSet:     cs,
 		cm:            cm,
 		k8sContext:    currentk8sContext,
+		opts:          opts,
+		redactor:      redactor,
+		manifest:      newManifest(currentk8sContext, opts, redactor),
 		ErrorList:     errorList,
 		WritingErrors: []error{},
 	}, nil
@@ -219,7 +226,7 @@ func (b *BugReporter) FetchLogSources(ctx context.Context) []Readable {
 	}
 
 	// Convert logSources to Readables
-	toBeRead, errs := toBeLogged.convertLogSourcesToReadables(ctx, b.clientSet)
+	toBeRead, errs := toBeLogged.convertLogSourcesToReadables(ctx, b.clientSet, b.opts.podLogOptions())
 	b.ErrorList = append(b.ErrorList, errs...)
 
 	return toBeRead
@@ -266,6 +273,15 @@ func (b *BugReporter) logSourcesForNamespace(ctx context.Context, name string, l
 		return nil, wrap(err, "failed to list pods for namespace %v", name)
 	}
 
+	// Only keep the logs of the reconcilers in the scope of the report.
+	var inScope []corev1.Pod
+	for _, p := range pods.Items {
+		if b.opts.podInScope(p) {
+			inScope = append(inScope, p)
+		}
+	}
+	pods.Items = inScope
+
 	return assembleLogSources(*ns, *pods), nil
 }
 
@@ -399,6 +415,9 @@ func (b *BugReporter) FetchResources(ctx context.Context) []Readable {
 
 	var namespacedResourceToReadables = func(u *unstructured.UnstructuredList, _ string) (r []Readable) {
 		for _, o := range u.Items {
+			if isSyncOrResourceGroup(o.GroupVersionKind()) && !b.opts.syncInScope(o.GetNamespace(), o.GetName()) {
+				continue
+			}
 			r = b.appendPrettyJSON(r, pathToNamespacedResource(o.GetNamespace(), o.GetKind(), o.GetName()), o)
 		}
 		return r
@@ -425,6 +444,17 @@ func (b *BugReporter) FetchResources(ctx context.Context) []Readable {
 	return rd
 }
 
+// isSyncOrResourceGroup returns true for RootSyncs, RepoSyncs and
+// ResourceGroups, which are limited to the scope of the report.
+func isSyncOrResourceGroup(gvk schema.GroupVersionKind) bool {
+	switch gvk {
+	case kinds.RootSyncV1Beta1(), kinds.RepoSyncV1Beta1(), live.ResourceGroupGVK:
+		return true
+	default:
+		return false
+	}
+}
+
 func pathToNamespacedResource(namespace, kind, name string) string {
 	return path.Join(Namespace, namespace, kind+"-"+name)
 }
@@ -479,23 +509,10 @@ func (b *BugReporter) AddNomosStatusToZip(ctx context.Context) {
 
 // copyToZip copies the content from the input file to the zip file.
 func (b *BugReporter) copyToZip(inputFile *os.File, zipFileName string) error {
-	baseName := filepath.Base(b.name)
-	dirName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
-	fileName := filepath.FromSlash(filepath.Join(dirName, zipFileName) + ".txt")
-	zipFile, err := b.writer.Create(fileName)
-	if err != nil {
-		e := fmt.Errorf("failed to create file %v inside zip: %v", fileName, err)
-		return e
-	}
-	if _, err = io.Copy(zipFile, inputFile); err != nil {
-		return fmt.Errorf("failed to copy file %s to zip %s: %v", inputFile.Name(), fileName, err)
-	}
-	if err = inputFile.Close(); err != nil {
-		klog.Errorf("failed to close file %s: %v", inputFile.Name(), err)
-	}
-
-	fmt.Println("Wrote file " + fileName)
-	return nil
+	return b.writeReadableToZip(Readable{
+		Name:       zipFileName,
+		ReadCloser: inputFile,
+	})
 }
 
 // AddNomosVersionToZip writes `nomos version` to bugreport zip file
@@ -521,10 +538,27 @@ func getReportName() string {
 	return nameWithPath
 }
 
-func (b *BugReporter) writeReadableToZip(readable Readable) error {
+// zipFileName returns the path of the file with the specified name in the zip
+// file.
+func (b *BugReporter) zipFileName(name string) string {
 	baseName := filepath.Base(b.name)
 	dirName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
-	fileName := filepath.FromSlash(filepath.Join(dirName, readable.Name) + ".txt")
+	return filepath.FromSlash(filepath.Join(dirName, name))
+}
+
+// writeReadableToZip redacts the content of the readable, writes it to the zip
+// file and adds it to the manifest.
+func (b *BugReporter) writeReadableToZip(readable Readable) error {
+	fileName := b.zipFileName(readable.Name) + ".txt"
+	content, err := ioutil.ReadAll(readable.ReadCloser)
+	if closeErr := readable.Close(); closeErr != nil {
+		klog.Errorf("failed to close the reader of file %s: %v", fileName, closeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file %v: %v", fileName, err)
+	}
+	content, redactions := b.redactor.Redact(content)
+
 	f, err := b.writer.Create(fileName)
 	if err != nil {
 		e := fmt.Errorf("failed to create file %v inside zip: %v", fileName, err)
@@ -532,7 +566,7 @@ func (b *BugReporter) writeReadableToZip(readable Readable) error {
 	}
 
 	w := bufio.NewWriter(f)
-	_, err = w.ReadFrom(readable.ReadCloser)
+	_, err = w.Write(content)
 	if err != nil {
 		e := fmt.Errorf("failed to write file %v to zip: %v", fileName, err)
 		return e
@@ -543,6 +577,7 @@ func (b *BugReporter) writeReadableToZip(readable Readable) error {
 		e := fmt.Errorf("failed to flush writer to zip for file %v:i %v", fileName, err)
 		return e
 	}
+	b.manifest.Files = append(b.manifest.Files, ManifestFile{Name: fileName, Redactions: redactions})
 
 	fmt.Println("Wrote file " + fileName)
 
@@ -562,9 +597,35 @@ func (b *BugReporter) WriteRawInZip(toBeRead []Readable) {
 
 }
 
+// writeManifest writes the manifest listing the files in the zip file, and
+// what was redacted from them.
+func (b *BugReporter) writeManifest() error {
+	for _, e := range append(append([]error{}, b.ErrorList...), b.WritingErrors...) {
+		b.manifest.Errors = append(b.manifest.Errors, e.Error())
+	}
+	data, err := yaml.Marshal(b.manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the manifest: %v", err)
+	}
+	fileName := b.zipFileName(manifestName)
+	f, err := b.writer.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create file %v inside zip: %v", fileName, err)
+	}
+	if _, err = f.Write(data); err != nil {
+		return fmt.Errorf("failed to write file %v to zip: %v", fileName, err)
+	}
+	fmt.Println("Wrote file " + fileName)
+	return nil
+}
+
 // Close closes all file streams
 func (b *BugReporter) Close() {
 
+	if err := b.writeManifest(); err != nil {
+		b.WritingErrors = append(b.WritingErrors, err)
+	}
+
 	err := b.writer.Close()
 	if err != nil {
 		e := fmt.Errorf("failed to close zip writer: %v", err)
@@ -107,7 +107,7 @@ type mockLogSource struct {
 var _ convertibleLogSourceIdentifiers = &mockLogSource{}
 
 // fetchRcForLogSource implements convertibleLogSourceIdentifiers.
-func (m *mockLogSource) fetchRcForLogSource(_ context.Context, _ coreClient) (io.ReadCloser, error) {
+func (m *mockLogSource) fetchRcForLogSource(_ context.Context, _ coreClient, _ v1.PodLogOptions) (io.ReadCloser, error) {
 	if m.returnError {
 		return nil, fmt.Errorf("failed to get RC")
 	}
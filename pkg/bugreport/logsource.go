@@ -32,7 +32,8 @@ func (l *logSource) pathName() string {
 	return path.Join(Namespace, l.ns.Name, l.pod.Name, l.cont.Name)
 }
 
-func (l *logSource) fetchRcForLogSource(ctx context.Context, cs coreClient) (io.ReadCloser, error) {
-	options := v1.PodLogOptions{Timestamps: true, Container: l.cont.Name}
+func (l *logSource) fetchRcForLogSource(ctx context.Context, cs coreClient, options v1.PodLogOptions) (io.ReadCloser, error) {
+	options.Timestamps = true
+	options.Container = l.cont.Name
 	return cs.CoreV1().Pods(l.ns.Name).GetLogs(l.pod.Name, &options).Stream(ctx)
 }
@@ -18,22 +18,26 @@ import (
 	"context"
 	"fmt"
 	"io"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 // convertibleLogSourceIdentifiers is an interface for mocking the logSource type in unit tests
 type convertibleLogSourceIdentifiers interface {
-	fetchRcForLogSource(context.Context, coreClient) (io.ReadCloser, error)
+	fetchRcForLogSource(context.Context, coreClient, v1.PodLogOptions) (io.ReadCloser, error)
 	pathName() string
 }
 
 type logSources []convertibleLogSourceIdentifiers
 
-func (ls logSources) convertLogSourcesToReadables(ctx context.Context, cs coreClient) ([]Readable, []error) {
+// convertLogSourcesToReadables returns a Readable for the logs of each source.
+// The options limit the logs, e.g. to a time window.
+func (ls logSources) convertLogSourcesToReadables(ctx context.Context, cs coreClient, options v1.PodLogOptions) ([]Readable, []error) {
 	var rs []Readable
 	var errorList []error
 
 	for _, l := range ls {
-		rc, err := l.fetchRcForLogSource(ctx, cs)
+		rc, err := l.fetchRcForLogSource(ctx, cs, options)
 		if err != nil {
 			e := fmt.Errorf("failed to create reader for logSource: %v", err)
 			errorList = append(errorList, e)
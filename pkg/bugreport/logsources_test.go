@@ -19,6 +19,7 @@ import (
 	"sort"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -130,7 +131,7 @@ func TestConvertLogSourcesToReadables(t *testing.T) {
 		client := fake.NewSimpleClientset()
 
 		t.Run(test.name, func(t *testing.T) {
-			output, errorList := test.logSources.convertLogSourcesToReadables(context.Background(), client)
+			output, errorList := test.logSources.convertLogSourcesToReadables(context.Background(), client, v1.PodLogOptions{})
 
 			if len(errorList) != test.numErrors {
 				t.Errorf("Expected %v errors but received %v.", test.numErrors, len(errorList))
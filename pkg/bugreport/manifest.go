@@ -0,0 +1,75 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bugreport
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// manifestName is the name of the manifest file in the bug report.
+const manifestName = "manifest.yaml"
+
+// Manifest lists what was collected in the bug report, and what was redacted.
+type Manifest struct {
+	// CreationTimestamp is when the bug report was created.
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// Context is the kubeconfig context of the cluster.
+	Context string `json:"context"`
+	// Scope lists the RootSyncs, RepoSyncs and log time window the report is
+	// limited to, if any.
+	Scope ManifestScope `json:"scope,omitempty"`
+	// RedactionRules are the rules applied to every file.
+	RedactionRules []string `json:"redactionRules"`
+	// Files are the files in the bug report.
+	Files []ManifestFile `json:"files"`
+	// Errors are the errors which occurred while collecting the report.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ManifestScope is the scope of the bug report.
+type ManifestScope struct {
+	RootSyncs     []string     `json:"rootSyncs,omitempty"`
+	RepoSyncs     []string     `json:"repoSyncs,omitempty"`
+	LogsSince     string       `json:"logsSince,omitempty"`
+	LogsSinceTime *metav1.Time `json:"logsSinceTime,omitempty"`
+}
+
+// ManifestFile is a file in the bug report.
+type ManifestFile struct {
+	// Name is the path of the file in the zip file.
+	Name string `json:"name"`
+	// Redactions is the number of redactions made by each rule, if any.
+	Redactions map[string]int `json:"redactions,omitempty"`
+}
+
+// newManifest returns the manifest of a bug report collected with the
+// specified options.
+func newManifest(k8sContext string, opts Options, redactor *Redactor) *Manifest {
+	m := &Manifest{
+		CreationTimestamp: metav1.Now(),
+		Context:           k8sContext,
+		Scope:             ManifestScope{RootSyncs: opts.RootSyncs},
+		RedactionRules:    redactor.Rules(),
+	}
+	for _, rs := range opts.RepoSyncs {
+		m.Scope.RepoSyncs = append(m.Scope.RepoSyncs, rs.String())
+	}
+	if opts.LogsSince > 0 {
+		m.Scope.LogsSince = opts.LogsSince.String()
+	} else if !opts.LogsSinceTime.IsZero() {
+		m.Scope.LogsSinceTime = &metav1.Time{Time: opts.LogsSinceTime}
+	}
+	return m
+}
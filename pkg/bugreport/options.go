@@ -0,0 +1,118 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bugreport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+)
+
+// Options configures what is collected in the bug report, and what is
+// redacted from it.
+type Options struct {
+	// RootSyncs limits the RootSyncs, ResourceGroups and reconciler logs in the
+	// report to those of the specified RootSyncs.
+	RootSyncs []string
+	// RepoSyncs limits the RepoSyncs, ResourceGroups and reconciler logs in the
+	// report to those of the specified RepoSyncs.
+	RepoSyncs []types.NamespacedName
+	// LogsSince limits the logs to those newer than a relative duration.
+	LogsSince time.Duration
+	// LogsSinceTime limits the logs to those after an absolute time.
+	LogsSinceTime time.Time
+	// RedactPatterns are the regular expressions whose matches are redacted,
+	// in addition to the built-in redaction rules.
+	RedactPatterns []*regexp.Regexp
+}
+
+// ParseRepoSync parses a RepoSync formatted as `<namespace>/<name>`.
+func ParseRepoSync(s string) (types.NamespacedName, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("invalid RepoSync %q, must be formatted as <namespace>/<name>", s)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// scoped returns true if the report is limited to specific RootSyncs and
+// RepoSyncs.
+func (o Options) scoped() bool {
+	return len(o.RootSyncs) > 0 || len(o.RepoSyncs) > 0
+}
+
+// syncInScope returns true if the RootSync, RepoSync or ResourceGroup with the
+// specified namespace and name is in the scope of the report. RootSyncs and
+// their ResourceGroups are in the config-management-system namespace, while
+// RepoSyncs and their ResourceGroups are in the namespace they sync to.
+func (o Options) syncInScope(namespace, name string) bool {
+	if !o.scoped() {
+		return true
+	}
+	if namespace == configmanagement.ControllerNamespace {
+		for _, rs := range o.RootSyncs {
+			if rs == name {
+				return true
+			}
+		}
+	}
+	for _, rs := range o.RepoSyncs {
+		if rs.Namespace == namespace && rs.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// podInScope returns true if the logs of the Pod are in the scope of the
+// report. Only reconciler Pods are scoped, the other Pods are always in scope.
+func (o Options) podInScope(pod v1.Pod) bool {
+	reconcilerName, found := pod.Labels[metadata.ReconcilerLabel]
+	if !o.scoped() || !found {
+		return true
+	}
+	for _, rs := range o.RootSyncs {
+		if core.RootReconcilerName(rs) == reconcilerName {
+			return true
+		}
+	}
+	for _, rs := range o.RepoSyncs {
+		if core.NsReconcilerName(rs.Namespace, rs.Name) == reconcilerName {
+			return true
+		}
+	}
+	return false
+}
+
+// podLogOptions returns the options limiting the logs to the time window of
+// the report.
+func (o Options) podLogOptions() v1.PodLogOptions {
+	opts := v1.PodLogOptions{}
+	if o.LogsSince > 0 {
+		seconds := int64(o.LogsSince.Seconds())
+		opts.SinceSeconds = &seconds
+	} else if !o.LogsSinceTime.IsZero() {
+		opts.SinceTime = &metav1.Time{Time: o.LogsSinceTime}
+	}
+	return opts
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bugreport
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestOptions_InScope(t *testing.T) {
+	scoped := Options{
+		RootSyncs: []string{"root-sync"},
+		RepoSyncs: []types.NamespacedName{{Namespace: "bookstore", Name: "repo-sync"}},
+	}
+	reconcilerPod := func(reconcilerName string) v1.Pod {
+		pod := fake.PodObject(reconcilerName+"-abc", nil)
+		pod.Labels = map[string]string{metadata.ReconcilerLabel: reconcilerName}
+		return *pod
+	}
+
+	testCases := []struct {
+		name      string
+		opts      Options
+		namespace string
+		syncName  string
+		pod       v1.Pod
+		want      bool
+	}{
+		{
+			name:      "unscoped",
+			opts:      Options{},
+			namespace: "shoestore",
+			syncName:  "repo-sync",
+			pod:       reconcilerPod(core.NsReconcilerName("shoestore", "repo-sync")),
+			want:      true,
+		},
+		{
+			name:      "scoped RootSync",
+			opts:      scoped,
+			namespace: configmanagement.ControllerNamespace,
+			syncName:  "root-sync",
+			pod:       reconcilerPod(core.RootReconcilerName("root-sync")),
+			want:      true,
+		},
+		{
+			name:      "scoped RepoSync",
+			opts:      scoped,
+			namespace: "bookstore",
+			syncName:  "repo-sync",
+			pod:       reconcilerPod(core.NsReconcilerName("bookstore", "repo-sync")),
+			want:      true,
+		},
+		{
+			name:      "RepoSync out of scope",
+			opts:      scoped,
+			namespace: "shoestore",
+			syncName:  "repo-sync",
+			pod:       reconcilerPod(core.NsReconcilerName("shoestore", "repo-sync")),
+			want:      false,
+		},
+		{
+			name:      "RootSync out of scope",
+			opts:      scoped,
+			namespace: configmanagement.ControllerNamespace,
+			syncName:  "other-sync",
+			pod:       reconcilerPod(core.RootReconcilerName("other-sync")),
+			want:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.opts.syncInScope(tc.namespace, tc.syncName); got != tc.want {
+				t.Errorf("syncInScope() = %v, want %v", got, tc.want)
+			}
+			if got := tc.opts.podInScope(tc.pod); got != tc.want {
+				t.Errorf("podInScope() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Pods which are not reconcilers are always in scope.
+	if !scoped.podInScope(*fake.PodObject("reconciler-manager-abc", nil)) {
+		t.Error("podInScope() = false for a non-reconciler Pod, want true")
+	}
+}
+
+func TestParseRepoSync(t *testing.T) {
+	got, err := ParseRepoSync("bookstore/repo-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (types.NamespacedName{Namespace: "bookstore", Name: "repo-sync"}); got != want {
+		t.Errorf("ParseRepoSync() = %v, want %v", got, want)
+	}
+	for _, invalid := range []string{"repo-sync", "/repo-sync", "bookstore/", "a/b/c"} {
+		if _, err := ParseRepoSync(invalid); err == nil {
+			t.Errorf("ParseRepoSync(%q) got nil error, want error", invalid)
+		}
+	}
+}
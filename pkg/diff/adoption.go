@@ -0,0 +1,53 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/metadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CanAdopt returns true if the adoption policy lets the reconciler update the
+// object on the cluster with its declaration, which sets the owning inventory
+// of the object to the inventory of the reconciler.
+//
+// It matches the inventory policy of the applier, so that the remediator does
+// not adopt the objects the applier does not adopt. If the policy is not
+// specified, the root reconcilers adopt every object, and the namespace
+// reconcilers only the objects which no inventory owns.
+func CanAdopt(scope declared.Scope, policy v1beta1.AdoptionPolicy, decl, actual client.Object) bool {
+	owner := core.GetAnnotation(actual, metadata.OwningInventoryKey)
+	if owner == core.GetAnnotation(decl, metadata.OwningInventoryKey) {
+		// The object is already in the inventory.
+		return true
+	}
+	if policy == "" {
+		policy = v1beta1.AdoptionPolicyAdoptIfUnmanaged
+		if scope == declared.RootReconciler {
+			policy = v1beta1.AdoptionPolicyAdopt
+		}
+	}
+	switch policy {
+	case v1beta1.AdoptionPolicyAdopt:
+		return true
+	case v1beta1.AdoptionPolicyAdoptIfUnmanaged:
+		return owner == ""
+	default:
+		return false
+	}
+}
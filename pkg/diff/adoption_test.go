@@ -0,0 +1,58 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"testing"
+
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCanAdopt(t *testing.T) {
+	decl := fake.RoleObject(core.Annotation(metadata.OwningInventoryKey, "bookstore_repo-sync"))
+	unmanaged := fake.RoleObject()
+	otherInventory := fake.RoleObject(core.Annotation(metadata.OwningInventoryKey, "other-inventory"))
+	inInventory := fake.RoleObject(core.Annotation(metadata.OwningInventoryKey, "bookstore_repo-sync"))
+
+	testCases := []struct {
+		name   string
+		scope  declared.Scope
+		policy v1beta1.AdoptionPolicy
+		actual client.Object
+		want   bool
+	}{
+		{name: "root default, other inventory", scope: declared.RootReconciler, actual: otherInventory, want: true},
+		{name: "namespace default, unmanaged", scope: "bookstore", actual: unmanaged, want: true},
+		{name: "namespace default, other inventory", scope: "bookstore", actual: otherInventory},
+		{name: "adopt, other inventory", scope: "bookstore", policy: v1beta1.AdoptionPolicyAdopt, actual: otherInventory, want: true},
+		{name: "adopt-if-unmanaged, unmanaged", scope: declared.RootReconciler, policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged, actual: unmanaged, want: true},
+		{name: "adopt-if-unmanaged, other inventory", scope: declared.RootReconciler, policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged, actual: otherInventory},
+		{name: "fail, unmanaged", scope: declared.RootReconciler, policy: v1beta1.AdoptionPolicyFail, actual: unmanaged},
+		{name: "fail, in inventory", scope: declared.RootReconciler, policy: v1beta1.AdoptionPolicyFail, actual: inInventory, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CanAdopt(tc.scope, tc.policy, decl, tc.actual); got != tc.want {
+				t.Errorf("CanAdopt() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
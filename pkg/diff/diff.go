@@ -22,6 +22,7 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/lifecycle"
@@ -71,7 +72,9 @@ type Diff struct {
 }
 
 // Operation returns the type of the difference between the repository and the API Server.
-func (d Diff) Operation(ctx context.Context, scope declared.Scope, syncName string) Operation {
+// The adoption policy determines whether the objects which are not in the
+// inventory of the reconciler yet are updated.
+func (d Diff) Operation(ctx context.Context, scope declared.Scope, syncName string, adoptionPolicy v1beta1.AdoptionPolicy) Operation {
 	switch {
 	case d.Declared != nil && d.Actual == nil:
 		// Create Branch.
@@ -84,7 +87,7 @@ func (d Diff) Operation(ctx context.Context, scope declared.Scope, syncName stri
 		//
 		// We have a declared and actual resource, so we need to figure out whether
 		// we update the resource.
-		return d.updateType(scope, syncName)
+		return d.updateType(scope, syncName, adoptionPolicy)
 	case d.Declared == nil && d.Actual != nil:
 		// Delete Branch.
 		//
@@ -118,7 +121,7 @@ func (d Diff) createType() Operation {
 	}
 }
 
-func (d Diff) updateType(scope declared.Scope, syncName string) Operation {
+func (d Diff) updateType(scope declared.Scope, syncName string, adoptionPolicy v1beta1.AdoptionPolicy) Operation {
 	// We don't need to check for owner references here since that would be a
 	// nomos vet error. Note that as-is, it is valid to declare something owned by
 	// another object, possible causing (and being surfaced as) a resource fight.
@@ -140,6 +143,12 @@ func (d Diff) updateType(scope declared.Scope, syncName string) Operation {
 			// on the cluster, we need to add it to the one in the cluster.
 			return NoOp
 		}
+		if !CanAdopt(scope, adoptionPolicy, d.Declared, d.Actual) {
+			// The adoption policy prevents taking over the object. The applier
+			// reports it.
+			klog.V(3).Infof("The adoption policy %q prevents adopting object %v", adoptionPolicy, core.GKNN(d.Actual))
+			return NoOp
+		}
 		return Update
 	case differ.ManagementEnabled(d.Declared) && !canManage:
 		// This reconciler can't manage this object but is erroneously being told to.
@@ -403,7 +403,7 @@ func TestDiffType(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			if d := cmp.Diff(tc.want, diff.Operation(ctx, scope, tc.syncName)); d != "" {
+			if d := cmp.Diff(tc.want, diff.Operation(ctx, scope, tc.syncName, "")); d != "" {
 				t.Fatal(d)
 			}
 		})
@@ -0,0 +1,69 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+// Reasons of the Events emitted on RootSyncs and RepoSyncs.
+const (
+	// ReasonSourceFetched means a new source commit was read successfully.
+	ReasonSourceFetched = "SourceFetched"
+	// ReasonSourceFailed means the source could not be read.
+	ReasonSourceFailed = "SourceFailed"
+	// ReasonRenderingSucceeded means the source was rendered successfully.
+	ReasonRenderingSucceeded = "RenderingSucceeded"
+	// ReasonRenderingFailed means the source could not be rendered.
+	ReasonRenderingFailed = "RenderingFailed"
+	// ReasonApplyStarted means the applier started applying the declared
+	// objects.
+	ReasonApplyStarted = "ApplyStarted"
+	// ReasonApplySucceeded means the applier applied all the declared objects
+	// without error.
+	ReasonApplySucceeded = "ApplySucceeded"
+	// ReasonApplyFailed means the applier finished with errors.
+	ReasonApplyFailed = "ApplyFailed"
+	// ReasonPruned means the applier deleted objects which are no longer
+	// declared.
+	ReasonPruned = "Pruned"
+	// ReasonManagementConflict means another RootSync or RepoSync manages an
+	// object declared in the source.
+	ReasonManagementConflict = "ManagementConflict"
+	// ReasonAdopting means the applier is taking over objects which already
+	// exist on the cluster, as allowed by the adoption policy.
+	ReasonAdopting = "Adopting"
+	// ReasonAdoptionDryRun means the adoption policy prevents the applier from
+	// taking over objects which already exist on the cluster, and lists the
+	// objects the adopt policy would take over.
+	ReasonAdoptionDryRun = "AdoptionDryRun"
+	// ReasonFinalizing means the finalizer started deleting managed objects.
+	ReasonFinalizing = "Finalizing"
+	// ReasonFinalized means the finalizer deleted all the managed objects.
+	ReasonFinalized = "Finalized"
+	// ReasonFinalizeFailed means the finalizer failed to delete the managed
+	// objects.
+	ReasonFinalizeFailed = "FinalizeFailed"
+)
+
+// Reasons of the Events emitted on the DriftExemptions.
+const (
+	// ReasonExemptionUsed means the admission webhook allowed a request which
+	// would otherwise have been denied, because of a drift exemption.
+	ReasonExemptionUsed = "ExemptionUsed"
+	// ReasonDriftNotReverted means the remediator did not revert the drift of
+	// a managed object, because of a drift exemption.
+	ReasonDriftNotReverted = "DriftNotReverted"
+	// ReasonDeclarationNotApplied means the applier applied the current state
+	// of a managed object instead of its declaration, because of a drift
+	// exemption.
+	ReasonDeclarationNotApplied = "DeclarationNotApplied"
+)
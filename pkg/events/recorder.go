@@ -0,0 +1,132 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// burstSize is the number of Events the reconciler can emit on its
+	// RootSync or RepoSync before being rate-limited.
+	burstSize = 25
+	// refillQPS is the rate at which the reconciler is allowed to emit
+	// Events on its RootSync or RepoSync once the burst is used up.
+	// One Event every 30 seconds.
+	refillQPS = 1.0 / 30
+)
+
+// NewEventRecorder returns an EventRecorder that writes Events to the API
+// server on behalf of the specified component.
+//
+// Events about the same object are rate-limited and similar Events are
+// aggregated, to avoid spamming the API server.
+func NewEventRecorder(cfg *rest.Config, component string) (record.EventRecorder, error) {
+	coreClient, err := typedcorev1.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		BurstSize: burstSize,
+		QPS:       refillQPS,
+	})
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: coreClient.Events("")})
+	return broadcaster.NewRecorder(core.Scheme, corev1.EventSource{Component: component}), nil
+}
+
+// Recorder records Events about the sync lifecycle on the RootSync or RepoSync
+// being reconciled, so they show up in `kubectl describe`.
+//
+// A nil Recorder drops all Events.
+type Recorder struct {
+	recorder record.EventRecorder
+	reader   client.Reader
+	scope    declared.Scope
+	syncName string
+
+	// mux prevents concurrent lookups of the RSync object.
+	mux sync.Mutex
+	// syncObj is the RootSync or RepoSync, cached after the first successful
+	// lookup so the Events reference its UID.
+	syncObj client.Object
+}
+
+// NewRecorder returns a Recorder that emits Events on the RootSync or RepoSync
+// with the specified scope and name.
+func NewRecorder(recorder record.EventRecorder, reader client.Reader, scope declared.Scope, syncName string) *Recorder {
+	return &Recorder{
+		recorder: recorder,
+		reader:   reader,
+		scope:    scope,
+		syncName: syncName,
+	}
+}
+
+// Eventf emits an Event on the RootSync or RepoSync.
+func (r *Recorder) Eventf(ctx context.Context, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+	obj, err := r.getSyncObject(ctx)
+	if err != nil {
+		klog.Warningf("Failed to record %s Event %q: %v", eventtype, reason, err)
+		return
+	}
+	r.recorder.Eventf(obj, eventtype, reason, messageFmt, args...)
+}
+
+// ObjectEventf emits an Event on the specified RootSync or RepoSync object.
+// Use this instead of Eventf when the caller already has the object.
+func (r *Recorder) ObjectEventf(obj runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+	r.recorder.Eventf(obj, eventtype, reason, messageFmt, args...)
+}
+
+func (r *Recorder) getSyncObject(ctx context.Context) (client.Object, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.syncObj != nil {
+		return r.syncObj, nil
+	}
+	var obj client.Object
+	key := client.ObjectKey{Name: r.syncName}
+	if r.scope == declared.RootReconciler {
+		obj = &v1beta1.RootSync{}
+		key.Namespace = configmanagement.ControllerNamespace
+	} else {
+		obj = &v1beta1.RepoSync{}
+		key.Namespace = string(r.scope)
+	}
+	if err := r.reader.Get(ctx, key, obj); err != nil {
+		return nil, err
+	}
+	r.syncObj = obj
+	return obj, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRecorderEventf(t *testing.T) {
+	testCases := []struct {
+		name       string
+		scope      declared.Scope
+		syncName   string
+		objs       []client.Object
+		wantEvents []string
+	}{
+		{
+			name:     "RootSync exists",
+			scope:    declared.RootReconciler,
+			syncName: "root-sync",
+			objs:     []client.Object{fake.RootSyncObjectV1Beta1("root-sync")},
+			wantEvents: []string{
+				"Normal ApplyStarted Applying 3 objects",
+			},
+		},
+		{
+			name:     "RepoSync exists",
+			scope:    "bookstore",
+			syncName: "repo-sync",
+			objs:     []client.Object{fake.RepoSyncObjectV1Beta1("bookstore", "repo-sync")},
+			wantEvents: []string{
+				"Normal ApplyStarted Applying 3 objects",
+			},
+		},
+		{
+			name:     "RSync not found",
+			scope:    declared.RootReconciler,
+			syncName: "root-sync",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeRecorder := record.NewFakeRecorder(10)
+			r := NewRecorder(fakeRecorder, syncertest.NewClient(t, core.Scheme, tc.objs...), tc.scope, tc.syncName)
+			r.Eventf(context.Background(), corev1.EventTypeNormal, ReasonApplyStarted, "Applying %d objects", 3)
+			close(fakeRecorder.Events)
+
+			var gotEvents []string
+			for e := range fakeRecorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			if diff := cmp.Diff(tc.wantEvents, gotEvents); diff != "" {
+				t.Errorf("unexpected events (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	// A nil Recorder drops all Events without panicking.
+	r.Eventf(context.Background(), corev1.EventTypeNormal, ReasonApplyStarted, "Applying %d objects", 3)
+	r.ObjectEventf(fake.RootSyncObjectV1Beta1("root-sync"), corev1.EventTypeNormal, ReasonFinalized, "Deleted managed objects")
+}
@@ -0,0 +1,320 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exemption implements break-glass exemptions from drift prevention.
+//
+// Exemptions are declared by cluster administrators as cluster-scoped
+// DriftExemption objects:
+//
+//	apiVersion: configsync.gke.io/v1beta1
+//	kind: DriftExemption
+//	metadata:
+//	  name: outage-1234
+//	spec:
+//	  reason: Mitigate outage 1234
+//	  users:
+//	  - alice@acme.com
+//	  groups:
+//	  - oncall@acme.com
+//	  serviceAccounts:
+//	  - namespace: kube-system
+//	    name: break-glass
+//	  objects:
+//	  - group: apps
+//	    kind: Deployment
+//	    namespace: bookstore
+//	    labelSelector:
+//	      matchLabels:
+//	        app: frontend
+//	  expires: "2022-06-01T18:00:00Z"
+//
+// Until it expires, the admission webhook allows the listed users, groups and
+// service accounts to modify the selected managed objects, the remediator does
+// not revert the drift of the selected objects, and the applier keeps the
+// current values of the fields it manages instead of applying their
+// declaration. Every use is recorded as an Event on the DriftExemption. Once the exemption expires, the remediator
+// reverts the drift of the objects it did not revert.
+//
+// The reconciler-manager allows every reconciler to read the DriftExemptions.
+package exemption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// refreshPeriod is how long the exemptions are cached before being listed
+// again.
+const refreshPeriod = 10 * time.Second
+
+// Exemption is a valid DriftExemption.
+type Exemption struct {
+	v1beta1.DriftExemption
+}
+
+// FromDriftExemptions returns the valid exemptions, and the errors of the
+// invalid ones. The schema of the DriftExemption API validates most fields,
+// but not the ones which depend on each other.
+func FromDriftExemptions(des []v1beta1.DriftExemption) ([]Exemption, []error) {
+	var exemptions []Exemption
+	var errs []error
+	for _, de := range des {
+		e := Exemption{DriftExemption: de}
+		if err := e.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid DriftExemption %q: %w", e.Name, err))
+			continue
+		}
+		exemptions = append(exemptions, e)
+	}
+	return exemptions, errs
+}
+
+func (e Exemption) validate() error {
+	if len(e.Spec.Users) == 0 && len(e.Spec.Groups) == 0 && len(e.Spec.ServiceAccounts) == 0 {
+		return fmt.Errorf("at least one of users, groups and serviceAccounts must be set")
+	}
+	if len(e.Spec.Objects) == 0 {
+		return fmt.Errorf("objects must be set")
+	}
+	for _, o := range e.Spec.Objects {
+		if o.LabelSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(o.LabelSelector); err != nil {
+				return err
+			}
+		}
+	}
+	if e.Spec.Expires.IsZero() {
+		return fmt.Errorf("expires must be set")
+	}
+	return nil
+}
+
+// Expires returns when the exemption stops being honored.
+func (e Exemption) Expires() time.Time {
+	return e.Spec.Expires.Time
+}
+
+// Active returns true if the exemption has not expired at the time.
+func (e Exemption) Active(now time.Time) bool {
+	return now.Before(e.Expires())
+}
+
+// Grants returns true if the exemption applies to the user.
+func (e Exemption) Grants(user authenticationv1.UserInfo) bool {
+	for _, u := range e.Spec.Users {
+		if u == user.Username {
+			return true
+		}
+	}
+	for _, sa := range e.Spec.ServiceAccounts {
+		if fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name) == user.Username {
+			return true
+		}
+	}
+	for _, g := range e.Spec.Groups {
+		for _, ug := range user.Groups {
+			if g == ug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Selects returns true if the exemption applies to the object.
+func (e Exemption) Selects(obj client.Object) bool {
+	for _, o := range e.Spec.Objects {
+		if selects(o, obj) {
+			return true
+		}
+	}
+	return false
+}
+
+func selects(s v1beta1.DriftExemptionSelector, obj client.Object) bool {
+	gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+	if s.Group != "" && s.Group != gk.Group {
+		return false
+	}
+	if s.Kind != "" && s.Kind != gk.Kind {
+		return false
+	}
+	if s.Namespace != "" && s.Namespace != obj.GetNamespace() {
+		return false
+	}
+	if s.Name != "" && s.Name != obj.GetName() {
+		return false
+	}
+	if s.LabelSelector != nil {
+		// The selectors were validated when the exemptions were read.
+		selector, err := metav1.LabelSelectorAsSelector(s.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(obj.GetLabels())) {
+			return false
+		}
+	}
+	return true
+}
+
+// version identifies a version of the exemption, so that the uses of an
+// exemption are recorded again after it is modified.
+func (e Exemption) version() string {
+	return e.Name + "@" + e.ResourceVersion
+}
+
+// Lister lists the active exemptions, caching them for a few seconds since
+// they are looked up whenever drift is detected. A nil Lister lists no
+// exemptions.
+type Lister struct {
+	reader   client.Reader
+	recorder record.EventRecorder
+	now      func() time.Time
+
+	mux        sync.Mutex
+	exemptions []Exemption
+	listed     time.Time
+	// recorded holds the uses recorded by RecordUseOnce, by exemption version.
+	recorded map[string]map[string]bool
+}
+
+// NewLister returns a Lister which reads the exemptions with the reader and
+// records their uses with the recorder.
+func NewLister(reader client.Reader, recorder record.EventRecorder) *Lister {
+	return &Lister{
+		reader:   reader,
+		recorder: recorder,
+		now:      time.Now,
+		recorded: make(map[string]map[string]bool),
+	}
+}
+
+// ForUser returns the active exemption allowing the user to modify the object,
+// if any.
+func (l *Lister) ForUser(ctx context.Context, user authenticationv1.UserInfo, obj client.Object) (Exemption, bool) {
+	for _, e := range l.list(ctx) {
+		if e.Grants(user) && e.Selects(obj) {
+			return e, true
+		}
+	}
+	return Exemption{}, false
+}
+
+// ForObject returns the active exemption selecting the object, if any.
+func (l *Lister) ForObject(ctx context.Context, obj client.Object) (Exemption, bool) {
+	for _, e := range l.list(ctx) {
+		if e.Selects(obj) {
+			return e, true
+		}
+	}
+	return Exemption{}, false
+}
+
+// RecordUse records an Event about a use of the exemption on its
+// DriftExemption.
+func (l *Lister) RecordUse(e Exemption, reason, messageFmt string, args ...interface{}) {
+	if l == nil || l.recorder == nil {
+		return
+	}
+	message := fmt.Sprintf(messageFmt, args...)
+	l.recorder.Eventf(&e.DriftExemption, corev1.EventTypeNormal, reason, "Exemption %q: %s", e.Name, message)
+}
+
+// RecordUseOnce records an Event about a use of the exemption for the object,
+// unless the same use of the same version of the exemption was already
+// recorded. It is used by the controllers which retry the use until the
+// exemption expires.
+func (l *Lister) RecordUseOnce(e Exemption, obj client.Object, reason, messageFmt string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	use := strings.Join([]string{reason, core.GKNN(obj)}, "/")
+	l.mux.Lock()
+	uses := l.recorded[e.version()]
+	if uses == nil {
+		uses = make(map[string]bool)
+		l.recorded[e.version()] = uses
+	}
+	recorded := uses[use]
+	uses[use] = true
+	l.mux.Unlock()
+
+	if !recorded {
+		l.RecordUse(e, reason, messageFmt, args...)
+	}
+}
+
+func (l *Lister) list(ctx context.Context) []Exemption {
+	if l == nil {
+		return nil
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+	if l.listed.IsZero() || now.Sub(l.listed) >= refreshPeriod {
+		des := &v1beta1.DriftExemptionList{}
+		if err := l.reader.List(ctx, des); err != nil {
+			// Keep the previous exemptions until the next refresh.
+			klog.Errorf("Failed to list the DriftExemptions, using the exemptions listed last: %v", err)
+		} else {
+			// The items of typed lists may not have their GroupVersionKind set,
+			// which Events require.
+			for i := range des.Items {
+				des.Items[i].SetGroupVersionKind(v1beta1.SchemeGroupVersion.WithKind(configsync.DriftExemptionKind))
+			}
+			exemptions, errs := FromDriftExemptions(des.Items)
+			for _, err := range errs {
+				klog.Warning(err)
+			}
+			l.exemptions = exemptions
+			l.forgetRecorded()
+		}
+		l.listed = now
+	}
+
+	var active []Exemption
+	for _, e := range l.exemptions {
+		if e.Active(now) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+// forgetRecorded forgets the uses of the exemption versions which no longer
+// exist.
+func (l *Lister) forgetRecorded() {
+	versions := make(map[string]bool, len(l.exemptions))
+	for _, e := range l.exemptions {
+		versions[e.version()] = true
+	}
+	for v := range l.recorded {
+		if !versions[v] {
+			delete(l.recorded, v)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exemption
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	testingfake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+)
+
+var expires = time.Date(2022, 6, 1, 18, 0, 0, 0, time.UTC)
+
+func driftExemption(name string, spec v1beta1.DriftExemptionSpec) *v1beta1.DriftExemption {
+	de := &v1beta1.DriftExemption{Spec: spec}
+	de.Name = name
+	return de
+}
+
+func frontend() *v1beta1.DriftExemption {
+	return driftExemption("outage-1234", v1beta1.DriftExemptionSpec{
+		Reason:          "Mitigate outage 1234",
+		Users:           []string{"alice@acme.com"},
+		Groups:          []string{"oncall@acme.com"},
+		ServiceAccounts: []v1beta1.DriftExemptionServiceAccount{{Namespace: "kube-system", Name: "break-glass"}},
+		Objects: []v1beta1.DriftExemptionSelector{{
+			Group:     "apps",
+			Kind:      "Deployment",
+			Namespace: "bookstore",
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "frontend"},
+			},
+		}},
+		Expires: metav1.NewTime(expires),
+	})
+}
+
+func TestFromDriftExemptions(t *testing.T) {
+	deployments := []v1beta1.DriftExemptionSelector{{Kind: "Deployment"}}
+	testCases := []struct {
+		name     string
+		de       *v1beta1.DriftExemption
+		want     []string
+		wantErrs int
+	}{
+		{
+			name: "valid exemption",
+			de:   frontend(),
+			want: []string{"outage-1234"},
+		},
+		{
+			name: "no subjects",
+			de: driftExemption("no-subjects", v1beta1.DriftExemptionSpec{
+				Objects: deployments,
+				Expires: metav1.NewTime(expires),
+			}),
+			wantErrs: 1,
+		},
+		{
+			name: "no objects",
+			de: driftExemption("no-objects", v1beta1.DriftExemptionSpec{
+				Users:   []string{"alice"},
+				Expires: metav1.NewTime(expires),
+			}),
+			wantErrs: 1,
+		},
+		{
+			name: "no expiry",
+			de: driftExemption("no-expiry", v1beta1.DriftExemptionSpec{
+				Users:   []string{"alice"},
+				Objects: deployments,
+			}),
+			wantErrs: 1,
+		},
+		{
+			name: "invalid label selector",
+			de: driftExemption("bad-selector", v1beta1.DriftExemptionSpec{
+				Users: []string{"alice"},
+				Objects: []v1beta1.DriftExemptionSelector{{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Between"}},
+					},
+				}},
+				Expires: metav1.NewTime(expires),
+			}),
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exemptions, errs := FromDriftExemptions([]v1beta1.DriftExemption{*tc.de})
+			var got []string
+			for _, e := range exemptions {
+				got = append(got, e.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+			if len(errs) != tc.wantErrs {
+				t.Errorf("got errors %v, want %d errors", errs, tc.wantErrs)
+			}
+		})
+	}
+}
+
+func TestExemption(t *testing.T) {
+	exemptions, errs := FromDriftExemptions([]v1beta1.DriftExemption{*frontend()})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	e := exemptions[0]
+
+	users := []struct {
+		user authenticationv1.UserInfo
+		want bool
+	}{
+		{authenticationv1.UserInfo{Username: "alice@acme.com"}, true},
+		{authenticationv1.UserInfo{Username: "bob@acme.com", Groups: []string{"oncall@acme.com"}}, true},
+		{authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:break-glass"}, true},
+		{authenticationv1.UserInfo{Username: "bob@acme.com", Groups: []string{"devs@acme.com"}}, false},
+	}
+	for _, u := range users {
+		if got := e.Grants(u.user); got != u.want {
+			t.Errorf("Grants(%v) = %t, want %t", u.user, got, u.want)
+		}
+	}
+
+	objects := []struct {
+		name string
+		obj  *metav1.PartialObjectMetadata
+		want bool
+	}{
+		{"selected", partialObject("Deployment", "bookstore", "frontend"), true},
+		{"other label", partialObject("Deployment", "bookstore", "backend"), false},
+		{"other namespace", partialObject("Deployment", "shipping", "frontend"), false},
+		{"other kind", partialObject("StatefulSet", "bookstore", "frontend"), false},
+	}
+	for _, o := range objects {
+		if got := e.Selects(o.obj); got != o.want {
+			t.Errorf("Selects(%s) = %t, want %t", o.name, got, o.want)
+		}
+	}
+
+	if !e.Active(expires.Add(-time.Second)) {
+		t.Error("got exemption inactive before it expires")
+	}
+	if e.Active(expires) {
+		t.Error("got exemption active when it expires")
+	}
+}
+
+func partialObject(kind, namespace, app string) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.APIVersion = "apps/v1"
+	obj.Kind = kind
+	obj.Namespace = namespace
+	obj.Name = app
+	obj.Labels = map[string]string{"app": app}
+	return obj
+}
+
+func TestLister(t *testing.T) {
+	c := testingfake.NewClient(t, core.Scheme, frontend())
+	recorder := record.NewFakeRecorder(10)
+	l := NewLister(c, recorder)
+	now := expires.Add(-time.Minute)
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	obj := partialObject("Deployment", "bookstore", "frontend")
+	alice := authenticationv1.UserInfo{Username: "alice@acme.com"}
+	bob := authenticationv1.UserInfo{Username: "bob@acme.com"}
+
+	e, found := l.ForUser(ctx, alice, obj)
+	if !found {
+		t.Fatal("got no exemption for alice, want one")
+	}
+	if _, found := l.ForUser(ctx, bob, obj); found {
+		t.Error("got exemption for bob, want none")
+	}
+	if _, found := l.ForObject(ctx, obj); !found {
+		t.Error("got no exemption for the object, want one")
+	}
+
+	l.RecordUse(e, "ExemptionUsed", "allowed %s", alice.Username)
+	l.RecordUse(e, "ExemptionUsed", "allowed %s", alice.Username)
+	// Repeated uses for the same object are only recorded once.
+	l.RecordUseOnce(e, obj, "DriftNotReverted", "kept %s", obj.Name)
+	l.RecordUseOnce(e, obj, "DriftNotReverted", "kept %s", obj.Name)
+	want := []string{
+		`Normal ExemptionUsed Exemption "outage-1234": allowed alice@acme.com`,
+		`Normal ExemptionUsed Exemption "outage-1234": allowed alice@acme.com`,
+		`Normal DriftNotReverted Exemption "outage-1234": kept frontend`,
+	}
+	var got []string
+	for len(recorder.Events) > 0 {
+		got = append(got, <-recorder.Events)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+
+	// Expired exemptions are ignored.
+	now = expires
+	if _, found := l.ForObject(ctx, obj); found {
+		t.Error("got expired exemption, want none")
+	}
+
+	// A nil Lister lists no exemptions.
+	var nilLister *Lister
+	if _, found := nilLister.ForUser(ctx, alice, obj); found {
+		t.Error("got exemption from nil Lister, want none")
+	}
+	nilLister.RecordUse(e, "ExemptionUsed", "allowed")
+	nilLister.RecordUseOnce(e, obj, "DriftNotReverted", "kept")
+}
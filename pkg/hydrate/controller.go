@@ -32,6 +32,7 @@ import (
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
 )
 
 const (
@@ -108,7 +109,12 @@ func (h *Hydrator) Run(ctx context.Context) {
 }
 
 // runHydrate runs `kustomize build` on the source configs.
-func (h *Hydrator) runHydrate(sourceCommit, syncDir string) HydrationError {
+func (h *Hydrator) runHydrate(sourceCommit, syncDir string) (hydrationErr HydrationError) {
+	_, span := tracing.StartCommitSpan(context.Background(), "hydrate.render", h.ReconcilerName, sourceCommit)
+	defer func() {
+		tracing.EndSpan(span, hydrationErr)
+	}()
+
 	newHydratedDir := h.HydratedRoot.Join(cmpath.RelativeOS(sourceCommit))
 	dest := newHydratedDir.Join(h.SyncDir).OSPath()
 
@@ -297,7 +303,7 @@ func deleteErrorFile(file string) error {
 	return nil
 }
 
-// SourceCommitAndDir returns the source hash (a git commit hash or an OCI image digest or a helm chart version), the absolute path of the sync directory, and source errors.
+// SourceCommitAndDir returns the source hash (a git commit hash or an OCI image digest or a helm chart version or a local source digest or a digest of the ETags of the objects of a bucket), the absolute path of the sync directory, and source errors.
 func SourceCommitAndDir(sourceType v1beta1.SourceType, sourceRoot cmpath.Absolute, syncDir cmpath.Relative, reconcilerName string) (string, cmpath.Absolute, status.Error) {
 	// Check if the source configs are synced successfully.
 	errFilePath := filepath.Join(path.Dir(sourceRoot.OSPath()), git.ErrorFile)
@@ -310,6 +316,10 @@ func SourceCommitAndDir(sourceType v1beta1.SourceType, sourceRoot cmpath.Absolut
 		containerName = reconcilermanager.GitSync
 	case v1beta1.HelmSource:
 		containerName = reconcilermanager.HelmSync
+	case v1beta1.LocalSource:
+		containerName = reconcilermanager.LocalSync
+	case v1beta1.BucketSource:
+		containerName = reconcilermanager.BucketSync
 	}
 
 	// A function that turns an error to a status sourceError.
@@ -0,0 +1,420 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hydrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"kpt.dev/configsync/pkg/api/configmanagement/v1/repo"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/validate"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ConvertedFile is a file of a converted repository.
+type ConvertedFile struct {
+	// SlashPath is the path of the file relative to the root of the repository.
+	SlashPath string
+	// Content is the content of the file.
+	Content string
+}
+
+// sourceDoc is a YAML document in the source repository.
+type sourceDoc struct {
+	node *kyaml.RNode
+	// isYAML is false for JSON files, whose documents are only read to
+	// inspect the annotations.
+	isYAML bool
+}
+
+// convertedObject is a hydrated object, and the clusters it is hydrated for.
+type convertedObject struct {
+	ast.FileObject
+	clusters []string
+}
+
+// ConvertToUnstructured converts a hierarchy repository into an equivalent
+// unstructured repository. It hydrates the repository for each declared
+// cluster with the same tree hydrators as the reconciler, so that abstract
+// namespaces, inheritance and NamespaceSelectors are replaced by objects with
+// explicit namespaces. Objects which are only hydrated for some clusters keep
+// their cluster selection.
+//
+// The comments of the source objects are preserved, unless hydration changed
+// the object in any other way than setting its namespace.
+//
+// It returns the files of the unstructured repository, and notes on the
+// semantics of the hierarchy repository which the unstructured repository
+// doesn't preserve exactly.
+func ConvertToUnstructured(parser filesystem.ConfigParser, options validate.Options, filePaths reader.FilePaths) ([]ConvertedFile, []string, status.MultiError) {
+	var clusters []string
+	var hydrated [][]ast.FileObject
+	var errs status.MultiError
+	ForEachCluster(parser, options, filesystem.SourceFormatHierarchy, filePaths, func(clusterName string, fileObjects []ast.FileObject, err status.MultiError) {
+		errs = status.Append(errs, err)
+		Clean(fileObjects)
+		clusters = append(clusters, clusterName)
+		hydrated = append(hydrated, fileObjects)
+	})
+	if errs != nil {
+		return nil, nil, errs
+	}
+
+	sources, registryFiles, err := readSources(filePaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &converter{
+		clusters:  clusters,
+		sources:   sources,
+		converted: mergeClusters(clusters, hydrated),
+	}
+	files, notes, err := c.convert()
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, registryFiles...)
+	notes = append(notes, sourceNotes(sources, c.converted)...)
+	sort.Slice(files, func(i, j int) bool { return files[i].SlashPath < files[j].SlashPath })
+	return files, notes, nil
+}
+
+// readSources reads the YAML and JSON documents of the source repository,
+// keyed by sourceKey. It also returns the files of the clusterregistry/
+// directory, which are copied to the converted repository as is.
+func readSources(filePaths reader.FilePaths) (map[string]sourceDoc, []ConvertedFile, status.MultiError) {
+	sources := make(map[string]sourceDoc)
+	var registryFiles []ConvertedFile
+	for _, f := range filePaths.Files {
+		rel, err := filepath.Rel(filePaths.RootDir.OSPath(), f.OSPath())
+		if err != nil {
+			return nil, nil, status.PathWrapError(err, f.OSPath())
+		}
+		slashPath := filepath.ToSlash(rel)
+		ext := filepath.Ext(slashPath)
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+		content, err := ioutil.ReadFile(f.OSPath())
+		if err != nil {
+			return nil, nil, status.PathWrapError(err, f.OSPath())
+		}
+		if strings.HasPrefix(slashPath, repo.ClusterRegistryDir+"/") {
+			registryFiles = append(registryFiles, ConvertedFile{SlashPath: slashPath, Content: string(content)})
+		}
+		nodes, err := kio.FromBytes(content)
+		if err != nil {
+			return nil, nil, status.PathWrapError(err, f.OSPath())
+		}
+		for _, n := range nodes {
+			sources[sourceKey(slashPath, n.GetKind(), n.GetName())] = sourceDoc{node: n, isYAML: ext != ".json"}
+		}
+	}
+	return sources, registryFiles, nil
+}
+
+func sourceKey(slashPath, kind, name string) string {
+	return slashPath + "#" + kind + "/" + name
+}
+
+// mergeClusters merges the objects hydrated for each cluster. Identical
+// objects from the same source file are merged into a single object which
+// lists the clusters it is hydrated for. Identical objects from different
+// source files are kept apart, since each has its own cluster selector.
+func mergeClusters(clusters []string, hydrated [][]ast.FileObject) []*convertedObject {
+	var result []*convertedObject
+	byContent := make(map[string]*convertedObject)
+	for i, objects := range hydrated {
+		for _, o := range objects {
+			content, _ := json.Marshal(o.Object)
+			key := o.SlashPath() + "#" + core.IDOf(o).String() + string(content)
+			if existing, found := byContent[key]; found {
+				existing.clusters = append(existing.clusters, clusters[i])
+				continue
+			}
+			c := &convertedObject{FileObject: o, clusters: []string{clusters[i]}}
+			byContent[key] = c
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+type converter struct {
+	clusters  []string
+	sources   map[string]sourceDoc
+	converted []*convertedObject
+}
+
+func (c *converter) convert() ([]ConvertedFile, []string, status.MultiError) {
+	// The clusters each Namespace is hydrated for.
+	namespaces := make(map[string][]string)
+	for _, o := range c.converted {
+		if o.GetObjectKind().GroupVersionKind() == kinds.Namespace() {
+			namespaces[o.GetName()] = append(namespaces[o.GetName()], o.clusters...)
+		}
+	}
+
+	var notes []string
+	contents := make(map[string][]string)
+	var paths []string
+	for _, o := range c.converted {
+		src, hasSource := c.sources[sourceKey(o.SlashPath(), o.GetKind(), o.GetName())]
+
+		annotationKey, annotationValue, note := c.clusterSelection(o, src, hasSource, namespaces)
+		if note != "" {
+			notes = append(notes, note)
+		}
+
+		content, preserved, err := convertedContent(o, src, hasSource, annotationKey, annotationValue)
+		if err != nil {
+			return nil, nil, status.PathWrapError(err, o.OSPath())
+		}
+		if hasSource && src.isYAML && !preserved && strings.Contains(src.node.MustString(), "#") {
+			notes = append(notes, fmt.Sprintf("%s: the comments of %s were dropped since hydration changed the object",
+				o.SlashPath(), objectString(o)))
+		}
+
+		p := convertedPath(o)
+		if _, found := contents[p]; !found {
+			paths = append(paths, p)
+		}
+		contents[p] = append(contents[p], content)
+	}
+
+	var files []ConvertedFile
+	for _, p := range paths {
+		files = append(files, ConvertedFile{SlashPath: p, Content: strings.Join(contents[p], "---\n")})
+	}
+	return files, notes, nil
+}
+
+// clusterSelection returns the cluster selector annotation which selects the
+// clusters the object is hydrated for, if it isn't hydrated for all of them.
+// The annotation of the source object is kept if it alone selects the same
+// clusters, otherwise the clusters are listed by name.
+func (c *converter) clusterSelection(o *convertedObject, src sourceDoc, hasSource bool, namespaces map[string][]string) (string, string, string) {
+	if len(o.clusters) == len(c.clusters) {
+		return "", "", ""
+	}
+
+	ownKey, ownValue := "", ""
+	if hasSource {
+		annotations := src.node.GetAnnotations()
+		for _, k := range []string{metadata.LegacyClusterSelectorAnnotationKey, metadata.ClusterNameSelectorAnnotationKey} {
+			if v, found := annotations[k]; found {
+				ownKey, ownValue = k, v
+			}
+		}
+	}
+	inheritsSelection := o.GetNamespace() != "" && len(namespaces[o.GetNamespace()]) != len(c.clusters)
+	if ownKey != "" && !inheritsSelection {
+		return ownKey, ownValue, ""
+	}
+
+	if contains(o.clusters, defaultCluster) {
+		return "", "", fmt.Sprintf("%s: %s is only hydrated for clusters %s, including clusters without a declared Cluster, which can't be expressed with a cluster selector; it is synced to all clusters",
+			o.SlashPath(), objectString(o), strings.Join(o.clusters, ", "))
+	}
+	names := append([]string{}, o.clusters...)
+	sort.Strings(names)
+	value := strings.Join(names, ",")
+	return metadata.ClusterNameSelectorAnnotationKey, value, fmt.Sprintf("%s: %s inherits the cluster selection of Namespace %q; it is now selected by the names of the declared Clusters: %s",
+		o.SlashPath(), objectString(o), o.GetNamespace(), value)
+}
+
+// convertedContent returns the YAML content of the converted object. The
+// source document is used if hydration only set its namespace, so that its
+// comments are preserved. It returns whether the source document was used.
+func convertedContent(o *convertedObject, src sourceDoc, hasSource bool, annotationKey, annotationValue string) (string, bool, error) {
+	if hasSource && src.isYAML {
+		node := src.node.Copy()
+		if err := cleanNode(node, o.GetNamespace()); err != nil {
+			return "", false, err
+		}
+		same, err := sameObject(node, o.Object)
+		if err != nil {
+			return "", false, err
+		}
+		if same {
+			if annotationKey != "" {
+				if _, err := node.Pipe(kyaml.SetAnnotation(annotationKey, annotationValue)); err != nil {
+					return "", false, err
+				}
+			}
+			return node.MustString(), true, nil
+		}
+	}
+
+	u := o.DeepCopy()
+	if annotationKey != "" {
+		core.SetAnnotation(u, annotationKey, annotationValue)
+	}
+	uObj, err := toUnstructured(u)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := yaml.Marshal(uObj.Object)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), false, nil
+}
+
+// cleanNode sets the namespace of the source document, and removes the
+// annotations and labels which Clean removes from hydrated objects.
+func cleanNode(node *kyaml.RNode, namespace string) error {
+	if namespace != "" && node.GetNamespace() != namespace {
+		if err := node.SetNamespace(namespace); err != nil {
+			return err
+		}
+	}
+	annotations := node.GetAnnotations()
+	cleaned := false
+	for k := range annotations {
+		if metadata.HasConfigSyncPrefix(k) && k != metadata.ResourceManagementKey {
+			delete(annotations, k)
+			cleaned = true
+		}
+	}
+	if cleaned {
+		if err := node.SetAnnotations(annotations); err != nil {
+			return err
+		}
+	}
+	labels := node.GetLabels()
+	cleaned = false
+	for k := range labels {
+		if metadata.HasConfigSyncPrefix(k) {
+			delete(labels, k)
+			cleaned = true
+		}
+	}
+	if cleaned {
+		return node.SetLabels(labels)
+	}
+	return nil
+}
+
+// sameObject returns true if the document has the same content as the object.
+func sameObject(node *kyaml.RNode, object map[string]interface{}) (bool, error) {
+	nodeMap, err := node.Map()
+	if err != nil {
+		return false, err
+	}
+	normalized := make([]interface{}, 2)
+	for i, m := range []map[string]interface{}{nodeMap, object} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(data, &normalized[i]); err != nil {
+			return false, err
+		}
+	}
+	return reflect.DeepEqual(normalized[0], normalized[1]), nil
+}
+
+// convertedPath returns the path of the converted object. Namespaced objects
+// are written to the directory of their namespace, and cluster-scoped objects
+// to the cluster/ directory.
+func convertedPath(o *convertedObject) string {
+	name := strings.ToLower(fmt.Sprintf("%s_%s.yaml", o.GetKind(), o.GetName()))
+	switch {
+	case o.GetObjectKind().GroupVersionKind() == kinds.Namespace():
+		return path.Join(repo.NamespacesDir, o.GetName(), "namespace.yaml")
+	case o.GetNamespace() != "":
+		return path.Join(repo.NamespacesDir, o.GetNamespace(), name)
+	default:
+		return path.Join(repo.ClusterDir, name)
+	}
+}
+
+// sourceNotes returns the notes on the source objects whose semantics change
+// in an unstructured repository.
+func sourceNotes(sources map[string]sourceDoc, converted []*convertedObject) []string {
+	// The namespaces each source object is hydrated into.
+	copies := make(map[string][]string)
+	for _, o := range converted {
+		if o.GetNamespace() == "" {
+			continue
+		}
+		key := sourceKey(o.SlashPath(), o.GetKind(), o.GetName())
+		if !contains(copies[key], o.GetNamespace()) {
+			copies[key] = append(copies[key], o.GetNamespace())
+		}
+	}
+
+	var keys []string
+	for k := range sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var notes []string
+	for _, k := range keys {
+		node := sources[k].node
+		slashPath := strings.SplitN(k, "#", 2)[0]
+		obj := fmt.Sprintf("%s %s", node.GetKind(), node.GetName())
+		namespaces := copies[k]
+		sort.Strings(namespaces)
+		switch {
+		case strings.HasPrefix(slashPath, repo.SystemDir+"/"):
+			notes = append(notes, fmt.Sprintf("%s: %s is only used by hierarchy repositories and was dropped", slashPath, obj))
+		case node.GetKind() == kinds.NamespaceSelector().Kind && node.GetApiVersion() == kinds.NamespaceSelector().GroupVersion().String():
+			notes = append(notes, fmt.Sprintf("%s: %s was evaluated against the Namespaces declared in the repository and dropped; Namespaces added later are not selected",
+				slashPath, obj))
+		case node.GetAnnotations()[metadata.NamespaceSelectorAnnotationKey] != "":
+			notes = append(notes, fmt.Sprintf("%s: %s selected by NamespaceSelector %q was copied into Namespaces %s",
+				slashPath, obj, node.GetAnnotations()[metadata.NamespaceSelectorAnnotationKey], strings.Join(namespaces, ", ")))
+		case len(namespaces) > 1 || (len(namespaces) == 1 && path.Base(path.Dir(slashPath)) != namespaces[0]):
+			notes = append(notes, fmt.Sprintf("%s: %s inherited from an abstract namespace was copied into Namespaces %s",
+				slashPath, obj, strings.Join(namespaces, ", ")))
+		}
+	}
+	return notes
+}
+
+func objectString(o *convertedObject) string {
+	if o.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", o.GetKind(), o.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", o.GetKind(), o.GetNamespace(), o.GetName())
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hydrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/util/discovery"
+	"kpt.dev/configsync/pkg/validate"
+)
+
+var hierarchyRepo = map[string]string{
+	"system/repo.yaml": `apiVersion: configmanagement.gke.io/v1
+kind: Repo
+metadata:
+  name: repo
+spec:
+  version: 1.0.0
+`,
+	"clusterregistry/cluster-dev.yaml": `apiVersion: clusterregistry.k8s.io/v1alpha1
+kind: Cluster
+metadata:
+  name: cluster-dev
+  labels:
+    environment: dev
+`,
+	"clusterregistry/selector-dev.yaml": `apiVersion: configmanagement.gke.io/v1
+kind: ClusterSelector
+metadata:
+  name: selector-dev
+spec:
+  selector:
+    matchLabels:
+      environment: dev
+`,
+	"namespaces/shop/quota.yaml": `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+spec:
+  hard:
+    pods: "1"
+`,
+	"namespaces/shop/bookstore/namespace.yaml": `# The bookstore.
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bookstore
+`,
+	"namespaces/shop/bookstore/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  annotations:
+    configmanagement.gke.io/cluster-selector: selector-dev
+data:
+  key: value # A comment.
+`,
+	"namespaces/shop/shoestore/namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shoestore
+  annotations:
+    configmanagement.gke.io/cluster-selector: selector-dev
+`,
+}
+
+func TestConvertToUnstructured(t *testing.T) {
+	root := t.TempDir()
+	var files []cmpath.Absolute
+	for p, content := range hierarchyRepo {
+		osPath := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(osPath), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(osPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, cmpath.Absolute(osPath))
+	}
+	rootDir := cmpath.Absolute(root)
+	options := validate.Options{
+		PolicyDir:         cmpath.RelativeOS(root),
+		BuildScoper:       discovery.ScoperBuilder(discovery.NoOpServerResourcer{}),
+		AllowUnknownKinds: true,
+	}
+	filePaths := reader.FilePaths{
+		RootDir:   rootDir,
+		PolicyDir: cmpath.RelativeOS(root),
+		Files:     filesystem.FilterHierarchyFiles(rootDir, files),
+	}
+
+	converted, notes, errs := ConvertToUnstructured(filesystem.NewParser(&reader.File{}), options, filePaths)
+	if errs != nil {
+		t.Fatalf("ConvertToUnstructured() got errors: %v", errs)
+	}
+
+	got := make(map[string]string)
+	for _, f := range converted {
+		got[f.SlashPath] = f.Content
+	}
+	want := map[string]string{
+		"clusterregistry/cluster-dev.yaml":  hierarchyRepo["clusterregistry/cluster-dev.yaml"],
+		"clusterregistry/selector-dev.yaml": hierarchyRepo["clusterregistry/selector-dev.yaml"],
+		"namespaces/bookstore/namespace.yaml": `# The bookstore.
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bookstore
+`,
+		"namespaces/bookstore/configmap_cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: bookstore
+  annotations:
+    configmanagement.gke.io/cluster-selector: 'selector-dev'
+data:
+  key: value # A comment.
+`,
+		"namespaces/bookstore/resourcequota_quota.yaml": `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: bookstore
+spec:
+  hard:
+    pods: "1"
+`,
+		"namespaces/shoestore/namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: shoestore
+  annotations:
+    configmanagement.gke.io/cluster-selector: 'selector-dev'
+`,
+		"namespaces/shoestore/resourcequota_quota.yaml": `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: shoestore
+  annotations:
+    configsync.gke.io/cluster-name-selector: 'cluster-dev'
+spec:
+  hard:
+    pods: "1"
+`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ConvertToUnstructured() files diff (-want +got):\n%s", diff)
+	}
+
+	wantNotes := []string{
+		`namespaces/shop/quota.yaml: ResourceQuota shoestore/quota inherits the cluster selection of Namespace "shoestore"`,
+		"namespaces/shop/quota.yaml: ResourceQuota quota inherited from an abstract namespace was copied into Namespaces bookstore, shoestore",
+		"system/repo.yaml: Repo repo is only used by hierarchy repositories and was dropped",
+	}
+	for _, want := range wantNotes {
+		found := false
+		for _, n := range notes {
+			if strings.HasPrefix(n, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("ConvertToUnstructured() notes %q, want a note starting with %q", notes, want)
+		}
+	}
+}
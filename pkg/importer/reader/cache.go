@@ -0,0 +1,171 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"crypto/sha256"
+	"path/filepath"
+	"sync"
+
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+)
+
+// Cache caches the FileObjects read from each file, keyed by the path of the
+// file and the hash of its contents, so that a new commit only parses the files
+// it changed. It also records which files passed the validators which only
+// depend on each object, so that they are only run on the changed files.
+//
+// Entries for files which are not read by a Read are evicted.
+type Cache struct {
+	mux sync.Mutex
+	// files maps the path of each file relative to the root directory to its
+	// cached FileObjects.
+	files map[string]*cachedFile
+	// read is the set of files read by the latest Read.
+	read map[string]bool
+	// hits and misses count the cache lookups of the latest Read.
+	hits, misses int
+}
+
+type cachedFile struct {
+	hash [sha256.Size]byte
+	objs []ast.FileObject
+	// validated is true if the objects passed the validators which only depend
+	// on each object.
+	validated bool
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		files: make(map[string]*cachedFile),
+		read:  make(map[string]bool),
+	}
+}
+
+// CacheOf returns the Cache of the Reader, or nil if it does not cache the
+// FileObjects it reads.
+func CacheOf(r Reader) *Cache {
+	if f, ok := r.(*File); ok {
+		return f.Cache
+	}
+	return nil
+}
+
+// Stats returns the number of files read from the cache and the number of
+// files parsed by the latest Read.
+func (c *Cache) Stats() (hits, misses int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.hits, c.misses
+}
+
+// Validated returns true if the object was read from the cache, and passed the
+// validators which only depend on each object when it was cached.
+func (c *Cache) Validated(obj ast.FileObject) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	f, ok := c.files[obj.OSPath()]
+	return ok && f.validated && c.read[obj.OSPath()]
+}
+
+// MarkValidated records that the objects read by the latest Read passed the
+// validators which only depend on each object.
+func (c *Cache) MarkValidated() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for key := range c.read {
+		if f, ok := c.files[key]; ok {
+			f.validated = true
+		}
+	}
+}
+
+// begin resets the files read and the stats before a Read.
+func (c *Cache) begin() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.read = make(map[string]bool)
+	c.hits, c.misses = 0, 0
+}
+
+// end evicts the files which were not read after a Read.
+func (c *Cache) end() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for key := range c.files {
+		if !c.read[key] {
+			delete(c.files, key)
+		}
+	}
+}
+
+// get returns a copy of the cached FileObjects of the file, if its contents did
+// not change.
+func (c *Cache) get(rootDir cmpath.Absolute, file cmpath.Absolute, contents []byte) ([]ast.FileObject, bool) {
+	key, ok := cacheKey(rootDir, file)
+	if !ok {
+		return nil, false
+	}
+	hash := sha256.Sum256(contents)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.read[key] = true
+	f, ok := c.files[key]
+	if !ok || f.hash != hash {
+		c.misses++
+		delete(c.files, key)
+		return nil, false
+	}
+	c.hits++
+	return deepCopy(f.objs), true
+}
+
+// set caches a copy of the FileObjects read from the file.
+func (c *Cache) set(rootDir cmpath.Absolute, file cmpath.Absolute, contents []byte, objs []ast.FileObject) {
+	key, ok := cacheKey(rootDir, file)
+	if !ok {
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.read[key] = true
+	c.files[key] = &cachedFile{
+		hash: sha256.Sum256(contents),
+		objs: deepCopy(objs),
+	}
+}
+
+// cacheKey returns the path of the file relative to the root directory, which
+// is the path of the FileObjects read from it. The root directory changes with
+// every commit, so the key must not include it.
+func cacheKey(rootDir cmpath.Absolute, file cmpath.Absolute) (string, bool) {
+	rel, err := filepath.Rel(rootDir.OSPath(), file.OSPath())
+	if err != nil {
+		return "", false
+	}
+	return cmpath.RelativeOS(rel).OSPath(), true
+}
+
+func deepCopy(objs []ast.FileObject) []ast.FileObject {
+	result := make([]ast.FileObject, len(objs))
+	for i := range objs {
+		result[i] = objs[i].DeepCopy()
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader_test
+
+import (
+	"testing"
+
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	ft "kpt.dev/configsync/pkg/importer/filesystem/filesystemtest"
+	"kpt.dev/configsync/pkg/importer/reader"
+)
+
+const (
+	namespaceFoo = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo
+`
+	namespaceBar = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+`
+	roleFoo = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: admin
+  namespace: foo
+`
+)
+
+func readCached(t *testing.T, r *reader.File, dir *ft.TestDir, files ...string) []ast.FileObject {
+	t.Helper()
+	objs, err := r.Read(dir.FilePaths(files...))
+	if err != nil {
+		t.Fatalf("got Read() = %v, want nil", err)
+	}
+	return objs
+}
+
+func checkStats(t *testing.T, c *reader.Cache, wantHits, wantMisses int) {
+	t.Helper()
+	if hits, misses := c.Stats(); hits != wantHits || misses != wantMisses {
+		t.Errorf("got Stats() = %d, %d, want %d, %d", hits, misses, wantHits, wantMisses)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := reader.NewCache()
+	r := &reader.File{Cache: c}
+
+	// Every commit is checked out in a different directory.
+	first := ft.NewTestDir(t,
+		ft.FileContents("ns.yaml", namespaceFoo),
+		ft.FileContents("role.yaml", roleFoo),
+		ft.FileContents("README.md", "# README"))
+	objs := readCached(t, r, first, "ns.yaml", "role.yaml", "README.md")
+	checkStats(t, c, 0, 2)
+	if len(objs) != 2 {
+		t.Fatalf("got %d objects, want 2", len(objs))
+	}
+	for _, obj := range objs {
+		if c.Validated(obj) {
+			t.Errorf("got Validated(%s) = true before MarkValidated(), want false", obj.SlashPath())
+		}
+	}
+	c.MarkValidated()
+	// Mutating the objects read does not mutate the cached ones.
+	for _, obj := range objs {
+		obj.SetName("mutated")
+	}
+
+	second := ft.NewTestDir(t,
+		ft.FileContents("ns.yaml", namespaceBar),
+		ft.FileContents("role.yaml", roleFoo))
+	objs = readCached(t, r, second, "ns.yaml", "role.yaml")
+	checkStats(t, c, 1, 1)
+	for _, obj := range objs {
+		switch obj.SlashPath() {
+		case "ns.yaml":
+			if obj.GetName() != "bar" {
+				t.Errorf("got Namespace %q, want bar", obj.GetName())
+			}
+			if c.Validated(obj) {
+				t.Error("got Validated() = true for the changed file, want false")
+			}
+		case "role.yaml":
+			if obj.GetName() != "admin" {
+				t.Errorf("got Role %q, want admin", obj.GetName())
+			}
+			if !c.Validated(obj) {
+				t.Error("got Validated() = false for the unchanged file, want true")
+			}
+		}
+	}
+
+	// Files which are not read are evicted.
+	third := ft.NewTestDir(t,
+		ft.FileContents("ns.yaml", namespaceBar),
+		ft.FileContents("role.yaml", roleFoo))
+	readCached(t, r, third, "ns.yaml")
+	checkStats(t, c, 1, 0)
+	readCached(t, r, third, "ns.yaml", "role.yaml")
+	checkStats(t, c, 1, 1)
+}
@@ -33,33 +33,39 @@ import (
 // yamlWhitespace records the two valid YAML whitespace characters.
 const yamlWhitespace = " \t"
 
-func parseFile(path string) ([]*unstructured.Unstructured, error) {
+// readFile returns the contents of the YAML or JSON file, or nil if the file
+// is neither.
+func readFile(path string) ([]byte, error) {
 	if !filepath.IsAbs(path) {
 		return nil, errors.New("attempted to read relative path")
 	}
 
 	switch filepath.Ext(path) {
-	case ".yml", ".yaml":
+	case ".yml", ".yaml", ".json":
 		contents, err := ioutil.ReadFile(path)
 		if err != nil {
 			klog.Errorf("Failed to read file declared in git from mounted filesystem: %s", path)
 			importer.Metrics.Violations.Inc()
 			return nil, err
 		}
-		return parseYAMLFile(contents)
-	case ".json":
-		contents, err := ioutil.ReadFile(path)
-		if err != nil {
-			klog.Errorf("Failed to read file declared in git from mounted filesystem: %s", path)
-			importer.Metrics.Violations.Inc()
-			return nil, err
+		if contents == nil {
+			// Distinguish empty files from the files which are not read.
+			contents = []byte{}
 		}
-		return parseJSONFile(contents)
+		return contents, nil
 	default:
 		return nil, nil
 	}
 }
 
+// parseContents parses the contents of the YAML or JSON file.
+func parseContents(path string, contents []byte) ([]*unstructured.Unstructured, error) {
+	if filepath.Ext(path) == ".json" {
+		return parseJSONFile(contents)
+	}
+	return parseYAMLFile(contents)
+}
+
 func isEmptyYAMLDocument(document string) bool {
 	lines := strings.Split(document, "\n")
 	for _, line := range lines {
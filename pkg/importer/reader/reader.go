@@ -53,11 +53,18 @@ type FilePaths struct {
 }
 
 // File reads FileObjects from a filesystem.
-type File struct{}
+type File struct {
+	// Cache caches the FileObjects read from each file, if set.
+	Cache *Cache
+}
 
 var _ Reader = &File{}
 
 func (r *File) Read(filePaths FilePaths) ([]ast.FileObject, status.MultiError) {
+	if r.Cache != nil {
+		r.Cache.begin()
+		defer r.Cache.end()
+	}
 	var objs []ast.FileObject
 	var errs status.MultiError
 	for _, f := range filePaths.Files {
@@ -84,7 +91,20 @@ func (r *File) read(rootDir cmpath.Absolute, policyDir cmpath.Relative, file cmp
 		}
 	}
 
-	unstructureds, err := parseFile(file.OSPath())
+	contents, err := readFile(file.OSPath())
+	if err != nil {
+		return nil, status.PathWrapError(err, file.OSPath())
+	}
+	if contents == nil {
+		return nil, nil
+	}
+	if r.Cache != nil {
+		if objs, ok := r.Cache.get(rootDir, file, contents); ok {
+			return objs, nil
+		}
+	}
+
+	unstructureds, err := parseContents(file.OSPath(), contents)
 	if err != nil {
 		return nil, status.PathWrapError(err, file.OSPath())
 	}
@@ -98,6 +118,9 @@ func (r *File) read(rootDir cmpath.Absolute, policyDir cmpath.Relative, file cmp
 		}
 		fileObjects = append(fileObjects, newFileObjects...)
 	}
+	if r.Cache != nil && errs == nil {
+		r.Cache.set(rootDir, file, contents, fileObjects)
+	}
 
 	return fileObjects, errs
 }
@@ -0,0 +1,375 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package local fetches the sources stored on the cluster for the local-sync
+// container: tarballs held by ConfigMaps or Secrets, and directory trees on
+// PersistentVolumeClaims mounted into the reconciler Pod.
+//
+// Like the other sync containers, it writes every version of the source into
+// a directory under the root directory named after its digest, and points the
+// rev symlink to it. The digest is reported as the commit of the source.
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// lostAndFound is the directory created by mkfs at the root of most volumes.
+// It is not readable by the local-sync container, and never part of the source.
+const lostAndFound = "lost+found"
+
+// ConfigMapData returns the value of the key of the ConfigMap, preferring
+// binaryData over data.
+func ConfigMapData(ctx context.Context, c client.Reader, key client.ObjectKey, dataKey string) ([]byte, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get the ConfigMap %s: %w", key, err)
+	}
+	if data, ok := cm.BinaryData[dataKey]; ok {
+		return data, nil
+	}
+	if data, ok := cm.Data[dataKey]; ok {
+		return []byte(data), nil
+	}
+	return nil, fmt.Errorf("the ConfigMap %s has no key %q", key, dataKey)
+}
+
+// SecretData returns the value of the key of the Secret.
+func SecretData(ctx context.Context, c client.Reader, key client.ObjectKey, dataKey string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the Secret %s: %w", key, err)
+	}
+	data, ok := secret.Data[dataKey]
+	if !ok {
+		return nil, fmt.Errorf("the Secret %s has no key %q", key, dataKey)
+	}
+	return data, nil
+}
+
+// FetchTarball extracts the tarball, which may be gzip-compressed, and points
+// the rev symlink under the root directory to it.
+func FetchTarball(tarball []byte, root, rev string) error {
+	sum := sha256.Sum256(tarball)
+	digest := hex.EncodeToString(sum[:])
+	return update(root, rev, digest, func(destDir string) error {
+		return extract(tarball, destDir)
+	})
+}
+
+// FetchDir copies the directory tree and points the rev symlink under the root
+// directory to the copy. The copy isolates the reconciler from changes made to
+// the tree while it is being read.
+func FetchDir(srcDir, root, rev string) error {
+	digest, err := dirDigest(srcDir)
+	if err != nil {
+		return fmt.Errorf("failed to calculate the digest of the directory %q: %w", srcDir, err)
+	}
+	return update(root, rev, digest, func(destDir string) error {
+		return copyDir(srcDir, destDir)
+	})
+}
+
+// update writes the source with the digest into a new directory under the
+// root directory, unless the rev symlink already points to it.
+func update(root, rev, digest string, write func(destDir string) error) error {
+	destDir := filepath.Join(root, digest)
+
+	linkPath := filepath.Join(root, rev)
+	oldDir, err := filepath.EvalSymlinks(linkPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to evaluate the symbolic path %q to the source: %w", linkPath, err)
+	}
+	if oldDir == destDir {
+		klog.V(4).Infof("no update required with the same digest %q", digest)
+		return nil
+	}
+
+	// Remove the leftovers of a previous attempt which failed halfway.
+	if err := os.RemoveAll(destDir); err != nil {
+		return fmt.Errorf("failed to clean up the directory %q: %w", destDir, err)
+	}
+	if err := os.MkdirAll(destDir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", destDir, err)
+	}
+	if err := write(destDir); err != nil {
+		return fmt.Errorf("failed to write the source to the directory %q: %w", destDir, err)
+	}
+
+	klog.Infof("fetched source with digest %q", digest)
+	return util.UpdateSymlink(root, linkPath, destDir, oldDir)
+}
+
+// extract extracts the tarball into the directory.
+func extract(tarball []byte, dir string) error {
+	var r io.Reader = bytes.NewReader(tarball)
+	if len(tarball) >= 2 && tarball[0] == 0x1f && tarball[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := gz.Close(); err != nil {
+				klog.Warningf("failed to close gzip reader: %v", err)
+			}
+		}()
+		r = gz
+	}
+
+	var links []string
+	tarReader := tar.NewReader(r)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			return checkLinks(dir, links)
+		}
+		if err != nil {
+			return err
+		}
+		path, err := within(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		// The symbolic links extracted before must not redirect the writes
+		// outside of the directory.
+		if err := checkParent(dir, path); err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := symlink(dir, hdr.Linkname, path); err != nil {
+				return err
+			}
+			links = append(links, path)
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("illegal path %q in the tarball, writing through a symbolic link", hdr.Name)
+			}
+			if err := writeFile(path, tarReader, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		default:
+			klog.Warningf("skipping %q of unsupported type %q in the tarball", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// within returns the path of the name in the directory, and an error if the
+// name escapes it.
+func within(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if !isWithin(dir, path) {
+		return "", fmt.Errorf("illegal path %q in the tarball", name)
+	}
+	return path, nil
+}
+
+// isWithin returns true if the clean path is the directory or under it.
+func isWithin(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// symlink creates the symbolic link to the target at the path, unless the
+// target is absolute or escapes the directory.
+func symlink(dir, target, path string) error {
+	if filepath.IsAbs(target) || !isWithin(dir, filepath.Join(filepath.Dir(path), target)) {
+		return fmt.Errorf("illegal symbolic link %q to %q", path, target)
+	}
+	return os.Symlink(target, path)
+}
+
+// checkParent returns an error if the parent directory of the path resolves
+// outside of the directory through a symbolic link.
+func checkParent(dir, path string) error {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	// The missing parent directories are created under the closest existing
+	// one.
+	parent := filepath.Dir(path)
+	for {
+		_, err := os.Lstat(parent)
+		if err == nil {
+			break
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent = filepath.Dir(parent)
+	}
+	realParent, err := filepath.EvalSymlinks(parent)
+	if err != nil {
+		return err
+	}
+	if !isWithin(realDir, realParent) {
+		return fmt.Errorf("illegal path %q, writing through a symbolic link", path)
+	}
+	return nil
+}
+
+// checkLinks returns an error if any of the symbolic links resolves outside of
+// the directory, which links to other links may do even if each of their
+// targets is within it. Dangling links are allowed.
+func checkLinks(dir string, links []string) error {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		target, err := filepath.EvalSymlinks(link)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !isWithin(realDir, target) {
+			return fmt.Errorf("illegal symbolic link %q resolving to %q", link, target)
+		}
+	}
+	return nil
+}
+
+// dirDigest returns the sha256 digest of the paths, types and contents of the
+// files of the directory tree.
+func dirDigest(dir string) (string, error) {
+	h := sha256.New()
+	err := walk(dir, func(path, rel string, info os.FileInfo) error {
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(h, "l %s %s\n", rel, target)
+			return err
+		case info.IsDir():
+			_, err := fmt.Fprintf(h, "d %s\n", rel)
+			return err
+		default:
+			if _, err := fmt.Fprintf(h, "f %s %o %d\n", rel, info.Mode().Perm(), info.Size()); err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := f.Close(); err != nil {
+					klog.Warningf("failed to close file %q: %v", path, err)
+				}
+			}()
+			_, err = io.Copy(h, f)
+			return err
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyDir copies the directory tree into the destination directory. The
+// symbolic links must stay within the tree.
+func copyDir(srcDir, destDir string) error {
+	var links []string
+	err := walk(srcDir, func(path, rel string, info os.FileInfo) error {
+		dest := filepath.Join(destDir, rel)
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			links = append(links, dest)
+			return symlink(destDir, target, dest)
+		case info.IsDir():
+			return os.MkdirAll(dest, 0755)
+		default:
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := f.Close(); err != nil {
+					klog.Warningf("failed to close file %q: %v", path, err)
+				}
+			}()
+			return writeFile(dest, f, info.Mode().Perm())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return checkLinks(destDir, links)
+}
+
+// walk calls fn for every file of the directory tree except its root and the
+// lost+found directory, in lexical order.
+func walk(dir string, fn func(path, rel string, info os.FileInfo) error) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == lostAndFound && info.IsDir() {
+			return filepath.SkipDir
+		}
+		return fn(path, filepath.ToSlash(rel), info)
+	})
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
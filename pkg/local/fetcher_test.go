@@ -0,0 +1,417 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/core"
+	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type tarEntry struct {
+	name     string
+	content  string
+	linkname string
+}
+
+func tarball(t *testing.T, compress bool, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var gz *gzip.Writer
+	var tw *tar.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gz)
+	} else {
+		tw = tar.NewWriter(&buf)
+	}
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.linkname != "" {
+			hdr = &tar.Header{Name: e.name, Linkname: e.linkname, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// readTree returns the contents of the regular files under the directory.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	result := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		result[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func revDir(t *testing.T, root string) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, "rev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFetchTarball(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tarball  func(t *testing.T) []byte
+		want     map[string]string
+		wantLink string
+		wantErr  bool
+	}{
+		{
+			name: "plain tarball",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false,
+					tarEntry{name: "namespaces/bookstore/ns.yaml", content: "kind: Namespace"},
+					tarEntry{name: "cluster/cr.yaml", content: "kind: ClusterRole"})
+			},
+			want: map[string]string{
+				"namespaces/bookstore/ns.yaml": "kind: Namespace",
+				"cluster/cr.yaml":              "kind: ClusterRole",
+			},
+		},
+		{
+			name: "gzip-compressed tarball",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, true, tarEntry{name: "ns.yaml", content: "kind: Namespace"})
+			},
+			want: map[string]string{"ns.yaml": "kind: Namespace"},
+		},
+		{
+			name: "symlink",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false,
+					tarEntry{name: "base/ns.yaml", content: "kind: Namespace"},
+					tarEntry{name: "current", linkname: "base"})
+			},
+			want:     map[string]string{"base/ns.yaml": "kind: Namespace"},
+			wantLink: "base",
+		},
+		{
+			name: "path escaping the directory",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false, tarEntry{name: "../escape.yaml", content: "kind: Namespace"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "absolute symlink",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false, tarEntry{name: "current", linkname: "/etc"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "symlink escaping the directory",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false, tarEntry{name: "current", linkname: "../.."})
+			},
+			wantErr: true,
+		},
+		{
+			name: "file written through a symlink",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false,
+					tarEntry{name: "base/ns.yaml", content: "kind: Namespace"},
+					tarEntry{name: "current", linkname: "base/ns.yaml"},
+					tarEntry{name: "current", content: "kind: ClusterRole"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "file written through symlinks escaping the directory",
+			tarball: func(t *testing.T) []byte {
+				// Each target is within the directory, but "parent" resolves
+				// to the parent of the directory, since "self" is the
+				// directory itself.
+				return tarball(t, false,
+					tarEntry{name: "base/ns.yaml", content: "kind: Namespace"},
+					tarEntry{name: "self", linkname: "."},
+					tarEntry{name: "parent", linkname: "self/base/../.."},
+					tarEntry{name: "parent/escape.yaml", content: "kind: Namespace"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "symlinks escaping the directory",
+			tarball: func(t *testing.T) []byte {
+				return tarball(t, false,
+					tarEntry{name: "base/ns.yaml", content: "kind: Namespace"},
+					tarEntry{name: "self", linkname: "."},
+					tarEntry{name: "parent", linkname: "self/base/../.."})
+			},
+			wantErr: true,
+		},
+		{
+			name: "not a tarball",
+			tarball: func(*testing.T) []byte {
+				return []byte("kind: Namespace")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			err := FetchTarball(tc.tarball(t), root, "rev")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("FetchTarball() got nil error, want error")
+				}
+				if _, err := os.Stat(filepath.Join(root, "escape.yaml")); !os.IsNotExist(err) {
+					t.Errorf("FetchTarball() wrote outside of the directory: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchTarball() got error %v, want nil", err)
+			}
+			dir := revDir(t, root)
+			if diff := cmp.Diff(tc.want, readTree(t, dir)); diff != "" {
+				t.Errorf("FetchTarball() files diff (- want, + got):\n%s", diff)
+			}
+			if tc.wantLink != "" {
+				got, err := os.Readlink(filepath.Join(dir, "current"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != tc.wantLink {
+					t.Errorf("FetchTarball() got symlink to %q, want %q", got, tc.wantLink)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchTarballUpdates(t *testing.T) {
+	root := t.TempDir()
+	v1 := tarball(t, false, tarEntry{name: "ns.yaml", content: "v1"})
+	if err := FetchTarball(v1, root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	dir1 := revDir(t, root)
+
+	// The same tarball keeps the same directory.
+	if err := FetchTarball(v1, root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	if got := revDir(t, root); got != dir1 {
+		t.Errorf("FetchTarball() with the same tarball moved the symlink from %q to %q", dir1, got)
+	}
+
+	// A new tarball moves the symlink and removes the previous directory.
+	v2 := tarball(t, false, tarEntry{name: "ns.yaml", content: "v2"})
+	if err := FetchTarball(v2, root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	dir2 := revDir(t, root)
+	if dir2 == dir1 {
+		t.Fatalf("FetchTarball() with a new tarball kept the symlink to %q", dir1)
+	}
+	if diff := cmp.Diff(map[string]string{"ns.yaml": "v2"}, readTree(t, dir2)); diff != "" {
+		t.Errorf("FetchTarball() files diff (- want, + got):\n%s", diff)
+	}
+	if _, err := os.Stat(dir1); !os.IsNotExist(err) {
+		t.Errorf("FetchTarball() did not remove the previous directory %q: %v", dir1, err)
+	}
+}
+
+func TestFetchDir(t *testing.T) {
+	src := t.TempDir()
+	writeFile := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(src, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("namespaces/bookstore/ns.yaml", "kind: Namespace")
+	writeFile("lost+found/orphan", "garbage")
+
+	root := t.TempDir()
+	if err := FetchDir(src, root, "rev"); err != nil {
+		t.Fatalf("FetchDir() got error %v, want nil", err)
+	}
+	dir1 := revDir(t, root)
+	if diff := cmp.Diff(map[string]string{"namespaces/bookstore/ns.yaml": "kind: Namespace"}, readTree(t, dir1)); diff != "" {
+		t.Errorf("FetchDir() files diff (- want, + got):\n%s", diff)
+	}
+
+	// An unchanged tree keeps the same directory.
+	if err := FetchDir(src, root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	if got := revDir(t, root); got != dir1 {
+		t.Errorf("FetchDir() with an unchanged tree moved the symlink from %q to %q", dir1, got)
+	}
+
+	// A change anywhere in the tree moves the symlink.
+	writeFile("namespaces/bookstore/ns.yaml", "kind: Namespace\nmetadata:\n  name: bookstore")
+	if err := FetchDir(src, root, "rev"); err != nil {
+		t.Fatal(err)
+	}
+	if got := revDir(t, root); got == dir1 {
+		t.Errorf("FetchDir() with a changed tree kept the symlink to %q", dir1)
+	}
+}
+
+func TestFetchDirSymlinks(t *testing.T) {
+	testCases := []struct {
+		name    string
+		target  string
+		wantErr bool
+	}{
+		{
+			name:   "symlink within the tree",
+			target: "base",
+		},
+		{
+			name:    "absolute symlink",
+			target:  "/etc",
+			wantErr: true,
+		},
+		{
+			name:    "symlink escaping the tree",
+			target:  "../outside",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(src, "base"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink(tc.target, filepath.Join(src, "current")); err != nil {
+				t.Fatal(err)
+			}
+			err := FetchDir(src, t.TempDir(), "rev")
+			if (err != nil) != tc.wantErr {
+				t.Errorf("FetchDir() got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestObjectData(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		BinaryData: map[string][]byte{"source.tar.gz": []byte("binary")},
+		Data:       map[string]string{"source.tar": "text"},
+	}
+	cm.Name = "source"
+	cm.Namespace = "bookstore"
+	secret := &corev1.Secret{Data: map[string][]byte{"source.tar.gz": []byte("secret")}}
+	secret.Name = "source"
+	secret.Namespace = "bookstore"
+	c := syncerFake.NewClient(t, core.Scheme, cm, secret)
+	key := client.ObjectKey{Namespace: "bookstore", Name: "source"}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		read    func() ([]byte, error)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ConfigMap binaryData",
+			read: func() ([]byte, error) { return ConfigMapData(ctx, c, key, "source.tar.gz") },
+			want: "binary",
+		},
+		{
+			name: "ConfigMap data",
+			read: func() ([]byte, error) { return ConfigMapData(ctx, c, key, "source.tar") },
+			want: "text",
+		},
+		{
+			name:    "ConfigMap missing key",
+			read:    func() ([]byte, error) { return ConfigMapData(ctx, c, key, "missing") },
+			wantErr: true,
+		},
+		{
+			name: "missing ConfigMap",
+			read: func() ([]byte, error) {
+				return ConfigMapData(ctx, c, client.ObjectKey{Namespace: "bookstore", Name: "missing"}, "source.tar")
+			},
+			wantErr: true,
+		},
+		{
+			name: "Secret",
+			read: func() ([]byte, error) { return SecretData(ctx, c, key, "source.tar.gz") },
+			want: "secret",
+		},
+		{
+			name:    "Secret missing key",
+			read:    func() ([]byte, error) { return SecretData(ctx, c, key, "missing") },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.read()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -133,10 +133,22 @@ const (
 	// to indicate that the scope of a resource is unknown.
 	UnknownScopeAnnotationValue = "true"
 
+	// PolicyRulesAnnotationKey is the annotation key marking a ConfigMap whose
+	// data are policy rules, which every object must satisfy to be synced.
+	// This annotation is set by Config Sync users on ConfigMaps in the
+	// repository, or in the config-management-system namespace.
+	PolicyRulesAnnotationKey = configsync.ConfigSyncPrefix + "policy-rules"
+
 	// DeletionPropagationPolicyAnnotationKey is the annotation key set on
 	// RootSync/RepoSync objects to indicate what do do with the managed
 	// resources when the RootSync/RepoSync object is deleted.
 	DeletionPropagationPolicyAnnotationKey = configsync.ConfigSyncPrefix + "deletion-propagation-policy"
+
+	// InventoryChunksAnnotationKey is the annotation key set on ResourceGroup
+	// inventories to indicate the number of additional ResourceGroup chunks
+	// the inventory is spread across.
+	// This annotation is set by Config Sync on ResourceGroup objects.
+	InventoryChunksAnnotationKey = configsync.ConfigSyncPrefix + "inventory-chunks"
 )
 
 // Lifecycle annotations
@@ -53,6 +53,11 @@ const (
 	// This is used to enable selecting pods by label, primarily for printing logs.
 	// Example: kubectl logs deployment/<deploy-name> <container-name> -n config-management-system
 	DeploymentNameLabel = configsync.ConfigSyncPrefix + "deployment-name"
+
+	// InventoryChunkOfLabel indicates the name of the ResourceGroup inventory
+	// a ResourceGroup chunk belongs to.
+	// This label is set by Config Sync on ResourceGroup chunks.
+	InventoryChunkOfLabel = configsync.ConfigSyncPrefix + "inventory-chunk-of"
 )
 
 // DepthSuffix is a label suffix for hierarchical namespace depth.
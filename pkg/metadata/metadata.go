@@ -54,6 +54,7 @@ var sourceAnnotations = map[string]bool{
 	ResourceManagementKey:                  true,
 	LifecycleMutationAnnotation:            true,
 	DeletionPropagationPolicyAnnotationKey: true,
+	PolicyRulesAnnotationKey:               true,
 }
 
 // IsSourceAnnotation returns true if the annotation is a ConfigSync source
@@ -50,6 +50,13 @@ var (
 		"The duration of the parse-apply-watch loop in seconds",
 		stats.UnitSeconds)
 
+	// ParserCacheLookups metric measures the number of files looked up in the
+	// parser cache.
+	ParserCacheLookups = stats.Int64(
+		"parser_cache_lookups",
+		"The number of files looked up in the parser cache",
+		stats.UnitDimensionless)
+
 	// LastSync metric measures the timestamp of the latest Git sync.
 	LastSync = stats.Int64(
 		"last_sync_timestamp",
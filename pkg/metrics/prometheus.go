@@ -0,0 +1,141 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opencensus.io/stats/view"
+	"k8s.io/klog/v2"
+)
+
+// PrometheusNamespace is the prefix of the names of the metrics exposed on the
+// Prometheus endpoint. It matches the namespace used by the Prometheus
+// exporter of the otel-collector, so dashboards work with either.
+const PrometheusNamespace = "config_sync"
+
+// prometheusCollector exposes the data of OpenCensus views as Prometheus
+// metrics. The view data is read when the metrics are scraped, so the
+// endpoint is always up to date regardless of the OpenCensus reporting period.
+type prometheusCollector struct {
+	views []*view.View
+	descs map[string]*prometheus.Desc
+}
+
+var _ prometheus.Collector = &prometheusCollector{}
+
+// NewPrometheusCollector returns a Prometheus Collector which exposes the data
+// of the specified OpenCensus views. The views must be registered for their
+// data to be collected.
+func NewPrometheusCollector(views ...*view.View) prometheus.Collector {
+	c := &prometheusCollector{
+		views: views,
+		descs: make(map[string]*prometheus.Desc, len(views)),
+	}
+	for _, v := range views {
+		labels := make([]string, len(v.TagKeys))
+		for i, k := range v.TagKeys {
+			labels[i] = sanitizePrometheusName(k.Name())
+		}
+		name := prometheus.BuildFQName(PrometheusNamespace, "", sanitizePrometheusName(v.Name))
+		c.descs[v.Name] = prometheus.NewDesc(name, v.Description, labels, nil)
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *prometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, v := range c.views {
+		ch <- c.descs[v.Name]
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *prometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, v := range c.views {
+		rows, err := view.RetrieveData(v.Name)
+		if err != nil {
+			// The view is not registered in this process.
+			continue
+		}
+		desc := c.descs[v.Name]
+		for _, row := range rows {
+			m, err := toPrometheusMetric(desc, v, row)
+			if err != nil {
+				klog.Warningf("Failed to convert the data of view %q to a Prometheus metric: %v", v.Name, err)
+				continue
+			}
+			if m != nil {
+				ch <- m
+			}
+		}
+	}
+}
+
+func toPrometheusMetric(desc *prometheus.Desc, v *view.View, row *view.Row) (prometheus.Metric, error) {
+	labelValues := tagValues(v, row)
+	switch data := row.Data.(type) {
+	case *view.CountData:
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, float64(data.Value), labelValues...)
+	case *view.SumData:
+		return prometheus.NewConstMetric(desc, prometheus.UntypedValue, data.Value, labelValues...)
+	case *view.LastValueData:
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, data.Value, labelValues...)
+	case *view.DistributionData:
+		// OpenCensus counts per bucket, while Prometheus buckets are
+		// cumulative. The last OpenCensus bucket holds the values above the
+		// highest bound, which Prometheus represents with the implicit +Inf
+		// bucket.
+		buckets := make(map[float64]uint64, len(v.Aggregation.Buckets))
+		var cumulative uint64
+		for i, bound := range v.Aggregation.Buckets {
+			if i < len(data.CountPerBucket) {
+				cumulative += uint64(data.CountPerBucket[i])
+			}
+			buckets[bound] = cumulative
+		}
+		sum := data.Mean * float64(data.Count)
+		return prometheus.NewConstHistogram(desc, uint64(data.Count), sum, buckets, labelValues...)
+	default:
+		return nil, nil
+	}
+}
+
+// tagValues returns the values of the tags of the row, in the order of the tag
+// keys of the view. Missing tags have an empty value.
+func tagValues(v *view.View, row *view.Row) []string {
+	values := make([]string, len(v.TagKeys))
+	for i, k := range v.TagKeys {
+		for _, t := range row.Tags {
+			if t.Key == k {
+				values[i] = t.Value
+				break
+			}
+		}
+	}
+	return values
+}
+
+// sanitizePrometheusName replaces the characters which are not allowed in
+// Prometheus metric and label names with underscores.
+func sanitizePrometheusName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
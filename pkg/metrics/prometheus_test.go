@@ -0,0 +1,118 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	counter := stats.Int64("test_counter", "A test counter", stats.UnitDimensionless)
+	gauge := stats.Int64("test_gauge", "A test gauge", stats.UnitDimensionless)
+	duration := stats.Float64("test/duration_seconds", "A test duration", stats.UnitSeconds)
+	views := []*view.View{
+		{
+			Name:        counter.Name(),
+			Measure:     counter,
+			Description: "The number of test operations",
+			TagKeys:     []tag.Key{KeyOperation, KeyStatus},
+			Aggregation: view.Count(),
+		},
+		{
+			Name:        gauge.Name(),
+			Measure:     gauge,
+			Description: "The current test value",
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        duration.Name(),
+			Measure:     duration,
+			Description: "The test duration distribution",
+			Aggregation: view.Distribution(1, 5),
+		},
+	}
+	require.NoError(t, view.Register(views...))
+	t.Cleanup(func() { view.Unregister(views...) })
+
+	ctx, err := tag.New(context.Background(), tag.Upsert(KeyOperation, "update"), tag.Upsert(KeyStatus, "success"))
+	require.NoError(t, err)
+	stats.Record(ctx, counter.M(1))
+	stats.Record(ctx, counter.M(1))
+	// Leave the status tag unset.
+	ctx, err = tag.New(context.Background(), tag.Upsert(KeyOperation, "delete"))
+	require.NoError(t, err)
+	stats.Record(ctx, counter.M(1))
+	stats.Record(context.Background(), gauge.M(7), gauge.M(3))
+	stats.Record(context.Background(), duration.M(0.5), duration.M(2), duration.M(10))
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(NewPrometheusCollector(views...)))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	got := make(map[string]*dto.MetricFamily)
+	for _, mf := range families {
+		got[mf.GetName()] = mf
+	}
+	require.Len(t, got, 3)
+
+	counterFamily := got["config_sync_test_counter"]
+	require.NotNil(t, counterFamily)
+	assert.Equal(t, dto.MetricType_COUNTER, counterFamily.GetType())
+	assert.Equal(t, "The number of test operations", counterFamily.GetHelp())
+	counts := make(map[string]float64)
+	for _, m := range counterFamily.GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		counts[labels["operation"]+"/"+labels["status"]] = m.GetCounter().GetValue()
+	}
+	assert.Equal(t, map[string]float64{"update/success": 2, "delete/": 1}, counts)
+
+	gaugeFamily := got["config_sync_test_gauge"]
+	require.NotNil(t, gaugeFamily)
+	assert.Equal(t, dto.MetricType_GAUGE, gaugeFamily.GetType())
+	assert.Equal(t, float64(3), gaugeFamily.GetMetric()[0].GetGauge().GetValue())
+
+	histogramFamily := got["config_sync_test_duration_seconds"]
+	require.NotNil(t, histogramFamily)
+	assert.Equal(t, dto.MetricType_HISTOGRAM, histogramFamily.GetType())
+	histogram := histogramFamily.GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(3), histogram.GetSampleCount())
+	assert.InDelta(t, 12.5, histogram.GetSampleSum(), 1e-9)
+	var buckets []uint64
+	for _, b := range histogram.GetBucket() {
+		buckets = append(buckets, b.GetCumulativeCount())
+	}
+	assert.Equal(t, []uint64{1, 2}, buckets)
+}
+
+func TestPrometheusCollectorUnregisteredView(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(NewPrometheusCollector(ReconcileDurationView)))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	assert.Empty(t, families)
+}
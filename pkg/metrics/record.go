@@ -93,6 +93,13 @@ func RecordParserDuration(ctx context.Context, trigger, source, status string, s
 	record(tagCtx, measurement)
 }
 
+// RecordParserCacheLookups produces a measurement for the ParserCacheLookups view.
+func RecordParserCacheLookups(ctx context.Context, result string, count int) {
+	tagCtx, _ := tag.New(ctx, tag.Upsert(KeyCacheResult, result))
+	measurement := ParserCacheLookups.M(int64(count))
+	record(tagCtx, measurement)
+}
+
 // RecordLastSync produces a measurement for the LastSync view.
 func RecordLastSync(ctx context.Context, status, commit string, timestamp time.Time) {
 	tagCtx, _ := tag.New(ctx,
@@ -141,14 +148,15 @@ func RecordApplyDuration(ctx context.Context, status, commit string, startTime t
 }
 
 // RecordResourceFight produces measurements for the ResourceFights view.
-func RecordResourceFight(ctx context.Context, operation string, gvk schema.GroupVersionKind) {
-	//tagCtx, _ := tag.New(ctx,
-	//tag.Upsert(KeyName, GetResourceLabels()),
-	//tag.Upsert(KeyOperation, operation),
-	//tag.Upsert(KeyType, gvk.Kind),
-	//)
+func RecordResourceFight(ctx context.Context, operation string, gvk schema.GroupVersionKind, manager string) {
+	tagCtx, _ := tag.New(ctx,
+		//tag.Upsert(KeyName, GetResourceLabels()),
+		//tag.Upsert(KeyOperation, operation),
+		//tag.Upsert(KeyType, gvk.Kind),
+		tag.Upsert(KeyFieldManager, manager),
+	)
 	measurement := ResourceFights.M(1)
-	record(ctx, measurement)
+	record(tagCtx, measurement)
 }
 
 // RecordRemediateDuration produces measurements for the RemediateDuration view.
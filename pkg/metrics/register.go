@@ -42,26 +42,36 @@ func RegisterOCAgentExporter(containerName string) (*ocagent.Exporter, error) {
 	return oce, nil
 }
 
+// ReconcilerManagerViews are the views of the metrics recorded in the
+// reconciler manager.
+var ReconcilerManagerViews = []*view.View{
+	ReconcileDurationView,
+}
+
+// ReconcilerViews are the views of the metrics recorded in the reconcilers.
+var ReconcilerViews = []*view.View{
+	APICallDurationView,
+	ReconcilerErrorsView,
+	ParserDurationView,
+	ParserCacheLookupsView,
+	LastApplyTimestampView,
+	LastSyncTimestampView,
+	DeclaredResourcesView,
+	ApplyOperationsView,
+	ApplyDurationView,
+	ResourceFightsView,
+	RemediateDurationView,
+	ResourceConflictsView,
+	InternalErrorsView,
+	PipelineErrorView,
+}
+
 // RegisterReconcilerManagerMetricsViews registers the views so that recorded metrics can be exported in the reconciler manager.
 func RegisterReconcilerManagerMetricsViews() error {
-	return view.Register(ReconcileDurationView)
+	return view.Register(ReconcilerManagerViews...)
 }
 
 // RegisterReconcilerMetricsViews registers the views so that recorded metrics can be exported in the reconcilers.
 func RegisterReconcilerMetricsViews() error {
-	return view.Register(
-		APICallDurationView,
-		ReconcilerErrorsView,
-		ParserDurationView,
-		LastApplyTimestampView,
-		LastSyncTimestampView,
-		DeclaredResourcesView,
-		ApplyOperationsView,
-		ApplyDurationView,
-		ResourceFightsView,
-		RemediateDurationView,
-		ResourceConflictsView,
-		InternalErrorsView,
-		PipelineErrorView,
-	)
+	return view.Register(ReconcilerViews...)
 }
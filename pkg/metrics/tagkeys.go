@@ -52,6 +52,9 @@ var (
 	// KeyParserSource groups the metrics for the parser by their source. Possible values: read, parse, update.
 	KeyParserSource, _ = tag.NewKey("source")
 
+	// KeyCacheResult groups the metrics for the parser cache by their result. Possible values: hit, miss.
+	KeyCacheResult, _ = tag.NewKey("result")
+
 	// KeyTrigger groups metrics by their trigger. Possible values: retry, watchUpdate, managementConflict, resync, reimport.
 	KeyTrigger, _ = tag.NewKey("trigger")
 
@@ -65,6 +68,11 @@ var (
 	// TODO: replace with k8s.container.name resource attribute
 	KeyContainer, _ = tag.NewKey("container")
 
+	// KeyFieldManager groups the resource fight metrics by the field manager
+	// Config Sync is fighting with, for example kube-controller-manager or
+	// kubectl-edit. Possible values: <field manager>, unknown.
+	KeyFieldManager, _ = tag.NewKey("field_manager")
+
 	// KeyResourceType groups metris by their resource types. Possible values: cpu, memory.
 	KeyResourceType, _ = tag.NewKey("resource")
 )
@@ -74,6 +74,15 @@ var (
 		Aggregation: view.Distribution(longDistributionBounds...),
 	}
 
+	// ParserCacheLookupsView aggregates the ParserCacheLookups metric measurements.
+	ParserCacheLookupsView = &view.View{
+		Name:        ParserCacheLookups.Name() + "_total",
+		Measure:     ParserCacheLookups,
+		Description: "The total number of files looked up in the parser cache",
+		TagKeys:     []tag.Key{KeyCacheResult},
+		Aggregation: view.Sum(),
+	}
+
 	// LastSyncTimestampView aggregates the LastSyncTimestamp metric measurements.
 	LastSyncTimestampView = &view.View{
 		Name:        LastSync.Name(),
@@ -123,6 +132,7 @@ var (
 		Name:        ResourceFights.Name() + "_total",
 		Measure:     ResourceFights,
 		Description: "The total number of resources that are being synced too frequently",
+		TagKeys:     []tag.Key{KeyFieldManager},
 		Aggregation: view.Count(),
 	}
 
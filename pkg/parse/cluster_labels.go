@@ -0,0 +1,70 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getClusterLabels returns the labels of the current cluster, read from the
+// data of the cluster-labels ConfigMap in the config-management-system
+// namespace. It returns no labels if the ConfigMap does not exist.
+func getClusterLabels(ctx context.Context, c client.Client) (map[string]string, status.Error) {
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{
+		Namespace: configmanagement.ControllerNamespace,
+		Name:      configmanagement.ClusterLabelsConfigMapName,
+	}
+	if err := c.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, status.APIServerError(err, "failed to get the cluster labels ConfigMap")
+	}
+	return cm.Data, nil
+}
+
+// updateClusterLabels refreshes the cached cluster labels used to evaluate
+// ClusterSelectors, and returns true if they changed since the last call.
+//
+// Only the root reconciler reads the cluster labels, since namespace
+// reconcilers are not allowed to read objects in config-management-system.
+func updateClusterLabels(ctx context.Context, p Parser) bool {
+	opts := p.options()
+	if opts.scope != declared.RootReconciler {
+		return false
+	}
+	newLabels, err := getClusterLabels(ctx, p.K8sClient())
+	if err != nil {
+		klog.Warningf("Failed to refresh the cluster labels: %v", err)
+		return false
+	}
+	if labels.Equals(opts.clusterLabels, newLabels) {
+		return false
+	}
+	klog.Infof("Cluster labels changed from %v to %v", opts.clusterLabels, newLabels)
+	opts.clusterLabels = newLabels
+	return true
+}
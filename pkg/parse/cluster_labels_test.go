@@ -0,0 +1,97 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpdateClusterLabels(t *testing.T) {
+	clusterLabelsCM := func(data map[string]string) client.Object {
+		cm := fake.ConfigMapObject(core.Name(configmanagement.ClusterLabelsConfigMapName),
+			core.Namespace(configmanagement.ControllerNamespace))
+		cm.Data = data
+		return cm
+	}
+
+	testCases := []struct {
+		name        string
+		scope       declared.Scope
+		oldLabels   map[string]string
+		objs        []client.Object
+		wantChanged bool
+		wantLabels  map[string]string
+	}{
+		{
+			name:  "no ConfigMap",
+			scope: declared.RootReconciler,
+		},
+		{
+			name:        "new labels",
+			scope:       declared.RootReconciler,
+			objs:        []client.Object{clusterLabelsCM(map[string]string{"environment": "prod"})},
+			wantChanged: true,
+			wantLabels:  map[string]string{"environment": "prod"},
+		},
+		{
+			name:       "unchanged labels",
+			scope:      declared.RootReconciler,
+			oldLabels:  map[string]string{"environment": "prod"},
+			objs:       []client.Object{clusterLabelsCM(map[string]string{"environment": "prod"})},
+			wantLabels: map[string]string{"environment": "prod"},
+		},
+		{
+			name:        "ConfigMap deleted",
+			scope:       declared.RootReconciler,
+			oldLabels:   map[string]string{"environment": "prod"},
+			wantChanged: true,
+		},
+		{
+			name:  "namespace reconciler ignores cluster labels",
+			scope: "bookstore",
+			objs:  []client.Object{clusterLabelsCM(map[string]string{"environment": "prod"})},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := &root{
+				opts: opts{
+					client:        syncertest.NewClient(t, core.Scheme, tc.objs...),
+					clusterLabels: tc.oldLabels,
+					updater: updater{
+						scope: tc.scope,
+					},
+				},
+			}
+			changed := updateClusterLabels(context.Background(), parser)
+			if changed != tc.wantChanged {
+				t.Errorf("updateClusterLabels() = %t, want %t", changed, tc.wantChanged)
+			}
+			if diff := cmp.Diff(tc.wantLabels, parser.clusterLabels); diff != "" {
+				t.Errorf("unexpected cluster labels (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
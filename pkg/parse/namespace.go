@@ -27,13 +27,17 @@ import (
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/policy"
 	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/reposyncpolicy"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
 	"kpt.dev/configsync/pkg/util/compare"
 	utildiscovery "kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate"
@@ -41,7 +45,7 @@ import (
 )
 
 // NewNamespaceRunner creates a new runnable parser for parsing a Namespace repo.
-func NewNamespaceRunner(clusterName, syncName, reconcilerName string, scope declared.Scope, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface) (Parser, error) {
+func NewNamespaceRunner(clusterName, syncName, reconcilerName string, scope declared.Scope, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface, eventRecorder *events.Recorder) (Parser, error) {
 	converter, err := declared.NewValueConverter(dc)
 	if err != nil {
 		return nil, err
@@ -59,6 +63,7 @@ func NewNamespaceRunner(clusterName, syncName, reconcilerName string, scope decl
 			statusUpdatePeriod: statusUpdatePeriod,
 			files:              files{FileSource: fs},
 			parser:             filesystem.NewParser(fileReader),
+			cache:              reader.CacheOf(fileReader),
 			updater: updater{
 				scope:      scope,
 				resources:  resources,
@@ -67,6 +72,7 @@ func NewNamespaceRunner(clusterName, syncName, reconcilerName string, scope decl
 			},
 			discoveryInterface: dc,
 			converter:          converter,
+			eventRecorder:      eventRecorder,
 			mux:                &sync.Mutex{},
 		},
 		scope: scope,
@@ -104,26 +110,44 @@ func (p *namespace) parseSource(ctx context.Context, state sourceState) ([]ast.F
 	}
 	builder := utildiscovery.ScoperBuilder(p.discoveryInterface)
 
+	policies, listErr := reposyncpolicy.List(ctx, p.client)
+	if listErr != nil {
+		return nil, status.APIServerError(listErr, "failed to list the RepoSyncPolicies")
+	}
+
 	klog.Infof("Parsing files from source dir: %s", state.syncDir.OSPath())
 	objs, err := p.parser.Parse(filePaths)
+	p.recordCacheLookups(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	options := validate.Options{
-		ClusterName:  p.clusterName,
-		PolicyDir:    p.SyncDir,
-		PreviousCRDs: crds,
-		BuildScoper:  builder,
-		Converter:    p.converter,
+		ClusterName:   p.clusterName,
+		ClusterLabels: p.clusterLabels,
+		PolicyDir:     p.SyncDir,
+		PreviousCRDs:  crds,
+		BuildScoper:   builder,
+		Converter:     p.converter,
+		Validated:     p.validated(),
 	}
 	options = OptionsForScope(options, p.scope)
+	// Namespace reconcilers cannot read the ConfigMaps of the
+	// config-management-system namespace, so only the rules declared in the
+	// repository apply.
+	options.Visitors = append(options.Visitors, policy.Visitor(nil),
+		reposyncpolicy.Visitor(reposyncpolicy.ForNamespace(policies, string(p.scope)), p.SourceRepo))
 
+	_, span := tracing.StartSpan(ctx, "parse.validate")
 	objs, err = validate.Unstructured(objs, options)
+	tracing.EndSpan(span, err)
 
 	if status.HasBlockingErrors(err) {
 		return nil, err
 	}
+	if err == nil {
+		p.markValidated()
+	}
 
 	// Duplicated with root.go.
 	e := addAnnotationsAndLabels(objs, p.scope, p.syncName, p.sourceContext(), state.commit)
@@ -19,9 +19,14 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem"
+	"kpt.dev/configsync/pkg/importer/reader"
+	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/util/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,9 +36,19 @@ import (
 type opts struct {
 	parser filesystem.ConfigParser
 
+	// cache caches the objects read from each file by the parser, and whether
+	// they passed the validators which only depend on each object. It is nil if
+	// the parser does not cache them.
+	cache *reader.Cache
+
 	// clusterName is the name of the cluster we're syncing configuration to.
 	clusterName string
 
+	// clusterLabels are the labels of the cluster we're syncing configuration
+	// to, read from the cluster itself. They are used to evaluate
+	// ClusterSelectors.
+	clusterLabels map[string]string
+
 	// client knows how to read objects from a Kubernetes cluster and update
 	// status.
 	client client.Client
@@ -68,6 +83,13 @@ type opts struct {
 	// objects in Git.
 	converter *declared.ValueConverter
 
+	// nsControllerState caches the Namespaces on the cluster for evaluating
+	// dynamic NamespaceSelectors. It is only set for the root reconciler.
+	nsControllerState *namespacecontroller.State
+
+	// eventRecorder emits Events about the sync progress on the RSync object.
+	eventRecorder *events.Recorder
+
 	// mux prevents status update conflicts.
 	mux *sync.Mutex
 
@@ -91,6 +113,36 @@ type Parser interface {
 	K8sClient() client.Client
 }
 
+// validated returns the function which skips the validators which only depend
+// on each object for the unchanged objects read from the cache, or nil if the
+// parser does not cache them.
+func (o *opts) validated() func(obj ast.FileObject) bool {
+	if o.cache == nil {
+		return nil
+	}
+	return o.cache.Validated
+}
+
+// markValidated records that the objects read by the latest parse passed the
+// validators.
+func (o *opts) markValidated() {
+	if o.cache != nil {
+		o.cache.MarkValidated()
+	}
+}
+
+// recordCacheLookups records the number of files read from the cache and the
+// number of files parsed by the latest parse.
+func (o *opts) recordCacheLookups(ctx context.Context) {
+	if o.cache == nil {
+		return
+	}
+	hits, misses := o.cache.Stats()
+	klog.V(3).Infof("Read %d unchanged files from the parser cache and parsed %d files", hits, misses)
+	metrics.RecordParserCacheLookups(ctx, "hit", hits)
+	metrics.RecordParserCacheLookups(ctx, "miss", misses)
+}
+
 func (o *opts) k8sClient() client.Client {
 	return o.client
 }
@@ -17,6 +17,7 @@ package parse
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,21 +29,29 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/policy"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
 	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
 	"kpt.dev/configsync/pkg/util/compare"
 	utildiscovery "kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate"
@@ -51,7 +60,7 @@ import (
 )
 
 // NewRootRunner creates a new runnable parser for parsing a Root repository.
-func NewRootRunner(clusterName, syncName, reconcilerName string, format filesystem.SourceFormat, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface) (Parser, error) {
+func NewRootRunner(clusterName, syncName, reconcilerName string, format filesystem.SourceFormat, fileReader reader.Reader, c client.Client, pollingPeriod, resyncPeriod, retryPeriod, statusUpdatePeriod time.Duration, fs FileSource, dc discovery.DiscoveryInterface, resources *declared.Resources, app applier.Applier, rem remediator.Interface, s shard.Shard, nsControllerState *namespacecontroller.State, clusterRules *policy.ClusterRules, eventRecorder *events.Recorder) (Parser, error) {
 	converter, err := declared.NewValueConverter(dc)
 	if err != nil {
 		return nil, err
@@ -69,17 +78,22 @@ func NewRootRunner(clusterName, syncName, reconcilerName string, format filesyst
 			statusUpdatePeriod: statusUpdatePeriod,
 			files:              files{FileSource: fs},
 			parser:             filesystem.NewParser(fileReader),
+			cache:              reader.CacheOf(fileReader),
 			updater: updater{
 				scope:      declared.RootReconciler,
 				resources:  resources,
 				applier:    app,
 				remediator: rem,
+				shard:      s,
 			},
 			discoveryInterface: dc,
 			converter:          converter,
+			eventRecorder:      eventRecorder,
+			nsControllerState:  nsControllerState,
 			mux:                &sync.Mutex{},
 		},
 		sourceFormat: format,
+		clusterRules: clusterRules,
 	}, nil
 }
 
@@ -90,6 +104,9 @@ type root struct {
 	// repository may be SourceFormatHierarchy; all others are implicitly
 	// SourceFormatUnstructured.
 	sourceFormat filesystem.SourceFormat
+
+	// clusterRules are the policy rules declared on the cluster.
+	clusterRules *policy.ClusterRules
 }
 
 var _ Parser = &root{}
@@ -121,29 +138,43 @@ func (p *root) parseSource(ctx context.Context, state sourceState) ([]ast.FileOb
 
 	klog.Infof("Parsing files from source dir: %s", state.syncDir.OSPath())
 	objs, err := p.parser.Parse(filePaths)
+	p.recordCacheLookups(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// Malformed cluster rules are reported without blocking the sync.
+	clusterRules, ruleErrs := p.clusterRules.Rules(ctx)
+
 	options := validate.Options{
-		ClusterName:  p.clusterName,
-		PolicyDir:    p.SyncDir,
-		PreviousCRDs: crds,
-		BuildScoper:  builder,
-		Converter:    p.converter,
+		ClusterName:   p.clusterName,
+		ClusterLabels: p.clusterLabels,
+		PolicyDir:     p.SyncDir,
+		PreviousCRDs:  crds,
+		BuildScoper:   builder,
+		Converter:     p.converter,
+		Validated:     p.validated(),
 	}
 	options = OptionsForScope(options, p.scope)
+	options.Visitors = append(options.Visitors, policy.Visitor(clusterRules))
 
+	_, span := tracing.StartSpan(ctx, "parse.validate")
 	if p.sourceFormat == filesystem.SourceFormatUnstructured {
+		options.NSControllerState = p.nsControllerState
 		options.Visitors = append(options.Visitors, p.addImplicitNamespaces)
 		objs, err = validate.Unstructured(objs, options)
 	} else {
 		objs, err = validate.Hierarchical(objs, options)
 	}
+	tracing.EndSpan(span, err)
 
 	if status.HasBlockingErrors(err) {
-		return nil, err
+		return nil, status.Append(err, ruleErrs)
+	}
+	if err == nil {
+		p.markValidated()
 	}
+	err = status.Append(err, ruleErrs)
 
 	// Duplicated with namespace.go.
 	e := addAnnotationsAndLabels(objs, declared.RootReconciler, p.syncName, p.sourceContext(), state.commit)
@@ -151,11 +182,22 @@ func (p *root) parseSource(ctx context.Context, state sourceState) ([]ast.FileOb
 		err = status.Append(err, status.InternalErrorf("unable to add annotations and labels: %v", e))
 		return nil, err
 	}
+	if p.shard.Sharded() {
+		// Each shard tracks its objects in its own inventory.
+		for _, obj := range objs {
+			inventoryName := shard.Name(p.syncName, p.shard.IndexOf(obj))
+			core.SetAnnotation(obj, metadata.OwningInventoryKey, applier.InventoryID(inventoryName, configsync.ControllerNamespace))
+		}
+	}
 	return objs, err
 }
 
 // setSourceStatus implements the Parser interface
 func (p *root) setSourceStatus(ctx context.Context, newStatus sourceStatus) error {
+	if !p.shard.Primary() {
+		// Only the first shard reports the source status.
+		return nil
+	}
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	return p.setSourceStatusWithRetries(ctx, newStatus, defaultDenominator)
@@ -240,6 +282,11 @@ func setSourceStatusFields(source *v1beta1.SourceStatus, p Parser, newStatus sou
 		}
 		source.Git = nil
 		source.Oci = nil
+	case v1beta1.LocalSource, v1beta1.BucketSource:
+		// The digest of a local or bucket source is reported as the commit.
+		source.Git = nil
+		source.Oci = nil
+		source.Helm = nil
 	}
 	errorSummary := &v1beta1.ErrorSummary{
 		TotalCount:                len(cse),
@@ -253,7 +300,8 @@ func setSourceStatusFields(source *v1beta1.SourceStatus, p Parser, newStatus sou
 
 // setRenderingStatus implements the Parser interface
 func (p *root) setRenderingStatus(ctx context.Context, oldStatus, newStatus renderingStatus) error {
-	if oldStatus.equal(newStatus) {
+	if oldStatus.equal(newStatus) || !p.shard.Primary() {
+		// Only the first shard reports the rendering status.
 		return nil
 	}
 
@@ -341,6 +389,11 @@ func setRenderingStatusFields(rendering *v1beta1.RenderingStatus, p Parser, newS
 		}
 		rendering.Git = nil
 		rendering.Oci = nil
+	case v1beta1.LocalSource, v1beta1.BucketSource:
+		// The digest of a local or bucket source is reported as the commit.
+		rendering.Git = nil
+		rendering.Oci = nil
+		rendering.Helm = nil
 	}
 	rendering.Message = newStatus.message
 	errorSummary := &v1beta1.ErrorSummary{
@@ -358,7 +411,13 @@ func setRenderingStatusFields(rendering *v1beta1.RenderingStatus, p Parser, newS
 func (p *root) SetSyncStatus(ctx context.Context, newStatus syncStatus) error {
 	p.mux.Lock()
 	defer p.mux.Unlock()
-	return p.setSyncStatusWithRetries(ctx, newStatus, defaultDenominator)
+	// The shards of a sharded RootSync update its sync status concurrently.
+	isConflict := func(err error) bool {
+		return apierrors.IsConflict(errors.Cause(err))
+	}
+	return retry.OnError(retry.DefaultRetry, isConflict, func() error {
+		return p.setSyncStatusWithRetries(ctx, newStatus, defaultDenominator)
+	})
 }
 
 func (p *root) setSyncStatusWithRetries(ctx context.Context, newStatus syncStatus, denominator int) error {
@@ -373,10 +432,16 @@ func (p *root) setSyncStatusWithRetries(ctx context.Context, newStatus syncStatu
 
 	currentRS := rs.DeepCopy()
 
-	setSyncStatusFields(&rs.Status.Status, newStatus, denominator)
+	syncing := newStatus.syncing
+	if p.shard.Sharded() {
+		syncing = setShardSyncStatusFields(&rs.Status, p.shard, p.reconcilerName, newStatus, denominator)
+	} else {
+		setSyncStatusFields(&rs.Status.Status, newStatus, denominator)
+		rs.Status.Shards = nil
+	}
 
 	errorSources, errorSummary := summarizeErrors(rs.Status.Source, rs.Status.Sync)
-	if newStatus.syncing {
+	if syncing {
 		rootsync.SetSyncing(rs, true, "Sync", "Syncing", rs.Status.Sync.Commit, errorSources, errorSummary, rs.Status.Sync.LastUpdate)
 	} else {
 		if errorSummary.TotalCount == 0 {
@@ -398,7 +463,7 @@ func (p *root) setSyncStatusWithRetries(ctx context.Context, newStatus syncStatu
 		klog.Infof("New sync errors for RootSync %s/%s: %+v",
 			rs.Namespace, rs.Name, csErrs)
 	}
-	if !newStatus.syncing && rs.Status.Sync.Commit != "" {
+	if !syncing && rs.Status.Sync.Commit != "" {
 		metrics.RecordLastSync(ctx, metrics.StatusTagValueFromSummary(errorSummary), rs.Status.Sync.Commit, rs.Status.Sync.LastUpdate.Time)
 	}
 
@@ -428,6 +493,63 @@ func setSyncStatusFields(syncStatus *v1beta1.Status, newStatus syncStatus, denom
 	syncStatus.Sync.LastUpdate = newStatus.lastUpdate
 }
 
+// setShardSyncStatusFields sets the sync status of the shard in
+// `.status.shards`, and aggregates the sync status of every shard into
+// `.status.sync`. It returns true if any shard is still syncing.
+func setShardSyncStatusFields(rsStatus *v1beta1.RootSyncStatus, s shard.Shard, reconcilerName string, newStatus syncStatus, denominator int) bool {
+	cse := status.ToCSE(newStatus.errs)
+	shardStatus := v1beta1.ShardStatus{
+		Index:      s.Index,
+		Reconciler: reconcilerName,
+		Commit:     newStatus.commit,
+		Syncing:    newStatus.syncing,
+		LastUpdate: newStatus.lastUpdate,
+		Errors:     cse[0 : len(cse)/denominator],
+		ErrorSummary: &v1beta1.ErrorSummary{
+			TotalCount:                len(cse),
+			Truncated:                 denominator != 1,
+			ErrorCountAfterTruncation: len(cse) / denominator,
+		},
+	}
+	// Drop the status of the shards removed since the last update.
+	shards := []v1beta1.ShardStatus{shardStatus}
+	for _, other := range rsStatus.Shards {
+		if other.Index != s.Index && other.Index < s.Count {
+			shards = append(shards, other)
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].Index < shards[j].Index
+	})
+	rsStatus.Shards = shards
+
+	// The RootSync is synced once every shard has synced the same commit.
+	syncing := len(shards) < s.Count
+	var errs []v1beta1.ConfigSyncError
+	errorSummary := &v1beta1.ErrorSummary{}
+	for _, other := range shards {
+		if other.Syncing || other.Commit != newStatus.commit {
+			syncing = true
+		}
+		errs = append(errs, other.Errors...)
+		if other.ErrorSummary != nil {
+			errorSummary.TotalCount += other.ErrorSummary.TotalCount
+			errorSummary.ErrorCountAfterTruncation += other.ErrorSummary.ErrorCountAfterTruncation
+			errorSummary.Truncated = errorSummary.Truncated || other.ErrorSummary.Truncated
+		}
+	}
+	if !syncing {
+		rsStatus.Sync.Commit = newStatus.commit
+	}
+	rsStatus.Sync.Git = rsStatus.Source.Git
+	rsStatus.Sync.Oci = rsStatus.Source.Oci
+	rsStatus.Sync.Helm = rsStatus.Source.Helm
+	rsStatus.Sync.Errors = errs
+	rsStatus.Sync.ErrorSummary = errorSummary
+	rsStatus.Sync.LastUpdate = newStatus.lastUpdate
+	return syncing
+}
+
 func setSyncStatusErrors(syncStatus *v1beta1.Status, cse []v1beta1.ConfigSyncError, denominator int) {
 	syncStatus.Sync.ErrorSummary = &v1beta1.ErrorSummary{
 		TotalCount: len(cse),
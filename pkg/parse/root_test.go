@@ -26,6 +26,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	discovery "k8s.io/client-go/discovery"
@@ -43,7 +44,10 @@ import (
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
+	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
+	syncerreconcile "kpt.dev/configsync/pkg/syncer/reconcile"
 	syncertest "kpt.dev/configsync/pkg/syncer/syncertest/fake"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/testing/openapitest"
@@ -62,11 +66,12 @@ const (
 )
 
 type noOpRemediator struct {
-	needsUpdate bool
+	needsUpdate  bool
+	conflictErrs []status.Error
 }
 
-func (r *noOpRemediator) ConflictErrors() []status.ManagementConflictError {
-	return nil
+func (r *noOpRemediator) ConflictErrors() []status.Error {
+	return r.conflictErrs
 }
 
 func (r *noOpRemediator) NeedsUpdate() bool {
@@ -881,6 +886,41 @@ func TestRoot_SourceAndSyncReconcilerErrorsMetricValidation(t *testing.T) {
 	}
 }
 
+func TestRoot_FightErrorsInSyncStatus(t *testing.T) {
+	deployment := fake.UnstructuredObject(kinds.Deployment(), core.Name("frontend"), core.Namespace("bookstore"))
+	fightErr := syncerreconcile.FightWarning(9.5, deployment, "kube-controller-manager", []string{".spec.replicas"})
+
+	parser := &root{
+		sourceFormat: filesystem.SourceFormatUnstructured,
+		opts: opts{
+			parser: &fakeParser{},
+			updater: updater{
+				scope:      declared.RootReconciler,
+				resources:  &declared.Resources{},
+				remediator: &noOpRemediator{conflictErrs: []status.Error{fightErr}},
+				applier:    &fakeApplier{},
+			},
+			syncName:           rootSyncName,
+			reconcilerName:     rootReconcilerName,
+			client:             syncertest.NewClient(t, core.Scheme, fake.RootSyncObjectV1Beta1(rootSyncName)),
+			discoveryInterface: syncertest.NewDiscoveryClient(kinds.Namespace(), kinds.Role()),
+			mux:                &sync.Mutex{},
+		},
+	}
+	if err := parseAndUpdate(context.Background(), parser, triggerReimport, &reconcilerState{}); err == nil {
+		t.Errorf("parseAndUpdate() should return the fight error")
+	}
+
+	rs := &v1beta1.RootSync{}
+	if err := parser.client.Get(context.Background(), rootsync.ObjectKey(rootSyncName), rs); err != nil {
+		t.Fatal(err)
+	}
+	want := []v1beta1.ConfigSyncError{fightErr.ToCSE()}
+	if diff := cmp.Diff(want, rs.Status.Sync.Errors); diff != "" {
+		t.Errorf("status.sync.errors diff (- want, + got):\n%s", diff)
+	}
+}
+
 func sortObjects(left, right client.Object) bool {
 	leftID := core.IDOf(left)
 	rightID := core.IDOf(right)
@@ -1183,3 +1223,89 @@ func TestSummarizeErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSetShardSyncStatusFields(t *testing.T) {
+	shardErr := status.InternalError("shard-1-error")
+	lastUpdate := metav1.Now()
+	otherShard := v1beta1.ShardStatus{
+		Index:      0,
+		Reconciler: rootReconcilerName,
+		Commit:     "abc",
+		Errors:     status.ToCSE(shardErr),
+		ErrorSummary: &v1beta1.ErrorSummary{
+			TotalCount:                1,
+			ErrorCountAfterTruncation: 1,
+		},
+	}
+	testCases := []struct {
+		name           string
+		shards         []v1beta1.ShardStatus
+		newStatus      syncStatus
+		expectedShards int
+		expectedCommit string
+		expectedErrors int
+		expectSyncing  bool
+	}{
+		{
+			name:           "other shards have not reported yet",
+			newStatus:      syncStatus{commit: "abc", lastUpdate: lastUpdate},
+			expectedShards: 1,
+			expectSyncing:  true,
+		},
+		{
+			name:           "every shard synced the same commit",
+			shards:         []v1beta1.ShardStatus{otherShard},
+			newStatus:      syncStatus{commit: "abc", lastUpdate: lastUpdate},
+			expectedShards: 2,
+			expectedCommit: "abc",
+			expectedErrors: 1,
+		},
+		{
+			name:           "shards synced different commits",
+			shards:         []v1beta1.ShardStatus{otherShard},
+			newStatus:      syncStatus{commit: "def", errs: shardErr, lastUpdate: lastUpdate},
+			expectedShards: 2,
+			expectedErrors: 2,
+			expectSyncing:  true,
+		},
+		{
+			name:           "shard still syncing",
+			shards:         []v1beta1.ShardStatus{otherShard},
+			newStatus:      syncStatus{commit: "abc", syncing: true, lastUpdate: lastUpdate},
+			expectedShards: 2,
+			expectedErrors: 1,
+			expectSyncing:  true,
+		},
+		{
+			name:           "status of removed shards is dropped",
+			shards:         []v1beta1.ShardStatus{otherShard, {Index: 2, Commit: "abc"}},
+			newStatus:      syncStatus{commit: "abc", lastUpdate: lastUpdate},
+			expectedShards: 2,
+			expectedCommit: "abc",
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := shard.Shard{Index: 1, Count: 2, Key: v1beta1.ShardByNamespace}
+			rsStatus := &v1beta1.RootSyncStatus{Shards: tc.shards}
+			syncing := setShardSyncStatusFields(rsStatus, s, rootReconcilerName+"-shard-1", tc.newStatus, defaultDenominator)
+			if syncing != tc.expectSyncing {
+				t.Errorf("setShardSyncStatusFields() got syncing %t, expected %t", syncing, tc.expectSyncing)
+			}
+			if len(rsStatus.Shards) != tc.expectedShards {
+				t.Fatalf("setShardSyncStatusFields() got %d shards, expected %d", len(rsStatus.Shards), tc.expectedShards)
+			}
+			if got := rsStatus.Shards[len(rsStatus.Shards)-1]; got.Index != s.Index || got.Commit != tc.newStatus.commit {
+				t.Errorf("setShardSyncStatusFields() got shard status %+v, expected index %d and commit %q", got, s.Index, tc.newStatus.commit)
+			}
+			if rsStatus.Sync.Commit != tc.expectedCommit {
+				t.Errorf("setShardSyncStatusFields() got commit %q, expected %q", rsStatus.Sync.Commit, tc.expectedCommit)
+			}
+			if len(rsStatus.Sync.Errors) != tc.expectedErrors || rsStatus.Sync.ErrorSummary.TotalCount != tc.expectedErrors {
+				t.Errorf("setShardSyncStatusFields() got errors %v with summary %+v, expected %d errors", rsStatus.Sync.Errors, rsStatus.Sync.ErrorSummary, tc.expectedErrors)
+			}
+		})
+	}
+}
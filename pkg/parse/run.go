@@ -20,13 +20,17 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/hydrate"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
 	webhookconfiguration "kpt.dev/configsync/pkg/webhook/configuration"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,6 +41,8 @@ const (
 	triggerRetry              = "retry"
 	triggerManagementConflict = "managementConflict"
 	triggerWatchUpdate        = "watchUpdate"
+	triggerClusterLabels      = "clusterLabels"
+	triggerNamespaceUpdate    = "namespaceUpdate"
 )
 
 const (
@@ -92,7 +98,15 @@ func Run(ctx context.Context, p Parser) {
 
 		// Re-import declared resources from the filesystem (from git-sync).
 		case <-runTimer.C:
-			run(ctx, p, triggerReimport, state)
+			trigger := triggerReimport
+			if updateClusterLabels(ctx, p) {
+				// The cluster labels changed, so ClusterSelectors need to be
+				// re-evaluated even if there are no new source changes.
+				// The cached sourceState will not be reset to avoid reading all the source files unnecessarily.
+				state.resetAllButSourceState()
+				trigger = triggerClusterLabels
+			}
+			run(ctx, p, trigger, state)
 
 			runTimer.Reset(opts.pollingPeriod)               // Schedule re-run attempt
 			retryTimer.Reset(opts.retryPeriod)               // Schedule retry attempt
@@ -114,6 +128,13 @@ func Run(ctx context.Context, p Parser) {
 			} else if opts.needToUpdateWatch() {
 				klog.Infof("Some watches need to be updated")
 				trigger = triggerWatchUpdate
+			} else if opts.nsControllerState != nil && opts.nsControllerState.IsSyncPending() {
+				klog.Infof("The Namespaces selected by dynamic NamespaceSelectors changed")
+				// Reset the cache to make sure all the steps of a parse-apply-watch loop will run.
+				// The cached sourceState will not be reset to avoid reading all the source files unnecessarily.
+				state.resetAllButSourceState()
+				opts.nsControllerState.ResetSyncPending()
+				trigger = triggerNamespaceUpdate
 			} else {
 				// Don't reset the retry timer if there's nothing to retry.
 				continue
@@ -154,6 +175,7 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 		gs.lastUpdate = metav1.Now()
 		var setSourceStatusErr error
 		if state.needToSetSourceStatus(gs) {
+			recordSourceEvent(ctx, p, state.sourceStatus, gs)
 			setSourceStatusErr = p.setSourceStatus(ctx, gs)
 			if setSourceStatusErr == nil {
 				state.sourceStatus = gs
@@ -188,6 +210,7 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 		rs.message = RenderingFailed
 		rs.lastUpdate = metav1.Now()
 		rs.errs = status.InternalHydrationError(err, "unable to read the done file: %s", doneFilePath)
+		recordRenderingEvent(ctx, p, state.renderingStatus, rs)
 		setRenderingStatusErr := p.setRenderingStatus(ctx, state.renderingStatus, rs)
 		if setRenderingStatusErr == nil {
 			state.renderingStatus = rs
@@ -197,6 +220,26 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 		return
 	}
 
+	if state.needToTraceRun(trigger, gs.commit) {
+		var span *trace.Span
+		start := time.Now()
+		ctx, span = tracing.StartCommitSpan(ctx, "parse.run", p.options().reconcilerName, gs.commit)
+		span.AddAttributes(
+			trace.StringAttribute(tracing.AttrSyncName, p.options().syncName),
+			trace.StringAttribute(tracing.AttrTrigger, trigger))
+		defer func() {
+			span.End()
+			// The reconciler exports the root span of the trace of the commit,
+			// the first time it runs for the commit.
+			if state.tracedCommit != gs.commit {
+				tracing.ExportCommitSpan(p.options().reconcilerName, p.options().syncName, gs.commit, start, time.Now())
+				state.tracedCommit = gs.commit
+			}
+		}()
+	} else {
+		ctx = tracing.WithoutSpans(ctx)
+	}
+
 	// rendering is done, starts to read the source or hydrated configs.
 	oldSyncDir := state.cache.source.syncDir
 	// `read` is called no matter what the trigger is.
@@ -235,6 +278,7 @@ func run(ctx context.Context, p Parser, trigger string, state *reconcilerState)
 func read(ctx context.Context, p Parser, trigger string, state *reconcilerState, sourceState sourceState) status.MultiError {
 	hydrationStatus, sourceStatus := readFromSource(ctx, p, trigger, state, sourceState)
 	hydrationStatus.lastUpdate = metav1.Now()
+	recordRenderingEvent(ctx, p, state.renderingStatus, hydrationStatus)
 	// update the rendering status before source status because the parser needs to
 	// read and parse the configs after rendering is done and there might have errors.
 	setRenderingStatusErr := p.setRenderingStatus(ctx, state.renderingStatus, hydrationStatus)
@@ -256,6 +300,7 @@ func read(ctx context.Context, p Parser, trigger string, state *reconcilerState,
 	sourceStatus.lastUpdate = metav1.Now()
 	var setSourceStatusErr error
 	if state.needToSetSourceStatus(sourceStatus) {
+		recordSourceEvent(ctx, p, state.sourceStatus, sourceStatus)
 		setSourceStatusErr := p.setSourceStatus(ctx, sourceStatus)
 		if setSourceStatusErr == nil {
 			state.sourceStatus = sourceStatus
@@ -316,10 +361,14 @@ func readFromSource(ctx context.Context, p Parser, trigger string, state *reconc
 	state.resetCache()
 
 	// Read all the files under state.syncDir
+	_, span := tracing.StartSpan(ctx, "parse.read")
 	sourceStatus.errs = opts.readConfigFiles(&sourceState)
+	tracing.EndSpan(span, sourceStatus.errs)
 	if sourceStatus.errs == nil {
 		// Set `state.cache.source` after `readConfigFiles` succeeded
 		state.cache.source = sourceState
+		opts.eventRecorder.Eventf(ctx, corev1.EventTypeNormal, events.ReasonSourceFetched,
+			"Fetched commit %s (%d files)", sourceState.commit, len(sourceState.files))
 	}
 	metrics.RecordParserDuration(ctx, trigger, "read", metrics.StatusTagKey(sourceStatus.errs), start)
 	return hydrationStatus, sourceStatus
@@ -331,7 +380,9 @@ func parseSource(ctx context.Context, p Parser, trigger string, state *reconcile
 	}
 
 	start := time.Now()
+	ctx, span := tracing.StartSpan(ctx, "parse.parseSource")
 	objs, sourceErrs := p.parseSource(ctx, state.cache.source)
+	tracing.EndSpan(span, sourceErrs)
 	metrics.RecordParserDuration(ctx, trigger, "parse", metrics.StatusTagKey(sourceErrs), start)
 	state.cache.setParserResult(objs, sourceErrs)
 
@@ -360,6 +411,7 @@ func parseAndUpdate(ctx context.Context, p Parser, trigger string, state *reconc
 		lastUpdate: metav1.Now(),
 	}
 	if state.needToSetSourceStatus(newSourceStatus) {
+		recordSourceEvent(ctx, p, state.sourceStatus, newSourceStatus)
 		if err := p.setSourceStatus(ctx, newSourceStatus); err != nil {
 			// If `p.setSourceStatus` fails, we terminate the reconciliation.
 			// If we call `update` in this case and `update` succeeds, `Status.Source.Commit` would end up be older
@@ -380,7 +432,9 @@ func parseAndUpdate(ctx context.Context, p Parser, trigger string, state *reconc
 	go updateSyncStatusPeriodically(ctxForUpdateSyncStatus, p, state)
 
 	start := time.Now()
-	syncErrs := p.options().Update(ctx, &state.cache)
+	updateCtx, span := tracing.StartSpan(ctx, "parse.update")
+	syncErrs := p.options().Update(updateCtx, &state.cache)
+	tracing.EndSpan(span, syncErrs)
 	metrics.RecordParserDuration(ctx, trigger, "update", metrics.StatusTagKey(syncErrs), start)
 
 	// This is to terminate `updateSyncStatusPeriodically`.
@@ -405,14 +459,6 @@ func setSyncStatus(ctx context.Context, p Parser, state *reconcilerState, syncin
 		errs:       syncErrs,
 		lastUpdate: metav1.Now(),
 	}
-	if state.needToSetSyncStatus(newSyncStatus) {
-		if err := p.SetSyncStatus(ctx, newSyncStatus); err != nil {
-			return err
-		}
-		state.syncStatus = newSyncStatus
-		state.syncingConditionLastUpdate = newSyncStatus.lastUpdate
-	}
-
 	// Extract conflict errors from sync errors.
 	var conflictErrs []status.ManagementConflictError
 	if syncErrs != nil {
@@ -422,6 +468,18 @@ func setSyncStatus(ctx context.Context, p Parser, state *reconcilerState, syncin
 			}
 		}
 	}
+
+	if state.needToSetSyncStatus(newSyncStatus) {
+		if err := p.SetSyncStatus(ctx, newSyncStatus); err != nil {
+			return err
+		}
+		if len(conflictErrs) > 0 && !status.DeepEqual(state.syncStatus.errs, newSyncStatus.errs) {
+			p.options().eventRecorder.Eventf(ctx, corev1.EventTypeWarning, events.ReasonManagementConflict,
+				"%d objects are also managed by another RootSync or RepoSync: %v", len(conflictErrs), conflictErrs[0])
+		}
+		state.syncStatus = newSyncStatus
+		state.syncingConditionLastUpdate = newSyncStatus.lastUpdate
+	}
 	// Report conflict errors to the remote manager, if it's a RootSync.
 	if err := reportRootSyncConflicts(ctx, p.K8sClient(), conflictErrs); err != nil {
 		return errors.Wrapf(err, "failed to report remote conflicts")
@@ -452,6 +510,32 @@ func updateSyncStatusPeriodically(ctx context.Context, p Parser, state *reconcil
 	}
 }
 
+// recordSourceEvent emits an Event when the source starts failing or the
+// source errors change.
+func recordSourceEvent(ctx context.Context, p Parser, oldStatus, newStatus sourceStatus) {
+	if newStatus.errs == nil || oldStatus.equal(newStatus) {
+		return
+	}
+	p.options().eventRecorder.Eventf(ctx, corev1.EventTypeWarning, events.ReasonSourceFailed,
+		"Failed to read or parse commit %q: %s", newStatus.commit, status.FormatSingleLine(newStatus.errs))
+}
+
+// recordRenderingEvent emits an Event when rendering a new commit succeeds or
+// fails.
+func recordRenderingEvent(ctx context.Context, p Parser, oldStatus, newStatus renderingStatus) {
+	if oldStatus.equal(newStatus) {
+		return
+	}
+	switch newStatus.message {
+	case RenderingSucceeded:
+		p.options().eventRecorder.Eventf(ctx, corev1.EventTypeNormal, events.ReasonRenderingSucceeded,
+			"Rendered commit %s", newStatus.commit)
+	case RenderingFailed:
+		p.options().eventRecorder.Eventf(ctx, corev1.EventTypeWarning, events.ReasonRenderingFailed,
+			"Failed to render commit %q: %s", newStatus.commit, status.FormatSingleLine(newStatus.errs))
+	}
+}
+
 // reportRootSyncConflicts reports conflicts to the RootSync that manages the
 // conflicting resources.
 func reportRootSyncConflicts(ctx context.Context, k8sClient client.Client, conflictErrs []status.ManagementConflictError) error {
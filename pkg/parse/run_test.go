@@ -75,3 +75,39 @@ func TestSplitObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestNeedToTraceRun(t *testing.T) {
+	state := &reconcilerState{cache: cacheForCommit{source: sourceState{commit: "abc123"}}}
+	testCases := []struct {
+		name    string
+		trigger string
+		commit  string
+		want    bool
+	}{
+		{
+			name:    "periodic reimport of the commit already read",
+			trigger: triggerReimport,
+			commit:  "abc123",
+		},
+		{
+			name:    "periodic reimport of a new commit",
+			trigger: triggerReimport,
+			commit:  "def456",
+			want:    true,
+		},
+		{
+			name:    "retry of the commit already read",
+			trigger: triggerRetry,
+			commit:  "abc123",
+			want:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := state.needToTraceRun(tc.trigger, tc.commit); got != tc.want {
+				t.Errorf("needToTraceRun(%q, %q) = %t, want %t", tc.trigger, tc.commit, got, tc.want)
+			}
+		})
+	}
+}
@@ -78,6 +78,17 @@ type reconcilerState struct {
 
 	// cache tracks the progress made by the reconciler for a source commit.
 	cache cacheForCommit
+
+	// tracedCommit is the last commit whose root trace span was exported.
+	tracedCommit string
+}
+
+// needToTraceRun returns true if a run of the commit may change the parse
+// state, which is when the commit has not been read yet or the trigger is not
+// the periodic reimport. The periodic reimports of a commit already read are
+// no-ops, which are not traced.
+func (s *reconcilerState) needToTraceRun(trigger, commit string) bool {
+	return trigger != triggerReimport || commit != s.cache.source.commit
 }
 
 func (s *reconcilerState) checkpoint() {
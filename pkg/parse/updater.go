@@ -28,7 +28,9 @@ import (
 	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/remediator"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/tracing"
 	"kpt.dev/configsync/pkg/util/clusterconfig"
 )
 
@@ -38,6 +40,8 @@ type updater struct {
 	resources  *declared.Resources
 	remediator remediator.Interface
 	applier    applier.Applier
+	// shard is the shard of the RootSync objects declared by the updater.
+	shard shard.Shard
 
 	errorMux       sync.RWMutex
 	validationErrs status.MultiError
@@ -121,12 +125,19 @@ func (u *updater) Update(ctx context.Context, cache *cacheForCommit) status.Mult
 	// starts enforcing the updated state.
 	if !cache.resourceDeclSetUpdated {
 		var validationErrs status.MultiError
-		objs, validationErrs = u.resources.Update(ctx, objs)
+		declaredCtx, span := tracing.StartSpan(ctx, "declared.Resources.Update")
+		// Only the objects of the shard are declared, so that the Remediator
+		// leaves the objects of other shards to their reconcilers. The applier
+		// still gets every object, to release the ones of other shards.
+		owned, others := u.shard.Partition(objs)
+		owned, validationErrs = u.resources.Update(declaredCtx, owned)
+		tracing.EndSpan(span, validationErrs)
 		u.setValidationErrs(validationErrs)
 		if validationErrs != nil {
 			klog.Warningf("Failed to validate declared resources: %v", validationErrs)
 			return validationErrs
 		}
+		objs = append(owned, others...)
 
 		if cache.parserErrs == nil {
 			cache.resourceDeclSetUpdated = true
@@ -151,7 +162,9 @@ func (u *updater) Update(ctx context.Context, cache *cacheForCommit) status.Mult
 	}
 
 	// Update the Remediator's watches to start new ones and stop old ones.
-	watchErrs := u.remediator.UpdateWatches(ctx, gvks)
+	watchCtx, span := tracing.StartSpan(ctx, "remediator.UpdateWatches")
+	watchErrs := u.remediator.UpdateWatches(watchCtx, gvks)
+	tracing.EndSpan(span, watchErrs)
 	u.setWatchErrs(watchErrs)
 	if watchErrs != nil {
 		klog.Warningf("Failed to update resource watches: %v", watchErrs)
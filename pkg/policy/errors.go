@@ -0,0 +1,34 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InvalidPolicyRuleErrorCode is the error code for InvalidPolicyRuleError.
+const InvalidPolicyRuleErrorCode = status.InvalidPolicyRuleErrorCode
+
+var invalidPolicyRuleErrorBuilder = status.NewErrorBuilder(InvalidPolicyRuleErrorCode)
+
+// InvalidPolicyRuleError reports that a policy rule is malformed, or could not
+// be evaluated against an object. The rule is skipped, but the other rules are
+// still evaluated and the sync is not blocked.
+func InvalidPolicyRuleError(resource client.Object, rule string, err error) status.Error {
+	return invalidPolicyRuleErrorBuilder.
+		Sprintf("The policy rule %q is invalid: %s", rule, err.Error()).
+		BuildWithResources(resource)
+}
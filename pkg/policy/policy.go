@@ -0,0 +1,273 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy evaluates user-defined policy rules against the objects of a
+// repository.
+//
+// Rules are declared in ConfigMaps annotated with
+// `configsync.gke.io/policy-rules: "true"`, either in the repository itself or
+// in the config-management-system namespace of the cluster. Each key of the
+// ConfigMap data is a rule formatted as YAML:
+//
+//	message: Containers must set resource limits
+//	code: "3001"
+//	match:
+//	- group: apps
+//	  kind: Deployment
+//	expression: object.spec.template.spec.containers.all(c, has(c.resources.limits))
+//
+// The expression is a CEL expression evaluated with the object bound to the
+// `object` variable, like the validations of a Kubernetes
+// ValidatingAdmissionPolicy. An object matched by the rule violates it if the
+// expression evaluates to false.
+//
+// Namespace reconcilers only evaluate the rules declared in their repository.
+// The ConfigMaps declared in a repository are synced like any other object.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// objectVariable is the name of the CEL variable bound to the object.
+	objectVariable = "object"
+	// costLimit bounds the cost of evaluating an expression against an
+	// object, so a rule cannot stall the validation of a repository.
+	costLimit = 1000000
+)
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+// celEnv returns the CEL environment the expressions are compiled in.
+func celEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(cel.Variable(objectVariable, cel.DynType))
+	})
+	return env, envErr
+}
+
+// Rule is a user-defined policy rule.
+type Rule struct {
+	// Name identifies the rule, formatted as `<namespace>/<configmap>/<key>`.
+	Name string `json:"-"`
+	// Message describes the rule to the users violating it.
+	Message string `json:"message"`
+	// Code is the error code reported for violations, between 3000 and 3999.
+	// Defaults to 3000.
+	Code string `json:"code,omitempty"`
+	// Match limits the rule to the objects of the specified kinds. The rule
+	// applies to every object if empty.
+	Match []Match `json:"match,omitempty"`
+	// Expression is a CEL expression which must evaluate to true for every
+	// object the rule applies to.
+	Expression string `json:"expression"`
+
+	// program is the compiled Expression.
+	program cel.Program
+}
+
+// Match selects objects by group and kind.
+type Match struct {
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind"`
+}
+
+// IsRules returns true if the object is a ConfigMap declaring policy rules.
+func IsRules(obj client.Object) bool {
+	return obj.GetObjectKind().GroupVersionKind().GroupKind() == kinds.ConfigMap().GroupKind() &&
+		core.GetAnnotation(obj, metadata.PolicyRulesAnnotationKey) == "true"
+}
+
+// FromObjects returns the policy rules declared in the ConfigMaps among the
+// objects.
+func FromObjects(objs []ast.FileObject) ([]Rule, status.MultiError) {
+	var rules []Rule
+	var errs status.MultiError
+	for i := range objs {
+		obj := &objs[i]
+		if !IsRules(obj) {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, cm); err != nil {
+			errs = status.Append(errs, InvalidPolicyRuleError(obj, obj.GetName(), err))
+			continue
+		}
+		r, err := fromConfigMap(cm)
+		rules = append(rules, r...)
+		errs = status.Append(errs, err)
+	}
+	return rules, errs
+}
+
+// FromConfigMaps returns the policy rules declared in the ConfigMaps.
+func FromConfigMaps(cms []corev1.ConfigMap) ([]Rule, status.MultiError) {
+	var rules []Rule
+	var errs status.MultiError
+	for i := range cms {
+		if !IsRules(&cms[i]) {
+			continue
+		}
+		r, err := fromConfigMap(&cms[i])
+		rules = append(rules, r...)
+		errs = status.Append(errs, err)
+	}
+	return rules, errs
+}
+
+func fromConfigMap(cm *corev1.ConfigMap) ([]Rule, status.MultiError) {
+	cm.SetGroupVersionKind(kinds.ConfigMap())
+	var keys []string
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var rules []Rule
+	var errs status.MultiError
+	for _, k := range keys {
+		name := fmt.Sprintf("%s/%s/%s", cm.Namespace, cm.Name, k)
+		rule := Rule{}
+		if err := yaml.UnmarshalStrict([]byte(cm.Data[k]), &rule); err != nil {
+			errs = status.Append(errs, InvalidPolicyRuleError(cm, name, err))
+			continue
+		}
+		rule.Name = name
+		if err := rule.compile(); err != nil {
+			errs = status.Append(errs, InvalidPolicyRuleError(cm, name, err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, errs
+}
+
+// compile validates the rule and compiles its expression.
+func (r *Rule) compile() error {
+	if r.Message == "" {
+		return fmt.Errorf("message must be set")
+	}
+	if r.Code != "" && !status.IsPolicyViolationErrorCode(r.Code) {
+		return fmt.Errorf("code must be between %d and %d, got %q",
+			status.MinPolicyViolationErrorCode, status.MaxPolicyViolationErrorCode, r.Code)
+	}
+	for _, m := range r.Match {
+		if m.Kind == "" {
+			return fmt.Errorf("match must set the kind")
+		}
+	}
+	if r.Expression == "" {
+		return fmt.Errorf("expression must be set")
+	}
+	e, err := celEnv()
+	if err != nil {
+		return err
+	}
+	checked, issues := e.Compile(r.Expression)
+	if issues != nil && issues.Err() != nil {
+		return fmt.Errorf("invalid CEL expression: %w", issues.Err())
+	}
+	if checked.OutputType() != cel.BoolType && checked.OutputType() != cel.DynType {
+		return fmt.Errorf("expression must evaluate to a bool, got %s", checked.OutputType())
+	}
+	r.program, err = e.Program(checked, cel.CostLimit(costLimit))
+	if err != nil {
+		return fmt.Errorf("invalid CEL expression: %w", err)
+	}
+	return nil
+}
+
+// matches returns true if the rule applies to objects of the GroupKind.
+func (r Rule) matches(gk schema.GroupKind) bool {
+	if len(r.Match) == 0 {
+		return true
+	}
+	for _, m := range r.Match {
+		if m.Group == gk.Group && m.Kind == gk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// violated returns true if the object violates the rule.
+func (r Rule) violated(obj map[string]interface{}) (bool, error) {
+	out, _, err := r.program.Eval(map[string]interface{}{objectVariable: obj})
+	if err != nil {
+		return false, err
+	}
+	allowed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %v, not a bool", out.Value())
+	}
+	return !allowed, nil
+}
+
+// Evaluate returns the violations of the rules by the objects.
+func Evaluate(rules []Rule, objs []ast.FileObject) status.MultiError {
+	var errs status.MultiError
+	for i := range objs {
+		obj := &objs[i]
+		gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+		for _, rule := range rules {
+			if !rule.matches(gk) {
+				continue
+			}
+			violated, err := rule.violated(obj.Unstructured.Object)
+			if err != nil {
+				errs = status.Append(errs, InvalidPolicyRuleError(obj, rule.Name, err))
+				continue
+			}
+			if violated {
+				errs = status.Append(errs, status.PolicyViolationError(rule.Code, rule.Name, rule.Message, obj))
+			}
+		}
+	}
+	return errs
+}
+
+// Merge returns the rules, with the rules declared in the repository
+// overriding the rules with the same name declared on the cluster.
+func Merge(clusterRules, repoRules []Rule) []Rule {
+	names := make(map[string]bool)
+	for _, r := range repoRules {
+		names[r.Name] = true
+	}
+	rules := append([]Rule{}, repoRules...)
+	for _, r := range clusterRules {
+		if !names[r.Name] {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
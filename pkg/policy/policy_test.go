@@ -0,0 +1,244 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/status"
+	syncertestfake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+const (
+	limitsRule = `message: Containers must set resource limits
+code: "3001"
+match:
+- group: apps
+  kind: Deployment
+expression: object.spec.template.spec.containers.all(c, has(c.resources.limits))
+`
+	hostPathRule = `message: hostPath volumes are not allowed
+match:
+- group: apps
+  kind: Deployment
+expression: "!has(object.spec.template.spec.volumes) || object.spec.template.spec.volumes.all(v, !has(v.hostPath))"
+`
+)
+
+func rulesConfigMap(data map[string]string) ast.FileObject {
+	cm := fake.ConfigMapObject(core.Name("policy"), core.Namespace("default"),
+		core.Annotation(metadata.PolicyRulesAnnotationKey, "true"))
+	cm.Data = data
+	return fake.FileObject(cm, "policy.yaml")
+}
+
+func deployment(limits bool, hostPath bool) ast.FileObject {
+	d := fake.DeploymentObject(core.Name("app"), core.Namespace("default"))
+	d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app"}}
+	if limits {
+		d.Spec.Template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}
+	}
+	if hostPath {
+		d.Spec.Template.Spec.Volumes = []corev1.Volume{{
+			Name:         "host",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}},
+		}}
+	}
+	return fake.FileObject(d, "deployment.yaml")
+}
+
+// compiledRules returns the rules declared in a ConfigMap on the cluster.
+func compiledRules(t *testing.T, data map[string]string) []Rule {
+	t.Helper()
+	cm := fake.ConfigMapObject(core.Name("policy"), core.Namespace(configmanagement.ControllerNamespace),
+		core.Annotation(metadata.PolicyRulesAnnotationKey, "true"))
+	cm.Data = data
+	rules, errs := FromConfigMaps([]corev1.ConfigMap{*cm})
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	return rules
+}
+
+func TestVisitor(t *testing.T) {
+	testCases := []struct {
+		name         string
+		clusterRules []Rule
+		objs         []ast.FileObject
+		wantCodes    []string
+	}{
+		{
+			name: "no rules",
+			objs: []ast.FileObject{deployment(false, true)},
+		},
+		{
+			name: "rules satisfied",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"limits": limitsRule, "host-path": hostPathRule}),
+				deployment(true, false),
+			},
+		},
+		{
+			name: "require violated",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"limits": limitsRule}),
+				deployment(false, false),
+			},
+			wantCodes: []string{"3001"},
+		},
+		{
+			name: "deny violated",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"host-path": hostPathRule}),
+				deployment(true, true),
+			},
+			wantCodes: []string{status.PolicyViolationErrorCode},
+		},
+		{
+			name:         "cluster rule violated",
+			clusterRules: compiledRules(t, map[string]string{"limits": strings.Replace(limitsRule, "3001", "3002", 1)}),
+			objs:         []ast.FileObject{deployment(false, false)},
+			wantCodes:    []string{"3002"},
+		},
+		{
+			name: "invalid code",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"limits": "message: limits\ncode: \"1010\"\nexpression: has(object.spec)"}),
+				deployment(false, false),
+			},
+			wantCodes: []string{InvalidPolicyRuleErrorCode},
+		},
+		{
+			name: "invalid rule does not disable the other rules",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{
+					"invalid": "message: invalid\nexpression: object.spec.(",
+					"limits":  limitsRule,
+				}),
+				deployment(false, false),
+			},
+			wantCodes: []string{InvalidPolicyRuleErrorCode, "3001"},
+		},
+		{
+			name: "expression not a bool",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"limits": "message: limits\nexpression: size(object.metadata.name)"}),
+			},
+			wantCodes: []string{InvalidPolicyRuleErrorCode},
+		},
+		{
+			name: "unknown field",
+			objs: []ast.FileObject{
+				rulesConfigMap(map[string]string{"limits": "message: limits\nrequire: $.spec"}),
+			},
+			wantCodes: []string{InvalidPolicyRuleErrorCode},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := Visitor(tc.clusterRules)(tc.objs)
+			var gotCodes []string
+			if errs != nil {
+				for _, err := range errs.Errors() {
+					gotCodes = append(gotCodes, err.Code())
+				}
+			}
+			if diff := cmp.Diff(tc.wantCodes, gotCodes); diff != "" {
+				t.Errorf("got diff in error codes (-want +got):\n%s\nerrors: %v", diff, errs)
+			}
+		})
+	}
+}
+
+func TestFromConfigMaps(t *testing.T) {
+	cms := []corev1.ConfigMap{
+		*fake.ConfigMapObject(core.Name("policy"), core.Namespace("config-management-system"),
+			core.Annotation(metadata.PolicyRulesAnnotationKey, "true")),
+		*fake.ConfigMapObject(core.Name("other"), core.Namespace("config-management-system")),
+	}
+	cms[0].Data = map[string]string{"host-path": hostPathRule}
+	cms[1].Data = map[string]string{"host-path": hostPathRule}
+
+	rules, errs := FromConfigMaps(cms)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	want := []Rule{{
+		Name:       "config-management-system/policy/host-path",
+		Message:    "hostPath volumes are not allowed",
+		Match:      []Match{{Group: "apps", Kind: "Deployment"}},
+		Expression: "!has(object.spec.template.spec.volumes) || object.spec.template.spec.volumes.all(v, !has(v.hostPath))",
+	}}
+	if diff := cmp.Diff(want, rules, cmpopts.IgnoreUnexported(Rule{})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cluster := []Rule{{Name: "a", Message: "cluster"}, {Name: "b", Message: "cluster"}}
+	repo := []Rule{{Name: "b", Message: "repo"}}
+	want := []Rule{{Name: "b", Message: "repo"}, {Name: "a", Message: "cluster"}}
+	if diff := cmp.Diff(want, Merge(cluster, repo), cmpopts.IgnoreUnexported(Rule{})); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClusterRules(t *testing.T) {
+	valid := fake.ConfigMapObject(core.Name("valid"), core.Namespace(configmanagement.ControllerNamespace),
+		core.Annotation(metadata.PolicyRulesAnnotationKey, "true"))
+	valid.Data = map[string]string{"limits": limitsRule}
+	invalid := fake.ConfigMapObject(core.Name("invalid"), core.Namespace(configmanagement.ControllerNamespace),
+		core.Annotation(metadata.PolicyRulesAnnotationKey, "true"))
+	invalid.Data = map[string]string{"broken": "message: broken\nexpression: object.("}
+	other := fake.ConfigMapObject(core.Name("other"), core.Namespace(configmanagement.ControllerNamespace))
+	other.Data = map[string]string{"limits": limitsRule}
+	fakeClient := syncertestfake.NewClient(t, core.Scheme, valid, invalid, other)
+
+	clusterRules := NewClusterRules(fakeClient)
+	rules, errs := clusterRules.Rules(context.Background())
+	// The malformed rule is reported on its own, and the valid rule is kept.
+	if errs == nil || len(errs.Errors()) != 1 || errs.Errors()[0].Code() != InvalidPolicyRuleErrorCode {
+		t.Errorf("got errors %v, want one %s error", errs, InvalidPolicyRuleErrorCode)
+	}
+	if len(rules) != 1 || rules[0].Name != "config-management-system/valid/limits" {
+		t.Fatalf("got rules %v, want config-management-system/valid/limits", rules)
+	}
+
+	// Unchanged ConfigMaps are not compiled again.
+	program := rules[0].program
+	rules, _ = clusterRules.Rules(context.Background())
+	if len(rules) != 1 || rules[0].program != program {
+		t.Errorf("got a recompiled rule for an unchanged ConfigMap")
+	}
+
+	var nilRules *ClusterRules
+	if rules, errs := nilRules.Rules(context.Background()); rules != nil || errs != nil {
+		t.Errorf("got rules %v and errors %v from nil ClusterRules, want none", rules, errs)
+	}
+}
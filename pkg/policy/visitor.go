@@ -0,0 +1,123 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterRules looks up the policy rules declared in the ConfigMaps of the
+// config-management-system namespace. The reader is expected to be backed by
+// an informer, so looking up the rules on every parse does not contact the
+// API server. The rules of a ConfigMap are only compiled again when it
+// changes. A nil ClusterRules has no rules.
+type ClusterRules struct {
+	reader client.Reader
+
+	mux      sync.Mutex
+	compiled map[types.NamespacedName]compiledConfigMap
+}
+
+// compiledConfigMap is the result of compiling the rules of a version of a
+// ConfigMap.
+type compiledConfigMap struct {
+	resourceVersion string
+	rules           []Rule
+	errs            status.MultiError
+}
+
+// NewClusterRules returns a ClusterRules which reads the ConfigMaps with the
+// reader.
+func NewClusterRules(reader client.Reader) *ClusterRules {
+	return &ClusterRules{
+		reader:   reader,
+		compiled: make(map[types.NamespacedName]compiledConfigMap),
+	}
+}
+
+// Rules returns the valid rules declared on the cluster, and the errors of the
+// malformed rules. Failing to list the ConfigMaps is logged, and the rules
+// listed last are returned, so it does not fail the sync.
+func (c *ClusterRules) Rules(ctx context.Context) ([]Rule, status.MultiError) {
+	if c == nil {
+		return nil, nil
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	cms := &corev1.ConfigMapList{}
+	if err := c.reader.List(ctx, cms, client.InNamespace(configmanagement.ControllerNamespace)); err != nil {
+		klog.Warningf("Failed to list the ConfigMaps declaring policy rules, using the rules listed last: %v", err)
+		return c.collect()
+	}
+
+	compiled := make(map[types.NamespacedName]compiledConfigMap)
+	for i := range cms.Items {
+		cm := &cms.Items[i]
+		if !IsRules(cm) {
+			continue
+		}
+		key := client.ObjectKeyFromObject(cm)
+		if prev, found := c.compiled[key]; found && prev.resourceVersion == cm.ResourceVersion {
+			compiled[key] = prev
+			continue
+		}
+		rules, errs := fromConfigMap(cm.DeepCopy())
+		compiled[key] = compiledConfigMap{resourceVersion: cm.ResourceVersion, rules: rules, errs: errs}
+	}
+	c.compiled = compiled
+	return c.collect()
+}
+
+// collect returns the compiled rules and errors of every ConfigMap, sorted by
+// ConfigMap name.
+func (c *ClusterRules) collect() ([]Rule, status.MultiError) {
+	var keys []types.NamespacedName
+	for key := range c.compiled {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	var rules []Rule
+	var errs status.MultiError
+	for _, key := range keys {
+		cm := c.compiled[key]
+		rules = append(rules, cm.rules...)
+		errs = status.Append(errs, cm.errs)
+	}
+	return rules, errs
+}
+
+// Visitor returns a visitor which evaluates the policy rules declared in the
+// repository and the specified rules declared on the cluster against the final
+// objects. Malformed rules are reported and skipped.
+func Visitor(clusterRules []Rule) validate.VisitorFunc {
+	return func(objs []ast.FileObject) ([]ast.FileObject, status.MultiError) {
+		repoRules, errs := FromObjects(objs)
+		return objs, status.Append(errs, Evaluate(Merge(clusterRules, repoRules), objs))
+	}
+}
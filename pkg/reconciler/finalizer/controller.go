@@ -27,6 +27,7 @@ import (
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -53,6 +54,8 @@ import (
 type Controller struct {
 	SyncScope declared.Scope
 	SyncName  string
+	// Shard is the shard of the RootSync objects handled by the reconciler.
+	Shard     shard.Shard
 	Client    client.Client
 	Mapper    meta.RESTMapper
 	Scheme    *runtime.Scheme
@@ -127,7 +130,7 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	if !rs.GetDeletionTimestamp().IsZero() {
 		// Object being deleted.
-		if controllerutil.ContainsFinalizer(rs, metadata.ReconcilerFinalizer) {
+		if controllerutil.ContainsFinalizer(rs, finalizerName(c.Shard)) {
 			if err := c.Finalizer.Finalize(ctx, rs); err != nil {
 				return result, errors.Wrapf(err, "finalizing")
 			}
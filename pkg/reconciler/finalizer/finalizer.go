@@ -20,7 +20,9 @@ import (
 
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/shard"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -34,13 +36,15 @@ type Finalizer interface {
 
 // New constructs a new RootSyncFinalizer or RepoSyncFinalizer, depending on the
 // specified scope.
-func New(scope declared.Scope, destroyer applier.Destroyer, c client.Client, stopControllers context.CancelFunc, controllersStopped <-chan struct{}) Finalizer {
+func New(scope declared.Scope, s shard.Shard, destroyer applier.Destroyer, c client.Client, stopControllers context.CancelFunc, controllersStopped <-chan struct{}, recorder *events.Recorder) Finalizer {
 	if scope == declared.RootReconciler {
 		return &RootSyncFinalizer{
+			Shard:              s,
 			Destroyer:          destroyer,
 			Client:             c,
 			StopControllers:    stopControllers,
 			ControllersStopped: controllersStopped,
+			Recorder:           recorder,
 		}
 	}
 	return &RepoSyncFinalizer{
@@ -48,21 +52,29 @@ func New(scope declared.Scope, destroyer applier.Destroyer, c client.Client, sto
 		Client:             c,
 		StopControllers:    stopControllers,
 		ControllersStopped: controllersStopped,
+		Recorder:           recorder,
 	}
 }
 
-// addFinalizer adds the `configsync.gke.io/reconciler` finalizer to the
-// specified object, locally.
+// finalizerName returns the name of the finalizer of the reconciler of the
+// shard. Each shard of a RootSync adds its own finalizer, so that the RootSync
+// is only deleted after every shard has deleted its managed objects.
+func finalizerName(s shard.Shard) string {
+	return shard.Name(metadata.ReconcilerFinalizer, s.Index)
+}
+
+// addFinalizer adds the `configsync.gke.io/reconciler` finalizer of the shard
+// to the specified object, locally.
 // Returns true, if the object was modified.
-func addFinalizer(syncObj client.Object) bool {
-	return controllerutil.AddFinalizer(syncObj, metadata.ReconcilerFinalizer)
+func addFinalizer(syncObj client.Object, s shard.Shard) bool {
+	return controllerutil.AddFinalizer(syncObj, finalizerName(s))
 }
 
-// removeFinalizer removes the `configsync.gke.io/reconciler` finalizer from the
-// specified object, locally.
+// removeFinalizer removes the `configsync.gke.io/reconciler` finalizer of the
+// shard from the specified object, locally.
 // Returns true, if the object was modified.
-func removeFinalizer(syncObj client.Object) bool {
-	return controllerutil.RemoveFinalizer(syncObj, metadata.ReconcilerFinalizer)
+func removeFinalizer(syncObj client.Object, s shard.Shard) bool {
+	return controllerutil.RemoveFinalizer(syncObj, finalizerName(s))
 }
 
 func objSummary(obj client.Object) string {
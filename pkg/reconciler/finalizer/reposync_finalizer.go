@@ -18,10 +18,13 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/util/mutate"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +44,9 @@ type RepoSyncFinalizer struct {
 	// remediator have fully stopped. This unblocks Finalize() to destroy
 	// managed resource objects.
 	ControllersStopped <-chan struct{}
+
+	// Recorder emits Events about the finalizer progress on the syncObj.
+	Recorder *events.Recorder
 }
 
 // Finalize performs the following actions on the syncObj (RepoSync):
@@ -70,10 +76,16 @@ func (f *RepoSyncFinalizer) Finalize(ctx context.Context, syncObj client.Object)
 		return errors.Wrap(err, "setting Finalizing condition")
 	}
 
+	f.Recorder.ObjectEventf(rs, corev1.EventTypeNormal, events.ReasonFinalizing,
+		"Deleting managed objects")
 	if err := f.deleteManagedObjects(ctx, rs); err != nil {
+		f.Recorder.ObjectEventf(rs, corev1.EventTypeWarning, events.ReasonFinalizeFailed,
+			"Failed to delete managed objects: %v", err)
 		return errors.Wrap(err, "deleting managed objects")
 	}
 	klog.Infof("Deletion of managed objects successful")
+	f.Recorder.ObjectEventf(rs, corev1.EventTypeNormal, events.ReasonFinalized,
+		"Deleted managed objects")
 
 	// TODO: optimize by combining these updates into a single update
 	if _, err := f.removeFinalizingCondition(ctx, rs); err != nil {
@@ -91,7 +103,7 @@ func (f *RepoSyncFinalizer) Finalize(ctx context.Context, syncObj client.Object)
 // The specified syncObj must be of type `*v1beta1.RepoSync`.
 func (f *RepoSyncFinalizer) AddFinalizer(ctx context.Context, syncObj client.Object) (bool, error) {
 	updated, err := mutate.WithRetry(ctx, f.Client, syncObj, func() error {
-		if !addFinalizer(syncObj) {
+		if !addFinalizer(syncObj, shard.Shard{}) {
 			// Already added. No change necessary.
 			return &mutate.NoUpdateError{}
 		}
@@ -114,7 +126,7 @@ func (f *RepoSyncFinalizer) AddFinalizer(ctx context.Context, syncObj client.Obj
 // The specified syncObj must be of type `*v1beta1.RepoSync`.
 func (f *RepoSyncFinalizer) RemoveFinalizer(ctx context.Context, syncObj client.Object) (bool, error) {
 	updated, err := mutate.WithRetry(ctx, f.Client, syncObj, func() error {
-		if !removeFinalizer(syncObj) {
+		if !removeFinalizer(syncObj, shard.Shard{}) {
 			// Already removed. No change necessary.
 			return &mutate.NoUpdateError{}
 		}
@@ -18,10 +18,13 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
+	"kpt.dev/configsync/pkg/events"
 	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/util/mutate"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,6 +34,8 @@ import (
 // to destroy all managed user objects previously applied from source.
 // Impliments the Finalizer interface.
 type RootSyncFinalizer struct {
+	// Shard is the shard of the RootSync objects deleted by the finalizer.
+	Shard     shard.Shard
 	Destroyer applier.Destroyer
 	Client    client.Client
 
@@ -41,6 +46,9 @@ type RootSyncFinalizer struct {
 	// remediator have fully stopped. This unblocks Finalize() to destroy
 	// managed resource objects.
 	ControllersStopped <-chan struct{}
+
+	// Recorder emits Events about the finalizer progress on the syncObj.
+	Recorder *events.Recorder
 }
 
 // Finalize performs the following actions on the syncObj (RootSync):
@@ -70,10 +78,16 @@ func (f *RootSyncFinalizer) Finalize(ctx context.Context, syncObj client.Object)
 		return errors.Wrap(err, "setting Finalizing condition")
 	}
 
+	f.Recorder.ObjectEventf(rs, corev1.EventTypeNormal, events.ReasonFinalizing,
+		"Deleting managed objects")
 	if err := f.deleteManagedObjects(ctx, rs); err != nil {
+		f.Recorder.ObjectEventf(rs, corev1.EventTypeWarning, events.ReasonFinalizeFailed,
+			"Failed to delete managed objects: %v", err)
 		return errors.Wrap(err, "deleting managed objects")
 	}
 	klog.Infof("Deletion of managed objects successful")
+	f.Recorder.ObjectEventf(rs, corev1.EventTypeNormal, events.ReasonFinalized,
+		"Deleted managed objects")
 
 	// TODO: optimize by combining these updates into a single update
 	if _, err := f.removeFinalizingCondition(ctx, rs); err != nil {
@@ -91,7 +105,7 @@ func (f *RootSyncFinalizer) Finalize(ctx context.Context, syncObj client.Object)
 // The specified syncObj must be of type `*v1beta1.RootSync`.
 func (f *RootSyncFinalizer) AddFinalizer(ctx context.Context, syncObj client.Object) (bool, error) {
 	updated, err := mutate.WithRetry(ctx, f.Client, syncObj, func() error {
-		if !addFinalizer(syncObj) {
+		if !addFinalizer(syncObj, f.Shard) {
 			// Already added. No change necessary.
 			return &mutate.NoUpdateError{}
 		}
@@ -114,7 +128,7 @@ func (f *RootSyncFinalizer) AddFinalizer(ctx context.Context, syncObj client.Obj
 // The specified syncObj must be of type `*v1beta1.RootSync`.
 func (f *RootSyncFinalizer) RemoveFinalizer(ctx context.Context, syncObj client.Object) (bool, error) {
 	updated, err := mutate.WithRetry(ctx, f.Client, syncObj, func() error {
-		if !removeFinalizer(syncObj) {
+		if !removeFinalizer(syncObj, f.Shard) {
 			// Already removed. No change necessary.
 			return &mutate.NoUpdateError{}
 		}
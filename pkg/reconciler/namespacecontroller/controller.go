@@ -0,0 +1,114 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/status"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Controller watches the Namespaces on the cluster and records their labels
+// in the State, so that dynamic NamespaceSelectors can be evaluated against
+// live Namespaces and re-evaluated when their labels change.
+type Controller struct {
+	Client client.Client
+	State  *State
+}
+
+// SetupWithManager registers the namespace Controller with the reconciler.
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("Namespace").
+		For(&corev1.Namespace{}, builder.WithPredicates(
+			predicate.Or(predicate.LabelChangedPredicate{}, deletionPredicate()),
+		)).
+		Complete(c)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return wait.PollImmediateUntilWithContext(ctx, loadRetryPeriod, c.loadNamespaces)
+	}))
+}
+
+// loadRetryPeriod is how long to wait before retrying to load the Namespaces.
+const loadRetryPeriod = 5 * time.Second
+
+// loadNamespaces records the labels of the Namespaces in the cache in the
+// State, and marks it as synced. Listing from the cache blocks until the
+// Namespace cache has synced. It returns false if the Namespaces could not be
+// listed.
+func (c *Controller) loadNamespaces(ctx context.Context) (bool, error) {
+	nsList := &corev1.NamespaceList{}
+	if err := c.Client.List(ctx, nsList); err != nil {
+		klog.Warningf("Failed to list Namespaces: %v", err)
+		return false, nil
+	}
+	namespaces := make(map[string]map[string]string, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		if ns.GetDeletionTimestamp().IsZero() {
+			namespaces[ns.Name] = ns.Labels
+		}
+	}
+	c.State.MarkSynced(namespaces)
+	klog.V(3).Infof("Loaded %d Namespaces", len(namespaces))
+	return true, nil
+}
+
+// Reconcile responds to changes in the Namespaces being watched.
+func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Client.Get(ctx, req.NamespacedName, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(3).Infof("Namespace %q deleted", req.Name)
+			c.State.DeleteNamespace(req.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, status.APIServerError(err, fmt.Sprintf("failed to get Namespace %q", req.Name))
+	}
+	if !ns.GetDeletionTimestamp().IsZero() {
+		// Objects can't be created in a terminating Namespace, so treat it as
+		// if it had already been deleted.
+		klog.V(3).Infof("Namespace %q terminating", req.Name)
+		c.State.DeleteNamespace(req.Name)
+		return reconcile.Result{}, nil
+	}
+	c.State.SetNamespace(ns.Name, ns.Labels)
+	return reconcile.Result{}, nil
+}
+
+// deletionPredicate returns a Predicate which only passes update events that
+// mark a Namespace for deletion.
+func deletionPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+}
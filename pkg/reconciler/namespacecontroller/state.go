@@ -0,0 +1,157 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacecontroller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// State stores the Namespaces on the cluster and the dynamic
+// NamespaceSelectors declared in the source, and tracks whether a change to
+// the Namespaces requires the reconciler to re-sync.
+type State struct {
+	mux sync.RWMutex
+	// synced indicates whether the Namespaces on the cluster have been loaded
+	// from the Namespace cache.
+	synced bool
+	// syncPending indicates whether the set of Namespaces selected by a dynamic
+	// NamespaceSelector has changed since the last sync.
+	syncPending bool
+	// selectors maps the name of each dynamic NamespaceSelector to its label
+	// selector.
+	selectors map[string]labels.Selector
+	// namespaces maps the name of each live Namespace to its labels.
+	namespaces map[string]labels.Set
+}
+
+// NewState instantiates the namespace controller state.
+func NewState() *State {
+	return &State{
+		selectors:  make(map[string]labels.Selector),
+		namespaces: make(map[string]labels.Set),
+	}
+}
+
+// SetSelectorCache replaces the dynamic NamespaceSelectors declared in the
+// source, and returns a copy of the labels of every live Namespace, keyed by
+// Namespace name. Both happen under the same lock, so that a change to the
+// Namespaces is either part of the result or schedules a sync with the new
+// selectors.
+//
+// Returns false, without replacing the selectors, if there are dynamic
+// NamespaceSelectors and the live Namespaces have not been loaded yet.
+func (s *State) SetSelectorCache(selectors map[string]labels.Selector) (map[string]labels.Set, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.synced && len(selectors) > 0 {
+		return nil, false
+	}
+	s.selectors = selectors
+	result := make(map[string]labels.Set, len(s.namespaces))
+	for name, nsLabels := range s.namespaces {
+		result[name] = nsLabels
+	}
+	return result, true
+}
+
+// IsSynced returns whether the live Namespaces have been loaded.
+func (s *State) IsSynced() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.synced
+}
+
+// MarkSynced records the labels of the live Namespaces loaded from the
+// Namespace cache once it has synced, and lets the dynamic NamespaceSelectors
+// be evaluated.
+func (s *State) MarkSynced(namespaces map[string]map[string]string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for name, nsLabels := range namespaces {
+		newLabels := labels.Set(nsLabels)
+		if newLabels == nil {
+			newLabels = labels.Set{}
+		}
+		s.namespaces[name] = newLabels
+	}
+	s.synced = true
+}
+
+// IsSyncPending returns whether the reconciler needs to re-sync because the
+// Namespaces selected by a dynamic NamespaceSelector have changed.
+func (s *State) IsSyncPending() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.syncPending
+}
+
+// ResetSyncPending marks the pending Namespace changes as handled. It should
+// be called right before the reconciler starts to re-sync.
+func (s *State) ResetSyncPending() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.syncPending = false
+}
+
+// SetNamespace records the labels of a live Namespace, and schedules a sync if
+// the change affects a dynamic NamespaceSelector.
+func (s *State) SetNamespace(name string, nsLabels map[string]string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	oldLabels, found := s.namespaces[name]
+	newLabels := labels.Set(nsLabels)
+	if newLabels == nil {
+		newLabels = labels.Set{}
+	}
+	s.namespaces[name] = newLabels
+	if !found {
+		s.syncPending = s.syncPending || s.anySelectorMatches(newLabels)
+		return
+	}
+	s.syncPending = s.syncPending || s.selectionChanged(oldLabels, newLabels)
+}
+
+// DeleteNamespace forgets a live Namespace, and schedules a sync if it was
+// selected by a dynamic NamespaceSelector.
+func (s *State) DeleteNamespace(name string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	oldLabels, found := s.namespaces[name]
+	if !found {
+		return
+	}
+	delete(s.namespaces, name)
+	s.syncPending = s.syncPending || s.anySelectorMatches(oldLabels)
+}
+
+func (s *State) anySelectorMatches(nsLabels labels.Set) bool {
+	for _, selector := range s.selectors {
+		if selector.Matches(nsLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *State) selectionChanged(oldLabels, newLabels labels.Set) bool {
+	for _, selector := range s.selectors {
+		if selector.Matches(oldLabels) != selector.Matches(newLabels) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacecontroller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestStateSyncPending(t *testing.T) {
+	devSelector := labels.SelectorFromSet(labels.Set{"environment": "dev"})
+
+	testCases := []struct {
+		name            string
+		selectors       map[string]labels.Selector
+		existing        map[string]map[string]string
+		update          func(s *State)
+		wantSyncPending bool
+	}{
+		{
+			name: "new namespace without dynamic selectors",
+			update: func(s *State) {
+				s.SetNamespace("foo", map[string]string{"environment": "dev"})
+			},
+		},
+		{
+			name:      "new namespace not selected",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			update: func(s *State) {
+				s.SetNamespace("foo", map[string]string{"environment": "prod"})
+			},
+		},
+		{
+			name:      "new namespace selected",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			update: func(s *State) {
+				s.SetNamespace("foo", map[string]string{"environment": "dev"})
+			},
+			wantSyncPending: true,
+		},
+		{
+			name:      "namespace starts matching",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			existing:  map[string]map[string]string{"foo": {"environment": "prod"}},
+			update: func(s *State) {
+				s.SetNamespace("foo", map[string]string{"environment": "dev"})
+			},
+			wantSyncPending: true,
+		},
+		{
+			name:      "namespace stops matching",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			existing:  map[string]map[string]string{"foo": {"environment": "dev"}},
+			update: func(s *State) {
+				s.SetNamespace("foo", nil)
+			},
+			wantSyncPending: true,
+		},
+		{
+			name:      "unrelated label change",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			existing:  map[string]map[string]string{"foo": {"environment": "dev"}},
+			update: func(s *State) {
+				s.SetNamespace("foo", map[string]string{"environment": "dev", "team": "x"})
+			},
+		},
+		{
+			name:      "selected namespace deleted",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			existing:  map[string]map[string]string{"foo": {"environment": "dev"}},
+			update: func(s *State) {
+				s.DeleteNamespace("foo")
+			},
+			wantSyncPending: true,
+		},
+		{
+			name:      "unknown namespace deleted",
+			selectors: map[string]labels.Selector{"dev": devSelector},
+			update: func(s *State) {
+				s.DeleteNamespace("foo")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewState()
+			s.MarkSynced(tc.existing)
+			if _, synced := s.SetSelectorCache(tc.selectors); !synced {
+				t.Fatalf("SetSelectorCache() not synced after MarkSynced()")
+			}
+			tc.update(s)
+			if got := s.IsSyncPending(); got != tc.wantSyncPending {
+				t.Errorf("IsSyncPending() = %t, want %t", got, tc.wantSyncPending)
+			}
+			s.ResetSyncPending()
+			if s.IsSyncPending() {
+				t.Errorf("IsSyncPending() = true after ResetSyncPending()")
+			}
+		})
+	}
+}
+
+func TestStateSetSelectorCache(t *testing.T) {
+	devSelector := labels.SelectorFromSet(labels.Set{"environment": "dev"})
+	s := NewState()
+	s.SetNamespace("foo", map[string]string{"environment": "dev"})
+
+	if _, synced := s.SetSelectorCache(nil); !synced {
+		t.Errorf("SetSelectorCache() without dynamic selectors should not wait for the Namespaces to be loaded")
+	}
+	if _, synced := s.SetSelectorCache(map[string]labels.Selector{"dev": devSelector}); synced {
+		t.Errorf("SetSelectorCache() with dynamic selectors should wait for the Namespaces to be loaded")
+	}
+	if s.IsSynced() {
+		t.Errorf("IsSynced() = true before MarkSynced()")
+	}
+
+	s.MarkSynced(map[string]map[string]string{"bar": nil})
+	got, synced := s.SetSelectorCache(map[string]labels.Selector{"dev": devSelector})
+	if !synced {
+		t.Fatalf("SetSelectorCache() not synced after MarkSynced()")
+	}
+	want := map[string]labels.Set{
+		"foo": {"environment": "dev"},
+		"bar": {},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+
+	// A change to a Namespace after the selectors are set schedules a sync.
+	s.SetNamespace("baz", map[string]string{"environment": "dev"})
+	if !s.IsSyncPending() {
+		t.Errorf("IsSyncPending() = false after a selected Namespace was created")
+	}
+}
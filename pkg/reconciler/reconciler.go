@@ -16,28 +16,37 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
+	"kpt.dev/configsync/pkg/api/configmanagement"
 	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/client/restconfig"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/importer/filesystem"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
 	"kpt.dev/configsync/pkg/importer/reader"
 	"kpt.dev/configsync/pkg/parse"
+	"kpt.dev/configsync/pkg/policy"
 	"kpt.dev/configsync/pkg/reconciler/finalizer"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
+	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/remediator"
 	"kpt.dev/configsync/pkg/remediator/watch"
+	"kpt.dev/configsync/pkg/shard"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
 	"kpt.dev/configsync/pkg/syncer/metrics"
 	"kpt.dev/configsync/pkg/syncer/reconcile"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
@@ -95,7 +104,7 @@ type Options struct {
 	SourceBranch string
 	// SourceRepo is the git or OCI or Helm repo being synced.
 	SourceRepo string
-	// SourceType is the type of the source repository, must be git or oci or Helm.
+	// SourceType is the type of the source repository, must be git or oci or Helm or local or bucket.
 	SourceType v1beta1.SourceType
 	// SyncDir is the relative path to the configurations in the source.
 	SyncDir cmpath.Relative
@@ -105,6 +114,18 @@ type Options struct {
 	ReconcileTimeout string
 	// APIServerTimeout is the client-side timeout used for talking to the API server
 	APIServerTimeout string
+	// AdoptionPolicy determines what the applier and the remediator do with
+	// the objects which already exist on the cluster but are not in the
+	// inventory. The applier uses its default policy if empty.
+	AdoptionPolicy v1beta1.AdoptionPolicy
+	// ImpersonateServiceAccount is the name of the ServiceAccount in the
+	// namespace of the RepoSync, which the reconciler impersonates to apply,
+	// prune and remediate the objects in the source of truth.
+	// Unset for Root repositories.
+	ImpersonateServiceAccount string
+	// MetricsAddr is the address the Prometheus metrics endpoint binds to.
+	// "0" disables the endpoint.
+	MetricsAddr string
 	// RootOptions is the set of options to fill in if this is configuring the
 	// Root reconciler.
 	// Unset for Namespace repositories.
@@ -115,6 +136,8 @@ type Options struct {
 type RootOptions struct {
 	// SourceFormat is how the Root repository is structured.
 	SourceFormat filesystem.SourceFormat
+	// Shard is the shard of the RootSync objects handled by the reconciler.
+	Shard shard.Shard
 }
 
 // Run configures and starts the various components of a reconciler process.
@@ -159,9 +182,32 @@ func Run(opts Options) {
 		klog.Fatalf("failed to create client: %v", err)
 	}
 
+	// The objects in the source of truth are managed with a separate config,
+	// which impersonates the ServiceAccount of the RepoSync, if any. The
+	// reconciler keeps using its own ServiceAccount for the RSync, its
+	// ResourceGroup inventory and Events.
+	objectsCfg := cfg
+	objectsConfigFlags := configFlags
+	objectsClient := cl
+	if opts.ImpersonateServiceAccount != "" {
+		klog.Infof("Impersonating ServiceAccount %s/%s", opts.ReconcilerScope, opts.ImpersonateServiceAccount)
+		objectsCfg = impersonate(cfg, opts)
+		objectsConfigFlags, err = restconfig.NewConfigFlags(objectsCfg)
+		if err != nil {
+			klog.Fatalf("Error creating config flags from impersonating rest config: %v", err)
+		}
+		objectsClient, err = client.New(objectsCfg, client.Options{
+			Scheme: core.Scheme,
+			Mapper: mapper,
+		})
+		if err != nil {
+			klog.Fatalf("failed to create impersonating client: %v", err)
+		}
+	}
+
 	// Configure the Applier.
-	genericClient := syncerclient.New(cl, metrics.APICallDuration)
-	baseApplier, err := reconcile.NewApplierForMultiRepo(cfg, genericClient)
+	genericClient := syncerclient.New(objectsClient, metrics.APICallDuration)
+	baseApplier, err := reconcile.NewApplierForMultiRepo(objectsCfg, genericClient)
 	if err != nil {
 		klog.Fatalf("Instantiating Applier: %v", err)
 	}
@@ -173,11 +219,25 @@ func Run(opts Options) {
 	if reconcileTimeout < 0 {
 		klog.Fatalf("Invalid reconcileTimeout: %v, timeout should not be negative", reconcileTimeout)
 	}
-	clientSet, err := applier.NewClientSet(cl, configFlags, opts.StatusMode)
+	clientSet, err := applier.NewClientSet(cl, configFlags, objectsConfigFlags, opts.StatusMode)
 	if err != nil {
 		klog.Fatalf("Error creating clients: %v", err)
 	}
-	supervisor, err := applier.NewSupervisor(clientSet, opts.ReconcilerScope, opts.SyncName, reconcileTimeout)
+	eventRecorder, err := events.NewEventRecorder(cfg, opts.ReconcilerName)
+	if err != nil {
+		klog.Fatalf("Error creating event recorder: %v", err)
+	}
+	recorder := events.NewRecorder(eventRecorder, cl, opts.ReconcilerScope, opts.SyncName)
+
+	// Only RootSyncs are sharded.
+	var s shard.Shard
+	if opts.RootOptions != nil {
+		s = opts.Shard
+	}
+
+	// Both the applier and the remediator honor the drift exemptions.
+	exemptions := exemption.NewLister(cl, eventRecorder)
+	supervisor, err := applier.NewSupervisor(clientSet, opts.ReconcilerScope, opts.SyncName, s, reconcileTimeout, opts.AdoptionPolicy, recorder, exemptions)
 	if err != nil {
 		klog.Fatalf("Error creating applier: %v", err)
 	}
@@ -193,13 +253,20 @@ func Run(opts Options) {
 		klog.Fatalf("Error creating rest config for the remediator: %v", err)
 	}
 
-	rem, err := remediator.New(opts.ReconcilerScope, opts.SyncName, cfgForWatch, baseApplier, decls, opts.NumWorkers)
+	remediatorCfg := cfgForWatch
+	if opts.ImpersonateServiceAccount != "" {
+		remediatorCfg = impersonate(cfgForWatch, opts)
+	}
+
+	rem, err := remediator.New(opts.ReconcilerScope, opts.SyncName, remediatorCfg, baseApplier, decls, opts.NumWorkers, opts.AdoptionPolicy, exemptions, s)
 	if err != nil {
 		klog.Fatalf("Instantiating Remediator: %v", err)
 	}
 
 	// Configure the Parser.
 	var parser parse.Parser
+	var nsControllerState *namespacecontroller.State
+	var rulesCache cache.Cache
 	fs := parse.FileSource{
 		SourceDir:    opts.SourceRoot,
 		RepoRoot:     opts.RepoRoot,
@@ -211,15 +278,32 @@ func Run(opts Options) {
 		SourceBranch: opts.SourceBranch,
 		SourceRev:    opts.SourceRev,
 	}
+	// The cache lets new commits only parse and validate the files they change.
+	fileReader := &reader.File{Cache: reader.NewCache()}
 	if opts.ReconcilerScope == declared.RootReconciler {
-		parser, err = parse.NewRootRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.SourceFormat, &reader.File{}, cl,
-			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem)
+		if opts.SourceFormat == filesystem.SourceFormatUnstructured {
+			nsControllerState = namespacecontroller.NewState()
+		}
+		// The policy rules declared on the cluster are read from an informer
+		// cache of the ConfigMaps in the config-management-system namespace,
+		// which is started with the ControllerManager.
+		rulesCache, err = cache.New(cfgForWatch, cache.Options{
+			Scheme:    core.Scheme,
+			Mapper:    mapper,
+			Namespace: configmanagement.ControllerNamespace,
+		})
+		if err != nil {
+			klog.Fatalf("Instantiating the policy rules cache: %v", err)
+		}
+		parser, err = parse.NewRootRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.SourceFormat, fileReader, cl,
+			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem, s, nsControllerState,
+			policy.NewClusterRules(rulesCache), recorder)
 		if err != nil {
 			klog.Fatalf("Instantiating Root Repository Parser: %v", err)
 		}
 	} else {
-		parser, err = parse.NewNamespaceRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.ReconcilerScope, &reader.File{}, cl,
-			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem)
+		parser, err = parse.NewNamespaceRunner(opts.ClusterName, opts.SyncName, opts.ReconcilerName, opts.ReconcilerScope, fileReader, cl,
+			opts.PollingPeriod, opts.ResyncPeriod, opts.RetryPeriod, opts.StatusUpdatePeriod, fs, discoveryClient, decls, supervisor, rem, recorder)
 		if err != nil {
 			klog.Fatalf("Instantiating Namespace Repository Parser: %v", err)
 		}
@@ -238,6 +322,7 @@ func Run(opts Options) {
 		BaseContext: func() context.Context {
 			return signalCtx
 		},
+		MetricsBindAddress: opts.MetricsAddr,
 	}
 	// For Namespaced Reconcilers, set the default namespace to watch.
 	// Otherwise, all namespaced informers will watch at the cluster-scope.
@@ -262,13 +347,14 @@ func Run(opts Options) {
 	// The caching client built by the controller-manager doesn't update
 	// the GET cache on UPDATE/PATCH. So we need to use the non-caching client
 	// for the finalizer, which does GET/LIST after UPDATE/PATCH.
-	f := finalizer.New(opts.ReconcilerScope, supervisor, cl, // non-caching client
-		stopControllers, continueChanForFinalizer)
+	f := finalizer.New(opts.ReconcilerScope, s, supervisor, cl, // non-caching client
+		stopControllers, continueChanForFinalizer, recorder)
 
 	// Create the Finalizer Controller
 	finalizerController := &finalizer.Controller{
 		SyncScope: opts.ReconcilerScope,
 		SyncName:  opts.SyncName,
+		Shard:     s,
 		Client:    mgr.GetClient(), // caching client
 		Scheme:    mgr.GetScheme(),
 		Mapper:    mgr.GetRESTMapper(),
@@ -280,6 +366,24 @@ func Run(opts Options) {
 		klog.Fatalf("Instantiating Finalizer: %v", err)
 	}
 
+	if rulesCache != nil {
+		if err := mgr.Add(rulesCache); err != nil {
+			klog.Fatalf("Registering the policy rules cache: %v", err)
+		}
+	}
+
+	// Register the Namespace Controller, which watches the Namespaces on the
+	// cluster for dynamic NamespaceSelectors.
+	if nsControllerState != nil {
+		nsController := &namespacecontroller.Controller{
+			Client: mgr.GetClient(), // caching client
+			State:  nsControllerState,
+		}
+		if err := nsController.SetupWithManager(mgr); err != nil {
+			klog.Fatalf("Instantiating Namespace Controller: %v", err)
+		}
+	}
+
 	klog.Info("Starting ControllerManager")
 	// TODO: Once everything is using the controller-manager, move mgr.Start to the top level.
 	doneChanForManager := make(chan struct{})
@@ -323,3 +427,17 @@ func Run(opts Options) {
 	<-signalCtx.Done()
 	klog.Info("All controllers exited")
 }
+
+// impersonate returns a copy of the config impersonating the ServiceAccount of
+// the RepoSync. The name of the reconciler is passed as user extra info, so
+// that the admission webhook can identify the requests of the reconciler.
+func impersonate(cfg *rest.Config, opts Options) *rest.Config {
+	impersonating := restconfig.DeepCopy(cfg)
+	impersonating.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", opts.ReconcilerScope, opts.ImpersonateServiceAccount),
+		Extra: map[string][]string{
+			reconcilermanager.ReconcilerUserExtraKey: {opts.ReconcilerName},
+		},
+	}
+	return impersonating
+}
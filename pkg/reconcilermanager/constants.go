@@ -58,6 +58,12 @@ const (
 	// HelmSync is the name of the helm-sync container in reconciler pods.
 	HelmSync = "helm-sync"
 
+	// LocalSync is the name of the local-sync container in reconciler pods.
+	LocalSync = "local-sync"
+
+	// BucketSync is the name of the bucket-sync container in reconciler pods.
+	BucketSync = "bucket-sync"
+
 	// HydrationController is the name of the hydration-controller container in reconciler pods.
 	HydrationController = "hydration-controller"
 
@@ -77,6 +83,38 @@ const (
 	// StatusMode is to control if the kpt applier needs to inject the actuation data
 	// into the ResourceGroup object.
 	StatusMode = "STATUS_MODE"
+
+	// TracingEndpointKey is the OS env variable key for the address of the
+	// OC Agent compatible endpoint to export trace spans to. Tracing is
+	// disabled if it is empty.
+	TracingEndpointKey = "TRACING_ENDPOINT"
+
+	// ShardIndexKey is the OS env variable key for the index of the shard of
+	// the RootSync handled by the reconciler.
+	ShardIndexKey = "SHARD_INDEX"
+
+	// ShardCountKey is the OS env variable key for the number of shards of
+	// the RootSync.
+	ShardCountKey = "SHARD_COUNT"
+
+	// ShardKeyKey is the OS env variable key for the key which objects are
+	// assigned to shards by.
+	ShardKeyKey = "SHARD_KEY"
+
+	// ImpersonateServiceAccountKey is the OS env variable key for the name of
+	// the ServiceAccount in the namespace of the RepoSync, which the reconciler
+	// impersonates to manage the objects in the source of truth.
+	ImpersonateServiceAccountKey = "IMPERSONATE_SERVICE_ACCOUNT"
+
+	// ReconcilerUserExtraKey is the key of the user extra info set by
+	// reconcilers impersonating a ServiceAccount, whose value is the name of the
+	// reconciler. It lets the admission webhook identify the requests of the
+	// reconciler.
+	ReconcilerUserExtraKey = "configsync.gke.io/reconciler"
+
+	// AdoptionPolicyKey is the OS env variable key for the adoption policy of
+	// the objects which already exist on the cluster.
+	AdoptionPolicyKey = "ADOPTION_POLICY"
 )
 
 const (
@@ -114,6 +152,62 @@ const (
 	OciSyncWait = "OCI_SYNC_WAIT"
 )
 
+const (
+	// LocalSyncNamespace is the OS env variable key for the namespace of the
+	// ConfigMap or Secret holding the local source.
+	LocalSyncNamespace = "LOCAL_SYNC_NAMESPACE"
+
+	// LocalSyncConfigMap is the OS env variable key for the name of the
+	// ConfigMap holding the local source.
+	LocalSyncConfigMap = "LOCAL_SYNC_CONFIGMAP"
+
+	// LocalSyncSecret is the OS env variable key for the name of the Secret
+	// holding the local source.
+	LocalSyncSecret = "LOCAL_SYNC_SECRET"
+
+	// LocalSyncKey is the OS env variable key for the key of the ConfigMap or
+	// Secret holding the local source.
+	LocalSyncKey = "LOCAL_SYNC_KEY"
+
+	// LocalSyncDir is the OS env variable key for the directory the
+	// PersistentVolumeClaim holding the local source is mounted at.
+	LocalSyncDir = "LOCAL_SYNC_DIR"
+
+	// LocalSyncWait is the OS env variable key for the local sync wait period in seconds.
+	LocalSyncWait = "LOCAL_SYNC_WAIT"
+)
+
+const (
+	// BucketSyncEndpoint is the OS env variable key for the endpoint of the
+	// S3-compatible API.
+	BucketSyncEndpoint = "BUCKET_SYNC_ENDPOINT"
+
+	// BucketSyncBucket is the OS env variable key for the name of the bucket.
+	BucketSyncBucket = "BUCKET_SYNC_BUCKET"
+
+	// BucketSyncPrefix is the OS env variable key for the prefix of the keys of
+	// the objects of the source.
+	BucketSyncPrefix = "BUCKET_SYNC_PREFIX"
+
+	// BucketSyncRegion is the OS env variable key for the region of the bucket.
+	BucketSyncRegion = "BUCKET_SYNC_REGION"
+
+	// BucketSyncAccessKeyID is the OS env variable key for the access key ID
+	// authenticating to the bucket.
+	BucketSyncAccessKeyID = "BUCKET_SYNC_ACCESS_KEY_ID"
+
+	// BucketSyncSecretAccessKey is the OS env variable key for the secret access
+	// key authenticating to the bucket.
+	BucketSyncSecretAccessKey = "BUCKET_SYNC_SECRET_ACCESS_KEY"
+
+	// BucketSyncSessionToken is the OS env variable key for the session token
+	// of temporary security credentials authenticating to the bucket.
+	BucketSyncSessionToken = "BUCKET_SYNC_SESSION_TOKEN"
+
+	// BucketSyncWait is the OS env variable key for the bucket sync wait period in seconds.
+	BucketSyncWait = "BUCKET_SYNC_WAIT"
+)
+
 const (
 	// HelmRepo is the OS env variable key for the Helm repository URL.
 	HelmRepo = "HELM_REPO"
@@ -37,3 +37,34 @@ func RepoSyncPermissionsName() string {
 func RootSyncPermissionsName() string {
 	return fmt.Sprintf("%s:%s", configsync.GroupName, core.RootReconcilerPrefix)
 }
+
+// RepoSyncImpersonationName returns the name of the RBAC objects allowing the
+// namespace reconciler to impersonate the ServiceAccount of its RepoSync.
+// e.g. configsync.gke.io:ns-reconciler-bookstore-impersonation
+func RepoSyncImpersonationName(reconcilerName string) string {
+	return fmt.Sprintf("%s:%s", configsync.GroupName, ReconcilerResourceName(reconcilerName, "impersonation"))
+}
+
+// RepoSyncLocalSourceName returns the name of the RBAC objects allowing the
+// namespace reconciler to read the ConfigMap or Secret holding the local
+// source of its RepoSync.
+// e.g. configsync.gke.io:ns-reconciler-bookstore-local-source
+func RepoSyncLocalSourceName(reconcilerName string) string {
+	return fmt.Sprintf("%s:%s", configsync.GroupName, ReconcilerResourceName(reconcilerName, "local-source"))
+}
+
+// RepoSyncClusterReaderName returns the name of the ClusterRole allowing the
+// namespace reconcilers to read the cluster-scoped configuration of Config
+// Sync, which is granted to each reconciler by its own ClusterRoleBinding.
+// e.g. configsync.gke.io:ns-reconciler-cluster-reader
+func RepoSyncClusterReaderName() string {
+	return fmt.Sprintf("%s:%s-cluster-reader", configsync.GroupName, core.NsReconcilerPrefix)
+}
+
+// RepoSyncClusterReaderBindingName returns the name of the ClusterRoleBinding
+// allowing the namespace reconciler to read the cluster-scoped configuration
+// of Config Sync.
+// e.g. configsync.gke.io:ns-reconciler-bookstore-cluster-reader
+func RepoSyncClusterReaderBindingName(reconcilerName string) string {
+	return fmt.Sprintf("%s:%s", configsync.GroupName, ReconcilerResourceName(reconcilerName, "cluster-reader"))
+}
@@ -41,3 +41,14 @@ const (
 	// HelmSecretKeyUsername is the key at which a token's username is stored
 	HelmSecretKeyUsername = "username"
 )
+
+// Bucket secret data key names
+const (
+	// BucketSecretKeyAccessKeyID is the key at which an access key ID is stored
+	BucketSecretKeyAccessKeyID = "access_key_id"
+	// BucketSecretKeySecretAccessKey is the key at which a secret access key is stored
+	BucketSecretKeySecretAccessKey = "secret_access_key"
+	// BucketSecretKeySessionToken is the optional key at which the session token
+	// of temporary security credentials is stored
+	BucketSecretKeySessionToken = "session_token"
+)
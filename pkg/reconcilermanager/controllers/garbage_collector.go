@@ -69,6 +69,18 @@ func (r *RepoSyncReconciler) cleanupNSControllerResources(ctx context.Context, r
 	if err := r.deleteSecrets(ctx, reconcilerRef); err != nil {
 		return err
 	}
+	// impersonation permissions
+	if err := r.deleteImpersonation(ctx, reconcilerRef, rsKey.Namespace); err != nil {
+		return err
+	}
+	// cluster reader permissions
+	if err := r.deleteClusterReader(ctx, reconcilerRef); err != nil {
+		return err
+	}
+	// local source permissions
+	if err := r.deleteLocalSourceAccess(ctx, reconcilerRef, rsKey.Namespace); err != nil {
+		return err
+	}
 
 	delete(r.repoSyncs, rsKey)
 	return nil
@@ -143,6 +155,63 @@ func (r *RepoSyncReconciler) deleteRoleBinding(ctx context.Context, reconcilerRe
 	return r.client.Update(ctx, rb)
 }
 
+// deleteImpersonation deletes the RBAC objects allowing the reconciler to
+// impersonate the ServiceAccount of its RepoSync, if any.
+func (r *RepoSyncReconciler) deleteImpersonation(ctx context.Context, reconcilerRef types.NamespacedName, rsNamespace string) error {
+	name := RepoSyncImpersonationName(reconcilerRef.Name)
+	return r.deleteOptional(ctx, []managedObject{
+		{types.NamespacedName{Namespace: rsNamespace, Name: name}, kinds.RoleBinding()},
+		{types.NamespacedName{Namespace: rsNamespace, Name: name}, kinds.Role()},
+		{types.NamespacedName{Name: name}, kinds.ClusterRoleBinding()},
+		{types.NamespacedName{Name: name}, kinds.ClusterRole()},
+	})
+}
+
+// deleteClusterReader deletes the ClusterRoleBinding allowing the reconciler to
+// read the cluster-scoped configuration.
+func (r *RepoSyncReconciler) deleteClusterReader(ctx context.Context, reconcilerRef types.NamespacedName) error {
+	return r.deleteOptional(ctx, []managedObject{
+		{types.NamespacedName{Name: RepoSyncClusterReaderBindingName(reconcilerRef.Name)}, kinds.ClusterRoleBinding()},
+	})
+}
+
+// deleteLocalSourceAccess deletes the RBAC objects allowing the reconciler to
+// read the local source of its RepoSync, if any.
+func (r *RepoSyncReconciler) deleteLocalSourceAccess(ctx context.Context, reconcilerRef types.NamespacedName, rsNamespace string) error {
+	name := RepoSyncLocalSourceName(reconcilerRef.Name)
+	return r.deleteOptional(ctx, []managedObject{
+		{types.NamespacedName{Namespace: rsNamespace, Name: name}, kinds.RoleBinding()},
+		{types.NamespacedName{Namespace: rsNamespace, Name: name}, kinds.Role()},
+	})
+}
+
+// managedObject identifies an object managed by the reconciler-manager.
+type managedObject struct {
+	key types.NamespacedName
+	gvk schema.GroupVersionKind
+}
+
+// deleteOptional deletes the objects which only some RepoSyncs need. Unlike
+// cleanup, it does not log the objects which do not exist.
+func (r *RepoSyncReconciler) deleteOptional(ctx context.Context, objs []managedObject) error {
+	for _, obj := range objs {
+		u := &unstructured.Unstructured{}
+		u.SetName(obj.key.Name)
+		u.SetNamespace(obj.key.Namespace)
+		u.SetGroupVersionKind(obj.gvk)
+		if err := r.client.Delete(ctx, u); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to delete the %s object %s", obj.gvk.Kind, obj.key)
+		}
+		r.log.Info("Managed object delete successful",
+			logFieldObject, obj.key.String(),
+			logFieldKind, obj.gvk.Kind)
+	}
+	return nil
+}
+
 func (r *RepoSyncReconciler) deleteDeployment(ctx context.Context, reconcilerRef types.NamespacedName) error {
 	return r.cleanup(ctx, reconcilerRef, kinds.Deployment())
 }
@@ -0,0 +1,384 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/status"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Sync states recorded in the Notification status.
+const (
+	syncStateSynced     = "Synced"
+	syncStateStalled    = "Stalled"
+	syncStateSyncFailed = "SyncFailed"
+)
+
+var _ reconcile.Reconciler = &NotificationReconciler{}
+
+// NotificationReconciler posts notifications when the RootSyncs and RepoSyncs
+// selected by a Notification transition between sync states.
+type NotificationReconciler struct {
+	client   client.Client
+	log      logr.Logger
+	scheme   *runtime.Scheme
+	notifier *notifier
+}
+
+// NewNotificationReconciler returns a new NotificationReconciler.
+func NewNotificationReconciler(client client.Client, log logr.Logger, scheme *runtime.Scheme) *NotificationReconciler {
+	return &NotificationReconciler{
+		client:   client,
+		log:      log,
+		scheme:   scheme,
+		notifier: newNotifier(),
+	}
+}
+
+// syncState is the observed sync state of a RootSync or RepoSync.
+type syncState struct {
+	kind      string
+	namespace string
+	name      string
+	// state is one of Synced, Stalled, SyncFailed, or empty if the RootSync or
+	// RepoSync is still syncing.
+	state   string
+	commit  string
+	message string
+}
+
+// Reconcile the Notification by posting a notification for every selected
+// RootSync or RepoSync whose sync state changed since the last reconcile.
+//
+// Failed deliveries are recorded in the Notification status and retried when
+// the Notification is requeued, so each transition is notified at most once
+// after it is delivered.
+func (r *NotificationReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("notification", req.NamespacedName.String())
+
+	notification := &v1beta1.Notification{}
+	if err := r.client.Get(ctx, req.NamespacedName, notification); err != nil {
+		if apierrors.IsNotFound(err) {
+			return controllerruntime.Result{}, nil
+		}
+		return controllerruntime.Result{}, status.APIServerError(err, "failed to get Notification")
+	}
+	if notification.DeletionTimestamp != nil {
+		return controllerruntime.Result{}, nil
+	}
+
+	states, err := r.listSyncStates(ctx, notification)
+	if err != nil {
+		log.Error(err, "Failed to list the RootSyncs and RepoSyncs")
+		return controllerruntime.Result{}, err
+	}
+
+	existing := notification.DeepCopy()
+	var deliveryErr error
+	var syncs []v1beta1.NotificationSyncStatus
+	for _, s := range states {
+		prev := findSyncStatus(existing.Status.Syncs, s.kind, s.name)
+		if s.state == "" {
+			// Keep the last known state while the RootSync or RepoSync is
+			// syncing, so the next transition is compared against it.
+			if prev != nil {
+				syncs = append(syncs, *prev)
+			}
+			continue
+		}
+		if prev != nil && prev.State == s.state {
+			syncs = append(syncs, *prev)
+			continue
+		}
+		next := v1beta1.NotificationSyncStatus{
+			Kind:               s.kind,
+			Name:               s.name,
+			State:              s.state,
+			Commit:             s.commit,
+			LastTransitionTime: metav1.Now(),
+		}
+		prevState := ""
+		if prev != nil {
+			prevState = prev.State
+		}
+		if trigger := notificationTriggerFor(prevState, s.state); trigger != "" && triggerEnabled(notification, trigger) {
+			if err := r.notify(ctx, notification, trigger, prevState, s); err != nil {
+				log.Error(err, "Failed to post notification",
+					logFieldKind, s.kind, logFieldObject, s.name, "trigger", trigger)
+				deliveryErr = err
+				// Keep the previous state so the transition is notified again
+				// on the next attempt.
+				if prev == nil {
+					continue
+				}
+				failed := *prev
+				failed.LastDeliveryError = err.Error()
+				syncs = append(syncs, failed)
+				continue
+			}
+			log.Info("Notification posted",
+				logFieldKind, s.kind, logFieldObject, s.name, "trigger", trigger)
+		}
+		syncs = append(syncs, next)
+	}
+
+	notification.Status.Syncs = syncs
+	notification.Status.ObservedGeneration = notification.Generation
+	if !equality.Semantic.DeepEqual(existing.Status, notification.Status) {
+		if err := r.client.Status().Update(ctx, notification); err != nil {
+			log.Error(err, "Failed to update Notification status")
+			return controllerruntime.Result{}, status.APIServerError(err, "failed to update Notification status")
+		}
+	}
+	return controllerruntime.Result{}, deliveryErr
+}
+
+// listSyncStates returns the sync states of the RootSyncs and RepoSyncs
+// selected by the Notification, sorted by kind and name.
+func (r *NotificationReconciler) listSyncStates(ctx context.Context, notification *v1beta1.Notification) ([]syncState, error) {
+	var states []syncState
+	kind := notification.Spec.SyncKind
+	if kind == "" || kind == configsync.RootSyncKind {
+		rsList := &v1beta1.RootSyncList{}
+		if err := r.client.List(ctx, rsList, client.InNamespace(notification.Namespace)); err != nil {
+			return nil, status.APIServerError(err, "failed to list RootSyncs")
+		}
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if syncNameSelected(notification, rs.Name) {
+				states = append(states, rootSyncState(rs))
+			}
+		}
+	}
+	if kind == "" || kind == configsync.RepoSyncKind {
+		rsList := &v1beta1.RepoSyncList{}
+		if err := r.client.List(ctx, rsList, client.InNamespace(notification.Namespace)); err != nil {
+			return nil, status.APIServerError(err, "failed to list RepoSyncs")
+		}
+		for i := range rsList.Items {
+			rs := &rsList.Items[i]
+			if syncNameSelected(notification, rs.Name) {
+				states = append(states, repoSyncState(rs))
+			}
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].kind != states[j].kind {
+			return states[i].kind < states[j].kind
+		}
+		return states[i].name < states[j].name
+	})
+	return states, nil
+}
+
+// notify renders and posts a notification about the transition of the
+// RootSync or RepoSync to its current state.
+func (r *NotificationReconciler) notify(ctx context.Context, notification *v1beta1.Notification, trigger v1beta1.NotificationTrigger, prevState string, s syncState) error {
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{
+		Namespace: notification.Namespace,
+		Name:      notification.Spec.Endpoint.SecretRef.Name,
+	}
+	if err := r.client.Get(ctx, secretKey, secret); err != nil {
+		return status.APIServerErrorf(err, "failed to get notification Secret %s", secretKey)
+	}
+	url := string(secret.Data[notificationURLKey])
+	if url == "" {
+		return fmt.Errorf("notification Secret %s has no %q key", secretKey, notificationURLKey)
+	}
+	token := string(secret.Data[notificationTokenKey])
+
+	data := notificationData{
+		Trigger:       string(trigger),
+		Kind:          s.kind,
+		Namespace:     s.namespace,
+		Name:          s.name,
+		State:         s.state,
+		PreviousState: prevState,
+		Commit:        s.commit,
+		Message:       s.message,
+		Summary:       notificationSummary(trigger, s),
+	}
+	body, err := renderNotification(notification.Spec.Endpoint.Type, notification.Spec.Template, data)
+	if err != nil {
+		return err
+	}
+	return r.notifier.post(ctx, url, token, body)
+}
+
+// notificationSummary returns a one-line description of the transition.
+func notificationSummary(trigger v1beta1.NotificationTrigger, s syncState) string {
+	summary := fmt.Sprintf("%s %s/%s", s.kind, s.namespace, s.name)
+	switch trigger {
+	case v1beta1.NotificationStalled:
+		summary += " is stalled"
+	case v1beta1.NotificationSyncFailed:
+		summary += " failed to sync"
+	case v1beta1.NotificationRecovered:
+		summary += " recovered"
+	}
+	if s.commit != "" {
+		summary += fmt.Sprintf(" at commit %s", s.commit)
+	}
+	if s.message != "" {
+		summary += ": " + s.message
+	}
+	return summary
+}
+
+// notificationTriggerFor returns the trigger of the transition between the
+// specified sync states, or an empty string if the transition does not need
+// a notification.
+func notificationTriggerFor(prevState, state string) v1beta1.NotificationTrigger {
+	switch state {
+	case syncStateStalled:
+		return v1beta1.NotificationStalled
+	case syncStateSyncFailed:
+		return v1beta1.NotificationSyncFailed
+	case syncStateSynced:
+		if prevState == syncStateStalled || prevState == syncStateSyncFailed {
+			return v1beta1.NotificationRecovered
+		}
+	}
+	return ""
+}
+
+func triggerEnabled(notification *v1beta1.Notification, trigger v1beta1.NotificationTrigger) bool {
+	if len(notification.Spec.Triggers) == 0 {
+		return true
+	}
+	for _, t := range notification.Spec.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+func syncNameSelected(notification *v1beta1.Notification, name string) bool {
+	if len(notification.Spec.SyncNames) == 0 {
+		return true
+	}
+	for _, n := range notification.Spec.SyncNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findSyncStatus(syncs []v1beta1.NotificationSyncStatus, kind, name string) *v1beta1.NotificationSyncStatus {
+	for i := range syncs {
+		if syncs[i].Kind == kind && syncs[i].Name == name {
+			return &syncs[i]
+		}
+	}
+	return nil
+}
+
+func rootSyncState(rs *v1beta1.RootSync) syncState {
+	s := syncState{kind: configsync.RootSyncKind, namespace: rs.Namespace, name: rs.Name}
+	if rootsync.IsStalled(rs) {
+		s.state = syncStateStalled
+		s.message = rootsync.StalledMessage(rs)
+		return s
+	}
+	cond := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncSyncing)
+	if cond == nil || cond.Status == metav1.ConditionTrue {
+		return s
+	}
+	s.commit = cond.Commit
+	if rootsync.ConditionHasNoErrors(*cond) {
+		s.state = syncStateSynced
+	} else {
+		s.state = syncStateSyncFailed
+		s.message = cond.Message
+	}
+	return s
+}
+
+func repoSyncState(rs *v1beta1.RepoSync) syncState {
+	s := syncState{kind: configsync.RepoSyncKind, namespace: rs.Namespace, name: rs.Name}
+	if reposync.IsStalled(rs) {
+		s.state = syncStateStalled
+		s.message = reposync.StalledMessage(rs)
+		return s
+	}
+	cond := reposync.GetCondition(rs.Status.Conditions, v1beta1.RepoSyncSyncing)
+	if cond == nil || cond.Status == metav1.ConditionTrue {
+		return s
+	}
+	s.commit = cond.Commit
+	if reposync.ConditionHasNoErrors(*cond) {
+		s.state = syncStateSynced
+	} else {
+		s.state = syncStateSyncFailed
+		s.message = cond.Message
+	}
+	return s
+}
+
+// SetupWithManager registers Notification controller with reconciler-manager.
+func (r *NotificationReconciler) SetupWithManager(mgr controllerruntime.Manager) error {
+	return controllerruntime.NewControllerManagedBy(mgr).
+		For(&v1beta1.Notification{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &v1beta1.RootSync{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapSyncToNotifications),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &v1beta1.RepoSync{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapSyncToNotifications),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Complete(r)
+}
+
+// mapSyncToNotifications maps a RootSync or RepoSync to the Notifications in
+// the same namespace.
+func (r *NotificationReconciler) mapSyncToNotifications(obj client.Object) []reconcile.Request {
+	notificationList := &v1beta1.NotificationList{}
+	if err := r.client.List(context.Background(), notificationList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "Failed to list Notifications",
+			logFieldObject, client.ObjectKeyFromObject(obj).String())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range notificationList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&notificationList.Items[i]),
+		})
+	}
+	return requests
+}
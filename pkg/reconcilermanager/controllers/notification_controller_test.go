@@ -0,0 +1,346 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/reposync"
+	"kpt.dev/configsync/pkg/rootsync"
+	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
+	"kpt.dev/configsync/pkg/testing/fake"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const notificationSecretName = "notification-endpoint"
+
+// notificationEndpoint is a local stand-in for a webhook endpoint which
+// records the requests it receives.
+type notificationEndpoint struct {
+	*httptest.Server
+
+	mux sync.Mutex
+	// responses are the status codes to respond with, in order. Once
+	// exhausted, the endpoint responds with 200 OK.
+	responses []int
+	bodies    []string
+	headers   []http.Header
+}
+
+func newNotificationEndpoint(t *testing.T, responses ...int) *notificationEndpoint {
+	t.Helper()
+	e := &notificationEndpoint{responses: responses}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		e.mux.Lock()
+		defer e.mux.Unlock()
+		e.bodies = append(e.bodies, string(body))
+		e.headers = append(e.headers, r.Header.Clone())
+		code := http.StatusOK
+		if len(e.responses) > 0 {
+			code = e.responses[0]
+			e.responses = e.responses[1:]
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func (e *notificationEndpoint) requests() []string {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return append([]string(nil), e.bodies...)
+}
+
+func setupNotificationReconciler(t *testing.T, objs ...client.Object) (*syncerFake.Client, *NotificationReconciler) {
+	t.Helper()
+
+	fakeClient := syncerFake.NewClient(t, core.Scheme, objs...)
+	testReconciler := NewNotificationReconciler(
+		fakeClient,
+		controllerruntime.Log.WithName("controllers").WithName(configsync.NotificationKind),
+		fakeClient.Scheme(),
+	)
+	return fakeClient, testReconciler
+}
+
+func notificationSecret(ns, url string) *corev1.Secret {
+	secret := fake.SecretObject(notificationSecretName, core.Namespace(ns))
+	secret.Data = map[string][]byte{
+		notificationURLKey:   []byte(url),
+		notificationTokenKey: []byte("s3cr3t"),
+	}
+	return secret
+}
+
+func notificationObject(ns string, spec v1beta1.NotificationSpec, syncs ...v1beta1.NotificationSyncStatus) *v1beta1.Notification {
+	n := &v1beta1.Notification{}
+	n.Name = "on-call"
+	n.Namespace = ns
+	n.Generation = 1
+	spec.Endpoint.SecretRef.Name = notificationSecretName
+	n.Spec = spec
+	n.Status.Syncs = syncs
+	return n
+}
+
+func stalledRootSync(name string) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1(name)
+	rootsync.SetStalled(rs, "Validation", errors.New("spec.git.repo is missing"))
+	return rs
+}
+
+func syncedRootSync(name, commit string) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1(name)
+	rootsync.SetSyncing(rs, false, "Sync", "Sync Completed", commit, nil, &v1beta1.ErrorSummary{}, metav1.Now())
+	return rs
+}
+
+func failedRepoSync(ns, name, commit string) *v1beta1.RepoSync {
+	rs := fake.RepoSyncObjectV1Beta1(ns, name)
+	reposync.SetSyncing(rs, false, "Sync", "Sync Completed", commit,
+		[]v1beta1.ErrorSource{v1beta1.SyncError}, &v1beta1.ErrorSummary{TotalCount: 1}, metav1.Now())
+	return rs
+}
+
+func reconcileNotification(t *testing.T, r *NotificationReconciler, n *v1beta1.Notification) error {
+	t.Helper()
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(n)})
+	return err
+}
+
+func getNotification(t *testing.T, c client.Client, n *v1beta1.Notification) *v1beta1.Notification {
+	t.Helper()
+	got := &v1beta1.Notification{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(n), got))
+	return got
+}
+
+func TestNotificationReconcilerStalled(t *testing.T) {
+	endpoint := newNotificationEndpoint(t)
+	n := notificationObject(configsync.ControllerNamespace, v1beta1.NotificationSpec{
+		Endpoint: v1beta1.NotificationEndpoint{Type: notificationEndpointSlack},
+	})
+	fakeClient, r := setupNotificationReconciler(t, n,
+		notificationSecret(configsync.ControllerNamespace, endpoint.URL),
+		stalledRootSync("root-sync"))
+
+	require.NoError(t, reconcileNotification(t, r, n))
+	require.Equal(t, []string{
+		`{"text": "RootSync config-management-system/root-sync is stalled: spec.git.repo is missing"}`,
+	}, endpoint.requests())
+	assert.Equal(t, "Bearer s3cr3t", endpoint.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", endpoint.headers[0].Get("Content-Type"))
+
+	got := getNotification(t, fakeClient, n)
+	require.Len(t, got.Status.Syncs, 1)
+	assert.Equal(t, configsync.RootSyncKind, got.Status.Syncs[0].Kind)
+	assert.Equal(t, "root-sync", got.Status.Syncs[0].Name)
+	assert.Equal(t, syncStateStalled, got.Status.Syncs[0].State)
+	assert.Equal(t, int64(1), got.Status.ObservedGeneration)
+
+	// The same state must not be notified twice.
+	require.NoError(t, reconcileNotification(t, r, n))
+	assert.Len(t, endpoint.requests(), 1)
+}
+
+func TestNotificationReconcilerTransitions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      v1beta1.NotificationSpec
+		syncs     []v1beta1.NotificationSyncStatus
+		objs      []client.Object
+		wantPosts []string
+		wantState string
+	}{
+		{
+			name: "recovered from Stalled",
+			syncs: []v1beta1.NotificationSyncStatus{
+				{Kind: configsync.RootSyncKind, Name: "root-sync", State: syncStateStalled},
+			},
+			objs: []client.Object{syncedRootSync("root-sync", "abc123")},
+			wantPosts: []string{
+				`{"text": "RootSync config-management-system/root-sync recovered at commit abc123"}`,
+			},
+			wantState: syncStateSynced,
+		},
+		{
+			name:      "first Synced is not notified",
+			objs:      []client.Object{syncedRootSync("root-sync", "abc123")},
+			wantState: syncStateSynced,
+		},
+		{
+			name: "SyncFailed filtered out by triggers",
+			spec: v1beta1.NotificationSpec{
+				SyncKind: configsync.RootSyncKind,
+				Triggers: []v1beta1.NotificationTrigger{v1beta1.NotificationRecovered},
+			},
+			objs:      []client.Object{stalledRootSync("root-sync")},
+			wantState: syncStateStalled,
+		},
+		{
+			name: "unselected name is ignored",
+			spec: v1beta1.NotificationSpec{
+				SyncNames: []string{"other"},
+			},
+			objs: []client.Object{stalledRootSync("root-sync")},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint := newNotificationEndpoint(t)
+			tc.spec.Endpoint.Type = notificationEndpointTeams
+			n := notificationObject(configsync.ControllerNamespace, tc.spec, tc.syncs...)
+			objs := append(tc.objs, n, notificationSecret(configsync.ControllerNamespace, endpoint.URL))
+			fakeClient, r := setupNotificationReconciler(t, objs...)
+
+			require.NoError(t, reconcileNotification(t, r, n))
+			assert.Equal(t, tc.wantPosts, endpoint.requests())
+
+			got := getNotification(t, fakeClient, n)
+			if tc.wantState == "" {
+				assert.Empty(t, got.Status.Syncs)
+				return
+			}
+			require.Len(t, got.Status.Syncs, 1)
+			assert.Equal(t, tc.wantState, got.Status.Syncs[0].State)
+		})
+	}
+}
+
+func TestNotificationReconcilerRetry(t *testing.T) {
+	ns := "bookstore"
+	// The first two attempts fail. Each attempt posts once and returns the
+	// error, so the Notification is requeued instead of retried inline.
+	endpoint := newNotificationEndpoint(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	n := notificationObject(ns, v1beta1.NotificationSpec{
+		Template: `{{ .Trigger }} {{ .Kind }} {{ .Namespace }}/{{ .Name }} {{ .Commit }}`,
+	})
+	fakeClient, r := setupNotificationReconciler(t, n,
+		notificationSecret(ns, endpoint.URL),
+		failedRepoSync(ns, "repo-sync", "abc123"))
+
+	wantBody := "SyncFailed RepoSync bookstore/repo-sync abc123"
+	require.Error(t, reconcileNotification(t, r, n))
+	assert.Equal(t, []string{wantBody}, endpoint.requests())
+	got := getNotification(t, fakeClient, n)
+	assert.Empty(t, got.Status.Syncs)
+
+	require.Error(t, reconcileNotification(t, r, n))
+	assert.Equal(t, []string{wantBody, wantBody}, endpoint.requests())
+
+	require.NoError(t, reconcileNotification(t, r, n))
+	assert.Equal(t, []string{wantBody, wantBody, wantBody}, endpoint.requests())
+	got = getNotification(t, fakeClient, n)
+	require.Len(t, got.Status.Syncs, 1)
+	assert.Equal(t, syncStateSyncFailed, got.Status.Syncs[0].State)
+	assert.Equal(t, "abc123", got.Status.Syncs[0].Commit)
+}
+
+func TestNotificationReconcilerDeliveryFailure(t *testing.T) {
+	endpoint := newNotificationEndpoint(t, http.StatusBadRequest)
+	prev := v1beta1.NotificationSyncStatus{Kind: configsync.RootSyncKind, Name: "root-sync", State: syncStateSynced}
+	n := notificationObject(configsync.ControllerNamespace, v1beta1.NotificationSpec{}, prev)
+	fakeClient, r := setupNotificationReconciler(t, n,
+		notificationSecret(configsync.ControllerNamespace, endpoint.URL),
+		stalledRootSync("root-sync"))
+
+	require.Error(t, reconcileNotification(t, r, n))
+	assert.Len(t, endpoint.requests(), 1)
+
+	// The previous state is kept, so the transition is notified again when
+	// the Notification is requeued.
+	got := getNotification(t, fakeClient, n)
+	require.Len(t, got.Status.Syncs, 1)
+	assert.Equal(t, syncStateSynced, got.Status.Syncs[0].State)
+	assert.Contains(t, got.Status.Syncs[0].LastDeliveryError, "400 Bad Request")
+
+	require.NoError(t, reconcileNotification(t, r, n))
+	assert.Len(t, endpoint.requests(), 2)
+	got = getNotification(t, fakeClient, n)
+	require.Len(t, got.Status.Syncs, 1)
+	assert.Equal(t, syncStateStalled, got.Status.Syncs[0].State)
+	assert.Empty(t, got.Status.Syncs[0].LastDeliveryError)
+}
+
+func TestRenderNotification(t *testing.T) {
+	data := notificationData{
+		Trigger:   string(v1beta1.NotificationStalled),
+		Kind:      configsync.RootSyncKind,
+		Namespace: configsync.ControllerNamespace,
+		Name:      "root-sync",
+		State:     syncStateStalled,
+		Summary:   `RootSync "root-sync" is stalled`,
+	}
+	testCases := []struct {
+		name         string
+		endpointType string
+		template     string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "slack",
+			endpointType: notificationEndpointSlack,
+			want:         `{"text": "RootSync \"root-sync\" is stalled"}`,
+		},
+		{
+			name: "generic",
+			want: `{"trigger":"Stalled","kind":"RootSync","namespace":"config-management-system","name":"root-sync","state":"Stalled","summary":"RootSync \"root-sync\" is stalled"}`,
+		},
+		{
+			name:     "custom template",
+			template: `{"alert": {{ json .Name }}, "state": {{ json .State }}}`,
+			want:     `{"alert": "root-sync", "state": "Stalled"}`,
+		},
+		{
+			name:         "unknown endpoint type",
+			endpointType: "pager",
+			wantErr:      true,
+		},
+		{
+			name:     "invalid template",
+			template: `{{ .Name `,
+			wantErr:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := renderNotification(tc.endpointType, tc.template, data)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
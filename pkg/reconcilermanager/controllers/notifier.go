@@ -0,0 +1,143 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// notificationEndpointSlack posts a Slack incoming webhook message.
+	notificationEndpointSlack = "slack"
+	// notificationEndpointTeams posts a Microsoft Teams incoming webhook
+	// message.
+	notificationEndpointTeams = "teams"
+	// notificationEndpointGeneric posts the notification data as JSON.
+	notificationEndpointGeneric = "generic"
+
+	// notificationURLKey is the key of the endpoint URL in the Secret
+	// referenced by a Notification.
+	notificationURLKey = "url"
+	// notificationTokenKey is the key of the optional bearer token in the
+	// Secret referenced by a Notification.
+	notificationTokenKey = "token"
+)
+
+// defaultNotificationTemplates are the templates used to render the body of
+// a notification when the Notification does not specify one.
+var defaultNotificationTemplates = map[string]string{
+	notificationEndpointSlack:   `{"text": {{ json .Summary }}}`,
+	notificationEndpointTeams:   `{"text": {{ json .Summary }}}`,
+	notificationEndpointGeneric: `{{ json . }}`,
+}
+
+// notificationData is the data available to notification templates.
+type notificationData struct {
+	// Trigger is the transition being notified about.
+	Trigger string `json:"trigger"`
+	// Kind is the kind of the RootSync or RepoSync.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the RootSync or RepoSync.
+	Namespace string `json:"namespace"`
+	// Name is the name of the RootSync or RepoSync.
+	Name string `json:"name"`
+	// State is the current sync state.
+	State string `json:"state"`
+	// PreviousState is the sync state before the transition, if known.
+	PreviousState string `json:"previousState,omitempty"`
+	// Commit is the source commit of the current sync state.
+	Commit string `json:"commit,omitempty"`
+	// Message describes the current sync state.
+	Message string `json:"message,omitempty"`
+	// Summary is a one-line human-readable description of the transition.
+	Summary string `json:"summary"`
+}
+
+// renderNotification renders the body of a notification with the specified
+// template, or the default template of the endpoint type if tmpl is empty.
+func renderNotification(endpointType, tmpl string, data notificationData) ([]byte, error) {
+	if tmpl == "" {
+		if endpointType == "" {
+			endpointType = notificationEndpointGeneric
+		}
+		var found bool
+		tmpl, found = defaultNotificationTemplates[endpointType]
+		if !found {
+			return nil, errors.Errorf("unknown notification endpoint type %q", endpointType)
+		}
+	}
+	t, err := template.New("notification").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid notification template")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "failed to render notification template")
+	}
+	return buf.Bytes(), nil
+}
+
+// notifier posts notifications to HTTP endpoints.
+type notifier struct {
+	httpClient *http.Client
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// post sends the body to the URL once. Failed deliveries are not retried
+// here, so a slow or failing endpoint does not block the other
+// Notifications; the caller returns the error and the Notification is
+// requeued with the rate-limited backoff of the workqueue.
+func (n *notifier) post(ctx context.Context, url, token string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "invalid notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post notification")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return fmt.Errorf("notification endpoint responded with %s", resp.Status)
+}
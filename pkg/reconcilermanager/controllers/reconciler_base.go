@@ -41,6 +41,7 @@ import (
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/util"
+	"kpt.dev/configsync/pkg/util/jsonpath"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -62,6 +63,11 @@ const (
 	// It will be used in both the indexing and watching.
 	helmSecretRefField = ".spec.helm.secretRef.name"
 
+	// bucketSecretRefField is the path of the field in the RootSync|RepoSync CRDs
+	// that we wish to use as the "object reference".
+	// It will be used in both the indexing and watching.
+	bucketSecretRefField = ".spec.bucket.secretRef.name"
+
 	// fleetMembershipName is the name of the fleet membership
 	fleetMembershipName = "membership"
 
@@ -70,6 +76,14 @@ const (
 	logFieldOperation = "operation"
 )
 
+const (
+	// tolerationsPath and nodeSelectorPath may be set by other controllers, or
+	// by the reconciler-manager from the scheduling overrides. They are only
+	// compared when the reconciler-manager sets them.
+	tolerationsPath  = "$.spec.template.spec.tolerations"
+	nodeSelectorPath = "$.spec.template.spec.nodeSelector"
+)
+
 // The fields in reconcilerManagerAllowList are the fields that reconciler manager allow
 // users or other controllers to modify.
 var reconcilerManagerAllowList = []string{
@@ -79,9 +93,9 @@ var reconcilerManagerAllowList = []string{
 	"$.spec.template.spec.containers[*].*.periodSeconds",
 	"$.spec.template.spec.containers[*].*.successThreshold",
 	"$.spec.template.spec.containers[*].*.failureThreshold",
-	"$.spec.template.spec.tolerations",
+	tolerationsPath,
 	"$.spec.template.spec.restartPolicy",
-	"$.spec.template.spec.nodeSelector", // remove this after Config Sync support user-defined nodeSelector through API
+	nodeSelectorPath,
 	"$.spec.template.spec.terminationGracePeriodSeconds",
 	"$.spec.template.spec.dnsPolicy",
 	"$.spec.template.spec.schedulerName",
@@ -99,6 +113,7 @@ type reconcilerBase struct {
 	isAutopilotCluster      *bool
 	reconcilerPollingPeriod time.Duration
 	hydrationPollingPeriod  time.Duration
+	tracingEndpoint         string
 	membership              *hubv1.Membership
 
 	// syncKind is the kind of the sync object: RootSync or RepoSync.
@@ -240,7 +255,8 @@ func (r *reconcilerBase) createOrPatchDeployment(ctx context.Context, declared *
 		}
 		r.isAutopilotCluster = &isAutopilot
 	}
-	dep, err := compareDeploymentsToCreatePatchData(*r.isAutopilotCluster, declared, currentDeploymentUnstructured, reconcilerManagerAllowList, r.scheme)
+	allowList := deploymentAllowList(declared, currentDeploymentUnstructured)
+	dep, err := compareDeploymentsToCreatePatchData(*r.isAutopilotCluster, declared, currentDeploymentUnstructured, allowList, r.scheme)
 	if err != nil {
 		return nil, controllerutil.OperationResultNone, err
 	}
@@ -286,10 +302,46 @@ func (r *reconcilerBase) createOrPatchDeployment(ctx context.Context, declared *
 	return appliedObj, controllerutil.OperationResultUpdated, nil
 }
 
+// deploymentAllowList returns the fields of reconcilerManagerAllowList which
+// are not managed by the reconciler-manager, neither in the declared
+// Deployment from the scheduling overrides, nor in the current Deployment from
+// previous overrides which may have to be removed.
+func deploymentAllowList(declared *appsv1.Deployment, current *unstructured.Unstructured) []string {
+	managed := map[string]bool{
+		tolerationsPath:  len(declared.Spec.Template.Spec.Tolerations) > 0 || managesPodSpecField(current, "tolerations"),
+		nodeSelectorPath: len(declared.Spec.Template.Spec.NodeSelector) > 0 || managesPodSpecField(current, "nodeSelector"),
+	}
+	var allowList []string
+	for _, path := range reconcilerManagerAllowList {
+		if !managed[path] {
+			allowList = append(allowList, path)
+		}
+	}
+	return allowList
+}
+
+// managesPodSpecField returns true if the reconciler-manager applied the field
+// of the pod template spec of the Deployment.
+func managesPodSpecField(deployment *unstructured.Unstructured, field string) bool {
+	for _, entry := range deployment.GetManagedFields() {
+		if entry.Manager != reconcilermanager.ManagerName || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(fields, "f:spec", "f:template", "f:spec", "f:"+field); found {
+			return true
+		}
+	}
+	return false
+}
+
 // deleteDeploymentFields delete all the fields in allowlist from unstructured object and convert the unstructured object to Deployment object
 func deleteDeploymentFields(allowList []string, unstructuredDeployment *unstructured.Unstructured) (*appsv1.Deployment, error) {
 	for _, path := range allowList {
-		if err := deleteFields(unstructuredDeployment.Object, path); err != nil {
+		if err := jsonpath.DeleteFields(unstructuredDeployment.Object, path); err != nil {
 			return nil, err
 		}
 	}
@@ -461,6 +513,39 @@ func mutateContainerResource(ctx context.Context, c *corev1.Container, override
 	}
 }
 
+// mutatePodScheduling applies the scheduling overrides to the pod template of a
+// reconciler Deployment. The labels already set on the pod template take
+// precedence over the label overrides.
+func mutatePodScheduling(template *corev1.PodTemplateSpec, override *v1beta1.OverrideSpec) {
+	if override == nil {
+		return
+	}
+	for k, v := range override.PodLabels {
+		if _, found := template.Labels[k]; !found {
+			core.SetLabel(template, k, v)
+		}
+	}
+	for k, v := range override.PodAnnotations {
+		core.SetAnnotation(template, k, v)
+	}
+	spec := &template.Spec
+	if len(override.NodeSelector) > 0 {
+		spec.NodeSelector = override.NodeSelector
+	}
+	if len(override.Tolerations) > 0 {
+		spec.Tolerations = override.Tolerations
+	}
+	if override.Affinity != nil {
+		spec.Affinity = override.Affinity
+	}
+	if override.PriorityClassName != "" {
+		spec.PriorityClassName = override.PriorityClassName
+	}
+	if len(override.TopologySpreadConstraints) > 0 {
+		spec.TopologySpreadConstraints = override.TopologySpreadConstraints
+	}
+}
+
 // addLabels will copy the content of labelMaps to the current resource labels
 func (r *reconcilerBase) addLabels(resource client.Object, labelMap map[string]string) {
 	currentLabels := resource.GetLabels()
@@ -25,6 +25,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/kinds"
@@ -33,6 +34,7 @@ import (
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 func TestAdjustContainerResources(t *testing.T) {
@@ -663,3 +665,81 @@ func mapToPodResources(m map[string]corev1.ResourceRequirements) *util.PodResour
 	}
 	return &util.PodResources{Containers: containers}
 }
+
+func TestDeploymentAllowList(t *testing.T) {
+	nodeSelectorManagedFields := []metav1.ManagedFieldsEntry{{
+		Manager:   reconcilermanager.ManagerName,
+		Operation: metav1.ManagedFieldsOperationApply,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:nodeSelector":{}}}}}`)},
+	}}
+	otherManagedFields := []metav1.ManagedFieldsEntry{{
+		Manager:   "kube-controller-manager",
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:tolerations":{}}}}}`)},
+	}}
+
+	testCases := map[string]struct {
+		declared      func(*appsv1.Deployment)
+		managedFields []metav1.ManagedFieldsEntry
+		wantCompared  []string
+	}{
+		"no overrides": {},
+		"tolerations override": {
+			declared: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "pool", Operator: corev1.TolerationOpExists}}
+			},
+			wantCompared: []string{tolerationsPath},
+		},
+		"nodeSelector applied previously": {
+			managedFields: nodeSelectorManagedFields,
+			wantCompared:  []string{nodeSelectorPath},
+		},
+		"tolerations set by another manager": {
+			managedFields: otherManagedFields,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			declared := yamlToDeployment(t, declaredDeployment)
+			if tc.declared != nil {
+				tc.declared(declared)
+			}
+			current := yamlToUnstructured(t, currentDeployment)
+			current.SetManagedFields(tc.managedFields)
+
+			got := deploymentAllowList(declared, current)
+			var compared []string
+			for _, path := range reconcilerManagerAllowList {
+				found := false
+				for _, p := range got {
+					found = found || p == path
+				}
+				if !found {
+					compared = append(compared, path)
+				}
+			}
+			require.Equal(t, tc.wantCompared, compared)
+		})
+	}
+}
+
+func yamlToUnstructured(t *testing.T, yml string) *unstructured.Unstructured {
+	m := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(yml), &m)
+	if err != nil {
+		t.Fatalf("error parsing yaml: %v", err)
+		return nil
+	}
+	return &unstructured.Unstructured{Object: m}
+}
+
+func yamlToDeployment(t *testing.T, yml string) *appsv1.Deployment {
+	d := &appsv1.Deployment{}
+	err := yaml.Unmarshal([]byte(yml), d)
+	if err != nil {
+		t.Fatalf("error parsing yaml: %v", err)
+		return nil
+	}
+	return d
+}
@@ -70,7 +70,7 @@ type RepoSyncReconciler struct {
 }
 
 // NewRepoSyncReconciler returns a new RepoSyncReconciler.
-func NewRepoSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RepoSyncReconciler {
+func NewRepoSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, tracingEndpoint string, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RepoSyncReconciler {
 	return &RepoSyncReconciler{
 		reconcilerBase: reconcilerBase{
 			clusterName:             clusterName,
@@ -80,6 +80,7 @@ func NewRepoSyncReconciler(clusterName string, reconcilerPollingPeriod, hydratio
 			scheme:                  scheme,
 			reconcilerPollingPeriod: reconcilerPollingPeriod,
 			hydrationPollingPeriod:  hydrationPollingPeriod,
+			tracingEndpoint:         tracingEndpoint,
 			syncKind:                configsync.RepoSyncKind,
 		},
 		repoSyncs: make(map[types.NamespacedName]struct{}),
@@ -192,6 +193,8 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 			authType = rs.Spec.Auth
 		} else if rs.Spec.SourceType == string(v1beta1.HelmSource) {
 			authType = rs.Spec.Helm.Auth
+		} else if rs.Spec.SourceType == string(v1beta1.BucketSource) {
+			authType = rs.Spec.Bucket.Auth
 		}
 		log.Error(err, "Managed object upsert failed",
 			logFieldObject, sRef.String(),
@@ -292,6 +295,65 @@ func (r *RepoSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		return controllerruntime.Result{}, errors.Wrap(err, "RoleBinding reconcile failed")
 	}
 
+	// Allow the reconciler to impersonate the ServiceAccount of the RepoSync.
+	if rbRef, err := r.upsertImpersonation(ctx, reconcilerRef, rs); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, rbRef.String(),
+			logFieldKind, "RoleBinding",
+			"serviceAccountName", rs.Spec.ServiceAccountName)
+		reposync.SetStalled(rs, "RoleBinding", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "RoleBinding reconcile failed")
+	}
+
+	// Allow the reconciler to read the cluster-scoped configuration.
+	if crbRef, err := r.upsertClusterReader(ctx, reconcilerRef); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, crbRef.String(),
+			logFieldKind, "ClusterRoleBinding")
+		reposync.SetStalled(rs, "ClusterRoleBinding", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "ClusterRoleBinding reconcile failed")
+	}
+
+	// Allow the reconciler to read the ConfigMap or Secret holding the local
+	// source of the RepoSync.
+	if rbRef, err := r.upsertLocalSourceAccess(ctx, reconcilerRef, rs); err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, rbRef.String(),
+			logFieldKind, "RoleBinding")
+		reposync.SetStalled(rs, "RoleBinding", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "RoleBinding reconcile failed")
+	}
+
 	containerEnvs := r.populateContainerEnvs(ctx, rs, reconcilerRef.Name)
 	mut := r.mutationsFor(ctx, rs, containerEnvs)
 
@@ -430,6 +492,16 @@ func (r *RepoSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, wat
 	}); err != nil {
 		return err
 	}
+	// Index the `bucketSecretRefName` field, so that we will be able to lookup RepoSync be a referenced `SecretRef` name.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.RepoSync{}, bucketSecretRefField, func(rawObj client.Object) []string {
+		rs := rawObj.(*v1beta1.RepoSync)
+		if rs.Spec.Bucket == nil || v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef) == "" {
+			return nil
+		}
+		return []string{rs.Spec.Bucket.SecretRef.Name}
+	}); err != nil {
+		return err
+	}
 
 	controllerBuilder := controllerruntime.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
@@ -556,7 +628,7 @@ func (r *RepoSyncReconciler) mapSecretToRepoSyncs(secret client.Object) []reconc
 	// The user-managed ns-reconciler Secret might be shared among multiple RepoSync objects in the same namespace,
 	// so requeue all the attached RepoSync objects.
 	attachedRepoSyncs := &v1beta1.RepoSyncList{}
-	secretFields := []string{gitSecretRefField, caCertSecretRefField, helmSecretRefField}
+	secretFields := []string{gitSecretRefField, caCertSecretRefField, helmSecretRefField, bucketSecretRefField}
 	for _, secretField := range secretFields {
 		listOps := &client.ListOptions{
 			FieldSelector: fields.OneTermEqualSelector(secretField, secret.GetName()),
@@ -666,8 +738,8 @@ func requeueRepoSyncRequest(obj client.Object, rs *v1beta1.RepoSync) []reconcile
 
 func (r *RepoSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) map[string][]corev1.EnvVar {
 	result := map[string][]corev1.EnvVar{
-		reconcilermanager.HydrationController: hydrationEnvs(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, declared.Scope(rs.Namespace), reconcilerName, r.hydrationPollingPeriod.String()),
-		reconcilermanager.Reconciler:          reconcilerEnvs(r.clusterName, rs.Name, reconcilerName, declared.Scope(rs.Namespace), rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm), r.reconcilerPollingPeriod.String(), rs.Spec.SafeOverride().StatusMode, v1beta1.GetReconcileTimeout(rs.Spec.SafeOverride().ReconcileTimeout), v1beta1.GetAPIServerTimeout(rs.Spec.SafeOverride().APIServerTimeout)),
+		reconcilermanager.HydrationController: append(hydrationEnvs(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rs.Spec.Local, rs.Spec.Bucket, declared.Scope(rs.Namespace), reconcilerName, r.hydrationPollingPeriod.String()), tracingEnvs(r.tracingEndpoint)...),
+		reconcilermanager.Reconciler:          append(reconcilerEnvs(r.clusterName, rs.Name, reconcilerName, declared.Scope(rs.Namespace), rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, reposync.GetHelmBase(rs.Spec.Helm), rs.Spec.Local, rs.Spec.Bucket, r.reconcilerPollingPeriod.String(), rs.Spec.SafeOverride().StatusMode, v1beta1.GetReconcileTimeout(rs.Spec.SafeOverride().ReconcileTimeout), v1beta1.GetAPIServerTimeout(rs.Spec.SafeOverride().APIServerTimeout)), append(append(impersonationEnvs(rs.Spec.ServiceAccountName), adoptionEnvs(rs.Spec.AdoptionPolicy)...), tracingEnvs(r.tracingEndpoint)...)...),
 	}
 	switch v1beta1.SourceType(rs.Spec.SourceType) {
 	case v1beta1.GitSource:
@@ -686,11 +758,20 @@ func (r *RepoSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1be
 		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period))
 	case v1beta1.HelmSource:
 		result[reconcilermanager.HelmSync] = helmSyncEnvs(&rs.Spec.Helm.HelmBase, rs.Namespace)
+	case v1beta1.LocalSource:
+		result[reconcilermanager.LocalSync] = localSyncEnvs(rs.Spec.Local, rs.Namespace)
+	case v1beta1.BucketSource:
+		result[reconcilermanager.BucketSync] = bucketSyncEnvs(rs.Spec.Bucket)
 	}
 	return result
 }
 
 func (r *RepoSyncReconciler) validateSpec(ctx context.Context, rs *v1beta1.RepoSync, reconcilerName string) error {
+	if rs.Spec.ServiceAccountName != "" {
+		if errs := validation.IsDNS1123Subdomain(rs.Spec.ServiceAccountName); errs != nil {
+			return errors.Errorf("The ServiceAccount name %q is invalid: %s. To fix it, update '.spec.serviceAccountName'", rs.Spec.ServiceAccountName, strings.Join(errs, ", "))
+		}
+	}
 	switch v1beta1.SourceType(rs.Spec.SourceType) {
 	case v1beta1.GitSource:
 		return r.validateGitSpec(ctx, rs, reconcilerName)
@@ -698,6 +779,13 @@ func (r *RepoSyncReconciler) validateSpec(ctx context.Context, rs *v1beta1.RepoS
 		return validate.OciSpec(rs.Spec.Oci, rs)
 	case v1beta1.HelmSource:
 		return validate.HelmSpec(reposync.GetHelmBase(rs.Spec.Helm), rs)
+	case v1beta1.LocalSource:
+		return validate.LocalSpec(rs.Spec.Local, rs)
+	case v1beta1.BucketSource:
+		if err := validate.BucketSpec(rs.Spec.Bucket, rs); err != nil {
+			return err
+		}
+		return r.validateNamespaceSecret(ctx, rs, reconcilerName)
 	default:
 		return validate.InvalidSourceType(rs)
 	}
@@ -720,6 +808,9 @@ func (r *RepoSyncReconciler) validateNamespaceSecret(ctx context.Context, repoSy
 	} else if repoSync.Spec.SourceType == string(v1beta1.HelmSource) {
 		authType = repoSync.Spec.Helm.Auth
 		namespaceSecretName = v1beta1.GetSecretName(repoSync.Spec.Helm.SecretRef)
+	} else if repoSync.Spec.SourceType == string(v1beta1.BucketSource) {
+		authType = repoSync.Spec.Bucket.Auth
+		namespaceSecretName = v1beta1.GetSecretName(repoSync.Spec.Bucket.SecretRef)
 	}
 	if SkipForAuth(authType) {
 		// There is no Secret to check for the Config object.
@@ -774,6 +865,179 @@ func (r *RepoSyncReconciler) upsertRoleBinding(ctx context.Context, reconcilerRe
 	return rbRef, nil
 }
 
+// upsertImpersonation allows the reconciler to impersonate the ServiceAccount
+// of the RepoSync, if any, and to identify itself while doing so with the
+// reconcilermanager.ReconcilerUserExtraKey user extra info. It deletes the
+// permissions if the RepoSync does not specify a ServiceAccount.
+//
+// ServiceAccounts are namespaced, so the permission to impersonate one is
+// granted by a Role in the namespace of the RepoSync. User extra info is not
+// namespaced, so the permission to set it is granted by a ClusterRole.
+func (r *RepoSyncReconciler) upsertImpersonation(ctx context.Context, reconcilerRef types.NamespacedName, rs *v1beta1.RepoSync) (client.ObjectKey, error) {
+	name := RepoSyncImpersonationName(reconcilerRef.Name)
+	rbRef := client.ObjectKey{Namespace: rs.Namespace, Name: name}
+	if rs.Spec.ServiceAccountName == "" {
+		return rbRef, r.deleteImpersonation(ctx, reconcilerRef, rs.Namespace)
+	}
+
+	role := &rbacv1.Role{}
+	role.Name = name
+	role.Namespace = rs.Namespace
+	roleBinding := &rbacv1.RoleBinding{}
+	roleBinding.Name = name
+	roleBinding.Namespace = rs.Namespace
+	clusterRole := &rbacv1.ClusterRole{}
+	clusterRole.Name = name
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	clusterRoleBinding.Name = name
+
+	subjects := []rbacv1.Subject{r.serviceAccountSubject(reconcilerRef)}
+	mutations := []struct {
+		obj client.Object
+		fn  controllerutil.MutateFn
+	}{
+		{role, func() error {
+			role.Rules = []rbacv1.PolicyRule{{
+				APIGroups:     []string{""},
+				Resources:     []string{"serviceaccounts"},
+				Verbs:         []string{"impersonate"},
+				ResourceNames: []string{rs.Spec.ServiceAccountName},
+			}}
+			return nil
+		}},
+		{roleBinding, func() error {
+			roleBinding.RoleRef = rolereference(name, "Role")
+			roleBinding.Subjects = subjects
+			return nil
+		}},
+		{clusterRole, func() error {
+			clusterRole.Rules = []rbacv1.PolicyRule{{
+				APIGroups:     []string{"authentication.k8s.io"},
+				Resources:     []string{"userextras/" + reconcilermanager.ReconcilerUserExtraKey},
+				Verbs:         []string{"impersonate"},
+				ResourceNames: []string{reconcilerRef.Name},
+			}}
+			return nil
+		}},
+		{clusterRoleBinding, func() error {
+			clusterRoleBinding.RoleRef = rolereference(name, "ClusterRole")
+			clusterRoleBinding.Subjects = subjects
+			return nil
+		}},
+	}
+	for _, m := range mutations {
+		op, err := controllerruntime.CreateOrUpdate(ctx, r.client, m.obj, m.fn)
+		if err != nil {
+			return client.ObjectKeyFromObject(m.obj), err
+		}
+		if op != controllerutil.OperationResultNone {
+			if err := r.addTypeInformationToObject(m.obj); err != nil {
+				return client.ObjectKeyFromObject(m.obj), err
+			}
+			r.log.Info("Managed object upsert successful",
+				logFieldObject, client.ObjectKeyFromObject(m.obj).String(),
+				logFieldKind, m.obj.GetObjectKind().GroupVersionKind().Kind,
+				logFieldOperation, op)
+		}
+	}
+	return rbRef, nil
+}
+
+// upsertClusterReader allows the reconciler to read the cluster-scoped
+// configuration of Config Sync, such as the RepoSyncPolicies and the
+// DriftExemptions. The RoleBinding
+// of the namespace reconcilers only grants access to the namespace of their
+// RepoSync, so the ClusterRole is bound by a ClusterRoleBinding for each
+// reconciler.
+func (r *RepoSyncReconciler) upsertClusterReader(ctx context.Context, reconcilerRef types.NamespacedName) (client.ObjectKey, error) {
+	crb := &rbacv1.ClusterRoleBinding{}
+	crb.Name = RepoSyncClusterReaderBindingName(reconcilerRef.Name)
+	crbRef := client.ObjectKeyFromObject(crb)
+	op, err := controllerruntime.CreateOrUpdate(ctx, r.client, crb, func() error {
+		crb.RoleRef = rolereference(RepoSyncClusterReaderName(), "ClusterRole")
+		crb.Subjects = []rbacv1.Subject{r.serviceAccountSubject(reconcilerRef)}
+		return nil
+	})
+	if err != nil {
+		return crbRef, err
+	}
+	if op != controllerutil.OperationResultNone {
+		r.log.Info("Managed object upsert successful",
+			logFieldObject, crbRef.String(),
+			logFieldKind, "ClusterRoleBinding",
+			logFieldOperation, op)
+	}
+	return crbRef, nil
+}
+
+// upsertLocalSourceAccess allows the reconciler to read the ConfigMap or Secret
+// holding the local source of the RepoSync, which is in the namespace of the
+// RepoSync. It deletes the RBAC objects granting the access when the RepoSync
+// does not sync from a local source.
+func (r *RepoSyncReconciler) upsertLocalSourceAccess(ctx context.Context, reconcilerRef types.NamespacedName, rs *v1beta1.RepoSync) (client.ObjectKey, error) {
+	name := RepoSyncLocalSourceName(reconcilerRef.Name)
+	rbRef := client.ObjectKey{Namespace: rs.Namespace, Name: name}
+	var rule rbacv1.PolicyRule
+	switch {
+	case v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.LocalSource || rs.Spec.Local == nil:
+		return rbRef, r.deleteLocalSourceAccess(ctx, reconcilerRef, rs.Namespace)
+	case rs.Spec.Local.ConfigMapRef != nil:
+		rule = rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			Verbs:         []string{"get"},
+			ResourceNames: []string{rs.Spec.Local.ConfigMapRef.Name},
+		}
+	case rs.Spec.Local.SecretRef != nil:
+		rule = rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			Verbs:         []string{"get"},
+			ResourceNames: []string{rs.Spec.Local.SecretRef.Name},
+		}
+	default:
+		return rbRef, r.deleteLocalSourceAccess(ctx, reconcilerRef, rs.Namespace)
+	}
+
+	role := &rbacv1.Role{}
+	role.Name = name
+	role.Namespace = rs.Namespace
+	roleBinding := &rbacv1.RoleBinding{}
+	roleBinding.Name = name
+	roleBinding.Namespace = rs.Namespace
+
+	mutations := []struct {
+		obj client.Object
+		fn  controllerutil.MutateFn
+	}{
+		{role, func() error {
+			role.Rules = []rbacv1.PolicyRule{rule}
+			return nil
+		}},
+		{roleBinding, func() error {
+			roleBinding.RoleRef = rolereference(name, "Role")
+			roleBinding.Subjects = []rbacv1.Subject{r.serviceAccountSubject(reconcilerRef)}
+			return nil
+		}},
+	}
+	for _, m := range mutations {
+		op, err := controllerruntime.CreateOrUpdate(ctx, r.client, m.obj, m.fn)
+		if err != nil {
+			return client.ObjectKeyFromObject(m.obj), err
+		}
+		if op != controllerutil.OperationResultNone {
+			if err := r.addTypeInformationToObject(m.obj); err != nil {
+				return client.ObjectKeyFromObject(m.obj), err
+			}
+			r.log.Info("Managed object upsert successful",
+				logFieldObject, client.ObjectKeyFromObject(m.obj).String(),
+				logFieldKind, m.obj.GetObjectKind().GroupVersionKind().Kind,
+				logFieldOperation, op)
+		}
+	}
+	return rbRef, nil
+}
+
 func (r *RepoSyncReconciler) updateStatus(ctx context.Context, currentRS, rs *v1beta1.RepoSync) (bool, error) {
 	rs.Status.ObservedGeneration = rs.Generation
 
@@ -828,6 +1092,9 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 			auth = rs.Spec.Helm.Auth
 			gcpSAEmail = rs.Spec.Helm.GCPServiceAccountEmail
 			secretRefName = v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)
+		case v1beta1.BucketSource:
+			auth = rs.Spec.Bucket.Auth
+			secretRefName = v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef)
 		}
 		injectFWICreds := useFWIAuth(auth, r.membership)
 		if injectFWICreds {
@@ -836,6 +1103,10 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 			}
 		}
 
+		// Apply the scheduling overrides before the reconciler label, which
+		// takes precedence.
+		mutatePodScheduling(&d.Spec.Template, rs.Spec.Override)
+
 		// Add unique reconciler label
 		core.SetLabel(&d.Spec.Template, metadata.ReconcilerLabel, reconcilerName)
 
@@ -879,6 +1150,25 @@ func (r *RepoSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RepoS
 					injectFWICredsToContainer(&container, injectFWICreds)
 					mutateContainerResource(ctx, &container, rs.Spec.Override, string(NamespaceReconcilerType))
 				}
+			case reconcilermanager.LocalSync:
+				// Don't add the local-sync container when sourceType is NOT local.
+				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.LocalSource {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					mutateContainerResource(ctx, &container, rs.Spec.Override, string(NamespaceReconcilerType))
+				}
+			case reconcilermanager.BucketSync:
+				// Don't add the bucket-sync container when sourceType is NOT bucket.
+				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.BucketSource {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					if rs.Spec.Bucket.Auth == configsync.AuthAccessKey {
+						container.Env = append(container.Env, bucketSyncAccessKeyEnv(secretName)...)
+					}
+					mutateContainerResource(ctx, &container, rs.Spec.Override, string(NamespaceReconcilerType))
+				}
 			case reconcilermanager.HelmSync:
 				// Don't add the helm-sync container when sourceType is NOT helm.
 				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.HelmSource {
@@ -179,6 +179,12 @@ func reposyncGCPSAEmail(email string) func(sync *v1beta1.RepoSync) {
 	}
 }
 
+func reposyncServiceAccountName(name string) func(*v1beta1.RepoSync) {
+	return func(sync *v1beta1.RepoSync) {
+		sync.Spec.ServiceAccountName = name
+	}
+}
+
 func reposyncOverrideResources(containers []v1beta1.ContainerResourcesSpec) func(sync *v1beta1.RepoSync) {
 	return func(sync *v1beta1.RepoSync) {
 		sync.Spec.Override = &v1beta1.OverrideSpec{
@@ -276,6 +282,7 @@ func setupNSReconciler(t *testing.T, objs ...client.Object) (*syncerFake.Client,
 		testCluster,
 		filesystemPollingPeriod,
 		hydrationPollingPeriod,
+		"",
 		fakeClient,
 		fakeDynamicClient,
 		controllerruntime.Log.WithName("controllers").WithName(configsync.RepoSyncKind),
@@ -2343,12 +2350,20 @@ func validateRepoGeneratedResourcesDeleted(t *testing.T, fakeClient *syncerFake.
 		t.Error(err)
 	}
 
-	// Verify the copied secret is deleted for RepoSync.
+	// Verify the copied secret and the cluster reader ClusterRoleBinding are
+	// deleted for RepoSync.
 	if strings.HasPrefix(reconcilerName, core.NsReconcilerPrefix) {
 		s := fake.SecretObject(ReconcilerResourceName(reconcilerName, secretRefName), core.Namespace(nsReconcilerKey.Namespace))
 		if err := validateResourceDeleted(core.IDOf(s), fakeClient); err != nil {
 			t.Error(err)
 		}
+		crbID := core.ID{
+			GroupKind: kinds.ClusterRoleBinding().GroupKind(),
+			ObjectKey: client.ObjectKey{Name: RepoSyncClusterReaderBindingName(reconcilerName)},
+		}
+		if err := validateResourceDeleted(crbID, fakeClient); err != nil {
+			t.Error(err)
+		}
 	}
 }
 
@@ -3053,6 +3068,350 @@ func TestRepoSyncWithOCI(t *testing.T) {
 	t.Log("Deployment successfully updated")
 }
 
+func TestRepoSyncServiceAccountName(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := repoSync(reposyncNs, reposyncName, reposyncRef(gitRevision), reposyncBranch(branch), reposyncSecretType(configsync.AuthSSH), reposyncSecretRef(reposyncSSHKey), reposyncServiceAccountName("app-deployer"))
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, fakeDynamicClient, testReconciler := setupNSReconciler(t, rs, secretObj(t, reposyncSSHKey, configsync.AuthSSH, v1beta1.GitSource, core.Namespace(rs.Namespace)))
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	repoContainerEnv := testReconciler.populateContainerEnvs(ctx, rs, nsReconcilerName)
+	found := false
+	for _, env := range repoContainerEnv[reconcilermanager.Reconciler] {
+		if env.Name == reconcilermanager.ImpersonateServiceAccountKey && env.Value == "app-deployer" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("reconciler container envs %v do not set %s", repoContainerEnv[reconcilermanager.Reconciler], reconcilermanager.ImpersonateServiceAccountKey)
+	}
+
+	name := RepoSyncImpersonationName(nsReconcilerName)
+	role := &rbacv1.Role{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: reposyncNs, Name: name}, role); err != nil {
+		t.Fatalf("failed to get Role: %v", err)
+	}
+	wantRules := []rbacv1.PolicyRule{{
+		APIGroups:     []string{""},
+		Resources:     []string{"serviceaccounts"},
+		Verbs:         []string{"impersonate"},
+		ResourceNames: []string{"app-deployer"},
+	}}
+	if diff := cmp.Diff(wantRules, role.Rules); diff != "" {
+		t.Errorf("Role rules diff (- want, + got):\n%s", diff)
+	}
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Name: name}, clusterRole); err != nil {
+		t.Fatalf("failed to get ClusterRole: %v", err)
+	}
+	wantRules = []rbacv1.PolicyRule{{
+		APIGroups:     []string{"authentication.k8s.io"},
+		Resources:     []string{"userextras/" + reconcilermanager.ReconcilerUserExtraKey},
+		Verbs:         []string{"impersonate"},
+		ResourceNames: []string{nsReconcilerName},
+	}}
+	if diff := cmp.Diff(wantRules, clusterRole.Rules); diff != "" {
+		t.Errorf("ClusterRole rules diff (- want, + got):\n%s", diff)
+	}
+	wantSubjects := []rbacv1.Subject{{
+		Kind:      "ServiceAccount",
+		Name:      nsReconcilerName,
+		Namespace: configsync.ControllerNamespace,
+	}}
+	roleBinding := &rbacv1.RoleBinding{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: reposyncNs, Name: name}, roleBinding); err != nil {
+		t.Fatalf("failed to get RoleBinding: %v", err)
+	}
+	if diff := cmp.Diff(wantSubjects, roleBinding.Subjects); diff != "" {
+		t.Errorf("RoleBinding subjects diff (- want, + got):\n%s", diff)
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Name: name}, clusterRoleBinding); err != nil {
+		t.Fatalf("failed to get ClusterRoleBinding: %v", err)
+	}
+	if diff := cmp.Diff(wantSubjects, clusterRoleBinding.Subjects); diff != "" {
+		t.Errorf("ClusterRoleBinding subjects diff (- want, + got):\n%s", diff)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	// Unset the ServiceAccount to impersonate.
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the repo sync: %v", err)
+	}
+	rs.Spec.ServiceAccountName = ""
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the repo sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+
+	for _, id := range []core.ID{
+		{GroupKind: kinds.Role().GroupKind(), ObjectKey: client.ObjectKey{Namespace: reposyncNs, Name: name}},
+		{GroupKind: kinds.RoleBinding().GroupKind(), ObjectKey: client.ObjectKey{Namespace: reposyncNs, Name: name}},
+		{GroupKind: kinds.ClusterRole().GroupKind(), ObjectKey: client.ObjectKey{Name: name}},
+		{GroupKind: kinds.ClusterRoleBinding().GroupKind(), ObjectKey: client.ObjectKey{Name: name}},
+	} {
+		if err := validateResourceDeleted(id, fakeClient); err != nil {
+			t.Error(err)
+		}
+	}
+
+	repoContainerEnv = testReconciler.populateContainerEnvs(ctx, rs, nsReconcilerName)
+	repoDeployment := repoSyncDeployment(
+		nsReconcilerName,
+		setServiceAccountName(nsReconcilerName),
+		secretMutator(nsReconcilerName+"-"+reposyncSSHKey),
+		containerEnvMutator(repoContainerEnv),
+		setUID("1"), setResourceVersion("2"), setGeneration(2),
+	)
+	wantDeployments := map[core.ID]*appsv1.Deployment{core.IDOf(repoDeployment): repoDeployment}
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+}
+
+func TestRepoSyncClusterReader(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := repoSync(reposyncNs, reposyncName, reposyncRef(gitRevision), reposyncBranch(branch), reposyncSecretType(configsync.AuthSSH), reposyncSecretRef(reposyncSSHKey))
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, _, testReconciler := setupNSReconciler(t, rs, secretObj(t, reposyncSSHKey, configsync.AuthSSH, v1beta1.GitSource, core.Namespace(rs.Namespace)))
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	name := RepoSyncClusterReaderBindingName(nsReconcilerName)
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Name: name}, clusterRoleBinding); err != nil {
+		t.Fatalf("failed to get ClusterRoleBinding: %v", err)
+	}
+	if diff := cmp.Diff(rolereference(RepoSyncClusterReaderName(), "ClusterRole"), clusterRoleBinding.RoleRef); diff != "" {
+		t.Errorf("ClusterRoleBinding roleRef diff (- want, + got):\n%s", diff)
+	}
+	wantSubjects := []rbacv1.Subject{{
+		Kind:      "ServiceAccount",
+		Name:      nsReconcilerName,
+		Namespace: configsync.ControllerNamespace,
+	}}
+	if diff := cmp.Diff(wantSubjects, clusterRoleBinding.Subjects); diff != "" {
+		t.Errorf("ClusterRoleBinding subjects diff (- want, + got):\n%s", diff)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	rs.ResourceVersion = "" // Skip ResourceVersion validation
+	if err := fakeClient.Delete(ctx, rs); err != nil {
+		t.Fatalf("failed to delete the repo sync request, got error: %v, want error: nil", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request deletion, got error: %q, want error: nil", err)
+	}
+	crbID := core.ID{GroupKind: kinds.ClusterRoleBinding().GroupKind(), ObjectKey: client.ObjectKey{Name: name}}
+	if err := validateResourceDeleted(crbID, fakeClient); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepoSyncWithLocal(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := fake.RepoSyncObjectV1Beta1(reposyncNs, reposyncName)
+	rs.Spec.SourceType = string(v1beta1.LocalSource)
+	rs.Spec.Local = &v1beta1.Local{
+		ConfigMapRef: &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+	}
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, _, testReconciler := setupNSReconciler(t, rs)
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	name := RepoSyncLocalSourceName(nsReconcilerName)
+	validateRules := func(want []rbacv1.PolicyRule) {
+		t.Helper()
+		role := &rbacv1.Role{}
+		if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: reposyncNs, Name: name}, role); err != nil {
+			t.Fatalf("failed to get Role: %v", err)
+		}
+		if diff := cmp.Diff(want, role.Rules); diff != "" {
+			t.Errorf("Role rules diff (- want, + got):\n%s", diff)
+		}
+	}
+	validateRules([]rbacv1.PolicyRule{{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		Verbs:         []string{"get"},
+		ResourceNames: []string{"source"},
+	}})
+	roleBinding := &rbacv1.RoleBinding{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: reposyncNs, Name: name}, roleBinding); err != nil {
+		t.Fatalf("failed to get RoleBinding: %v", err)
+	}
+	wantSubjects := []rbacv1.Subject{{
+		Kind:      "ServiceAccount",
+		Name:      nsReconcilerName,
+		Namespace: configsync.ControllerNamespace,
+	}}
+	if diff := cmp.Diff(wantSubjects, roleBinding.Subjects); diff != "" {
+		t.Errorf("RoleBinding subjects diff (- want, + got):\n%s", diff)
+	}
+
+	repoContainerEnvs := testReconciler.populateContainerEnvs(ctx, rs, nsReconcilerName)
+	wantEnvs := []corev1.EnvVar{
+		{Name: reconcilermanager.LocalSyncConfigMap, Value: "source"},
+		{Name: reconcilermanager.LocalSyncKey, Value: "source.tar.gz"},
+		{Name: reconcilermanager.LocalSyncNamespace, Value: reposyncNs},
+		{Name: reconcilermanager.LocalSyncWait, Value: "15.000000"},
+	}
+	if diff := cmp.Diff(wantEnvs, repoContainerEnvs[reconcilermanager.LocalSync]); diff != "" {
+		t.Errorf("local-sync envs diff (- want, + got):\n%s", diff)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	// Switch to a Secret.
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the repo sync: %v", err)
+	}
+	rs.Spec.Local = &v1beta1.Local{
+		SecretRef: &v1beta1.LocalObjectKeyReference{Name: "secret-source", Key: "source.tar"},
+	}
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the repo sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+	validateRules([]rbacv1.PolicyRule{{
+		APIGroups:     []string{""},
+		Resources:     []string{"secrets"},
+		Verbs:         []string{"get"},
+		ResourceNames: []string{"secret-source"},
+	}})
+
+	// Switch to an OCI image, which doesn't need the permissions.
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the repo sync: %v", err)
+	}
+	rs.Spec.SourceType = string(v1beta1.OciSource)
+	rs.Spec.Local = nil
+	rs.Spec.Oci = &v1beta1.Oci{Image: ociImage, Auth: configsync.AuthNone}
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the repo sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+	for _, id := range []core.ID{
+		{GroupKind: kinds.Role().GroupKind(), ObjectKey: client.ObjectKey{Namespace: reposyncNs, Name: name}},
+		{GroupKind: kinds.RoleBinding().GroupKind(), ObjectKey: client.ObjectKey{Namespace: reposyncNs, Name: name}},
+	} {
+		if err := validateResourceDeleted(id, fakeClient); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestRepoSyncWithBucket(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	secretName := "bucket-creds"
+	rs := fake.RepoSyncObjectV1Beta1(reposyncNs, reposyncName)
+	rs.Spec.SourceType = string(v1beta1.BucketSource)
+	rs.Spec.Bucket = &v1beta1.Bucket{
+		Endpoint:  "http://minio.minio:9000",
+		Bucket:    "configs",
+		Auth:      configsync.AuthAccessKey,
+		SecretRef: &v1beta1.SecretReference{Name: secretName},
+	}
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	bucketSecret := secretObj(t, secretName, configsync.AuthAccessKey, v1beta1.BucketSource, core.Namespace(rs.Namespace))
+	fakeClient, fakeDynamicClient, testReconciler := setupNSReconciler(t, rs, bucketSecret)
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	// The Secret is copied into the config-management-system namespace.
+	copiedSecretName := ReconcilerResourceName(nsReconcilerName, secretName)
+	copiedSecret := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Namespace: configsync.ControllerNamespace, Name: copiedSecretName}, copiedSecret); err != nil {
+		t.Fatalf("failed to get the copied Secret: %v", err)
+	}
+	if diff := cmp.Diff(bucketSecret.Data, copiedSecret.Data); diff != "" {
+		t.Errorf("copied Secret data diff (- want, + got):\n%s", diff)
+	}
+	if !isUpsertedSecret(rs, copiedSecretName) {
+		t.Errorf("isUpsertedSecret(%q) = false, want true", copiedSecretName)
+	}
+
+	repoContainerEnvs := testReconciler.populateContainerEnvs(ctx, rs, nsReconcilerName)
+	repoDeployment := repoSyncDeployment(nsReconcilerName,
+		setServiceAccountName(nsReconcilerName),
+		containersWithRepoVolumeMutator(bucketContainers()),
+		containerEnvMutator(repoContainerEnvs),
+		envVarMutator(reconcilermanager.BucketSyncAccessKeyID, copiedSecretName, BucketSecretKeyAccessKeyID),
+		envVarMutator(reconcilermanager.BucketSyncSecretAccessKey, copiedSecretName, BucketSecretKeySecretAccessKey),
+		optionalEnvVarMutator(reconcilermanager.BucketSyncSessionToken, copiedSecretName, BucketSecretKeySessionToken),
+		setUID("1"), setResourceVersion("1"), setGeneration(1),
+	)
+	wantDeployments := map[core.ID]*appsv1.Deployment{core.IDOf(repoDeployment): repoDeployment}
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Deployment successfully created")
+
+	// Switch to anonymous access.
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the repo sync: %v", err)
+	}
+	rs.Spec.Bucket.Auth = configsync.AuthNone
+	rs.Spec.Bucket.SecretRef = nil
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the repo sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+
+	repoContainerEnvs = testReconciler.populateContainerEnvs(ctx, rs, nsReconcilerName)
+	repoDeployment = repoSyncDeployment(nsReconcilerName,
+		setServiceAccountName(nsReconcilerName),
+		containersWithRepoVolumeMutator(bucketContainers()),
+		containerEnvMutator(repoContainerEnvs),
+		setUID("1"), setResourceVersion("2"), setGeneration(2),
+	)
+	wantDeployments[core.IDOf(repoDeployment)] = repoDeployment
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+	if isUpsertedSecret(rs, copiedSecretName) {
+		t.Errorf("isUpsertedSecret(%q) = true, want false", copiedSecretName)
+	}
+}
+
 func TestRepoSyncSpecValidation(t *testing.T) {
 	rs := fake.RepoSyncObjectV1Beta1(reposyncNs, reposyncName)
 	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
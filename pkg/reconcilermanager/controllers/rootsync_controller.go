@@ -28,8 +28,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -41,12 +44,15 @@ import (
 	hubv1 "kpt.dev/configsync/pkg/api/hub/v1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/kinds"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/util/compare"
+	"kpt.dev/configsync/pkg/util/mutate"
 	"kpt.dev/configsync/pkg/validate/raw/validate"
 	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -78,7 +84,7 @@ type RootSyncReconciler struct {
 }
 
 // NewRootSyncReconciler returns a new RootSyncReconciler.
-func NewRootSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RootSyncReconciler {
+func NewRootSyncReconciler(clusterName string, reconcilerPollingPeriod, hydrationPollingPeriod time.Duration, tracingEndpoint string, client client.Client, dynamicClient dynamic.Interface, log logr.Logger, scheme *runtime.Scheme) *RootSyncReconciler {
 	return &RootSyncReconciler{
 		reconcilerBase: reconcilerBase{
 			clusterName:             clusterName,
@@ -88,6 +94,7 @@ func NewRootSyncReconciler(clusterName string, reconcilerPollingPeriod, hydratio
 			scheme:                  scheme,
 			reconcilerPollingPeriod: reconcilerPollingPeriod,
 			hydrationPollingPeriod:  hydrationPollingPeriod,
+			tracingEndpoint:         tracingEndpoint,
 			syncKind:                configsync.RootSyncKind,
 		},
 	}
@@ -192,6 +199,27 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		metadata.SyncKindLabel:      r.syncKind,
 	}
 
+	// Delete the reconcilers of the shards removed since the last reconcile.
+	// This may update the RootSync, so it must happen before any status change.
+	if err = r.deleteRemovedShards(ctx, rs, reconcilerRef, labelMap); err != nil {
+		log.Error(err, "Managed object delete failed",
+			logFieldObject, rsRef.String(),
+			logFieldKind, "Deployment")
+		rootsync.SetStalled(rs, "Deployment", err)
+		// Delete errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the delete error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Deployment reconcile failed")
+	}
+	currentRS = rs.DeepCopy()
+
 	// Overwrite reconciler pod ServiceAccount.
 	var auth configsync.AuthType
 	var gcpSAEmail string
@@ -246,7 +274,7 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 	}
 
 	containerEnvs := r.populateContainerEnvs(ctx, rs, reconcilerRef.Name)
-	mut := r.mutationsFor(ctx, rs, containerEnvs)
+	mut := r.mutationsFor(ctx, rs, reconcilerRef.Name, containerEnvs)
 
 	// Upsert Root reconciler deployment.
 	deployObj, op, err := r.upsertDeployment(ctx, reconcilerRef, labelMap, mut)
@@ -313,6 +341,29 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 		"status", result.Status,
 		"message", result.Message)
 
+	// Upsert the reconciler Deployments of the other shards.
+	shardResult, err := r.upsertShardDeployments(ctx, rs, reconcilerRef, labelMap)
+	if err != nil {
+		log.Error(err, "Managed object upsert failed",
+			logFieldObject, reconcilerRef.String(),
+			logFieldKind, "Deployment")
+		rootsync.SetStalled(rs, "Deployment", err)
+		// Upsert errors should always trigger retry (return error),
+		// even if status update is successful.
+		_, updateErr := r.updateStatus(ctx, currentRS, rs)
+		if updateErr != nil {
+			log.Error(updateErr, "Object status update failed",
+				logFieldObject, rsRef.String(),
+				logFieldKind, r.syncKind)
+		}
+		// Use the upsert error for metric tagging.
+		metrics.RecordReconcileDuration(ctx, metrics.StatusTagKey(err), start)
+		return controllerruntime.Result{}, errors.Wrap(err, "Deployment reconcile failed")
+	}
+	if shardResult != nil && deploymentStatusRank(shardResult.Status) > deploymentStatusRank(result.Status) {
+		result = shardResult
+	}
+
 	// Update RootSync status based on reconciler deployment condition result.
 	switch result.Status {
 	case kstatus.InProgressStatus:
@@ -351,6 +402,118 @@ func (r *RootSyncReconciler) Reconcile(ctx context.Context, req controllerruntim
 	return controllerruntime.Result{}, nil
 }
 
+// upsertShardDeployments upserts the reconciler Deployments of the shards of
+// a sharded RootSync, other than the first one, and returns the least ready
+// of their statuses. It returns a nil status if the RootSync is not sharded.
+//
+// The shards share the ServiceAccount of the first one.
+func (r *RootSyncReconciler) upsertShardDeployments(ctx context.Context, rs *v1beta1.RootSync, reconcilerRef types.NamespacedName, labelMap map[string]string) (*kstatus.Result, error) {
+	var worst *kstatus.Result
+	for i := 1; i < rs.Spec.GetShards(); i++ {
+		shardRef := types.NamespacedName{
+			Namespace: reconcilerRef.Namespace,
+			Name:      shard.Name(reconcilerRef.Name, i),
+		}
+		containerEnvs := r.populateShardContainerEnvs(ctx, rs, shardRef.Name, i)
+		deployObj, op, err := r.upsertDeployment(ctx, shardRef, labelMap, r.mutationsFor(ctx, rs, shardRef.Name, containerEnvs))
+		if err != nil {
+			return nil, err
+		}
+		if op == controllerutil.OperationResultNone {
+			deployObj, err = r.deployment(ctx, shardRef)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result, err := kstatus.Compute(deployObj)
+		if err != nil {
+			return nil, err
+		}
+		if worst == nil || deploymentStatusRank(result.Status) > deploymentStatusRank(worst.Status) {
+			worst = result
+		}
+	}
+	return worst, nil
+}
+
+// deleteInventoryChunks deletes the ResourceGroup chunks of the inventory, if
+// any.
+func (r *RootSyncReconciler) deleteInventoryChunks(ctx context.Context, inventoryRef types.NamespacedName) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(kinds.ResourceGroup())
+	err := r.client.DeleteAllOf(ctx, u, client.InNamespace(inventoryRef.Namespace),
+		client.MatchingLabels{metadata.InventoryChunkOfLabel: inventoryRef.Name})
+	if err != nil && !meta.IsNoMatchError(err) {
+		return errors.Wrapf(err, "failed to delete the ResourceGroup chunks of %s", inventoryRef)
+	}
+	return nil
+}
+
+// deleteRemovedShards deletes the reconciler Deployments and the inventories
+// of the shards removed from a RootSync, and removes their finalizers from the
+// RootSync. The objects in the inventories are adopted by the remaining shards.
+func (r *RootSyncReconciler) deleteRemovedShards(ctx context.Context, rs *v1beta1.RootSync, reconcilerRef types.NamespacedName, labelMap map[string]string) error {
+	// The reconciler Deployments are managed with the dynamic client.
+	deploymentClient := r.dynamicClient.Resource(kinds.DeploymentResource()).Namespace(reconcilerRef.Namespace)
+	deployList, err := deploymentClient.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labelMap).String(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the reconciler Deployments of RootSync %s", rs.Name)
+	}
+	var removed []int
+	for _, d := range deployList.Items {
+		if index, ok := shard.Index(d.GetName(), reconcilerRef.Name); ok && index >= rs.Spec.GetShards() {
+			removed = append(removed, index)
+		}
+	}
+	for _, index := range removed {
+		deployName := shard.Name(reconcilerRef.Name, index)
+		if err := deploymentClient.Delete(ctx, deployName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the reconciler Deployment %s", deployName)
+		}
+		r.log.Info("Managed object delete successful",
+			logFieldObject, types.NamespacedName{Namespace: reconcilerRef.Namespace, Name: deployName}.String(),
+			logFieldKind, "Deployment")
+		inventoryRef := types.NamespacedName{Namespace: rs.Namespace, Name: shard.Name(rs.Name, index)}
+		if err := r.reconcilerBase.cleanup(ctx, inventoryRef, kinds.ResourceGroup()); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+		if err := r.deleteInventoryChunks(ctx, inventoryRef); err != nil {
+			return err
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	_, err = mutate.WithRetry(ctx, r.client, rs, func() error {
+		updated := false
+		for _, index := range removed {
+			if controllerutil.RemoveFinalizer(rs, shard.Name(metadata.ReconcilerFinalizer, index)) {
+				updated = true
+			}
+		}
+		if !updated {
+			return &mutate.NoUpdateError{}
+		}
+		return nil
+	})
+	return err
+}
+
+// deploymentStatusRank orders the statuses of reconciler Deployments from the
+// most to the least ready.
+func deploymentStatusRank(s kstatus.Status) int {
+	switch s {
+	case kstatus.CurrentStatus:
+		return 0
+	case kstatus.FailedStatus:
+		return 2
+	default:
+		return 1
+	}
+}
+
 // SetupWithManager registers RootSync controller with reconciler-manager.
 func (r *RootSyncReconciler) SetupWithManager(mgr controllerruntime.Manager, watchFleetMembership bool) error {
 	// Index the `gitSecretRefName` field, so that we will be able to lookup RootSync be a referenced `SecretRef` name.
@@ -472,9 +635,15 @@ func (r *RootSyncReconciler) mapSecretToRootSyncs(secret client.Object) []reconc
 }
 
 func (r *RootSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1beta1.RootSync, reconcilerName string) map[string][]corev1.EnvVar {
+	return r.populateShardContainerEnvs(ctx, rs, reconcilerName, 0)
+}
+
+// populateShardContainerEnvs returns the environment variables of the
+// containers of the reconciler Deployment of the shard with the index.
+func (r *RootSyncReconciler) populateShardContainerEnvs(ctx context.Context, rs *v1beta1.RootSync, reconcilerName string, shardIndex int) map[string][]corev1.EnvVar {
 	result := map[string][]corev1.EnvVar{
-		reconcilermanager.HydrationController: hydrationEnvs(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, declared.RootReconciler, reconcilerName, r.hydrationPollingPeriod.String()),
-		reconcilermanager.Reconciler:          append(reconcilerEnvs(r.clusterName, rs.Name, reconcilerName, declared.RootReconciler, rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm), r.reconcilerPollingPeriod.String(), rs.Spec.SafeOverride().StatusMode, v1beta1.GetReconcileTimeout(rs.Spec.SafeOverride().ReconcileTimeout), v1beta1.GetAPIServerTimeout(rs.Spec.SafeOverride().APIServerTimeout)), sourceFormatEnv(rs.Spec.SourceFormat)),
+		reconcilermanager.HydrationController: append(hydrationEnvs(rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rs.Spec.Local, rs.Spec.Bucket, declared.RootReconciler, reconcilerName, r.hydrationPollingPeriod.String()), tracingEnvs(r.tracingEndpoint)...),
+		reconcilermanager.Reconciler:          append(append(append(reconcilerEnvs(r.clusterName, rs.Name, reconcilerName, declared.RootReconciler, rs.Spec.SourceType, rs.Spec.Git, rs.Spec.Oci, rootsync.GetHelmBase(rs.Spec.Helm), rs.Spec.Local, rs.Spec.Bucket, r.reconcilerPollingPeriod.String(), rs.Spec.SafeOverride().StatusMode, v1beta1.GetReconcileTimeout(rs.Spec.SafeOverride().ReconcileTimeout), v1beta1.GetAPIServerTimeout(rs.Spec.SafeOverride().APIServerTimeout)), sourceFormatEnv(rs.Spec.SourceFormat)), shardEnvs(shardIndex, rs.Spec.GetShards(), rs.Spec.GetShardingKey())...), append(adoptionEnvs(rs.Spec.AdoptionPolicy), tracingEnvs(r.tracingEndpoint)...)...),
 	}
 	switch v1beta1.SourceType(rs.Spec.SourceType) {
 	case v1beta1.GitSource:
@@ -493,6 +662,10 @@ func (r *RootSyncReconciler) populateContainerEnvs(ctx context.Context, rs *v1be
 		result[reconcilermanager.OciSync] = ociSyncEnvs(rs.Spec.Oci.Image, rs.Spec.Oci.Auth, v1beta1.GetPeriodSecs(rs.Spec.Oci.Period))
 	case v1beta1.HelmSource:
 		result[reconcilermanager.HelmSync] = helmSyncEnvs(&rs.Spec.Helm.HelmBase, rs.Spec.Helm.Namespace)
+	case v1beta1.LocalSource:
+		result[reconcilermanager.LocalSync] = localSyncEnvs(rs.Spec.Local, rs.Namespace)
+	case v1beta1.BucketSource:
+		result[reconcilermanager.BucketSync] = bucketSyncEnvs(rs.Spec.Bucket)
 	}
 	return result
 }
@@ -505,6 +678,10 @@ func (r *RootSyncReconciler) validateSpec(ctx context.Context, rs *v1beta1.RootS
 		return validate.OciSpec(rs.Spec.Oci, rs)
 	case v1beta1.HelmSource:
 		return validate.HelmSpec(rootsync.GetHelmBase(rs.Spec.Helm), rs)
+	case v1beta1.LocalSource:
+		return validate.LocalSpec(rs.Spec.Local, rs)
+	case v1beta1.BucketSource:
+		return r.validateBucketSpec(ctx, rs)
 	default:
 		return validate.InvalidSourceType(rs)
 	}
@@ -517,6 +694,24 @@ func (r *RootSyncReconciler) validateGitSpec(ctx context.Context, rs *v1beta1.Ro
 	return r.validateRootSecret(ctx, rs)
 }
 
+func (r *RootSyncReconciler) validateBucketSpec(ctx context.Context, rs *v1beta1.RootSync) error {
+	if err := validate.BucketSpec(rs.Spec.Bucket, rs); err != nil {
+		return err
+	}
+	if SkipForAuth(rs.Spec.Bucket.Auth) {
+		// There is no Secret to check for the bucket.
+		return nil
+	}
+	secret, err := validateSecretExist(ctx,
+		v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef),
+		rs.Namespace,
+		r.client)
+	if err != nil {
+		return err
+	}
+	return validateSecretData(rs.Spec.Bucket.Auth, secret)
+}
+
 func (r *RootSyncReconciler) validateNamespaceName(namespaceName string) error {
 	if namespaceName != configsync.ControllerNamespace {
 		return fmt.Errorf("RootSync objects are only allowed in the %s namespace, not in %s", configsync.ControllerNamespace, namespaceName)
@@ -591,7 +786,10 @@ func (r *RootSyncReconciler) updateStatus(ctx context.Context, currentRS, rs *v1
 	return true, nil
 }
 
-func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootSync, containerEnvs map[string][]corev1.EnvVar) mutateFn {
+// mutationsFor returns the mutations of the reconciler Deployment with the
+// name. The shards of a RootSync have their own Deployments, but share the
+// ServiceAccount of the first one.
+func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootSync, deploymentName string, containerEnvs map[string][]corev1.EnvVar) mutateFn {
 	return func(obj client.Object) error {
 		d, ok := obj.(*appsv1.Deployment)
 		if !ok {
@@ -626,6 +824,9 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 			auth = rs.Spec.Helm.Auth
 			gcpSAEmail = rs.Spec.Helm.GCPServiceAccountEmail
 			secretRefName = v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)
+		case v1beta1.BucketSource:
+			auth = rs.Spec.Bucket.Auth
+			secretRefName = v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef)
 		}
 		injectFWICreds := useFWIAuth(auth, r.membership)
 		if injectFWICreds {
@@ -634,8 +835,12 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 			}
 		}
 
+		// Apply the scheduling overrides before the reconciler label, which
+		// takes precedence.
+		mutatePodScheduling(&d.Spec.Template, rs.Spec.Override)
+
 		// Add unique reconciler label
-		core.SetLabel(&d.Spec.Template, metadata.ReconcilerLabel, reconcilerName)
+		core.SetLabel(&d.Spec.Template, metadata.ReconcilerLabel, deploymentName)
 
 		templateSpec := &d.Spec.Template.Spec
 
@@ -650,6 +855,11 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 		// authenticate with the git or helm repository using the authorization method specified
 		// in the RootSync CR.
 		templateSpec.Volumes = filterVolumes(templateSpec.Volumes, auth, secretRefName, caCertSecretRefName, rs.Spec.SourceType, r.membership)
+		if v1beta1.SourceType(rs.Spec.SourceType) == v1beta1.LocalSource {
+			if volume, ok := localSourceVolume(rs.Spec.Local); ok {
+				templateSpec.Volumes = append(templateSpec.Volumes, volume)
+			}
+		}
 
 		var updatedContainers []corev1.Container
 
@@ -676,6 +886,28 @@ func (r *RootSyncReconciler) mutationsFor(ctx context.Context, rs *v1beta1.RootS
 					injectFWICredsToContainer(&container, injectFWICreds)
 					mutateContainerResource(ctx, &container, rs.Spec.Override, string(RootReconcilerType))
 				}
+			case reconcilermanager.LocalSync:
+				// Don't add the local-sync container when sourceType is NOT local.
+				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.LocalSource {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					if _, ok := localSourceVolume(rs.Spec.Local); ok {
+						container.VolumeMounts = append(container.VolumeMounts, localSourceVolumeMount())
+					}
+					mutateContainerResource(ctx, &container, rs.Spec.Override, string(RootReconcilerType))
+				}
+			case reconcilermanager.BucketSync:
+				// Don't add the bucket-sync container when sourceType is NOT bucket.
+				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.BucketSource {
+					addContainer = false
+				} else {
+					container.Env = append(container.Env, containerEnvs[container.Name]...)
+					if rs.Spec.Bucket.Auth == configsync.AuthAccessKey {
+						container.Env = append(container.Env, bucketSyncAccessKeyEnv(secretRefName)...)
+					}
+					mutateContainerResource(ctx, &container, rs.Spec.Override, string(RootReconcilerType))
+				}
 			case reconcilermanager.HelmSync:
 				// Don't add the helm-sync container when sourceType is NOT helm.
 				if v1beta1.SourceType(rs.Spec.SourceType) != v1beta1.HelmSource {
@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -43,6 +46,7 @@ import (
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/reconcilermanager"
 	"kpt.dev/configsync/pkg/rootsync"
+	"kpt.dev/configsync/pkg/shard"
 	syncerFake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/validate/raw/validate"
@@ -118,6 +122,7 @@ func setupRootReconciler(t *testing.T, objs ...client.Object) (*syncerFake.Clien
 		testCluster,
 		filesystemPollingPeriod,
 		hydrationPollingPeriod,
+		"",
 		fakeClient,
 		fakeDynamicClient,
 		controllerruntime.Log.WithName("controllers").WithName("RootSync"),
@@ -237,6 +242,20 @@ func rootSyncWithOCI(name string, opts ...func(*v1beta1.RootSync)) *v1beta1.Root
 	return rs
 }
 
+func rootSyncWithLocal(name string, local *v1beta1.Local) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1(name)
+	rs.Spec.SourceType = string(v1beta1.LocalSource)
+	rs.Spec.Local = local
+	return rs
+}
+
+func rootSyncWithBucket(name string, bucket *v1beta1.Bucket) *v1beta1.RootSync {
+	rs := fake.RootSyncObjectV1Beta1(name)
+	rs.Spec.SourceType = string(v1beta1.BucketSource)
+	rs.Spec.Bucket = bucket
+	return rs
+}
+
 func rootSyncWithHelm(name string, opts ...func(*v1beta1.RootSync)) *v1beta1.RootSync {
 	rs := fake.RootSyncObjectV1Beta1(name)
 	rs.Spec.SourceType = string(v1beta1.HelmSource)
@@ -2339,6 +2358,189 @@ func TestRootSyncWithHelm(t *testing.T) {
 	t.Log("Deployment successfully updated")
 }
 
+func TestRootSyncWithLocal(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := rootSyncWithLocal(rootsyncName, &v1beta1.Local{
+		PersistentVolumeClaimRef: &v1beta1.PersistentVolumeClaimReference{Name: "source"},
+	})
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, fakeDynamicClient, testReconciler := setupRootReconciler(t, rs)
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	rootContainerEnvs := testReconciler.populateContainerEnvs(ctx, rs, rootReconcilerName)
+	rootDeployment := rootSyncDeployment(rootReconcilerName,
+		setServiceAccountName(rootReconcilerName),
+		containersWithRepoVolumeMutator(localContainers(corev1.VolumeMount{Name: LocalSourceVolume, MountPath: LocalSourcePath, ReadOnly: true})),
+		func(dep *appsv1.Deployment) {
+			dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
+				Name: LocalSourceVolume,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "source", ReadOnly: true},
+				},
+			})
+		},
+		containerEnvMutator(rootContainerEnvs),
+		setUID("1"), setResourceVersion("1"), setGeneration(1),
+	)
+	wantDeployments := map[core.ID]*appsv1.Deployment{core.IDOf(rootDeployment): rootDeployment}
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Deployment successfully created")
+
+	t.Log("Test updating RootSync resources with a ConfigMap source.")
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the root sync: %v", err)
+	}
+	rs.Spec.Local = &v1beta1.Local{
+		ConfigMapRef: &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+	}
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the root sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+
+	rootContainerEnvs = testReconciler.populateContainerEnvs(ctx, rs, rootReconcilerName)
+	rootDeployment = rootSyncDeployment(rootReconcilerName,
+		setServiceAccountName(rootReconcilerName),
+		containersWithRepoVolumeMutator(localContainers()),
+		containerEnvMutator(rootContainerEnvs),
+		setUID("1"), setResourceVersion("2"), setGeneration(2),
+	)
+	wantDeployments[core.IDOf(rootDeployment)] = rootDeployment
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+
+	wantEnvs := []corev1.EnvVar{
+		{Name: reconcilermanager.LocalSyncConfigMap, Value: "source"},
+		{Name: reconcilermanager.LocalSyncKey, Value: "source.tar.gz"},
+		{Name: reconcilermanager.LocalSyncNamespace, Value: configsync.ControllerNamespace},
+		{Name: reconcilermanager.LocalSyncWait, Value: "15.000000"},
+	}
+	if diff := cmp.Diff(wantEnvs, rootContainerEnvs[reconcilermanager.LocalSync]); diff != "" {
+		t.Errorf("local-sync envs diff (- want, + got):\n%s", diff)
+	}
+	for _, env := range rootContainerEnvs[reconcilermanager.Reconciler] {
+		if env.Name == reconcilermanager.SourceRepoKey && env.Value != "configmap://config-management-system/source/source.tar.gz" {
+			t.Errorf("got %s %q, want %q", env.Name, env.Value, "configmap://config-management-system/source/source.tar.gz")
+		}
+	}
+}
+
+func TestRootSyncWithBucket(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := rootSyncWithBucket(rootsyncName, &v1beta1.Bucket{
+		Endpoint: "http://minio.minio:9000",
+		Bucket:   "configs",
+		Prefix:   "prod/",
+		Auth:     configsync.AuthNone,
+	})
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	secretName := "bucket-creds"
+	bucketSecret := secretObj(t, secretName, configsync.AuthAccessKey, v1beta1.BucketSource, core.Namespace(rs.Namespace))
+	fakeClient, fakeDynamicClient, testReconciler := setupRootReconciler(t, rs, bucketSecret)
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	rootContainerEnvs := testReconciler.populateContainerEnvs(ctx, rs, rootReconcilerName)
+	rootDeployment := rootSyncDeployment(rootReconcilerName,
+		setServiceAccountName(rootReconcilerName),
+		containersWithRepoVolumeMutator(bucketContainers()),
+		containerEnvMutator(rootContainerEnvs),
+		setUID("1"), setResourceVersion("1"), setGeneration(1),
+	)
+	wantDeployments := map[core.ID]*appsv1.Deployment{core.IDOf(rootDeployment): rootDeployment}
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+	wantEnvs := []corev1.EnvVar{
+		{Name: reconcilermanager.BucketSyncEndpoint, Value: "http://minio.minio:9000"},
+		{Name: reconcilermanager.BucketSyncBucket, Value: "configs"},
+		{Name: reconcilermanager.BucketSyncPrefix, Value: "prod/"},
+		{Name: reconcilermanager.BucketSyncRegion, Value: ""},
+		{Name: reconcilermanager.BucketSyncWait, Value: "15.000000"},
+	}
+	if diff := cmp.Diff(wantEnvs, rootContainerEnvs[reconcilermanager.BucketSync]); diff != "" {
+		t.Errorf("bucket-sync envs diff (- want, + got):\n%s", diff)
+	}
+	for _, env := range rootContainerEnvs[reconcilermanager.Reconciler] {
+		if env.Name == reconcilermanager.SourceRepoKey && env.Value != "http://minio.minio:9000/configs/prod/" {
+			t.Errorf("got %s %q, want %q", env.Name, env.Value, "http://minio.minio:9000/configs/prod/")
+		}
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Deployment successfully created")
+
+	t.Log("Test updating RootSync resources with accesskey auth type.")
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the root sync: %v", err)
+	}
+	rs.Spec.Bucket.Auth = configsync.AuthAccessKey
+	rs.Spec.Bucket.SecretRef = &v1beta1.SecretReference{Name: secretName}
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the root sync request, got error: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error upon request update, got error: %q, want error: nil", err)
+	}
+
+	rootContainerEnvs = testReconciler.populateContainerEnvs(ctx, rs, rootReconcilerName)
+	rootDeployment = rootSyncDeployment(rootReconcilerName,
+		setServiceAccountName(rootReconcilerName),
+		containersWithRepoVolumeMutator(bucketContainers()),
+		containerEnvMutator(rootContainerEnvs),
+		envVarMutator(reconcilermanager.BucketSyncAccessKeyID, secretName, BucketSecretKeyAccessKeyID),
+		envVarMutator(reconcilermanager.BucketSyncSecretAccessKey, secretName, BucketSecretKeySecretAccessKey),
+		optionalEnvVarMutator(reconcilermanager.BucketSyncSessionToken, secretName, BucketSecretKeySessionToken),
+		setUID("1"), setResourceVersion("2"), setGeneration(2),
+	)
+	wantDeployments[core.IDOf(rootDeployment)] = rootDeployment
+	if err := validateDeployments(wantDeployments, fakeDynamicClient); err != nil {
+		t.Errorf("Deployment validation failed. err: %v", err)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+	t.Log("Deployment successfully updated")
+
+	t.Log("Test the RootSync stalls if the Secret misses the access key.")
+	if err := fakeClient.Delete(ctx, bucketSecret); err != nil {
+		t.Fatalf("failed to delete the Secret: %v", err)
+	}
+	if err := fakeClient.Create(ctx, fake.SecretObject(secretName, core.Namespace(rs.Namespace))); err != nil {
+		t.Fatalf("failed to create the Secret: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(rs), rs); err != nil {
+		t.Fatalf("failed to get the root sync: %v", err)
+	}
+	stalled := rootsync.GetCondition(rs.Status.Conditions, v1beta1.RootSyncStalled)
+	if stalled == nil || stalled.Status != metav1.ConditionTrue || !strings.Contains(stalled.Message, "access_key_id") {
+		t.Errorf("got Stalled condition %v, want a condition reporting the missing access_key_id", stalled)
+	}
+}
+
 func TestRootSyncWithOCI(t *testing.T) {
 	// Mock out parseDeployment for testing.
 	parseDeployment = parsedDeployment
@@ -2992,7 +3194,7 @@ func caCertSecretMutator(secretName, caCertSecretName string) depMutator {
 func envVarMutator(envName, secretName, key string) depMutator {
 	return func(dep *appsv1.Deployment) {
 		for i, con := range dep.Spec.Template.Spec.Containers {
-			if con.Name == reconcilermanager.GitSync || con.Name == reconcilermanager.HelmSync {
+			if con.Name == reconcilermanager.GitSync || con.Name == reconcilermanager.HelmSync || con.Name == reconcilermanager.BucketSync {
 				dep.Spec.Template.Spec.Containers[i].Env = append(dep.Spec.Template.Spec.Containers[i].Env, corev1.EnvVar{
 					Name: envName,
 					ValueFrom: &corev1.EnvVarSource{
@@ -3009,6 +3211,18 @@ func envVarMutator(envName, secretName, key string) depMutator {
 	}
 }
 
+func optionalEnvVarMutator(envName, secretName, key string) depMutator {
+	return func(dep *appsv1.Deployment) {
+		envVarMutator(envName, secretName, key)(dep)
+		for i, con := range dep.Spec.Template.Spec.Containers {
+			if con.Name == reconcilermanager.GitSync || con.Name == reconcilermanager.HelmSync || con.Name == reconcilermanager.BucketSync {
+				env := dep.Spec.Template.Spec.Containers[i].Env
+				env[len(env)-1].ValueFrom.SecretKeyRef.Optional = pointer.Bool(true)
+			}
+		}
+	}
+}
+
 func containerEnvMutator(containerEnvs map[string][]corev1.EnvVar) depMutator {
 	return func(dep *appsv1.Deployment) {
 		for i, con := range dep.Spec.Template.Spec.Containers {
@@ -3224,6 +3438,20 @@ func defaultContainers() []corev1.Container {
 				{Name: "helm-creds", MountPath: "/etc/helm-secret", ReadOnly: true},
 			},
 		},
+		{
+			Name:      reconcilermanager.LocalSync,
+			Resources: defaultResourceRequirements(),
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "repo", MountPath: "/repo"},
+			},
+		},
+		{
+			Name:      reconcilermanager.BucketSync,
+			Resources: defaultResourceRequirements(),
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "repo", MountPath: "/repo"},
+			},
+		},
 	}
 }
 
@@ -3314,6 +3542,42 @@ func noneOciContainers() []corev1.Container {
 	}
 }
 
+func localContainers(mounts ...corev1.VolumeMount) []corev1.Container {
+	return []corev1.Container{
+		{
+			Name:      reconcilermanager.Reconciler,
+			Resources: defaultResourceRequirements(),
+		},
+		{
+			Name:      reconcilermanager.HydrationController,
+			Resources: defaultResourceRequirements(),
+		},
+		{
+			Name:         reconcilermanager.LocalSync,
+			Resources:    defaultResourceRequirements(),
+			VolumeMounts: append([]corev1.VolumeMount{{Name: "repo", MountPath: "/repo"}}, mounts...),
+		},
+	}
+}
+
+func bucketContainers() []corev1.Container {
+	return []corev1.Container{
+		{
+			Name:      reconcilermanager.Reconciler,
+			Resources: defaultResourceRequirements(),
+		},
+		{
+			Name:      reconcilermanager.HydrationController,
+			Resources: defaultResourceRequirements(),
+		},
+		{
+			Name:         reconcilermanager.BucketSync,
+			Resources:    defaultResourceRequirements(),
+			VolumeMounts: []corev1.VolumeMount{{Name: "repo", MountPath: "/repo"}},
+		},
+	}
+}
+
 func noneHelmContainers() []corev1.Container {
 	return []corev1.Container{
 		{
@@ -3380,3 +3644,141 @@ func helmDeploymentSecretVolumes(secretName string) []corev1.Volume {
 	}
 	return volumes
 }
+
+func TestRootSyncCreateWithOverrideScheduling(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	override := v1beta1.OverrideSpec{
+		NodeSelector: map[string]string{"pool": "system"},
+		Tolerations: []corev1.Toleration{{
+			Key: "pool", Operator: corev1.TolerationOpEqual, Value: "system", Effect: corev1.TaintEffectNoSchedule,
+		}},
+		PriorityClassName: "system-cluster-critical",
+		PodAnnotations:    map[string]string{"acme.com/team": "platform"},
+		PodLabels:         map[string]string{"acme.com/team": "platform", metadata.ReconcilerLabel: "other"},
+	}
+	rs := rootSync(rootsyncName, rootsyncRef(gitRevision), rootsyncBranch(branch), rootsyncSecretType(GitSecretConfigKeySSH), rootsyncSecretRef(rootsyncSSHKey))
+	rs.Spec.Override = &override
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	_, fakeDynamicClient, testReconciler := setupRootReconciler(t, rs, secretObj(t, rootsyncSSHKey, configsync.AuthSSH, v1beta1.GitSource, core.Namespace(rs.Namespace)))
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	uObj, err := fakeDynamicClient.Resource(kinds.DeploymentResource()).
+		Namespace(v1.NSConfigManagementSystem).
+		Get(ctx, rootReconcilerName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the reconciler Deployment: %v", err)
+	}
+	obj, err := kinds.ToTypedObject(uObj, core.Scheme)
+	if err != nil {
+		t.Fatalf("failed to convert the reconciler Deployment: %v", err)
+	}
+	template := obj.(*appsv1.Deployment).Spec.Template
+
+	if diff := cmp.Diff(override.NodeSelector, template.Spec.NodeSelector); diff != "" {
+		t.Errorf("unexpected nodeSelector (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(override.Tolerations, template.Spec.Tolerations); diff != "" {
+		t.Errorf("unexpected tolerations (-want +got):\n%s", diff)
+	}
+	if template.Spec.PriorityClassName != override.PriorityClassName {
+		t.Errorf("got priorityClassName %q, want %q", template.Spec.PriorityClassName, override.PriorityClassName)
+	}
+	if got := template.Annotations["acme.com/team"]; got != "platform" {
+		t.Errorf("got pod annotation %q, want %q", got, "platform")
+	}
+	if got := template.Labels["acme.com/team"]; got != "platform" {
+		t.Errorf("got pod label %q, want %q", got, "platform")
+	}
+	// The labels set by Config Sync take precedence.
+	if got := template.Labels[metadata.ReconcilerLabel]; got != rootReconcilerName {
+		t.Errorf("got reconciler label %q, want %q", got, rootReconcilerName)
+	}
+}
+
+func TestRootSyncReconcileShards(t *testing.T) {
+	// Mock out parseDeployment for testing.
+	parseDeployment = parsedDeployment
+
+	rs := rootSync(rootsyncName, rootsyncRef(gitRevision), rootsyncBranch(branch), rootsyncSecretType(GitSecretConfigKeySSH), rootsyncSecretRef(rootsyncSSHKey))
+	rs.Spec.Sharding = &v1beta1.Sharding{Shards: 3, Key: v1beta1.ShardByGroupKind}
+	reqNamespacedName := namespacedName(rs.Name, rs.Namespace)
+	fakeClient, fakeDynamicClient, testReconciler := setupRootReconciler(t, rs, secretObj(t, rootsyncSSHKey, configsync.AuthSSH, v1beta1.GitSource, core.Namespace(rs.Namespace)))
+
+	ctx := context.Background()
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	deploymentClient := fakeDynamicClient.Resource(kinds.DeploymentResource()).Namespace(v1.NSConfigManagementSystem)
+	for i := 0; i < 3; i++ {
+		name := shard.Name(rootReconcilerName, i)
+		uObj, err := deploymentClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get the reconciler Deployment of shard %d: %v", i, err)
+		}
+		obj, err := kinds.ToTypedObject(uObj, core.Scheme)
+		if err != nil {
+			t.Fatalf("failed to convert the reconciler Deployment: %v", err)
+		}
+		template := obj.(*appsv1.Deployment).Spec.Template
+		if got := template.Labels[metadata.ReconcilerLabel]; got != name {
+			t.Errorf("got label %s=%q on the reconciler Pods of shard %d, want %q", metadata.ReconcilerLabel, got, i, name)
+		}
+		if got := template.Spec.ServiceAccountName; got != rootReconcilerName {
+			t.Errorf("got ServiceAccount %q for shard %d, want %q", got, i, rootReconcilerName)
+		}
+		want := map[string]string{
+			reconcilermanager.ReconcilerNameKey: name,
+			reconcilermanager.ShardIndexKey:     strconv.Itoa(i),
+			reconcilermanager.ShardCountKey:     "3",
+			reconcilermanager.ShardKeyKey:       string(v1beta1.ShardByGroupKind),
+		}
+		for _, container := range template.Spec.Containers {
+			if container.Name != reconcilermanager.Reconciler {
+				continue
+			}
+			got := make(map[string]string)
+			for _, env := range container.Env {
+				if _, found := want[env.Name]; found {
+					got[env.Name] = env.Value
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("unexpected environment variables of shard %d (-want +got):\n%s", i, diff)
+			}
+		}
+	}
+
+	// Remove a shard.
+	if err := fakeClient.Get(ctx, reqNamespacedName.NamespacedName, rs); err != nil {
+		t.Fatalf("failed to get the RootSync: %v", err)
+	}
+	rs.Spec.Sharding.Shards = 2
+	rs.Finalizers = []string{metadata.ReconcilerFinalizer, shard.Name(metadata.ReconcilerFinalizer, 1), shard.Name(metadata.ReconcilerFinalizer, 2)}
+	if err := fakeClient.Update(ctx, rs); err != nil {
+		t.Fatalf("failed to update the RootSync: %v", err)
+	}
+	if _, err := testReconciler.Reconcile(ctx, reqNamespacedName); err != nil {
+		t.Fatalf("unexpected reconciliation error, got error: %q, want error: nil", err)
+	}
+
+	if _, err := deploymentClient.Get(ctx, shard.Name(rootReconcilerName, 1), metav1.GetOptions{}); err != nil {
+		t.Errorf("failed to get the reconciler Deployment of shard 1: %v", err)
+	}
+	if _, err := deploymentClient.Get(ctx, shard.Name(rootReconcilerName, 2), metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("got error %v for the reconciler Deployment of the removed shard, want NotFound", err)
+	}
+	if err := fakeClient.Get(ctx, reqNamespacedName.NamespacedName, rs); err != nil {
+		t.Fatalf("failed to get the RootSync: %v", err)
+	}
+	wantFinalizers := []string{metadata.ReconcilerFinalizer, shard.Name(metadata.ReconcilerFinalizer, 1)}
+	if diff := cmp.Diff(wantFinalizers, rs.Finalizers); diff != "" {
+		t.Errorf("unexpected finalizers (-want +got):\n%s", diff)
+	}
+}
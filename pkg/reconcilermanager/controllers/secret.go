@@ -45,6 +45,9 @@ func isUpsertedSecret(rs *v1beta1.RepoSync, secretName string) bool {
 	if shouldUpsertHelmSecret(rs) && secretName == ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Helm.SecretRef)) {
 		return true
 	}
+	if shouldUpsertBucketSecret(rs) && secretName == ReconcilerResourceName(reconcilerName, v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef)) {
+		return true
+	}
 	return false
 }
 
@@ -60,6 +63,10 @@ func shouldUpsertHelmSecret(rs *v1beta1.RepoSync) bool {
 	return v1beta1.SourceType(rs.Spec.SourceType) == v1beta1.HelmSource && rs.Spec.Helm != nil && rs.Spec.Helm.SecretRef != nil && !SkipForAuth(rs.Spec.Helm.Auth)
 }
 
+func shouldUpsertBucketSecret(rs *v1beta1.RepoSync) bool {
+	return v1beta1.SourceType(rs.Spec.SourceType) == v1beta1.BucketSource && rs.Spec.Bucket != nil && rs.Spec.Bucket.SecretRef != nil && !SkipForAuth(rs.Spec.Bucket.Auth)
+}
+
 // upsertAuthSecret creates or updates the auth secret in the
 // config-management-system namespace using an existing secret in the RepoSync
 // namespace.
@@ -100,6 +107,23 @@ func upsertAuthSecret(ctx context.Context, log logr.Logger, rs *v1beta1.RepoSync
 				logFieldOperation, op)
 		}
 		return cmsSecretRef, nil
+	case shouldUpsertBucketSecret(rs):
+		nsSecretRef, cmsSecretRef := getSecretRefs(rsRef, reconcilerRef, v1beta1.GetSecretName(rs.Spec.Bucket.SecretRef))
+		userSecret, err := getUserSecret(ctx, c, nsSecretRef)
+		if err != nil {
+			return cmsSecretRef, errors.Wrap(err, "user secret required for bucket client authentication")
+		}
+		op, err := upsertSecret(ctx, c, cmsSecretRef, rsRef, userSecret)
+		if err != nil {
+			return cmsSecretRef, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("Managed object upsert successful",
+				logFieldObject, cmsSecretRef.String(),
+				logFieldKind, "Secret",
+				logFieldOperation, op)
+		}
+		return cmsSecretRef, nil
 	default:
 		// No secret required
 		return client.ObjectKey{}, nil
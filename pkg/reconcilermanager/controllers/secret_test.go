@@ -92,6 +92,12 @@ func secretData(t *testing.T, data string, auth configsync.AuthType, sourceType
 			"password": key,
 		}
 	}
+	if auth == configsync.AuthAccessKey {
+		return map[string][]byte{
+			BucketSecretKeyAccessKeyID:     key,
+			BucketSecretKeySecretAccessKey: key,
+		}
+	}
 	return map[string][]byte{
 		string(auth): key,
 	}
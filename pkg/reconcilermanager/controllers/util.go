@@ -17,6 +17,7 @@ package controllers
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,7 +36,7 @@ import (
 )
 
 // hydrationEnvs returns environment variables for the hydration controller.
-func hydrationEnvs(sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1.Oci, scope declared.Scope, reconcilerName, pollPeriod string) []corev1.EnvVar {
+func hydrationEnvs(sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1.Oci, localConfig *v1beta1.Local, bucketConfig *v1beta1.Bucket, scope declared.Scope, reconcilerName, pollPeriod string) []corev1.EnvVar {
 	var result []corev1.EnvVar
 	var syncDir string
 	switch v1beta1.SourceType(sourceType) {
@@ -45,6 +46,10 @@ func hydrationEnvs(sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1
 		syncDir = gitConfig.Dir
 	case v1beta1.HelmSource:
 		syncDir = "."
+	case v1beta1.LocalSource:
+		syncDir = localConfig.Dir
+	case v1beta1.BucketSource:
+		syncDir = bucketConfig.Dir
 	}
 
 	result = append(result,
@@ -77,7 +82,7 @@ func hydrationEnvs(sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1
 }
 
 // reconcilerEnvs returns environment variables for namespace reconciler.
-func reconcilerEnvs(clusterName, syncName, reconcilerName string, reconcilerScope declared.Scope, sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1.Oci, helmConfig *v1beta1.HelmBase, pollPeriod, statusMode string, reconcileTimeout string, apiServerTimeout string) []corev1.EnvVar {
+func reconcilerEnvs(clusterName, syncName, reconcilerName string, reconcilerScope declared.Scope, sourceType string, gitConfig *v1beta1.Git, ociConfig *v1beta1.Oci, helmConfig *v1beta1.HelmBase, localConfig *v1beta1.Local, bucketConfig *v1beta1.Bucket, pollPeriod, statusMode string, reconcileTimeout string, apiServerTimeout string) []corev1.EnvVar {
 	var result []corev1.EnvVar
 	if statusMode == "" {
 		statusMode = applier.StatusEnabled
@@ -111,6 +116,17 @@ func reconcilerEnvs(clusterName, syncName, reconcilerName string, reconcilerScop
 		} else {
 			syncRevision = "HEAD"
 		}
+	case v1beta1.LocalSource:
+		// The local source is in the namespace of the RootSync or RepoSync.
+		namespace := string(reconcilerScope)
+		if reconcilerScope == declared.RootReconciler {
+			namespace = configsync.ControllerNamespace
+		}
+		syncRepo = v1beta1.GetLocalSource(localConfig, namespace)
+		syncDir = localConfig.Dir
+	case v1beta1.BucketSource:
+		syncRepo = v1beta1.GetBucketSource(bucketConfig)
+		syncDir = bucketConfig.Dir
 	}
 
 	result = append(result,
@@ -180,6 +196,43 @@ func reconcilerEnvs(clusterName, syncName, reconcilerName string, reconcilerScop
 	return result
 }
 
+// tracingEnvs returns the environment variables which enable tracing in the
+// reconciler and hydration-controller containers, if a tracing endpoint is
+// configured.
+func tracingEnvs(endpoint string) []corev1.EnvVar {
+	if endpoint == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.TracingEndpointKey,
+		Value: endpoint,
+	}}
+}
+
+// impersonationEnvs returns the environment variables for the reconciler
+// container of a RepoSync impersonating a ServiceAccount.
+func impersonationEnvs(serviceAccountName string) []corev1.EnvVar {
+	if serviceAccountName == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.ImpersonateServiceAccountKey,
+		Value: serviceAccountName,
+	}}
+}
+
+// adoptionEnvs returns the environment variables for the reconciler
+// container of a RootSync or RepoSync with an explicit adoption policy.
+func adoptionEnvs(policy v1beta1.AdoptionPolicy) []corev1.EnvVar {
+	if policy == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.AdoptionPolicyKey,
+		Value: string(policy),
+	}}
+}
+
 // sourceFormatEnv returns the environment variable for SOURCE_FORMAT in the reconciler container.
 func sourceFormatEnv(format string) corev1.EnvVar {
 	return corev1.EnvVar{
@@ -188,6 +241,24 @@ func sourceFormatEnv(format string) corev1.EnvVar {
 	}
 }
 
+// shardEnvs returns the environment variables for the reconciler container of
+// the shard of a sharded RootSync.
+func shardEnvs(index, count int, key v1beta1.ShardingKey) []corev1.EnvVar {
+	if count <= 1 {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.ShardIndexKey,
+		Value: strconv.Itoa(index),
+	}, {
+		Name:  reconcilermanager.ShardCountKey,
+		Value: strconv.Itoa(count),
+	}, {
+		Name:  reconcilermanager.ShardKeyKey,
+		Value: string(key),
+	}}
+}
+
 // ociSyncEnvs returns the environment variables for the oci-sync container.
 func ociSyncEnvs(image string, auth configsync.AuthType, period float64) []corev1.EnvVar {
 	var result []corev1.EnvVar
@@ -204,6 +275,98 @@ func ociSyncEnvs(image string, auth configsync.AuthType, period float64) []corev
 	return result
 }
 
+// localSyncEnvs returns the environment variables for the local-sync
+// container. The ConfigMap and Secret are read from the namespace of the
+// RootSync or RepoSync, and the PersistentVolumeClaim is mounted at
+// LocalSourcePath.
+func localSyncEnvs(local *v1beta1.Local, namespace string) []corev1.EnvVar {
+	var result []corev1.EnvVar
+	switch {
+	case local.ConfigMapRef != nil:
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.LocalSyncConfigMap,
+			Value: local.ConfigMapRef.Name,
+		}, corev1.EnvVar{
+			Name:  reconcilermanager.LocalSyncKey,
+			Value: local.ConfigMapRef.Key,
+		})
+	case local.SecretRef != nil:
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.LocalSyncSecret,
+			Value: local.SecretRef.Name,
+		}, corev1.EnvVar{
+			Name:  reconcilermanager.LocalSyncKey,
+			Value: local.SecretRef.Key,
+		})
+	case local.PersistentVolumeClaimRef != nil:
+		result = append(result, corev1.EnvVar{
+			Name:  reconcilermanager.LocalSyncDir,
+			Value: LocalSourcePath,
+		})
+	}
+	result = append(result, corev1.EnvVar{
+		Name:  reconcilermanager.LocalSyncNamespace,
+		Value: namespace,
+	}, corev1.EnvVar{
+		Name:  reconcilermanager.LocalSyncWait,
+		Value: fmt.Sprintf("%f", v1beta1.GetPeriodSecs(local.Period)),
+	})
+	return result
+}
+
+// bucketSyncEnvs returns the environment variables for the bucket-sync
+// container.
+func bucketSyncEnvs(bucket *v1beta1.Bucket) []corev1.EnvVar {
+	return []corev1.EnvVar{{
+		Name:  reconcilermanager.BucketSyncEndpoint,
+		Value: bucket.Endpoint,
+	}, {
+		Name:  reconcilermanager.BucketSyncBucket,
+		Value: bucket.Bucket,
+	}, {
+		Name:  reconcilermanager.BucketSyncPrefix,
+		Value: bucket.Prefix,
+	}, {
+		Name:  reconcilermanager.BucketSyncRegion,
+		Value: bucket.Region,
+	}, {
+		Name:  reconcilermanager.BucketSyncWait,
+		Value: fmt.Sprintf("%f", v1beta1.GetPeriodSecs(bucket.Period)),
+	}}
+}
+
+// bucketSyncAccessKeyEnv returns environment variables for bucket-sync
+// container for 'accesskey' Auth.
+func bucketSyncAccessKeyEnv(secretRef string) []corev1.EnvVar {
+	secretKeyRef := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretRef,
+				},
+				Key: key,
+			},
+		}
+	}
+	// The session token is only set for temporary security credentials.
+	sessionToken := secretKeyRef(BucketSecretKeySessionToken)
+	sessionToken.SecretKeyRef.Optional = pointer.Bool(true)
+	return []corev1.EnvVar{
+		{
+			Name:      reconcilermanager.BucketSyncAccessKeyID,
+			ValueFrom: secretKeyRef(BucketSecretKeyAccessKeyID),
+		},
+		{
+			Name:      reconcilermanager.BucketSyncSecretAccessKey,
+			ValueFrom: secretKeyRef(BucketSecretKeySecretAccessKey),
+		},
+		{
+			Name:      reconcilermanager.BucketSyncSessionToken,
+			ValueFrom: sessionToken,
+		},
+	}
+}
+
 const (
 	// helm-sync container specific environment variables.
 	helmSyncName     = "HELM_SYNC_USERNAME"
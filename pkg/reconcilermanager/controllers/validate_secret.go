@@ -62,6 +62,13 @@ func validateSecretData(auth configsync.AuthType, secret *corev1.Secret) error {
 		if _, ok := secret.Data[GitSecretConfigKeyTokenUsername]; !ok {
 			return fmt.Errorf("git secretType was set as %q but username key is not present in %v secret", auth, secret.Name)
 		}
+	case configsync.AuthAccessKey:
+		if _, ok := secret.Data[BucketSecretKeyAccessKeyID]; !ok {
+			return fmt.Errorf("bucket auth was set as %q but access_key_id key is not present in %v secret", auth, secret.Name)
+		}
+		if _, ok := secret.Data[BucketSecretKeySecretAccessKey]; !ok {
+			return fmt.Errorf("bucket auth was set as %q but secret_access_key key is not present in %v secret", auth, secret.Name)
+		}
 	case configsync.AuthNone:
 	case configsync.AuthGCENode:
 	default:
@@ -41,6 +41,14 @@ const CACertSecretKey = "cert"
 // CACertPath is the path where the certificate is mounted.
 const CACertPath = "/etc/ca-cert"
 
+// LocalSourceVolume is the volume name of the PersistentVolumeClaim holding a
+// local source.
+const LocalSourceVolume = "local-source"
+
+// LocalSourcePath is the path where the PersistentVolumeClaim holding a local
+// source is mounted.
+const LocalSourcePath = "/local-source"
+
 // defaultMode is the default permission of the `gcp-ksa` volume.
 var defaultMode int32 = 0644
 
@@ -148,3 +156,30 @@ func volumeMounts(auth configsync.AuthType, caCertSecretRef, sourceType string,
 	})
 	return volumeMount
 }
+
+// localSourceVolume returns the volume of the PersistentVolumeClaim holding
+// the local source, if any.
+func localSourceVolume(local *v1beta1.Local) (corev1.Volume, bool) {
+	if local == nil || local.PersistentVolumeClaimRef == nil {
+		return corev1.Volume{}, false
+	}
+	return corev1.Volume{
+		Name: LocalSourceVolume,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: local.PersistentVolumeClaimRef.Name,
+				ReadOnly:  true,
+			},
+		},
+	}, true
+}
+
+// localSourceVolumeMount returns the VolumeMount of the PersistentVolumeClaim
+// holding the local source in the local-sync container.
+func localSourceVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      LocalSourceVolume,
+		MountPath: LocalSourcePath,
+		ReadOnly:  true,
+	}
+}
@@ -16,11 +16,16 @@ package reconcile
 
 import (
 	"context"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff"
+	"kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
@@ -43,6 +48,15 @@ type reconciler struct {
 	applier syncerreconcile.Applier
 	// declared is the threadsafe in-memory representation of declared configuration.
 	declared *declared.Resources
+	// adoptionPolicy determines whether the objects which are not in the
+	// inventory yet are remediated.
+	adoptionPolicy v1beta1.AdoptionPolicy
+	// exemptions lists the drift exemptions, whose selected objects are not
+	// remediated.
+	exemptions *exemption.Lister
+	// requeueAfter adds an object to the queue of the reconciler once the
+	// duration has passed, if not nil.
+	requeueAfter func(obj client.Object, d time.Duration)
 }
 
 // newReconciler instantiates a new reconciler.
@@ -51,12 +65,16 @@ func newReconciler(
 	syncName string,
 	applier syncerreconcile.Applier,
 	declared *declared.Resources,
+	adoptionPolicy v1beta1.AdoptionPolicy,
+	exemptions *exemption.Lister,
 ) *reconciler {
 	return &reconciler{
-		scope:    scope,
-		syncName: syncName,
-		applier:  applier,
-		declared: declared,
+		scope:          scope,
+		syncName:       syncName,
+		applier:        applier,
+		declared:       declared,
+		adoptionPolicy: adoptionPolicy,
+		exemptions:     exemptions,
 	}
 }
 
@@ -76,7 +94,11 @@ func (r *reconciler) Remediate(ctx context.Context, id core.ID, obj client.Objec
 		Declared: decl,
 		Actual:   obj,
 	}
-	switch t := d.Operation(ctx, r.scope, r.syncName); t {
+	t := d.Operation(ctx, r.scope, r.syncName, r.adoptionPolicy)
+	if r.exempted(ctx, t, decl, obj) {
+		return nil
+	}
+	switch t {
 	case diff.NoOp:
 		return nil
 	case diff.Create:
@@ -121,6 +143,40 @@ func (r *reconciler) Remediate(ctx context.Context, id core.ID, obj client.Objec
 	}
 }
 
+// exempted returns true if the operation reverts the drift of an object
+// selected by a drift exemption, recording the use of the exemption. The object
+// is requeued to revert its drift once the exemption expires.
+func (r *reconciler) exempted(ctx context.Context, t diff.Operation, decl, actual client.Object) bool {
+	if t != diff.Create && t != diff.Update && t != diff.Delete {
+		return false
+	}
+	obj := actual
+	if obj == nil {
+		obj = decl
+	}
+	e, found := r.exemptions.ForObject(ctx, obj)
+	if !found {
+		return false
+	}
+	klog.V(3).Infof("Drift exemption %q prevented the remediator from reverting the drift of object %v", e.Name, core.GKNN(obj))
+	r.exemptions.RecordUseOnce(e, obj, events.ReasonDriftNotReverted, "the remediator did not %s %q to revert its drift", t, core.GKNN(obj))
+	if r.requeueAfter != nil {
+		// Requeue the metadata of the object, so that its current state is
+		// looked up when the exemption expires.
+		r.requeueAfter(objectMetadata(obj), time.Until(e.Expires()))
+	}
+	return true
+}
+
+// objectMetadata returns the metadata identifying the object.
+func objectMetadata(obj client.Object) *metav1.PartialObjectMetadata {
+	p := &metav1.PartialObjectMetadata{}
+	p.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	p.SetNamespace(obj.GetNamespace())
+	p.SetName(obj.GetName())
+	return p
+}
+
 // GetClient returns the reconciler's underlying client.Client.
 func (r *reconciler) GetClient() client.Client {
 	return r.applier.GetClient()
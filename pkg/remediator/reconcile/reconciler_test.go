@@ -17,12 +17,16 @@ package reconcile
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/importer/analyzer/validation/nonhierarchical"
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/policycontroller"
@@ -229,7 +233,7 @@ func TestRemediator_Reconcile(t *testing.T) {
 			// Simulate the Parser having already parsed the resource and recorded it.
 			d := makeDeclared(t, tc.declared)
 
-			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d)
+			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, "", nil)
 
 			// Get the triggering object for the reconcile event.
 			var obj client.Object
@@ -266,3 +270,103 @@ func makeDeclared(t *testing.T, objs ...client.Object) *declared.Resources {
 	}
 	return d
 }
+
+func TestRemediator_Exemption(t *testing.T) {
+	declaredObj := fake.ClusterRoleBindingObject(syncertest.ManagementEnabled,
+		core.Label("new-label", "one"))
+	actual := fake.ClusterRoleBindingObject(syncertest.ManagementEnabled,
+		core.UID("1"), core.ResourceVersion("1"), core.Generation(1))
+
+	expires := time.Now().Add(time.Hour)
+	de := &v1beta1.DriftExemption{Spec: v1beta1.DriftExemptionSpec{
+		Users:   []string{"bob@acme.com"},
+		Objects: []v1beta1.DriftExemptionSelector{{Kind: "ClusterRoleBinding"}},
+		Expires: metav1.NewTime(expires),
+	}}
+	de.Name = "break-glass"
+	recorder := record.NewFakeRecorder(10)
+	exemptions := exemption.NewLister(testingfake.NewClient(t, core.Scheme, de), recorder)
+
+	c := testingfake.NewClient(t, core.Scheme, actual)
+	d := makeDeclared(t, declaredObj)
+	r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, "", exemptions)
+	var requeued []client.Object
+	var requeuedAfter []time.Duration
+	r.requeueAfter = func(obj client.Object, d time.Duration) {
+		requeued = append(requeued, obj)
+		requeuedAfter = append(requeuedAfter, d)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := r.Remediate(context.Background(), core.IDOf(declaredObj), actual); err != nil {
+			t.Fatalf("got Remediate() = %v, want nil", err)
+		}
+	}
+	// The drift of the exempted object is not reverted.
+	c.Check(t, actual)
+	// The use of the exemption is recorded once.
+	if len(recorder.Events) != 1 {
+		t.Errorf("got %d Events, want 1", len(recorder.Events))
+	}
+	// The object is requeued to be remediated when the exemption expires.
+	if len(requeued) != 2 {
+		t.Fatalf("got %d requeued objects, want 2", len(requeued))
+	}
+	if core.IDOf(requeued[0]) != core.IDOf(actual) {
+		t.Errorf("got requeued object %v, want %v", core.IDOf(requeued[0]), core.IDOf(actual))
+	}
+	if requeuedAfter[0] <= 0 || requeuedAfter[0] > time.Until(expires) {
+		t.Errorf("got object requeued after %v, want when the exemption expires", requeuedAfter[0])
+	}
+}
+
+func TestRemediator_AdoptionPolicy(t *testing.T) {
+	inventoryID := "config-management-system_root-sync"
+	declaredObj := fake.ClusterRoleBindingObject(syncertest.ManagementEnabled,
+		core.Label("new-label", "one"),
+		core.Annotation(metadata.OwningInventoryKey, inventoryID))
+	unmanaged := fake.ClusterRoleBindingObject()
+	otherInventory := fake.ClusterRoleBindingObject(core.Annotation(metadata.OwningInventoryKey, "other-inventory"))
+	inInventory := fake.ClusterRoleBindingObject(core.Annotation(metadata.OwningInventoryKey, inventoryID))
+
+	testCases := []struct {
+		name        string
+		policy      v1beta1.AdoptionPolicy
+		actual      client.Object
+		wantUpdated bool
+	}{
+		{name: "unspecified, unmanaged", actual: unmanaged, wantUpdated: true},
+		{name: "unspecified, other inventory", actual: otherInventory, wantUpdated: true},
+		{name: "unspecified, in inventory", actual: inInventory, wantUpdated: true},
+		{name: "adopt, unmanaged", policy: v1beta1.AdoptionPolicyAdopt, actual: unmanaged, wantUpdated: true},
+		{name: "adopt, other inventory", policy: v1beta1.AdoptionPolicyAdopt, actual: otherInventory, wantUpdated: true},
+		{name: "adopt, in inventory", policy: v1beta1.AdoptionPolicyAdopt, actual: inInventory, wantUpdated: true},
+		{name: "adopt-if-unmanaged, unmanaged", policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged, actual: unmanaged, wantUpdated: true},
+		{name: "adopt-if-unmanaged, other inventory", policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged, actual: otherInventory},
+		{name: "adopt-if-unmanaged, in inventory", policy: v1beta1.AdoptionPolicyAdoptIfUnmanaged, actual: inInventory, wantUpdated: true},
+		{name: "fail, unmanaged", policy: v1beta1.AdoptionPolicyFail, actual: unmanaged},
+		{name: "fail, other inventory", policy: v1beta1.AdoptionPolicyFail, actual: otherInventory},
+		{name: "fail, in inventory", policy: v1beta1.AdoptionPolicyFail, actual: inInventory, wantUpdated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.actual.DeepCopyObject().(client.Object)
+			c := testingfake.NewClient(t, core.Scheme, actual)
+			d := makeDeclared(t, declaredObj)
+			r := newReconciler(declared.RootReconciler, configsync.RootSyncName, c.Applier(), d, tc.policy, nil)
+
+			if err := r.Remediate(context.Background(), core.IDOf(declaredObj), actual); err != nil {
+				t.Fatalf("got Remediate() = %v, want nil", err)
+			}
+
+			got := fake.ClusterRoleBindingObject()
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(declaredObj), got); err != nil {
+				t.Fatal(err)
+			}
+			if updated := got.GetLabels()["new-label"] == "one"; updated != tc.wantUpdated {
+				t.Errorf("got updated %t, want %t", updated, tc.wantUpdated)
+			}
+		})
+	}
+}
@@ -18,17 +18,22 @@ import (
 	"context"
 	"time"
 
+	"go.opencensus.io/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/status"
 	syncerclient "kpt.dev/configsync/pkg/syncer/client"
 	syncerreconcile "kpt.dev/configsync/pkg/syncer/reconcile"
+	"kpt.dev/configsync/pkg/tracing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -40,10 +45,12 @@ type Worker struct {
 }
 
 // NewWorker returns a new Worker for the given queue and declared resources.
-func NewWorker(scope declared.Scope, syncName string, a syncerreconcile.Applier, q *queue.ObjectQueue, d *declared.Resources) *Worker {
+func NewWorker(scope declared.Scope, syncName string, a syncerreconcile.Applier, q *queue.ObjectQueue, d *declared.Resources, p v1beta1.AdoptionPolicy, e *exemption.Lister) *Worker {
+	r := newReconciler(scope, syncName, a, d, p, e)
+	r.requeueAfter = q.AddAfter
 	return &Worker{
 		objectQueue: q,
-		reconciler:  newReconciler(scope, syncName, a, d),
+		reconciler:  r,
 	}
 }
 
@@ -87,12 +94,26 @@ func (w *Worker) process(ctx context.Context, obj client.Object) bool {
 		// Passing a nil Object to the reconciler signals that the accompanying ID
 		// is for an Object that was deleted.
 		toRemediate = nil
+	} else if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		// Metadata-only watches only enqueue the metadata of the objects whose
+		// generation, deletion or Config Sync metadata changed, so get the
+		// whole object to compare it to its declaration.
+		if err := w.refresh(ctx, obj); err != nil {
+			klog.Errorf("Worker unable to get %q: %v", core.IDOf(obj), err)
+			w.objectQueue.Retry(obj)
+			return false
+		}
+		w.objectQueue.Forget(obj)
+		return true
 	} else {
 		toRemediate = obj
 	}
 
 	now := time.Now()
-	err := w.reconciler.Remediate(ctx, core.IDOf(obj), toRemediate)
+	remediateCtx, span := tracing.StartSpan(ctx, "remediator.Remediate",
+		trace.StringAttribute(tracing.AttrObject, core.IDOf(obj).String()))
+	err := w.reconciler.Remediate(remediateCtx, core.IDOf(obj), toRemediate)
+	tracing.EndSpan(span, err)
 	metrics.RecordRemediateDuration(ctx, metrics.StatusTagKey(err), obj.GetObjectKind().GroupVersionKind(), now)
 	if err != nil {
 		// To debug the set of events we've missed, you may need to comment out this
@@ -119,7 +140,8 @@ func (w *Worker) process(ctx context.Context, obj client.Object) bool {
 	return true
 }
 
-// refresh updates the cached version of the object.
+// refresh enqueues the current version of the object, or marks it deleted if
+// it no longer exists.
 func (w *Worker) refresh(ctx context.Context, o client.Object) status.Error {
 	c := w.reconciler.GetClient()
 
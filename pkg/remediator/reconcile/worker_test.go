@@ -25,6 +25,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -122,7 +123,7 @@ func TestWorker_ProcessNextObject(t *testing.T) {
 			}
 
 			d := makeDeclared(t, tc.declared...)
-			w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d)
+			w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, "", nil)
 
 			for _, obj := range tc.toProcess {
 				if ok := w.processNextObject(context.Background()); !ok {
@@ -141,7 +142,7 @@ func TestWorker_Run_CancelledWhenEmpty(t *testing.T) {
 	q := queue.New("test") // empty queue
 	c := testingfake.NewClient(t, core.Scheme)
 	d := makeDeclared(t) // no resources declared
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d)
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, "", nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -213,7 +214,7 @@ func TestWorker_Run_CancelledWhenNotEmpty(t *testing.T) {
 
 	d := makeDeclared(t, declaredObjs...)
 	a := &testingfake.Applier{Client: c}
-	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, a, q, d)
+	w := NewWorker(declared.RootReconciler, configsync.RootSyncName, a, q, d, "", nil)
 
 	// Run worker in the background
 	doneCh := make(chan struct{})
@@ -428,3 +429,66 @@ func (q *fakeQueue) Retry(o client.Object) {
 func (q *fakeQueue) Forget(_ client.Object) {
 	q.element = nil
 }
+
+func partialObject(obj client.Object) *metav1.PartialObjectMetadata {
+	partial := &metav1.PartialObjectMetadata{}
+	partial.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	partial.SetName(obj.GetName())
+	partial.SetNamespace(obj.GetNamespace())
+	partial.SetLabels(obj.GetLabels())
+	partial.SetAnnotations(obj.GetAnnotations())
+	return partial
+}
+
+func TestWorker_ProcessPartialObject(t *testing.T) {
+	testCases := []struct {
+		name     string
+		existing []client.Object
+		want     []client.Object
+	}{
+		{
+			name: "get and update the object",
+			existing: []client.Object{
+				fake.ClusterRoleObject(syncertest.ManagementEnabled),
+			},
+			want: []client.Object{
+				fake.ClusterRoleObject(syncertest.ManagementEnabled,
+					core.UID("1"), core.ResourceVersion("2"), core.Generation(1),
+					core.Label("first", "one")),
+			},
+		},
+		{
+			name: "create the missing object",
+			want: []client.Object{
+				fake.ClusterRoleObject(syncertest.ManagementEnabled,
+					core.UID("1"), core.ResourceVersion("1"), core.Generation(1),
+					core.Label("first", "one")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := testingfake.NewClient(t, core.Scheme, tc.existing...)
+			q := queue.New("test")
+			q.Add(partialObject(fake.ClusterRoleObject(syncertest.ManagementEnabled)))
+
+			d := makeDeclared(t, fake.ClusterRoleObject(syncertest.ManagementEnabled,
+				core.Label("first", "one")))
+			w := NewWorker(declared.RootReconciler, configsync.RootSyncName, c.Applier(), q, d, "", nil)
+
+			// The worker enqueues the current object, then remediates it.
+			for i := 0; i < 2; i++ {
+				if ok := w.processNextObject(ctx); !ok {
+					t.Error("unexpected false result from processNextObject()")
+				}
+			}
+			if q.Len() != 0 {
+				t.Errorf("got %d queued objects, want 0", q.Len())
+			}
+
+			c.Check(t, tc.want...)
+		})
+	}
+}
@@ -22,10 +22,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/remediator/reconcile"
 	"kpt.dev/configsync/pkg/remediator/watch"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	syncerreconcile "kpt.dev/configsync/pkg/syncer/reconcile"
 )
@@ -39,6 +42,7 @@ import (
 type Remediator struct {
 	watchMgr *watch.Manager
 	workers  []*reconcile.Worker
+	applier  syncerreconcile.Applier
 	// The following fields are guarded by the mutex.
 	mux sync.Mutex
 	// conflictErrs tracks all the management conflicts the remediator encounters,
@@ -59,8 +63,10 @@ type Interface interface {
 	UpdateWatches(context.Context, map[schema.GroupVersionKind]struct{}) status.MultiError
 	// ManagementConflict returns true if one of the watchers noticed a management conflict.
 	ManagementConflict() bool
-	// ConflictErrors returns the errors the remediator encounters.
-	ConflictErrors() []status.ManagementConflictError
+	// ConflictErrors returns the errors the remediator encounters: the
+	// management conflicts, and the fights with other processes over the
+	// resources it remediates.
+	ConflictErrors() []status.Error
 }
 
 var _ Interface = &Remediator{}
@@ -69,19 +75,29 @@ var _ Interface = &Remediator{}
 // cluster match the declared resources.
 //
 // It is safe for decls to be modified after they have been passed into the
-// Remediator.
-func New(scope declared.Scope, syncName string, cfg *rest.Config, applier syncerreconcile.Applier, decls *declared.Resources, numWorkers int) (*Remediator, error) {
+// Remediator. The objects which are not in the inventory yet are only
+// remediated if the adoption policy lets the reconciler adopt them. The drift
+// of the objects selected by the exemptions is not reverted, and only the
+// objects of the shard are remediated.
+func New(scope declared.Scope, syncName string, cfg *rest.Config, applier syncerreconcile.Applier, decls *declared.Resources, numWorkers int, adoptionPolicy v1beta1.AdoptionPolicy, exemptions *exemption.Lister, s shard.Shard) (*Remediator, error) {
 	q := queue.New(string(scope))
 	workers := make([]*reconcile.Worker, numWorkers)
 	for i := 0; i < numWorkers; i++ {
-		workers[i] = reconcile.NewWorker(scope, syncName, applier, q, decls)
+		workers[i] = reconcile.NewWorker(scope, syncName, applier, q, decls, adoptionPolicy, exemptions)
 	}
 
 	remediator := &Remediator{
 		workers: workers,
+		applier: applier,
 	}
 
-	watchMgr, err := watch.NewManager(scope, syncName, cfg, q, decls, nil,
+	options, err := watch.DefaultOptions(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating watch manager")
+	}
+	options.Shard = s
+
+	watchMgr, err := watch.NewManager(scope, syncName, cfg, q, decls, options,
 		remediator.addConflictError, remediator.removeConflictError)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating watch manager")
@@ -127,12 +143,16 @@ func (r *Remediator) ManagementConflict() bool {
 }
 
 // ConflictErrors implements Interface.
-func (r *Remediator) ConflictErrors() []status.ManagementConflictError {
+func (r *Remediator) ConflictErrors() []status.Error {
 	r.mux.Lock()
 	defer r.mux.Unlock()
 
 	// Return a copy
-	return append([]status.ManagementConflictError(nil), r.conflictErrs...)
+	var errs []status.Error
+	for _, conflictErr := range r.conflictErrs {
+		errs = append(errs, conflictErr)
+	}
+	return append(errs, r.applier.FightErrors()...)
 }
 
 func (r *Remediator) addConflictError(e status.ManagementConflictError) {
@@ -26,7 +26,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/core"
@@ -35,6 +38,7 @@ import (
 	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/remediator/queue"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -78,19 +82,38 @@ const (
 // and having the same errorType should be logged.
 const errorLoggingInterval = time.Second
 
+// resyncPeriod is how often the declared objects the label-filtered watches do
+// not see are enqueued.
+const resyncPeriod = 10 * time.Minute
+
+// resyncPageSize is the maximum number of objects per page of the lists of the
+// resyncs.
+const resyncPageSize = 500
+
 // filteredWatcher is wrapper around a watch interface.
 // It only keeps the events for objects that are
 // - either present in the declared resources,
 // - or managed by the same reconciler.
+// The base watch may only return the metadata of the objects, as
+// *metav1.PartialObjectMetadata, in which case the worker gets the objects
+// before remediating them.
 type filteredWatcher struct {
-	gvk        string
+	gvk        schema.GroupVersionKind
 	startWatch startWatchFunc
-	resources  *declared.Resources
-	queue      *queue.ObjectQueue
-	scope      declared.Scope
-	syncName   string
+	// listMetadata lists the metadata of all the objects, if the base watch
+	// may only select the objects with the managed-by label.
+	listMetadata listMetadataFunc
+	resources    *declared.Resources
+	queue        *queue.ObjectQueue
+	scope        declared.Scope
+	syncName     string
+	// shard is the shard of the RootSync objects remediated by the watcher.
+	shard shard.Shard
 	// errorTracker maps an error to the time when the same error happened last time.
 	errorTracker map[string]time.Time
+	// seen maps the objects received from a metadata-only watch to the last
+	// state of their metadata. It is only used by the watch goroutine.
+	seen map[core.ID]metadataState
 
 	// The following fields are guarded by the mutex.
 	mux                     sync.Mutex
@@ -108,14 +131,17 @@ var _ Runnable = &filteredWatcher{}
 // NewFiltered returns a new filtered watch initialized with the given options.
 func NewFiltered(_ context.Context, cfg watcherConfig) Runnable {
 	return &filteredWatcher{
-		gvk:                     cfg.gvk.String(),
+		gvk:                     cfg.gvk,
 		startWatch:              cfg.startWatch,
+		listMetadata:            cfg.listMetadata,
 		resources:               cfg.resources,
 		queue:                   cfg.queue,
 		scope:                   cfg.scope,
 		syncName:                cfg.syncName,
+		shard:                   cfg.shard,
 		base:                    watch.NewEmptyWatch(),
 		errorTracker:            make(map[string]time.Time),
+		seen:                    make(map[core.ID]metadataState),
 		conflictErrMap:          make(map[queue.GVKNN]status.ManagementConflictError),
 		addConflictErrorFunc:    cfg.addConflictErrorFunc,
 		removeConflictErrorFunc: cfg.removeConflictErrorFunc,
@@ -260,6 +286,11 @@ func waitUntilNextRetry(retries int) {
 // in the event to the controller work queue.
 func (w *filteredWatcher) Run(ctx context.Context) status.Error {
 	klog.Infof("Watch started for %s", w.gvk)
+	if w.listMetadata != nil {
+		resyncCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go wait.Until(w.resyncDeclared, resyncPeriod, resyncCtx.Done())
+	}
 	var resourceVersion string
 	var retriesForWatchError int
 
@@ -314,6 +345,74 @@ func (w *filteredWatcher) Run(ctx context.Context) status.Error {
 	return nil
 }
 
+// resyncDeclared enqueues the declared objects which the base watch does not
+// see when it only selects the objects with the managed-by label: the objects
+// whose label was removed, and the objects which do not exist.
+//
+// Only the namespaces with declared objects are listed, page by page, and the
+// list is narrowed to the name of the object if a single object is declared in
+// the namespace.
+func (w *filteredWatcher) resyncDeclared() {
+	// declared maps the namespaces to the names of the declared objects.
+	declared := make(map[string]map[string]bool)
+	for _, decl := range w.resources.Declarations() {
+		if decl.GroupVersionKind() != w.gvk {
+			continue
+		}
+		if declared[decl.GetNamespace()] == nil {
+			declared[decl.GetNamespace()] = make(map[string]bool)
+		}
+		declared[decl.GetNamespace()][decl.GetName()] = true
+	}
+	for namespace, names := range declared {
+		if err := w.resyncNamespace(namespace, names); err != nil {
+			klog.Warningf("Failed to list %s in namespace %q to resync the declared objects: %v", w.gvk, namespace, err)
+			continue
+		}
+		for name := range names {
+			// The worker gets the object, and recreates it since it does not exist.
+			obj := &metav1.PartialObjectMetadata{}
+			obj.SetGroupVersionKind(w.gvk)
+			obj.SetNamespace(namespace)
+			obj.SetName(name)
+			w.queue.Add(obj)
+		}
+	}
+}
+
+// resyncNamespace lists the objects in the namespace, and enqueues the
+// declared objects without the managed-by label. The names of the declared
+// objects which are found are removed from names.
+func (w *filteredWatcher) resyncNamespace(namespace string, names map[string]bool) error {
+	options := metav1.ListOptions{Limit: resyncPageSize}
+	if len(names) == 1 {
+		for name := range names {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}
+	}
+	for {
+		list, err := w.listMetadata(namespace, options)
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !names[obj.GetName()] {
+				continue
+			}
+			delete(names, obj.GetName())
+			if obj.GetLabels()[metadata.ManagedByKey] != metadata.ManagedByValue {
+				obj.SetGroupVersionKind(w.gvk)
+				w.queue.Add(obj)
+			}
+		}
+		if list.Continue == "" {
+			return nil
+		}
+		options.Continue = list.Continue
+	}
+}
+
 // start initiates a new base watch at the given resource version in a
 // threadsafe manner and returns true if the new base watch was created. Returns
 // false if the filteredWatcher is already stopped and returns error if the base
@@ -406,12 +505,35 @@ func (w *filteredWatcher) handle(ctx context.Context, event watch.Event) (string
 		metrics.RecordInternalError(ctx, "remediator")
 		return "", false, nil
 	}
+	if partial, ok := object.(*metav1.PartialObjectMetadata); ok {
+		// Metadata-only watches return objects with the PartialObjectMetadata
+		// kind, so set the kind of the watched objects.
+		partial = partial.DeepCopy()
+		partial.SetGroupVersionKind(w.gvk)
+		object = partial
+		// Metadata-only watches only select the objects with the managed-by
+		// label, and an object whose label was removed leaves the watch with a
+		// Deleted event although it still exists. Enqueue it without marking it
+		// deleted, so the worker gets the object and remediates it if it exists.
+		if deleted && object.GetLabels()[metadata.ManagedByKey] != metadata.ManagedByValue {
+			deleted = false
+		}
+	}
 	// filter objects.
 	if !w.shouldProcess(object) {
 		klog.V(4).Infof("Ignoring event for object: %v", object)
 		return object.GetResourceVersion(), true, nil
 	}
 
+	if _, partial := object.(*metav1.PartialObjectMetadata); partial {
+		if deleted {
+			delete(w.seen, core.IDOf(object))
+		} else if !w.metadataChanged(object) {
+			klog.V(4).Infof("Ignoring event for object with unchanged metadata: %v", core.IDOf(object))
+			return object.GetResourceVersion(), true, nil
+		}
+	}
+
 	if deleted {
 		klog.V(2).Infof("Received watch event for deleted object %q", core.IDOf(object))
 		object = queue.MarkDeleted(ctx, object)
@@ -424,9 +546,68 @@ func (w *filteredWatcher) handle(ctx context.Context, event watch.Event) (string
 	return object.GetResourceVersion(), false, nil
 }
 
+// metadataState is the part of the metadata of an object which shows a change
+// Config Sync remediates: the generation, which the API server bumps when the
+// spec changes, the deletion timestamp, and the Config Sync and declared
+// labels and annotations.
+type metadataState struct {
+	generation  int64
+	deleting    bool
+	labels      labels.Set
+	annotations labels.Set
+}
+
+func (s metadataState) equal(other metadataState) bool {
+	return s.generation == other.generation && s.deleting == other.deleting &&
+		labels.Equals(s.labels, other.labels) && labels.Equals(s.annotations, other.annotations)
+}
+
+// metadataChanged records the state of the metadata of the object received
+// from a metadata-only watch, and returns true if it changed since the last
+// event for the object, or if the object is new to the watcher.
+//
+// The kinds without a generation, such as ConfigMaps, do not show the changes
+// to their content in their metadata, so their events are always processed.
+func (w *filteredWatcher) metadataChanged(object client.Object) bool {
+	id := core.IDOf(object)
+	if object.GetGeneration() == 0 {
+		delete(w.seen, id)
+		return true
+	}
+	var declLabels, declAnnotations map[string]string
+	if decl, ok := w.resources.Get(id); ok {
+		declLabels, declAnnotations = decl.GetLabels(), decl.GetAnnotations()
+	}
+	state := metadataState{
+		generation:  object.GetGeneration(),
+		deleting:    object.GetDeletionTimestamp() != nil,
+		labels:      relevantMetadata(object.GetLabels(), declLabels, metadata.IsConfigSyncLabelKey),
+		annotations: relevantMetadata(object.GetAnnotations(), declAnnotations, metadata.IsConfigSyncAnnotationKey),
+	}
+	last, found := w.seen[id]
+	w.seen[id] = state
+	return !found || !last.equal(state)
+}
+
+// relevantMetadata returns the labels or annotations which are either managed
+// by Config Sync or declared.
+func relevantMetadata(live, declared map[string]string, isConfigSyncKey func(string) bool) labels.Set {
+	result := labels.Set{}
+	for k, v := range live {
+		if _, isDeclared := declared[k]; isDeclared || isConfigSyncKey(k) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 // shouldProcess returns true if the given object should be enqueued by the
 // watcher for processing.
 func (w *filteredWatcher) shouldProcess(object client.Object) bool {
+	// The objects of other shards are remediated by their reconcilers.
+	if !w.shard.Owns(object) {
+		return false
+	}
 	// Process the resource if we are the manager regardless if it is declared or not.
 	if diff.IsManager(w.scope, w.syncName, object) {
 		w.removeManagementConflictError(object)
@@ -16,14 +16,19 @@ package watch
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff/difftest"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/remediator/queue"
 	"kpt.dev/configsync/pkg/syncer/syncertest"
 	"kpt.dev/configsync/pkg/testing/fake"
@@ -228,3 +233,230 @@ func TestFilteredWatcher(t *testing.T) {
 		})
 	}
 }
+
+// partialObject returns the object as returned by metadata-only watches.
+func partialObject(obj *appsv1.Deployment) *metav1.PartialObjectMetadata {
+	partial := &metav1.PartialObjectMetadata{ObjectMeta: *obj.ObjectMeta.DeepCopy()}
+	partial.SetGroupVersionKind(metav1.SchemeGroupVersion.WithKind("PartialObjectMetadata"))
+	return partial
+}
+
+func TestFilteredWatcher_PartialObjectMetadata(t *testing.T) {
+	managed := fake.DeploymentObject(core.Name("managed"),
+		core.Label(metadata.ManagedByKey, metadata.ManagedByValue))
+	unlabeled := fake.DeploymentObject(core.Name("unlabeled"))
+
+	testCases := []struct {
+		name        string
+		action      action
+		wantDeleted bool
+	}{
+		{
+			name:   "modified object",
+			action: action{watch.Modified, partialObject(managed)},
+		},
+		{
+			name:        "deleted object",
+			action:      action{watch.Deleted, partialObject(managed)},
+			wantDeleted: true,
+		},
+		{
+			name:   "object whose managed-by label was removed",
+			action: action{watch.Deleted, partialObject(unlabeled)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dr := &declared.Resources{}
+			ctx := context.Background()
+			if _, err := dr.Update(ctx, []client.Object{managed, unlabeled}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			base := watch.NewFake()
+			q := queue.New("test")
+			cfg := watcherConfig{
+				gvk:       kinds.Deployment(),
+				scope:     declared.RootReconciler,
+				syncName:  "rs",
+				resources: dr,
+				queue:     q,
+				startWatch: func(options metav1.ListOptions) (watch.Interface, error) {
+					return base, nil
+				},
+			}
+			w := NewFiltered(ctx, cfg)
+
+			go func() {
+				base.Action(tc.action.event, tc.action.obj)
+				w.Stop()
+			}()
+			if err := w.Run(ctx); err != nil {
+				t.Fatalf("got Run() = %v, want Run() = <nil>", err)
+			}
+
+			if q.Len() != 1 {
+				t.Fatalf("got %d queued objects, want 1", q.Len())
+			}
+			obj, _ := q.Get()
+			if got := obj.GetObjectKind().GroupVersionKind(); got != kinds.Deployment() {
+				t.Errorf("got queued object with GVK %v, want %v", got, kinds.Deployment())
+			}
+			if got := queue.WasDeleted(ctx, obj); got != tc.wantDeleted {
+				t.Errorf("got queued object deleted %t, want %t", got, tc.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestFilteredWatcher_ResyncDeclared(t *testing.T) {
+	managed := fake.DeploymentObject(core.Name("managed"),
+		core.Label(metadata.ManagedByKey, metadata.ManagedByValue))
+	unlabeled := fake.DeploymentObject(core.Name("unlabeled"))
+	missing := fake.DeploymentObject(core.Name("missing"))
+	undeclared := fake.DeploymentObject(core.Name("undeclared"))
+
+	dr := &declared.Resources{}
+	ctx := context.Background()
+	if _, err := dr.Update(ctx, []client.Object{managed, unlabeled, missing, fake.ConfigMapObject()}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	q := queue.New("test")
+	cfg := watcherConfig{
+		gvk:       kinds.Deployment(),
+		scope:     declared.RootReconciler,
+		syncName:  "rs",
+		resources: dr,
+		queue:     q,
+		listMetadata: func(namespace string, options metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+			if options.LabelSelector != "" {
+				t.Errorf("got label selector %q, want none", options.LabelSelector)
+			}
+			if namespace != managed.Namespace {
+				t.Errorf("got list of namespace %q, want %q", namespace, managed.Namespace)
+			}
+			if options.Limit != resyncPageSize {
+				t.Errorf("got limit %d, want %d", options.Limit, resyncPageSize)
+			}
+			// Return one object per page.
+			items := []*appsv1.Deployment{managed, unlabeled, undeclared}
+			page := 0
+			if options.Continue != "" {
+				page, _ = strconv.Atoi(options.Continue)
+			}
+			list := &metav1.PartialObjectMetadataList{}
+			list.Items = append(list.Items, *partialObject(items[page]))
+			if page+1 < len(items) {
+				list.Continue = strconv.Itoa(page + 1)
+			}
+			return list, nil
+		},
+	}
+	w := NewFiltered(ctx, cfg).(*filteredWatcher)
+	w.resyncDeclared()
+
+	// Only the declared objects the label-filtered watch does not see are
+	// queued.
+	var got []core.ID
+	for q.Len() > 0 {
+		obj, _ := q.Get()
+		if _, ok := obj.(*metav1.PartialObjectMetadata); !ok {
+			t.Errorf("got queued %T, want *metav1.PartialObjectMetadata", obj)
+		}
+		if queue.WasDeleted(ctx, obj) {
+			t.Errorf("got queued object %v marked deleted", core.IDOf(obj))
+		}
+		got = append(got, core.IDOf(obj))
+		q.Done(obj)
+	}
+	want := []core.ID{core.IDOf(unlabeled), core.IDOf(missing)}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(x, y core.ID) bool { return x.String() < y.String() })); diff != "" {
+		t.Errorf("queued objects diff (- want, + got):\n%s", diff)
+	}
+}
+
+func TestFilteredWatcher_ResyncDeclaredSingleObject(t *testing.T) {
+	unlabeled := fake.DeploymentObject(core.Name("unlabeled"), core.Namespace("bookstore"))
+	ctx := context.Background()
+	dr := &declared.Resources{}
+	if _, err := dr.Update(ctx, []client.Object{unlabeled}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	q := queue.New("test")
+	var lists int
+	cfg := watcherConfig{
+		gvk:       kinds.Deployment(),
+		scope:     declared.RootReconciler,
+		syncName:  "rs",
+		resources: dr,
+		queue:     q,
+		listMetadata: func(namespace string, options metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+			lists++
+			if namespace != "bookstore" {
+				t.Errorf("got list of namespace %q, want %q", namespace, "bookstore")
+			}
+			if want := "metadata.name=unlabeled"; options.FieldSelector != want {
+				t.Errorf("got field selector %q, want %q", options.FieldSelector, want)
+			}
+			return &metav1.PartialObjectMetadataList{Items: []metav1.PartialObjectMetadata{*partialObject(unlabeled)}}, nil
+		},
+	}
+	w := NewFiltered(ctx, cfg).(*filteredWatcher)
+	w.resyncDeclared()
+
+	if lists != 1 {
+		t.Errorf("got %d lists, want 1", lists)
+	}
+	if q.Len() != 1 {
+		t.Errorf("got %d queued objects, want 1", q.Len())
+	}
+}
+
+func TestFilteredWatcher_MetadataChanged(t *testing.T) {
+	deployment := func(generation int64, opts ...core.MetaMutator) *metav1.PartialObjectMetadata {
+		opts = append(opts, core.Name("app"), core.Generation(generation),
+			core.Label(metadata.ManagedByKey, metadata.ManagedByValue))
+		// The watcher sets the watched kind on the partial objects.
+		partial := partialObject(fake.DeploymentObject(opts...))
+		partial.SetGroupVersionKind(kinds.Deployment())
+		return partial
+	}
+	declaredObj := fake.DeploymentObject(core.Name("app"), core.Label("team", "bookstore"))
+	ctx := context.Background()
+	dr := &declared.Resources{}
+	if _, err := dr.Update(ctx, []client.Object{declaredObj}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	w := NewFiltered(ctx, watcherConfig{gvk: kinds.Deployment(), resources: dr}).(*filteredWatcher)
+
+	steps := []struct {
+		name   string
+		obj    client.Object
+		wantOK bool
+	}{
+		{name: "new object", obj: deployment(1), wantOK: true},
+		{name: "same metadata", obj: deployment(1)},
+		{name: "undeclared label added", obj: deployment(1, core.Label("app", "x"))},
+		{name: "other annotation added", obj: deployment(1, core.Annotation("deployment.kubernetes.io/revision", "2"))},
+		{name: "spec changed", obj: deployment(2), wantOK: true},
+		{name: "declared label added", obj: deployment(2, core.Label("team", "other")), wantOK: true},
+		{name: "Config Sync annotation changed", obj: deployment(2, core.Label("team", "other"),
+			core.Annotation(metadata.SyncTokenAnnotationKey, "def456")), wantOK: true},
+	}
+	for _, step := range steps {
+		if got := w.metadataChanged(step.obj); got != step.wantOK {
+			t.Errorf("%s: got metadataChanged() = %t, want %t", step.name, got, step.wantOK)
+		}
+	}
+
+	// The events of the kinds without a generation are always processed.
+	cm := fake.ConfigMapObject(core.Name("cm"))
+	for i := 0; i < 2; i++ {
+		if !w.metadataChanged(cm) {
+			t.Errorf("got metadataChanged() = false for an object without a generation, want true")
+		}
+	}
+}
@@ -20,10 +20,13 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	metadataclient "k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/remediator/queue"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
@@ -37,12 +40,13 @@ type Manager struct {
 	// syncName is the corresponding RootSync|RepoSync name of the reconciler process running the Manager.
 	syncName string
 
-	// cfg is the rest config used to talk to apiserver.
-	cfg *rest.Config
-
 	// mapper is the RESTMapper to use for mapping GroupVersionKinds to Resources.
 	mapper meta.RESTMapper
 
+	// dynamicClient and metadataClient are shared by the watchers.
+	dynamicClient  dynamic.Interface
+	metadataClient metadataclient.Interface
+
 	// resources is the declared resources that are parsed from Git.
 	resources *declared.Resources
 
@@ -52,6 +56,9 @@ type Manager struct {
 	// createWatcherFunc is the function to create a watcher.
 	createWatcherFunc createWatcherFunc
 
+	// shard is the shard of the RootSync objects remediated by the watchers.
+	shard shard.Shard
+
 	// The following fields are guarded by the mutex.
 	mux sync.Mutex
 	// watcherMap maps GVKs to their associated watchers
@@ -69,21 +76,42 @@ type Options struct {
 	// Mapper is the RESTMapper to use for mapping GroupVersionKinds to Resources.
 	Mapper meta.RESTMapper
 
+	// DynamicClient is the client used by the watchers to watch full objects.
+	DynamicClient dynamic.Interface
+
+	// MetadataClient is the client used by the watchers to watch the metadata
+	// of the objects.
+	MetadataClient metadataclient.Interface
+
+	// Shard is the shard of the RootSync objects remediated by the watchers.
+	Shard shard.Shard
+
 	watcherFunc createWatcherFunc
 }
 
 // DefaultOptions return the default options:
 // - create discovery RESTmapper from the passed rest.Config
+// - create the dynamic and metadata clients from the passed rest.Config
 // - use createWatcher to create watchers
 func DefaultOptions(cfg *rest.Config) (*Options, error) {
 	mapper, err := apiutil.NewDynamicRESTMapper(cfg)
 	if err != nil {
 		return nil, err
 	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	metadataClient, err := metadataclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Options{
-		Mapper:      mapper,
-		watcherFunc: createWatcher,
+		Mapper:         mapper,
+		DynamicClient:  dynamicClient,
+		MetadataClient: metadataClient,
+		watcherFunc:    createWatcher,
 	}, nil
 }
 
@@ -103,11 +131,13 @@ func NewManager(scope declared.Scope, syncName string, cfg *rest.Config,
 	return &Manager{
 		scope:                   scope,
 		syncName:                syncName,
-		cfg:                     cfg,
 		resources:               decls,
 		watcherMap:              make(map[schema.GroupVersionKind]Runnable),
 		createWatcherFunc:       options.watcherFunc,
 		mapper:                  options.Mapper,
+		dynamicClient:           options.DynamicClient,
+		metadataClient:          options.MetadataClient,
+		shard:                   options.Shard,
 		queue:                   q,
 		addConflictErrorFunc:    addConflictErrorFunc,
 		removeConflictErrorFunc: removeConflictErrorFunc,
@@ -205,11 +235,13 @@ func (m *Manager) startWatcher(ctx context.Context, gvk schema.GroupVersionKind)
 	cfg := watcherConfig{
 		gvk:                     gvk,
 		mapper:                  m.mapper,
-		config:                  m.cfg,
+		dynamicClient:           m.dynamicClient,
+		metadataClient:          m.metadataClient,
 		resources:               m.resources,
 		queue:                   m.queue,
 		scope:                   m.scope,
 		syncName:                m.syncName,
+		shard:                   m.shard,
 		addConflictErrorFunc:    m.addConflictErrorFunc,
 		removeConflictErrorFunc: m.removeConflictErrorFunc,
 	}
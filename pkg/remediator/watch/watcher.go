@@ -17,30 +17,40 @@ package watch
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/rest"
+	metadataclient "k8s.io/client-go/metadata"
+	"k8s.io/klog/v2"
 	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/remediator/queue"
+	"kpt.dev/configsync/pkg/shard"
 	"kpt.dev/configsync/pkg/status"
 )
 
 type startWatchFunc func(metav1.ListOptions) (watch.Interface, error)
 
+type listMetadataFunc func(namespace string, options metav1.ListOptions) (*metav1.PartialObjectMetadataList, error)
+
 // watcherConfig contains the options needed
 // to create a watcher.
 type watcherConfig struct {
 	gvk                     schema.GroupVersionKind
 	mapper                  meta.RESTMapper
-	config                  *rest.Config
+	dynamicClient           dynamic.Interface
+	metadataClient          metadataclient.Interface
 	resources               *declared.Resources
 	queue                   *queue.ObjectQueue
 	scope                   declared.Scope
 	syncName                string
+	shard                   shard.Shard
 	startWatch              startWatchFunc
+	listMetadata            listMetadataFunc
 	addConflictErrorFunc    func(status.ManagementConflictError)
 	removeConflictErrorFunc func(status.ManagementConflictError)
 }
@@ -48,7 +58,18 @@ type watcherConfig struct {
 // createWatcherFunc is the type of functions to create watchers
 type createWatcherFunc func(ctx context.Context, cfg watcherConfig) (Runnable, status.Error)
 
-// createWatcher creates a watcher for a given GVK
+// managedBySelector selects the objects with the managed-by label, which the
+// applier sets on every object it applies.
+var managedBySelector = labels.SelectorFromSet(labels.Set{metadata.ManagedByKey: metadata.ManagedByValue}).String()
+
+// createWatcher creates a watcher for a given GVK.
+//
+// The watcher only watches the metadata of the objects with the managed-by
+// label, and the worker gets the objects before remediating them. If the API
+// server does not support metadata-only watches for the GVK, e.g. for some
+// aggregated APIs, it falls back to watching the full objects without a label
+// selector. The watcher also periodically lists the metadata of all the
+// objects, to remediate the declared objects without the label.
 func createWatcher(ctx context.Context, cfg watcherConfig) (Runnable, status.Error) {
 	if cfg.startWatch == nil {
 		mapping, err := cfg.mapper.RESTMapping(cfg.gvk.GroupKind(), cfg.gvk.Version)
@@ -56,21 +77,46 @@ func createWatcher(ctx context.Context, cfg watcherConfig) (Runnable, status.Err
 			return nil, status.APIServerErrorf(err, "watcher failed to get REST mapping for %s", cfg.gvk.String())
 		}
 
-		dynamicClient, err := dynamic.NewForConfig(cfg.config)
-		if err != nil {
-			return nil, status.APIServerErrorf(err, "watcher failed to get dynamic client for %s", cfg.gvk.String())
+		var resource dynamic.ResourceInterface = cfg.dynamicClient.Resource(mapping.Resource)
+		var metadataResource metadataclient.ResourceInterface = cfg.metadataClient.Resource(mapping.Resource)
+		if cfg.scope != declared.RootReconciler {
+			resource = cfg.dynamicClient.Resource(mapping.Resource).Namespace(string(cfg.scope))
+			metadataResource = cfg.metadataClient.Resource(mapping.Resource).Namespace(string(cfg.scope))
+		}
+		cfg.startWatch = newStartWatch(ctx, cfg.gvk, metadataResource.Watch, resource.Watch)
+		cfg.listMetadata = func(namespace string, options metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+			return cfg.metadataClient.Resource(mapping.Resource).Namespace(namespace).List(ctx, options)
 		}
+	}
 
-		if cfg.scope == declared.RootReconciler {
-			cfg.startWatch = func(options metav1.ListOptions) (watch.Interface, error) {
-				return dynamicClient.Resource(mapping.Resource).Watch(ctx, options)
-			}
-		} else {
-			cfg.startWatch = func(options metav1.ListOptions) (watch.Interface, error) {
-				return dynamicClient.Resource(mapping.Resource).Namespace(string(cfg.scope)).Watch(ctx, options)
+	return NewFiltered(ctx, cfg), nil
+}
+
+// newStartWatch returns a startWatchFunc which starts metadata-only watches of
+// the objects with the managed-by label, until the API server rejects them,
+// and full watches of all the objects afterwards.
+func newStartWatch(ctx context.Context, gvk schema.GroupVersionKind, metadataWatch, fullWatch func(context.Context, metav1.ListOptions) (watch.Interface, error)) startWatchFunc {
+	// startWatch is only called by the watcher goroutine, so the flag does not
+	// need a lock.
+	full := false
+	return func(options metav1.ListOptions) (watch.Interface, error) {
+		if !full {
+			options.LabelSelector = managedBySelector
+			w, err := metadataWatch(ctx, options)
+			if !unsupportedWatch(err) {
+				return w, err
 			}
+			klog.Infof("Metadata-only watches are not supported for %s, falling back to full watches: %v", gvk, err)
+			full = true
+			options.LabelSelector = ""
 		}
+		return fullWatch(ctx, options)
 	}
+}
 
-	return NewFiltered(ctx, cfg), nil
+// unsupportedWatch returns true if the error means the API server does not
+// support metadata-only watches with a label selector for the resource.
+func unsupportedWatch(err error) bool {
+	return apierrors.IsNotAcceptable(err) || apierrors.IsUnsupportedMediaType(err) ||
+		apierrors.IsMethodNotSupported(err) || apierrors.IsBadRequest(err)
 }
@@ -0,0 +1,83 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"kpt.dev/configsync/pkg/kinds"
+)
+
+func TestNewStartWatch(t *testing.T) {
+	testCases := []struct {
+		name string
+		// metadataErr is the error returned when starting metadata-only watches.
+		metadataErr error
+		want        []string
+	}{
+		{
+			name: "metadata-only watches",
+			want: []string{"metadata", "metadata"},
+		},
+		{
+			name:        "fall back to full watches",
+			metadataErr: apierrors.NewBadRequest("label selectors are not supported"),
+			want:        []string{"metadata", "full", "full"},
+		},
+		{
+			name:        "other errors do not fall back",
+			metadataErr: apierrors.NewForbidden(kinds.Deployment().GroupVersion().WithResource("deployments").GroupResource(), "", nil),
+			want:        []string{"metadata", "metadata"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			metadataWatch := func(_ context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				got = append(got, "metadata")
+				if options.LabelSelector != managedBySelector {
+					t.Errorf("got metadata-only watch label selector %q, want %q", options.LabelSelector, managedBySelector)
+				}
+				if tc.metadataErr != nil {
+					return nil, tc.metadataErr
+				}
+				return watch.NewEmptyWatch(), nil
+			}
+			fullWatch := func(_ context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				got = append(got, "full")
+				if options.LabelSelector != "" {
+					t.Errorf("got full watch label selector %q, want none", options.LabelSelector)
+				}
+				return watch.NewEmptyWatch(), nil
+			}
+
+			startWatch := newStartWatch(context.Background(), kinds.Deployment(), metadataWatch, fullWatch)
+			for i := 0; i < 2; i++ {
+				// Errors are returned to the watcher, which restarts the watch.
+				_, _ = startWatch(metav1.ListOptions{})
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("did not get desired watches: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reposyncpolicy
+
+import (
+	"kpt.dev/configsync/pkg/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ViolationErrorCode is the error code for ViolationError.
+const ViolationErrorCode = "1071"
+
+var violationErrorBuilder = status.NewErrorBuilder(ViolationErrorCode)
+
+// ViolationError reports that the RepoSync violates the RepoSyncPolicy, by
+// syncing from its source or by syncing the resources.
+func ViolationError(policy, message string, resources ...client.Object) status.Error {
+	eb := violationErrorBuilder.
+		Sprintf("The RepoSyncPolicy %q forbids this RepoSync to sync: %s", policy, message)
+	if len(resources) == 0 {
+		return eb.Build()
+	}
+	return eb.BuildWithResources(resources...)
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reposyncpolicy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// refreshPeriod is how long the policies are cached before being listed again.
+const refreshPeriod = 10 * time.Second
+
+// Lister lists the RepoSyncPolicies, caching them for a few seconds since they
+// are looked up on every request of a namespace reconciler. A nil Lister lists
+// no policies.
+type Lister struct {
+	reader client.Reader
+	now    func() time.Time
+
+	mux      sync.Mutex
+	policies []v1beta1.RepoSyncPolicy
+	listed   time.Time
+}
+
+// NewLister returns a Lister which reads the policies with the reader.
+func NewLister(reader client.Reader) *Lister {
+	return &Lister{
+		reader: reader,
+		now:    time.Now,
+	}
+}
+
+// ForNamespace returns the policies which apply to the RepoSyncs in the
+// namespace.
+func (l *Lister) ForNamespace(ctx context.Context, namespace string) []v1beta1.RepoSyncPolicy {
+	if l == nil {
+		return nil
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+	if l.listed.IsZero() || now.Sub(l.listed) >= refreshPeriod {
+		policies, err := List(ctx, l.reader)
+		if err != nil {
+			klog.Warningf("Failed to list RepoSyncPolicies: %v", err)
+			// Keep the previous policies until the next refresh.
+		} else {
+			l.policies = policies
+		}
+		l.listed = now
+	}
+	return ForNamespace(l.policies, namespace)
+}
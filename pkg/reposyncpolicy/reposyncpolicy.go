@@ -0,0 +1,272 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reposyncpolicy evaluates the RepoSyncPolicies declared by cluster
+// administrators, which restrict what the RepoSyncs may sync.
+//
+// Namespace reconcilers evaluate the policies of their namespace against their
+// source and the objects parsed from it, and report the violations as errors
+// in the status of their RepoSync. The admission webhook evaluates them against
+// the objects namespace reconcilers create or update, and denies the requests
+// violating them.
+package reposyncpolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/util/jsonpath"
+	"kpt.dev/configsync/pkg/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// List returns the RepoSyncPolicies on the cluster. It returns no policies if
+// the RepoSyncPolicy CRD is not installed.
+func List(ctx context.Context, reader client.Reader) ([]v1beta1.RepoSyncPolicy, error) {
+	policies := &v1beta1.RepoSyncPolicyList{}
+	if err := reader.List(ctx, policies); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policies.Items, nil
+}
+
+// ForNamespace returns the policies which apply to the RepoSyncs in the
+// namespace.
+func ForNamespace(policies []v1beta1.RepoSyncPolicy, namespace string) []v1beta1.RepoSyncPolicy {
+	var result []v1beta1.RepoSyncPolicy
+	for _, p := range policies {
+		if appliesTo(p, namespace) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func appliesTo(p v1beta1.RepoSyncPolicy, namespace string) bool {
+	if len(p.Spec.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range p.Spec.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSource returns the violations of the policies by syncing from the
+// source, which is the Git repository, OCI image or Helm repository of a
+// RepoSync.
+func CheckSource(policies []v1beta1.RepoSyncPolicy, source string) status.MultiError {
+	var errs status.MultiError
+	for _, p := range policies {
+		if !sourceAllowed(p, source) {
+			errs = status.Append(errs, ViolationError(p.Name,
+				fmt.Sprintf("source %q is not allowed, it must be or be under one of %s", source, strings.Join(p.Spec.AllowedSources, ", "))))
+		}
+	}
+	return errs
+}
+
+// sourceAllowed returns true if the source is equal to one of the allowed
+// sources of the policy, or under it on a path segment boundary, so that
+// `https://github.com/acme` allows `https://github.com/acme/repo` but not
+// `https://github.com/acme-evil/repo`.
+func sourceAllowed(p v1beta1.RepoSyncPolicy, source string) bool {
+	if len(p.Spec.AllowedSources) == 0 {
+		return true
+	}
+	source = normalizeSource(source)
+	for _, prefix := range p.Spec.AllowedSources {
+		prefix = normalizeSource(prefix)
+		if prefix == "" {
+			continue
+		}
+		if source == prefix || strings.HasPrefix(source, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSource returns the source with its scheme and host in lower case,
+// and without trailing slashes or `.git` suffix. The host ends at the first
+// "/" or ":", which also handles the scp-like syntax of Git over SSH, such as
+// `git@github.com:acme/repo.git`.
+func normalizeSource(source string) string {
+	s := strings.TrimSpace(source)
+	var scheme string
+	if i := strings.Index(s, "://"); i >= 0 {
+		scheme, s = strings.ToLower(s[:i+len("://")]), s[i+len("://"):]
+	}
+	host, path := s, ""
+	if i := strings.IndexAny(s, "/:"); i >= 0 {
+		host, path = s[:i], s[i:]
+	}
+	path = strings.TrimRight(path, "/")
+	path = strings.TrimRight(strings.TrimSuffix(path, ".git"), "/")
+	return scheme + strings.ToLower(host) + path
+}
+
+// CheckObjects returns the violations of the policies by syncing the objects.
+func CheckObjects(policies []v1beta1.RepoSyncPolicy, objs []ast.FileObject) status.MultiError {
+	var errs status.MultiError
+	for _, p := range policies {
+		if p.Spec.MaxObjects > 0 && int64(len(objs)) > p.Spec.MaxObjects {
+			errs = status.Append(errs, ViolationError(p.Name,
+				fmt.Sprintf("%d objects exceed the maximum of %d objects", len(objs), p.Spec.MaxObjects)))
+		}
+		for i := range objs {
+			obj := &objs[i]
+			gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+			messages, err := violations(p, gk, obj.Unstructured.Object)
+			if err != nil {
+				errs = status.Append(errs, ViolationError(p.Name, err.Error(), obj))
+				continue
+			}
+			for _, m := range messages {
+				errs = status.Append(errs, ViolationError(p.Name, m, obj))
+			}
+		}
+	}
+	return errs
+}
+
+// CheckObject returns the messages describing the violations of the policies
+// by syncing the object.
+func CheckObject(policies []v1beta1.RepoSyncPolicy, obj client.Object) ([]string, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+	var result []string
+	for _, p := range policies {
+		messages, err := violations(p, gk, u)
+		if err != nil {
+			return nil, fmt.Errorf("RepoSyncPolicy %q: %w", p.Name, err)
+		}
+		for _, m := range messages {
+			result = append(result, fmt.Sprintf("RepoSyncPolicy %q: %s", p.Name, m))
+		}
+	}
+	return result, nil
+}
+
+// violations returns the messages describing the violations of the policy by
+// the object of the GroupKind.
+func violations(p v1beta1.RepoSyncPolicy, gk schema.GroupKind, obj map[string]interface{}) ([]string, error) {
+	var messages []string
+	if len(p.Spec.AllowedKinds) > 0 && !matchesAny(p.Spec.AllowedKinds, gk) {
+		messages = append(messages, fmt.Sprintf("kind %s is not allowed", kindString(gk)))
+	}
+	if matchesAny(p.Spec.DeniedKinds, gk) {
+		messages = append(messages, fmt.Sprintf("kind %s is denied", kindString(gk)))
+	}
+	for _, f := range p.Spec.ForbiddenFields {
+		if len(f.Kinds) > 0 && !matchesAny(f.Kinds, gk) {
+			continue
+		}
+		paths, err := setFields(f.Path, obj)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbidden field path %q: %w", f.Path, err)
+		}
+		if len(paths) > 0 {
+			messages = append(messages, fmt.Sprintf("field %s is forbidden (at %s)", f.Path, strings.Join(paths, ", ")))
+		}
+	}
+	return messages, nil
+}
+
+func matchesAny(kinds []v1beta1.RepoSyncPolicyKind, gk schema.GroupKind) bool {
+	for _, k := range kinds {
+		if k.Group == gk.Group && (k.Kind == "*" || k.Kind == gk.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+func kindString(gk schema.GroupKind) string {
+	if gk.Group == "" {
+		return gk.Kind
+	}
+	return gk.String()
+}
+
+// setFields returns the paths of the fields of the object selected by the
+// JSONPath expression which are set to a value other than null, false, zero or
+// empty.
+func setFields(path string, obj map[string]interface{}) ([]string, error) {
+	_, nodes, err := jsonpath.Nodes(obj, path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, node := range nodes {
+		value, err := node.Unpack()
+		if err != nil {
+			return nil, err
+		}
+		if isSet(value) {
+			paths = append(paths, node.Path())
+		}
+	}
+	return paths, nil
+}
+
+func isSet(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// Visitor returns a visitor which evaluates the policies against the source
+// and the final objects.
+func Visitor(policies []v1beta1.RepoSyncPolicy, source string) validate.VisitorFunc {
+	return func(objs []ast.FileObject) ([]ast.FileObject, status.MultiError) {
+		if len(policies) == 0 {
+			return objs, nil
+		}
+		errs := CheckSource(policies, source)
+		errs = status.Append(errs, CheckObjects(policies, objs))
+		return objs, errs
+	}
+}
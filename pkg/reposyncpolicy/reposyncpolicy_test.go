@@ -0,0 +1,245 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reposyncpolicy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func policy(name string, spec v1beta1.RepoSyncPolicySpec) v1beta1.RepoSyncPolicy {
+	p := v1beta1.RepoSyncPolicy{Spec: spec}
+	p.Name = name
+	return p
+}
+
+func deployment(hostNetwork bool) ast.FileObject {
+	d := fake.DeploymentObject(core.Name("app"), core.Namespace("bookstore"))
+	d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app"}}
+	d.Spec.Template.Spec.HostNetwork = hostNetwork
+	return fake.FileObject(d, "deployment.yaml")
+}
+
+func TestVisitor(t *testing.T) {
+	testCases := []struct {
+		name      string
+		policies  []v1beta1.RepoSyncPolicy
+		source    string
+		objs      []ast.FileObject
+		wantCount int
+	}{
+		{
+			name:   "no policies",
+			source: "https://github.com/other/repo",
+			objs:   []ast.FileObject{deployment(true), fake.Role()},
+		},
+		{
+			name: "policy satisfied",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				AllowedKinds:    []v1beta1.RepoSyncPolicyKind{{Group: "apps", Kind: "*"}},
+				DeniedKinds:     []v1beta1.RepoSyncPolicyKind{{Kind: "Secret"}},
+				MaxObjects:      1,
+				ForbiddenFields: []v1beta1.RepoSyncPolicyField{{Path: "$..hostNetwork"}},
+				AllowedSources:  []string{"https://github.com/acme/"},
+			})},
+			source: "https://github.com/acme/bookstore",
+			objs:   []ast.FileObject{deployment(false)},
+		},
+		{
+			name: "source not allowed",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				AllowedSources: []string{"https://github.com/acme/", "us-docker.pkg.dev/acme/"},
+			})},
+			source:    "https://github.com/other/repo",
+			objs:      []ast.FileObject{deployment(false)},
+			wantCount: 1,
+		},
+		{
+			name: "kinds not allowed or denied",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				AllowedKinds: []v1beta1.RepoSyncPolicyKind{{Group: "apps", Kind: "Deployment"}, {Kind: "ConfigMap"}},
+				DeniedKinds:  []v1beta1.RepoSyncPolicyKind{{Kind: "ConfigMap"}},
+			})},
+			objs:      []ast.FileObject{deployment(false), fake.Role(), fake.ConfigMap()},
+			wantCount: 2,
+		},
+		{
+			name: "too many objects",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				MaxObjects: 1,
+			})},
+			objs:      []ast.FileObject{deployment(false), fake.Role()},
+			wantCount: 1,
+		},
+		{
+			name: "forbidden field set",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				ForbiddenFields: []v1beta1.RepoSyncPolicyField{{
+					Kinds: []v1beta1.RepoSyncPolicyKind{{Group: "apps", Kind: "Deployment"}},
+					Path:  "$..hostNetwork",
+				}},
+			})},
+			objs:      []ast.FileObject{deployment(true), fake.Role()},
+			wantCount: 1,
+		},
+		{
+			name: "invalid forbidden field path",
+			policies: []v1beta1.RepoSyncPolicy{policy("restricted", v1beta1.RepoSyncPolicySpec{
+				ForbiddenFields: []v1beta1.RepoSyncPolicyField{{Path: "$[?("}},
+			})},
+			objs:      []ast.FileObject{deployment(false)},
+			wantCount: 1,
+		},
+		{
+			name: "every policy is evaluated",
+			policies: []v1beta1.RepoSyncPolicy{
+				policy("no-roles", v1beta1.RepoSyncPolicySpec{
+					DeniedKinds: []v1beta1.RepoSyncPolicyKind{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+				}),
+				policy("small", v1beta1.RepoSyncPolicySpec{MaxObjects: 1}),
+			},
+			objs:      []ast.FileObject{deployment(false), fake.Role()},
+			wantCount: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := Visitor(tc.policies, tc.source)(tc.objs)
+			var got []status.Error
+			if errs != nil {
+				got = errs.Errors()
+			}
+			if len(got) != tc.wantCount {
+				t.Fatalf("got %d errors, want %d: %v", len(got), tc.wantCount, errs)
+			}
+			for _, err := range got {
+				if code := err.Code(); code != ViolationErrorCode {
+					t.Errorf("got error code %s, want %s: %v", code, ViolationErrorCode, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSourceAllowed(t *testing.T) {
+	testCases := []struct {
+		name    string
+		allowed []string
+		source  string
+		want    bool
+	}{
+		{
+			name:   "no allowed sources",
+			source: "https://github.com/other/repo",
+			want:   true,
+		},
+		{
+			name:    "repository under the prefix",
+			allowed: []string{"https://github.com/acme"},
+			source:  "https://github.com/acme/repo",
+			want:    true,
+		},
+		{
+			name:    "repository equal to the prefix",
+			allowed: []string{"https://github.com/acme/repo"},
+			source:  "https://github.com/acme/repo",
+			want:    true,
+		},
+		{
+			name:    "repository of a sibling organization",
+			allowed: []string{"https://github.com/acme"},
+			source:  "https://github.com/acme-evil/repo",
+		},
+		{
+			name:    "repository of a sibling organization with a trailing slash in the prefix",
+			allowed: []string{"https://github.com/acme/"},
+			source:  "https://github.com/acme-evil/repo",
+		},
+		{
+			name:    "sibling repository",
+			allowed: []string{"https://github.com/acme/repo"},
+			source:  "https://github.com/acme/repo-evil",
+		},
+		{
+			name:    "scheme and host case are ignored",
+			allowed: []string{"HTTPS://GitHub.com/acme"},
+			source:  "https://github.com/acme/repo",
+			want:    true,
+		},
+		{
+			name:    "path case is not ignored",
+			allowed: []string{"https://github.com/Acme"},
+			source:  "https://github.com/acme/repo",
+		},
+		{
+			name:    "trailing slashes and .git suffixes are ignored",
+			allowed: []string{"https://github.com/acme/repo.git/"},
+			source:  "https://github.com/acme/repo/",
+			want:    true,
+		},
+		{
+			name:    "repository over SSH",
+			allowed: []string{"git@GitHub.com:acme"},
+			source:  "git@github.com:acme/repo.git",
+			want:    true,
+		},
+		{
+			name:    "OCI image under the prefix",
+			allowed: []string{"us-docker.pkg.dev/acme/"},
+			source:  "us-docker.pkg.dev/acme/configs/bookstore",
+			want:    true,
+		},
+		{
+			name:    "OCI image of a sibling project",
+			allowed: []string{"us-docker.pkg.dev/acme"},
+			source:  "us-docker.pkg.dev/acme-evil/configs/bookstore",
+		},
+		{
+			name:    "different scheme",
+			allowed: []string{"https://github.com/acme"},
+			source:  "http://github.com/acme/repo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := policy("restricted", v1beta1.RepoSyncPolicySpec{AllowedSources: tc.allowed})
+			if got := sourceAllowed(p, tc.source); got != tc.want {
+				t.Errorf("sourceAllowed(%v, %q) = %t, want %t", tc.allowed, tc.source, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestForNamespace(t *testing.T) {
+	all := policy("all", v1beta1.RepoSyncPolicySpec{})
+	bookstore := policy("bookstore", v1beta1.RepoSyncPolicySpec{Namespaces: []string{"bookstore", "shipping"}})
+	policies := []v1beta1.RepoSyncPolicy{all, bookstore}
+
+	if diff := cmp.Diff([]v1beta1.RepoSyncPolicy{all, bookstore}, ForNamespace(policies, "shipping")); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]v1beta1.RepoSyncPolicy{all}, ForNamespace(policies, "videostore")); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/metadata"
+)
+
+// Inventories too large to fit in a single ResourceGroup object are spread
+// across the primary ResourceGroup and additional ResourceGroup chunks, named
+// after the primary one. The primary ResourceGroup is annotated with the number
+// of chunks, and every chunk is labeled with the name of the primary one.
+
+// ChunkName returns the name of the chunk with the index, from 1, of the
+// ResourceGroup with the name.
+func ChunkName(name string, index int) string {
+	return fmt.Sprintf("%s-chunk-%d", name, index)
+}
+
+// ChunkCount returns the number of chunks the inventory of the primary
+// ResourceGroup is spread across, not including the primary one.
+func ChunkCount(rg *unstructured.Unstructured) int {
+	count, err := strconv.Atoi(rg.GetAnnotations()[metadata.InventoryChunksAnnotationKey])
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// ChunkOf returns the name of the primary ResourceGroup the ResourceGroup is a
+// chunk of, or false if it is not a chunk.
+func ChunkOf(rg *unstructured.Unstructured) (string, bool) {
+	name, found := rg.GetLabels()[metadata.InventoryChunkOfLabel]
+	return name, found && name != ""
+}
+
+// MergeChunks returns the ResourceGroups with the resources and the resource
+// statuses of every chunk merged into their primary ResourceGroup, and the
+// chunks removed. Chunks whose primary ResourceGroup is missing are dropped.
+// The primary ResourceGroups are copied before being modified.
+func MergeChunks(rgs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	return Merge(rgs, ChunkOf)
+}
+
+// Merge returns the ResourceGroups with the resources and the resource
+// statuses of the ResourceGroups for which partOf returns the name of another
+// ResourceGroup in the same namespace merged into that one. The merged
+// ResourceGroups are removed, and dropped if the other one is missing.
+// The ResourceGroups merged into are copied before being modified.
+func Merge(rgs []*unstructured.Unstructured, partOf func(*unstructured.Unstructured) (string, bool)) ([]*unstructured.Unstructured, error) {
+	chunks := make(map[string][]*unstructured.Unstructured)
+	var primaries []*unstructured.Unstructured
+	for _, rg := range rgs {
+		if name, ok := partOf(rg); ok {
+			key := rg.GetNamespace() + "/" + name
+			chunks[key] = append(chunks[key], rg)
+		} else {
+			primaries = append(primaries, rg)
+		}
+	}
+	if len(chunks) == 0 {
+		return rgs, nil
+	}
+
+	var result []*unstructured.Unstructured
+	for _, rg := range primaries {
+		rgChunks := chunks[rg.GetNamespace()+"/"+rg.GetName()]
+		if len(rgChunks) == 0 {
+			result = append(result, rg)
+			continue
+		}
+		merged := rg.DeepCopy()
+		for _, field := range [][]string{{"spec", "resources"}, {"status", "resourceStatuses"}} {
+			items, _, err := unstructured.NestedSlice(merged.Object, field...)
+			if err != nil {
+				return nil, err
+			}
+			for _, chunk := range rgChunks {
+				chunkItems, _, err := unstructured.NestedSlice(chunk.Object, field...)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, chunkItems...)
+			}
+			if len(items) == 0 {
+				continue
+			}
+			if err := unstructured.SetNestedSlice(merged.Object, items, field...); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, merged)
+	}
+	return result, nil
+}
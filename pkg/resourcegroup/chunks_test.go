@@ -0,0 +1,110 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/metadata"
+)
+
+func resourceGroup(name, chunkOf string, resources ...string) *unstructured.Unstructured {
+	rg := Unstructured(name, "config-management-system", "config-management-system_"+name)
+	if chunkOf != "" {
+		labels := rg.GetLabels()
+		labels[metadata.InventoryChunkOfLabel] = chunkOf
+		rg.SetLabels(labels)
+	}
+	var items, statuses []interface{}
+	for _, r := range resources {
+		items = append(items, map[string]interface{}{"kind": "ConfigMap", "name": r})
+		statuses = append(statuses, map[string]interface{}{"kind": "ConfigMap", "name": r, "status": "Current"})
+	}
+	if len(items) > 0 {
+		_ = unstructured.SetNestedSlice(rg.Object, items, "spec", "resources")
+		_ = unstructured.SetNestedSlice(rg.Object, statuses, "status", "resourceStatuses")
+	}
+	return rg
+}
+
+func TestMergeChunks(t *testing.T) {
+	testCases := []struct {
+		name string
+		rgs  []*unstructured.Unstructured
+		want []*unstructured.Unstructured
+	}{
+		{
+			name: "no chunks",
+			rgs:  []*unstructured.Unstructured{resourceGroup("root-sync", "", "a"), resourceGroup("other", "", "b")},
+			want: []*unstructured.Unstructured{resourceGroup("root-sync", "", "a"), resourceGroup("other", "", "b")},
+		},
+		{
+			name: "chunks are merged into their primary ResourceGroup",
+			rgs: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "", "a", "b"),
+				resourceGroup("root-sync-chunk-1", "root-sync", "c", "d"),
+				resourceGroup("other", "", "e"),
+				resourceGroup("root-sync-chunk-2", "root-sync", "f"),
+			},
+			want: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "", "a", "b", "c", "d", "f"),
+				resourceGroup("other", "", "e"),
+			},
+		},
+		{
+			name: "chunks without primary ResourceGroup are dropped",
+			rgs: []*unstructured.Unstructured{
+				resourceGroup("other", "", "e"),
+				resourceGroup("root-sync-chunk-1", "root-sync", "c"),
+			},
+			want: []*unstructured.Unstructured{resourceGroup("other", "", "e")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MergeChunks(tc.rgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestChunkCount(t *testing.T) {
+	testCases := []struct {
+		annotation string
+		want       int
+	}{
+		{annotation: "", want: 0},
+		{annotation: "3", want: 3},
+		{annotation: "-1", want: 0},
+		{annotation: "x", want: 0},
+	}
+	for _, tc := range testCases {
+		rg := resourceGroup("root-sync", "")
+		if tc.annotation != "" {
+			rg.SetAnnotations(map[string]string{metadata.InventoryChunksAnnotationKey: tc.annotation})
+		}
+		if got := ChunkCount(rg); got != tc.want {
+			t.Errorf("ChunkCount() with annotation %q = %d, want %d", tc.annotation, got, tc.want)
+		}
+	}
+}
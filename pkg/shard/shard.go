@@ -0,0 +1,181 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shard splits the objects declared in a RootSync across multiple
+// reconcilers.
+//
+// Every shard parses the whole source of truth, but only declares, applies and
+// remediates the objects assigned to it by a stable hash of their namespace or
+// GroupKind. Each shard tracks its objects in its own ResourceGroup inventory,
+// and releases the objects assigned to other shards from it without deleting
+// them, so that changing the number of shards or the key moves objects between
+// shards without recreating them.
+//
+// The first shard reports the source and rendering status of the RootSync.
+// Every shard reports its sync status in `.status.shards`, which is aggregated
+// into `.status.sync`.
+//
+// Objects are applied in dependency order within each shard only, so objects
+// depending on objects of other shards, such as custom resources of CRDs
+// assigned to other shards, may fail to apply until the next retry.
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/resourcegroup"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Shard identifies the subset of the objects of a RootSync handled by a
+// reconciler. The zero Shard handles every object.
+type Shard struct {
+	// Index is the index of the shard, from 0 to Count-1.
+	Index int
+	// Count is the number of shards of the RootSync.
+	Count int
+	// Key determines which shard an object is assigned to.
+	Key v1beta1.ShardingKey
+}
+
+// New returns the Shard with the index, after validating it.
+func New(index, count int, key v1beta1.ShardingKey) (Shard, error) {
+	if count < 1 {
+		count = 1
+	}
+	if key == "" {
+		key = v1beta1.ShardByNamespace
+	}
+	if index < 0 || index >= count {
+		return Shard{}, fmt.Errorf("shard index %d must be between 0 and %d", index, count-1)
+	}
+	if key != v1beta1.ShardByNamespace && key != v1beta1.ShardByGroupKind {
+		return Shard{}, fmt.Errorf("unknown sharding key %q, must be one of %s or %s", key, v1beta1.ShardByNamespace, v1beta1.ShardByGroupKind)
+	}
+	return Shard{Index: index, Count: count, Key: key}, nil
+}
+
+// Sharded returns true if the objects of the RootSync are split across more
+// than one shard.
+func (s Shard) Sharded() bool {
+	return s.Count > 1
+}
+
+// Primary returns true if the shard reports the source and rendering status
+// of the RootSync.
+func (s Shard) Primary() bool {
+	return s.Index == 0
+}
+
+// IndexOf returns the index of the shard the object is assigned to.
+func (s Shard) IndexOf(obj client.Object) int {
+	if !s.Sharded() {
+		return 0
+	}
+	var key string
+	gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+	switch {
+	case s.Key == v1beta1.ShardByGroupKind:
+		key = gk.String()
+	case gk == kinds.Namespace().GroupKind():
+		// Namespaces are assigned to the same shard as the objects in them, so
+		// they are applied before and pruned after them.
+		key = obj.GetName()
+	default:
+		key = obj.GetNamespace()
+	}
+	h := fnv.New32a()
+	// Writing to a hash never fails.
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(s.Count))
+}
+
+// Owns returns true if the object is assigned to the shard.
+func (s Shard) Owns(obj client.Object) bool {
+	return s.IndexOf(obj) == s.Index
+}
+
+// Partition splits the objects between the ones assigned to the shard and the
+// ones assigned to other shards. Nil objects are considered owned, so that
+// they are reported along with the objects of the shard.
+func (s Shard) Partition(objs []client.Object) (owned, others []client.Object) {
+	if !s.Sharded() {
+		return objs, nil
+	}
+	for _, obj := range objs {
+		if obj == nil || s.Owns(obj) {
+			owned = append(owned, obj)
+		} else {
+			others = append(others, obj)
+		}
+	}
+	return owned, others
+}
+
+// Name returns the name of the resource of the shard with the index, given
+// the name of the resource of the first shard. The first shard keeps the
+// names used by unsharded RootSyncs.
+func Name(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s-shard-%d", name, index)
+}
+
+// Index returns the index of the shard of the resource with the name, given
+// the name of the resource of the first shard. It returns false if the
+// resource does not belong to a shard of the first one.
+func Index(name, firstName string) (int, bool) {
+	if name == firstName {
+		return 0, true
+	}
+	suffix := strings.TrimPrefix(name, firstName+"-shard-")
+	if suffix == name {
+		return 0, false
+	}
+	index, err := strconv.Atoi(suffix)
+	if err != nil || index < 1 || Name(firstName, index) != name {
+		return 0, false
+	}
+	return index, true
+}
+
+// ResourceGroupOf returns the name of the ResourceGroup of the first shard of
+// the RootSync the ResourceGroup tracks another shard of, or false if it is not
+// the ResourceGroup of a shard other than the first one. The ResourceGroups of
+// the shards are labeled with the name of the RootSync, which is the name of
+// the ResourceGroup of the first shard.
+func ResourceGroupOf(rg *unstructured.Unstructured) (string, bool) {
+	name := rg.GetLabels()[metadata.SyncNameLabel]
+	if name == "" || name == rg.GetName() {
+		return "", false
+	}
+	_, ok := Index(rg.GetName(), name)
+	return name, ok
+}
+
+// MergeResourceGroups returns the ResourceGroups with the resources and the
+// resource statuses of the shards of sharded RootSyncs merged into the
+// ResourceGroup of their first shard, and the other shards removed. The chunks
+// of the ResourceGroups must be merged first.
+func MergeResourceGroups(rgs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	return resourcegroup.Merge(rgs, ResourceGroupOf)
+}
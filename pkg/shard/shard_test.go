@@ -0,0 +1,245 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/resourcegroup"
+	"kpt.dev/configsync/pkg/testing/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name    string
+		index   int
+		count   int
+		key     v1beta1.ShardingKey
+		want    Shard
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: Shard{Index: 0, Count: 1, Key: v1beta1.ShardByNamespace},
+		},
+		{
+			name:  "group kind",
+			index: 2,
+			count: 3,
+			key:   v1beta1.ShardByGroupKind,
+			want:  Shard{Index: 2, Count: 3, Key: v1beta1.ShardByGroupKind},
+		},
+		{
+			name:    "index out of range",
+			index:   3,
+			count:   3,
+			wantErr: true,
+		},
+		{
+			name:    "negative index",
+			index:   -1,
+			count:   3,
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			count:   3,
+			key:     "name",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := New(tc.index, tc.count, tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("New() got error %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("New() got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPartition(t *testing.T) {
+	var objs []client.Object
+	for i := 0; i < 20; i++ {
+		ns := fmt.Sprintf("ns-%d", i)
+		objs = append(objs,
+			fake.NamespaceObject(ns),
+			fake.ConfigMapObject(core.Namespace(ns), core.Name("cm")),
+			fake.RoleObject(core.Namespace(ns), core.Name("role")))
+	}
+	objs = append(objs, fake.ClusterRoleObject(core.Name("cluster-role")))
+
+	for _, key := range []v1beta1.ShardingKey{v1beta1.ShardByNamespace, v1beta1.ShardByGroupKind} {
+		t.Run(string(key), func(t *testing.T) {
+			const count = 3
+			seen := make(map[core.ID]int)
+			for i := 0; i < count; i++ {
+				s, err := New(i, count, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				owned, others := s.Partition(objs)
+				if len(owned)+len(others) != len(objs) {
+					t.Errorf("shard %d got %d owned and %d other objects, want %d objects", i, len(owned), len(others), len(objs))
+				}
+				for _, obj := range owned {
+					if !s.Owns(obj) || s.IndexOf(obj) != i {
+						t.Errorf("shard %d does not own %v", i, core.IDOf(obj))
+					}
+					seen[core.IDOf(obj)]++
+				}
+			}
+			// Every object is owned by exactly one shard.
+			for _, obj := range objs {
+				if n := seen[core.IDOf(obj)]; n != 1 {
+					t.Errorf("%v is owned by %d shards, want 1", core.IDOf(obj), n)
+				}
+			}
+		})
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	s := Shard{Count: 8, Key: v1beta1.ShardByNamespace}
+	for i := 0; i < 20; i++ {
+		ns := fmt.Sprintf("ns-%d", i)
+		want := s.IndexOf(fake.NamespaceObject(ns))
+		// Namespaces are in the same shard as the objects in them.
+		if got := s.IndexOf(fake.ConfigMapObject(core.Namespace(ns))); got != want {
+			t.Errorf("IndexOf(ConfigMap in %s) = %d, want %d", ns, got, want)
+		}
+		// The index is stable.
+		if got := s.IndexOf(fake.NamespaceObject(ns)); got != want {
+			t.Errorf("IndexOf(Namespace %s) = %d, want %d", ns, got, want)
+		}
+	}
+
+	s = Shard{Count: 8, Key: v1beta1.ShardByGroupKind}
+	want := s.IndexOf(fake.ConfigMapObject(core.Namespace("foo")))
+	if got := s.IndexOf(fake.ConfigMapObject(core.Namespace("bar"))); got != want {
+		t.Errorf("IndexOf(ConfigMap) = %d, want %d", got, want)
+	}
+
+	if got := (Shard{}).IndexOf(fake.ConfigMapObject()); got != 0 {
+		t.Errorf("IndexOf() of the zero Shard = %d, want 0", got)
+	}
+}
+
+func TestName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		index     int
+		want      string
+		wantIndex bool
+	}{
+		{name: "root-reconciler", index: 0, want: "root-reconciler", wantIndex: true},
+		{name: "root-reconciler", index: 3, want: "root-reconciler-shard-3", wantIndex: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			got := Name(tc.name, tc.index)
+			if got != tc.want {
+				t.Errorf("Name() = %q, want %q", got, tc.want)
+			}
+			index, ok := Index(got, tc.name)
+			if ok != tc.wantIndex || index != tc.index {
+				t.Errorf("Index() = %d, %t, want %d, %t", index, ok, tc.index, tc.wantIndex)
+			}
+		})
+	}
+
+	for _, name := range []string{"root-reconciler-foo", "root-reconciler-shard-", "root-reconciler-shard-0", "root-reconciler-shard-01", "root-reconciler-shard-x"} {
+		if index, ok := Index(name, "root-reconciler"); ok {
+			t.Errorf("Index(%q) = %d, want not a shard", name, index)
+		}
+	}
+}
+
+func resourceGroup(name, syncName string, resources ...string) *unstructured.Unstructured {
+	rg := resourcegroup.Unstructured(name, "config-management-system", "config-management-system_"+name)
+	if syncName != "" {
+		core.SetLabel(rg, metadata.SyncNameLabel, syncName)
+	}
+	var items []interface{}
+	for _, r := range resources {
+		items = append(items, map[string]interface{}{"kind": "ConfigMap", "name": r})
+	}
+	if len(items) > 0 {
+		_ = unstructured.SetNestedSlice(rg.Object, items, "spec", "resources")
+	}
+	return rg
+}
+
+func TestMergeResourceGroups(t *testing.T) {
+	testCases := []struct {
+		name string
+		rgs  []*unstructured.Unstructured
+		want []*unstructured.Unstructured
+	}{
+		{
+			name: "unsharded",
+			rgs:  []*unstructured.Unstructured{resourceGroup("root-sync", "root-sync", "a"), resourceGroup("other", "", "b")},
+			want: []*unstructured.Unstructured{resourceGroup("root-sync", "root-sync", "a"), resourceGroup("other", "", "b")},
+		},
+		{
+			name: "shards are merged into the first shard",
+			rgs: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "root-sync", "a"),
+				resourceGroup("root-sync-shard-1", "root-sync", "b", "c"),
+				resourceGroup("other", "", "d"),
+				resourceGroup("root-sync-shard-2", "root-sync"),
+			},
+			want: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "root-sync", "a", "b", "c"),
+				resourceGroup("other", "", "d"),
+			},
+		},
+		{
+			name: "ResourceGroups not named after a shard are kept",
+			rgs: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "root-sync", "a"),
+				resourceGroup("root-sync-extra", "root-sync", "b"),
+			},
+			want: []*unstructured.Unstructured{
+				resourceGroup("root-sync", "root-sync", "a"),
+				resourceGroup("root-sync-extra", "root-sync", "b"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MergeResourceGroups(tc.rgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -97,8 +97,11 @@ func format(err Error) string {
 	var sb strings.Builder
 	sb.WriteString(prefix(knv(err.Code())))
 	sb.WriteString(formatCyclicDepErr(err.Body()))
-	sb.WriteString("\n\n")
-	sb.WriteString(url(err.Code()))
+	// User-defined policy rules have no documentation.
+	if !IsPolicyViolationErrorCode(err.Code()) {
+		sb.WriteString("\n\n")
+		sb.WriteString(url(err.Code()))
+	}
 	return sb.String()
 }
 
@@ -32,6 +32,8 @@ import (
 // four digits of an error code have no  meaning except:
 // - 1XXX, the user has a mistake in their repository they need to fix.
 // - 2XXX, something went wrong in the cluster - it could be transient or users may need to change something on the cluster.
+// - 3XXX, the repository violates a user-defined policy rule. These codes are chosen by users and are
+//   never registered, see PolicyViolationError.
 // - 9998, InternalError, and
 // - 9999, UndocumentedError.
 //
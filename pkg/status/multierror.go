@@ -34,6 +34,7 @@ type MultiError interface {
 var nonBlockingErrorCodes = map[string]struct{}{
 	UnknownKindErrorCode:         {},
 	EncodeDeclaredFieldErrorCode: {},
+	InvalidPolicyRuleErrorCode:   {},
 }
 
 // HasBlockingErrors return whether `errs` include any blocking errors.
@@ -0,0 +1,58 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// InvalidPolicyRuleErrorCode is the error code for malformed user-defined
+	// policy rules. It does not block syncing, so a malformed rule only
+	// disables itself.
+	InvalidPolicyRuleErrorCode = "1069"
+
+	// PolicyViolationErrorCode is the default error code for violations of
+	// user-defined policy rules.
+	PolicyViolationErrorCode = "3000"
+
+	// MinPolicyViolationErrorCode and MaxPolicyViolationErrorCode bound the
+	// error codes users may choose for their policy rules. Codes in this range
+	// are never registered by Config Sync itself.
+	MinPolicyViolationErrorCode = 3000
+	MaxPolicyViolationErrorCode = 3999
+)
+
+// IsPolicyViolationErrorCode returns true if the code is in the range of the
+// error codes of user-defined policy rules.
+func IsPolicyViolationErrorCode(code string) bool {
+	c, err := strconv.Atoi(code)
+	return err == nil && c >= MinPolicyViolationErrorCode && c <= MaxPolicyViolationErrorCode
+}
+
+// PolicyViolationError reports that the resource violates the user-defined
+// policy rule. The code must be in the range of policy violation error codes,
+// otherwise the default PolicyViolationErrorCode is used.
+func PolicyViolationError(code, rule, message string, resource client.Object) ResourceError {
+	if !IsPolicyViolationErrorCode(code) {
+		code = PolicyViolationErrorCode
+	}
+	// The codes are chosen by users, so they are not registered.
+	return ErrorBuilder{error: baseErrorImpl{code: code}}.
+		Sprintf("The object violates the policy rule %q: %s", rule, message).
+		BuildWithResources(resource)
+}
@@ -59,6 +59,9 @@ type Applier interface {
 	RemoveNomosMeta(ctx context.Context, intent *unstructured.Unstructured, controller string) (bool, status.Error)
 	Delete(ctx context.Context, obj *unstructured.Unstructured) (bool, status.Error)
 	GetClient() client.Client
+	// FightErrors returns the errors of the resources the Applier is currently
+	// fighting over with some other process.
+	FightErrors() []status.Error
 }
 
 // clientApplier does apply operations on resources, client-side, using the same approach as running `kubectl apply`.
@@ -67,7 +70,7 @@ type clientApplier struct {
 	discoveryClient  discovery.DiscoveryInterface
 	openAPIResources openapi.Resources
 	client           *syncerclient.Client
-	fights           fightDetector
+	fights           *fightDetector
 	fLogger          fightLogger
 }
 
@@ -123,7 +126,7 @@ func (c *clientApplier) Create(ctx context.Context, intendedState *unstructured.
 		klog.V(3).Infof("Failed to create object %v: %v", core.GKNN(intendedState), err)
 		return false, err
 	}
-	if c.fights.detectFight(ctx, time.Now(), intendedState, &c.fLogger, "create") {
+	if c.fights.detectFight(ctx, time.Now(), intendedState, nil, &c.fLogger, "create") {
 		klog.Warningf("Fight detected on create of %s.", description(intendedState))
 	}
 	klog.V(3).Infof("Created object %v", core.GKNN(intendedState))
@@ -147,7 +150,7 @@ func (c *clientApplier) Update(ctx context.Context, intendedState, currentState
 
 	updated := !isNoOpPatch(patch)
 	if updated {
-		if c.fights.detectFight(ctx, time.Now(), intendedState, &c.fLogger, "update") {
+		if c.fights.detectFight(ctx, time.Now(), intendedState, currentState, &c.fLogger, "update") {
 			diff := cmp.Diff(currentState, intendedState)
 			klog.Warningf("Fight detected on update of %s with difference %s", description(intendedState), diff)
 		}
@@ -189,7 +192,7 @@ func (c *clientApplier) Delete(ctx context.Context, obj *unstructured.Unstructur
 		klog.V(3).Infof("Failed to delete object %v: %v", core.GKNN(obj), err)
 		return false, err
 	}
-	if c.fights.detectFight(ctx, time.Now(), obj, &c.fLogger, "delete") {
+	if c.fights.detectFight(ctx, time.Now(), obj, obj, &c.fLogger, "delete") {
 		klog.Warningf("Fight detected on delete of %s.", description(obj))
 	}
 	klog.V(3).Infof("Deleted object %v", core.GKNN(obj))
@@ -410,6 +413,11 @@ func (c *clientApplier) GetClient() client.Client {
 	return c.client.Client
 }
 
+// FightErrors implements Applier.
+func (c *clientApplier) FightErrors() []status.Error {
+	return c.fights.errors(time.Now())
+}
+
 func equal(dryrunState, currentState *unstructured.Unstructured) bool {
 	cleanFields := func(u *unstructured.Unstructured) {
 		u.SetGeneration(0)
@@ -15,15 +15,23 @@
 package reconcile
 
 import (
+	"bytes"
 	ctx "context"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/metadata"
 	m "kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/status"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
 // fightThreshold is the threshold of updates per minute at which we log to Info
@@ -40,14 +48,65 @@ func SetFightThreshold(updatesPerMinute float64) {
 	fightThreshold = updatesPerMinute
 }
 
-var fightWarningBuilder = status.NewErrorBuilder("2005")
+// FightWarningCode is the error code for a FightError.
+const FightWarningCode = "2005"
+
+var fightWarningBuilder = status.NewErrorBuilder(FightWarningCode)
+
+// unknownManager is the field manager recorded in the resource_fights metric
+// when the competing field manager could not be identified.
+const unknownManager = "unknown"
+
+// FightError is the error reported when the Syncer is fighting over a resource
+// with some other process. It identifies the competing field manager, such as
+// an HPA, an operator or a human using kubectl, if it is known.
+type FightError interface {
+	status.ResourceError
+	// Manager returns the name of the competing field manager, or "" if it is
+	// unknown.
+	Manager() string
+	// Fields returns the paths of the fields contested by the competing field
+	// manager.
+	Fields() []string
+}
+
+type fightError struct {
+	status.ResourceError
+	manager string
+	fields  []string
+}
+
+var _ FightError = fightError{}
+
+// Manager implements FightError.
+func (e fightError) Manager() string {
+	return e.manager
+}
+
+// Fields implements FightError.
+func (e fightError) Fields() []string {
+	return e.fields
+}
 
 // FightWarning represents when the Syncer is fighting over a resource with
-// some other process on a Kubernetes cluster.
-func FightWarning(frequency float64, resource client.Object) status.ResourceError {
-	return fightWarningBuilder.Sprintf("syncer excessively updating resource, approximately %d times per minute. "+
-		"This may indicate ACM is fighting with another controller over the resource.", int(frequency)).
-		BuildWithResources(resource)
+// some other process on a Kubernetes cluster. manager and fields are the
+// competing field manager and the fields it contests, if they are known.
+func FightWarning(frequency float64, resource client.Object, manager string, fields []string) FightError {
+	var competitor string
+	if manager != "" {
+		competitor = fmt.Sprintf(" The competing field manager is %q", manager)
+		if len(fields) > 0 {
+			competitor += ", contesting the fields " + strings.Join(fields, ", ")
+		}
+		competitor += "."
+	}
+	return fightError{
+		ResourceError: fightWarningBuilder.Sprintf("syncer excessively updating resource, approximately %d times per minute. "+
+			"This may indicate ACM is fighting with another controller over the resource.%s", int(frequency), competitor).
+			BuildWithResources(resource),
+		manager: manager,
+		fields:  fields,
+	}
 }
 
 // fightDetector uses a linear differential equation to estimate the frequency
@@ -57,28 +116,67 @@ func FightWarning(frequency float64, resource client.Object) status.ResourceErro
 // Instantiate with newFightDetector().
 //
 // Performance characteristics:
-// 1. Current implementation is NOT threadsafe.
+// 1. Current implementation is threadsafe.
 // 2. Current implementation has unbounded memory usage on the order of the
 //   number of objects the Syncer updates through its lifetime.
 // 3. Updating an already-tracked resource requires no memory allocations and
 //   take approximately 30ns, ignoring logging time.
 type fightDetector struct {
+	// The following fields are guarded by the mutex.
+	mux sync.Mutex
 	// fights is a record of how much the Syncer is fighting over any given
 	// API resource.
 	fights map[gknn]*fight
+	// errs is the latest FightError of each resource the Syncer has been
+	// fighting over.
+	errs map[gknn]FightError
 }
 
-func newFightDetector() fightDetector {
-	return fightDetector{
+func newFightDetector() *fightDetector {
+	return &fightDetector{
 		fights: make(map[gknn]*fight),
+		errs:   make(map[gknn]FightError),
+	}
+}
+
+// errors returns the FightErrors of the resources the Syncer is still fighting
+// over at time `now`, sorted by resource. The errors of the fights which have
+// cooled down below `fightThreshold` are forgotten.
+func (d *fightDetector) errors(now time.Time) []status.Error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	var ids []gknn
+	for i := range d.errs {
+		if d.fights[i].frequency(now) < fightThreshold {
+			delete(d.errs, i)
+			continue
+		}
+		ids = append(ids, i)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return ids[a].String() < ids[b].String()
+	})
+
+	var errs []status.Error
+	for _, i := range ids {
+		errs = append(errs, d.errs[i])
 	}
+	return errs
 }
 
 // detectFight detects whether the resource is needing updates too frequently.
 // If so, it increments the resource_fights metric and logs to klog.Warning.
-func (d *fightDetector) detectFight(ctx ctx.Context, time time.Time, obj *unstructured.Unstructured, fLogger *fightLogger, operation string) bool {
-	if fight := d.markUpdated(time, obj); fight != nil {
-		m.RecordResourceFight(ctx, operation, obj.GroupVersionKind())
+//
+// live is the state of the resource on the API Server before the Syncer
+// updated it, which identifies the competing field manager. It may be nil.
+func (d *fightDetector) detectFight(ctx ctx.Context, time time.Time, obj, live *unstructured.Unstructured, fLogger *fightLogger, operation string) bool {
+	if fight := d.markUpdated(time, obj, live); fight != nil {
+		manager := fight.Manager()
+		if manager == "" {
+			manager = unknownManager
+		}
+		m.RecordResourceFight(ctx, operation, obj.GroupVersionKind(), manager)
 		if fLogger.logFight(time, fight) {
 			return true
 		}
@@ -87,24 +185,100 @@ func (d *fightDetector) detectFight(ctx ctx.Context, time time.Time, obj *unstru
 }
 
 // markUpdated marks that API resource `resource` was updated at time `now`.
-// Returns a ResourceError if the estimated frequency of updates is greater than
-// `fightThreshold`.
-func (d *fightDetector) markUpdated(now time.Time, resource client.Object) status.ResourceError {
+// Returns a FightError if the estimated frequency of updates is greater than
+// `fightThreshold`, naming the competing field manager found in the
+// managedFields of `live`.
+func (d *fightDetector) markUpdated(now time.Time, resource client.Object, live *unstructured.Unstructured) FightError {
 	i := gknn{
 		gk:        resource.GetObjectKind().GroupVersionKind().GroupKind(),
 		namespace: resource.GetNamespace(),
 		name:      resource.GetName(),
 	}
 
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
 	if d.fights[i] == nil {
 		d.fights[i] = &fight{}
 	}
 	if frequency := d.fights[i].markUpdated(now); frequency >= fightThreshold {
-		return FightWarning(frequency, resource)
+		manager, fields := competingManager(resource, live)
+		err := FightWarning(frequency, resource, manager, fields)
+		d.errs[i] = err
+		return err
 	}
 	return nil
 }
 
+// competingManager returns the field manager of `live` which most recently
+// wrote any of the fields Config Sync declares for `resource`, and the paths of
+// the declared fields it manages. If the declared fields are unknown, it
+// returns the field manager which most recently wrote `live` and the paths of
+// all the fields it manages. Config Sync itself and writes to subresources,
+// such as status, are ignored.
+//
+// Returns "" if no other field manager manages the resource.
+func competingManager(resource client.Object, live *unstructured.Unstructured) (string, []string) {
+	if live == nil {
+		return "", nil
+	}
+	declared := declaredFields(resource)
+	if declared == nil {
+		declared = declaredFields(live)
+	}
+
+	var manager string
+	var contested *fieldpath.Set
+	var latest time.Time
+	for _, entry := range live.GetManagedFields() {
+		if entry.Manager == configsync.FieldManager || entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			continue
+		}
+		if declared != nil {
+			fields = fields.Intersection(declared)
+		}
+		fields = fields.Leaves()
+		if fields.Empty() {
+			continue
+		}
+		var t time.Time
+		if entry.Time != nil {
+			t = entry.Time.Time
+		}
+		if manager == "" || t.After(latest) {
+			manager, contested, latest = entry.Manager, fields, t
+		}
+	}
+	if manager == "" {
+		return "", nil
+	}
+
+	var paths []string
+	contested.Iterate(func(p fieldpath.Path) {
+		paths = append(paths, p.String())
+	})
+	sort.Strings(paths)
+	return manager, paths
+}
+
+// declaredFields returns the fields Config Sync declares for the resource, or
+// nil if they are unknown.
+func declaredFields(resource client.Object) *fieldpath.Set {
+	decls, ok := resource.GetAnnotations()[metadata.DeclaredFieldsKey]
+	if !ok {
+		return nil
+	}
+	set := &fieldpath.Set{}
+	if err := set.FromJSON(strings.NewReader(decls)); err != nil {
+		return nil
+	}
+	return set
+}
+
 // gknn uniquely identifies a resource on the API Server with the resource's
 // Group, Kind, Namespace, and Name.
 type gknn struct {
@@ -114,6 +288,11 @@ type gknn struct {
 	namespace, name string
 }
 
+// String returns the resource identifier in the form "group/kind namespace/name".
+func (i gknn) String() string {
+	return fmt.Sprintf("%s %s/%s", i.gk, i.namespace, i.name)
+}
+
 // fight estimates how often a specific API resource is updated by the Syncer.
 type fight struct {
 	// heat is an estimate of the number of times a resource is updated per minute.
@@ -156,3 +335,10 @@ func (f *fight) markUpdated(now time.Time) float64 {
 	f.heat++
 	return f.heat
 }
+
+// frequency returns the estimated frequency of updates per minute at time
+// now, without marking the resource as updated.
+func (f *fight) frequency(now time.Time) float64 {
+	d := math.Max(0.0, now.Sub(f.last).Minutes())
+	return f.heat * math.Exp(-d)
+}
@@ -19,11 +19,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/kinds"
+	csmetadata "kpt.dev/configsync/pkg/metadata"
 	"kpt.dev/configsync/pkg/metrics"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/testing/testmetrics"
@@ -193,7 +199,7 @@ func TestFightDetector(t *testing.T) {
 
 				aboveThreshold := false
 				for _, update := range updates {
-					fight := fd.markUpdated(now.Add(update), u)
+					fight := fd.markUpdated(now.Add(update), u, nil)
 					aboveThreshold = aboveThreshold || fight != nil
 				}
 				if tc.wantAboveThreshold[o] && !aboveThreshold {
@@ -206,6 +212,26 @@ func TestFightDetector(t *testing.T) {
 	}
 }
 
+func TestFightDetectorErrors(t *testing.T) {
+	SetFightThreshold(5.0)
+	fd := newFightDetector()
+	u := fake.UnstructuredObject(kinds.Role(), core.Name("admin"), core.Namespace("foo"))
+
+	now := time.Now()
+	for i := 0; i < 6; i++ {
+		fd.markUpdated(now, u, nil)
+	}
+	errs := fd.errors(now)
+	if len(errs) != 1 || errs[0].Code() != FightWarningCode {
+		t.Fatalf("got errors() = %v, want one fight error", errs)
+	}
+
+	// The fight cools down below the threshold once the updates stop.
+	if errs := fd.errors(now.Add(time.Minute)); len(errs) != 0 {
+		t.Errorf("got errors() = %v after the fight cooled down, want none", errs)
+	}
+}
+
 func TestResourceFightsMetricValidation(t *testing.T) {
 	roleGVK := kinds.Role().GroupKind().WithVersion("")
 	roleBindingGVK := kinds.RoleBinding().GroupKind().WithVersion("")
@@ -215,6 +241,7 @@ func TestResourceFightsMetricValidation(t *testing.T) {
 		fightThreshold float64
 		operations     []string
 		gvk            schema.GroupVersionKind
+		live           *unstructured.Unstructured
 		wantMetrics    []*view.Row
 	}{
 		{
@@ -224,8 +251,7 @@ func TestResourceFightsMetricValidation(t *testing.T) {
 			gvk:            roleGVK,
 			wantMetrics: []*view.Row{
 				{Data: &view.CountData{Value: 1}, Tags: []tag.Tag{
-					{Key: metrics.KeyOperation, Value: "create"},
-					{Key: metrics.KeyType, Value: "Role"}}},
+					{Key: metrics.KeyFieldManager, Value: "unknown"}}},
 			},
 		},
 		{
@@ -235,8 +261,19 @@ func TestResourceFightsMetricValidation(t *testing.T) {
 			gvk:            roleBindingGVK,
 			wantMetrics: []*view.Row{
 				{Data: &view.CountData{Value: 2}, Tags: []tag.Tag{
-					{Key: metrics.KeyOperation, Value: "delete"},
-					{Key: metrics.KeyType, Value: "RoleBinding"}}},
+					{Key: metrics.KeyFieldManager, Value: "unknown"}}},
+			},
+		},
+		{
+			name:           "fight with a known field manager detected while updating Role",
+			fightThreshold: 0,
+			operations:     []string{"update"},
+			gvk:            roleGVK,
+			live: withManagedFields(fake.UnstructuredObject(roleGVK),
+				managedFieldsEntry("kubectl-edit", time.Now(), `{"f:rules":{}}`)),
+			wantMetrics: []*view.Row{
+				{Data: &view.CountData{Value: 1}, Tags: []tag.Tag{
+					{Key: metrics.KeyFieldManager, Value: "kubectl-edit"}}},
 			},
 		},
 		{
@@ -256,7 +293,7 @@ func TestResourceFightsMetricValidation(t *testing.T) {
 			u := fake.UnstructuredObject(tc.gvk)
 
 			for _, op := range tc.operations {
-				fd.detectFight(context.Background(), time.Now(), u, &fl, op)
+				fd.detectFight(context.Background(), time.Now(), u, tc.live, &fl, op)
 			}
 
 			if diff := m.ValidateMetrics(metrics.ResourceFightsView, tc.wantMetrics); diff != "" {
@@ -265,3 +302,132 @@ func TestResourceFightsMetricValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestCompetingManager(t *testing.T) {
+	deploymentGVK := kinds.Deployment()
+	now := time.Now()
+	declared := core.Annotation(csmetadata.DeclaredFieldsKey, `{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{}}}}}`)
+	testCases := []struct {
+		name        string
+		declared    *unstructured.Unstructured
+		live        *unstructured.Unstructured
+		wantManager string
+		wantFields  []string
+	}{
+		{
+			name:     "no live object",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+		},
+		{
+			name:     "only managed by Config Sync",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				managedFieldsEntry(configsync.FieldManager, now, `{"f:spec":{"f:replicas":{}}}`)),
+		},
+		{
+			name:     "HPA contesting the declared replicas",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				managedFieldsEntry(configsync.FieldManager, now, `{"f:spec":{"f:template":{"f:spec":{"f:containers":{}}}}}`),
+				managedFieldsEntry("kube-controller-manager", now, `{"f:spec":{"f:replicas":{}}}`)),
+			wantManager: "kube-controller-manager",
+			wantFields:  []string{".spec.replicas"},
+		},
+		{
+			name:     "undeclared fields are not contested",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				managedFieldsEntry("kubectl-label", now, `{"f:metadata":{"f:labels":{"f:team":{}}}}`)),
+		},
+		{
+			name:     "most recent competing field manager",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				managedFieldsEntry("kube-controller-manager", now.Add(-time.Hour), `{"f:spec":{"f:replicas":{}}}`),
+				managedFieldsEntry("kubectl-edit", now, `{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{}}}}}`)),
+			wantManager: "kubectl-edit",
+			wantFields:  []string{".spec.replicas", ".spec.template.spec.containers"},
+		},
+		{
+			name:     "status subresource is ignored",
+			declared: fake.UnstructuredObject(deploymentGVK, declared),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				subresourceEntry(managedFieldsEntry("kube-controller-manager", now, `{"f:spec":{"f:replicas":{}}}`), "status")),
+		},
+		{
+			name:     "all fields of the latest manager without declared fields",
+			declared: fake.UnstructuredObject(deploymentGVK),
+			live: withManagedFields(fake.UnstructuredObject(deploymentGVK),
+				managedFieldsEntry("my-operator", now, `{"f:metadata":{"f:labels":{"f:team":{}}},"f:spec":{"f:replicas":{}}}`)),
+			wantManager: "my-operator",
+			wantFields:  []string{".metadata.labels.team", ".spec.replicas"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager, fields := competingManager(tc.declared, tc.live)
+			if manager != tc.wantManager {
+				t.Errorf("got manager %q, want %q", manager, tc.wantManager)
+			}
+			if diff := cmp.Diff(tc.wantFields, fields); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFightWarning(t *testing.T) {
+	obj := fake.UnstructuredObject(kinds.Deployment(), core.Name("frontend"), core.Namespace("bookstore"))
+	err := FightWarning(9.5, obj, "kube-controller-manager", []string{".spec.replicas"})
+
+	if err.Manager() != "kube-controller-manager" {
+		t.Errorf("got Manager() %q, want %q", err.Manager(), "kube-controller-manager")
+	}
+	if diff := cmp.Diff([]string{".spec.replicas"}, err.Fields()); diff != "" {
+		t.Error(diff)
+	}
+
+	want := v1beta1.ConfigSyncError{
+		Code: FightWarningCode,
+		ErrorMessage: `KNV2005: syncer excessively updating resource, approximately 9 times per minute. ` +
+			`This may indicate ACM is fighting with another controller over the resource. ` +
+			`The competing field manager is "kube-controller-manager", contesting the fields .spec.replicas.
+
+namespace: bookstore
+metadata.name: frontend
+group: apps
+version: v1
+kind: Deployment
+
+For more information, see https://g.co/cloud/acm-errors#knv2005`,
+		Resources: []v1beta1.ResourceRef{{
+			Name:      "frontend",
+			Namespace: "bookstore",
+			GVK:       metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		}},
+	}
+	if diff := cmp.Diff(want, err.ToCSE()); diff != "" {
+		t.Errorf("ToCSE() diff (- want, + got):\n%s", diff)
+	}
+}
+
+func managedFieldsEntry(manager string, t time.Time, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		Time:       &metav1.Time{Time: t},
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func subresourceEntry(entry metav1.ManagedFieldsEntry, subresource string) metav1.ManagedFieldsEntry {
+	entry.Subresource = subresource
+	return entry
+}
+
+func withManagedFields(u *unstructured.Unstructured, entries ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	u.SetManagedFields(entries)
+	return u
+}
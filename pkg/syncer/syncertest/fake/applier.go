@@ -30,6 +30,8 @@ import (
 type Applier struct {
 	Client      *Client
 	UpdateError error
+	// Fights are the errors returned by FightErrors.
+	Fights []status.Error
 }
 
 var _ reconcile.Applier = &Applier{}
@@ -84,3 +86,8 @@ func (a *Applier) Delete(ctx context.Context, obj *unstructured.Unstructured) (b
 func (a *Applier) GetClient() client.Client {
 	return a.Client
 }
+
+// FightErrors implements reconcile.Applier.
+func (a *Applier) FightErrors() []status.Error {
+	return a.Fights
+}
@@ -632,8 +632,35 @@ func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patc
 }
 
 // DeleteAllOf implements client.Client.
-func (c *Client) DeleteAllOf(_ context.Context, _ client.Object, _ ...client.DeleteAllOfOption) error {
-	return errors.New("fake.Client does not support DeleteAllOf()")
+func (c *Client) DeleteAllOf(_ context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	options := client.DeleteAllOfOptions{}
+	options.ApplyOptions(opts)
+	err := validateDeleteOptions(options.DeleteOptions)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := kinds.Lookup(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	for _, cachedObj := range c.list(gvk.GroupKind()) {
+		ok, err := c.matchesListFilters(cachedObj, &options.ListOptions)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		id := c.idFromObject(cachedObj)
+		klog.V(5).Infof("Deleting %T %s (Generation: %v, ResourceVersion: %q): %s",
+			cachedObj, client.ObjectKeyFromObject(cachedObj),
+			cachedObj.GetGeneration(), cachedObj.GetResourceVersion(),
+			log.AsJSON(cachedObj))
+		c.deleteManagedObjects(id)
+		delete(c.Objects, id)
+	}
+	return nil
 }
 
 // Update implements client.StatusWriter. It only updates the status field.
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -73,7 +74,8 @@ func NewDynamicClient(t *testing.T, scheme *runtime.Scheme) *DynamicClient {
 		fakeClient.PrependReactor("update", resource, dc.update)
 		fakeClient.PrependReactor("patch", resource, dc.patch)
 		fakeClient.PrependReactor("delete", resource, dc.delete)
-		// TODO: add support for create, list, delete-collection, and watch, if needed
+		fakeClient.PrependReactor("list", resource, dc.list)
+		// TODO: add support for create, delete-collection, and watch, if needed
 	}
 	return dc
 }
@@ -258,6 +260,31 @@ func (dc *DynamicClient) delete(action clienttesting.Action) (bool, runtime.Obje
 	return true, nil, nil
 }
 
+func (dc *DynamicClient) list(action clienttesting.Action) (bool, runtime.Object, error) {
+	listAction := action.(clienttesting.ListAction)
+	gvk, err := dc.mapper.KindFor(listAction.GetResource())
+	if err != nil {
+		return true, nil, fmt.Errorf("failed to lookup kind for resource: %w", err)
+	}
+	selector := listAction.GetListRestrictions().Labels
+	uList := &unstructured.UnstructuredList{}
+	uList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	for id, cachedObj := range dc.objects {
+		if id.GroupKind != gvk.GroupKind() {
+			continue
+		}
+		if listAction.GetNamespace() != "" && id.Namespace != listAction.GetNamespace() {
+			continue
+		}
+		if selector != nil && !selector.Matches(labels.Set(cachedObj.GetLabels())) {
+			continue
+		}
+		uList.Items = append(uList.Items, *cachedObj.DeepCopy())
+	}
+	klog.V(5).Infof("Listing %s: %d objects", gvk.Kind, len(uList.Items))
+	return true, uList, nil
+}
+
 func genID(namespace, name string, gk schema.GroupKind) core.ID {
 	return core.ID{
 		GroupKind: gk,
@@ -0,0 +1,149 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing records trace spans for the steps of a sync, so slow syncs
+// can be broken down into fetching, rendering, parsing and applying.
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"time"
+
+	"contrib.go.opencensus.io/exporter/ocagent"
+	"go.opencensus.io/trace"
+)
+
+// Attributes added to the spans.
+const (
+	// AttrReconciler is the name of the reconciler Deployment.
+	AttrReconciler = "configsync.reconciler"
+	// AttrSyncName is the name of the RootSync or RepoSync.
+	AttrSyncName = "configsync.sync.name"
+	// AttrCommit is the source commit being synced.
+	AttrCommit = "configsync.commit"
+	// AttrTrigger is what triggered the sync.
+	AttrTrigger = "configsync.trigger"
+	// AttrObject is the ID of the object being processed.
+	AttrObject = "configsync.object"
+)
+
+// CommitSpanName is the name of the root span of the trace of a commit.
+const CommitSpanName = "sync.commit"
+
+// exporter is the exporter registered by RegisterOCAgentExporter. It also
+// exports the root spans of the traces of the commits, which are not recorded
+// with OpenCensus.
+var exporter trace.Exporter
+
+// RegisterOCAgentExporter creates an OC Agent trace exporter which sends the
+// spans to the specified endpoint, for example an OpenTelemetry Collector with
+// the opencensus receiver enabled, which can forward them over OTLP.
+//
+// Tracing is disabled and a nil exporter is returned if the endpoint is empty.
+func RegisterOCAgentExporter(serviceName, endpoint string) (*ocagent.Exporter, error) {
+	if endpoint == "" {
+		return nil, nil
+	}
+	oce, err := ocagent.NewExporter(
+		ocagent.WithInsecure(),
+		ocagent.WithAddress(endpoint),
+		ocagent.WithServiceName(serviceName),
+	)
+	if err != nil {
+		return nil, err
+	}
+	trace.RegisterExporter(oce)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	exporter = oce
+	return oce, nil
+}
+
+// CommitSpanContext returns the context of the root span of the trace of a
+// commit synced by a reconciler.
+//
+// The trace is derived from the reconciler name and the commit, so that the
+// containers of the reconciler Pod, like the hydration-controller and the
+// reconciler, add their spans to the same trace without exchanging any
+// context.
+func CommitSpanContext(reconcilerName, commit string) trace.SpanContext {
+	sum := sha256.Sum256([]byte(reconcilerName + "/" + commit))
+	sc := trace.SpanContext{TraceOptions: sampled}
+	copy(sc.TraceID[:], sum[:16])
+	copy(sc.SpanID[:], sum[16:24])
+	return sc
+}
+
+// sampled is the TraceOptions of the spans which are exported.
+const sampled = trace.TraceOptions(1)
+
+// StartCommitSpan starts a child span of the root span of the trace of the
+// commit synced by the reconciler.
+func StartCommitSpan(ctx context.Context, name, reconcilerName, commit string) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpanWithRemoteParent(ctx, name, CommitSpanContext(reconcilerName, commit))
+	span.AddAttributes(
+		trace.StringAttribute(AttrReconciler, reconcilerName),
+		trace.StringAttribute(AttrCommit, commit),
+	)
+	return ctx, span
+}
+
+// ExportCommitSpan exports the root span of the trace of the commit synced by
+// the reconciler. OpenCensus cannot record a span with the ID derived by
+// CommitSpanContext, so the root span is exported directly to the registered
+// exporter, if any.
+//
+// It must be called once per commit, by a single container of the reconciler
+// Pod, otherwise the trace has several root spans.
+func ExportCommitSpan(reconcilerName, syncName, commit string, start, end time.Time) {
+	if exporter == nil {
+		return
+	}
+	exporter.ExportSpan(&trace.SpanData{
+		SpanContext: CommitSpanContext(reconcilerName, commit),
+		Name:        CommitSpanName,
+		StartTime:   start,
+		EndTime:     end,
+		Attributes: map[string]interface{}{
+			AttrReconciler: reconcilerName,
+			AttrSyncName:   syncName,
+			AttrCommit:     commit,
+		},
+	})
+}
+
+// WithoutSpans returns a copy of the context in which the spans started by
+// StartSpan are not recorded.
+func WithoutSpans(ctx context.Context) context.Context {
+	// The children of a local span inherit its sampling decision.
+	ctx, _ = trace.StartSpan(ctx, "unsampled", trace.WithSampler(trace.NeverSample()))
+	return ctx
+}
+
+// StartSpan starts a child span of the span in the context, if any.
+func StartSpan(ctx context.Context, name string, attrs ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	if len(attrs) > 0 {
+		span.AddAttributes(attrs...)
+	}
+	return ctx, span
+}
+
+// EndSpan records the error, if any, as the status of the span and ends it.
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/trace"
+)
+
+func TestCommitSpanContext(t *testing.T) {
+	sc := CommitSpanContext("root-reconciler", "abc123")
+	assert.Equal(t, sc, CommitSpanContext("root-reconciler", "abc123"),
+		"the same commit of the same reconciler should map to the same trace")
+	assert.NotEqual(t, sc.TraceID, CommitSpanContext("root-reconciler", "def456").TraceID,
+		"different commits should map to different traces")
+	assert.NotEqual(t, sc.TraceID, CommitSpanContext("ns-reconciler-bookstore", "abc123").TraceID,
+		"different reconcilers should map to different traces")
+}
+
+func TestStartCommitSpan(t *testing.T) {
+	ctx, parent := StartCommitSpan(context.Background(), "parent", "root-reconciler", "abc123")
+	_, child := StartSpan(ctx, "child")
+	defer EndSpan(parent, nil)
+	defer EndSpan(child, nil)
+
+	want := CommitSpanContext("root-reconciler", "abc123").TraceID
+	assert.Equal(t, want, parent.SpanContext().TraceID)
+	assert.Equal(t, want, child.SpanContext().TraceID)
+}
+
+func TestRegisterOCAgentExporterDisabled(t *testing.T) {
+	exporter, err := RegisterOCAgentExporter("reconciler", "")
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+}
+
+type fakeExporter struct {
+	mux   sync.Mutex
+	spans []*trace.SpanData
+}
+
+func (e *fakeExporter) ExportSpan(sd *trace.SpanData) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.spans = append(e.spans, sd)
+}
+
+func registerFakeExporter(t *testing.T) *fakeExporter {
+	e := &fakeExporter{}
+	trace.RegisterExporter(e)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	exporter = e
+	t.Cleanup(func() {
+		trace.UnregisterExporter(e)
+		exporter = nil
+	})
+	return e
+}
+
+func TestExportCommitSpan(t *testing.T) {
+	e := registerFakeExporter(t)
+
+	start := time.Now()
+	ctx, span := StartCommitSpan(context.Background(), "parse.run", "root-reconciler", "abc123")
+	_, child := StartSpan(ctx, "parse.read")
+	EndSpan(child, nil)
+	EndSpan(span, nil)
+	ExportCommitSpan("root-reconciler", "root-sync", "abc123", start, time.Now())
+
+	require.Len(t, e.spans, 3)
+	read, run, root := e.spans[0], e.spans[1], e.spans[2]
+	sc := CommitSpanContext("root-reconciler", "abc123")
+	assert.Equal(t, CommitSpanName, root.Name)
+	assert.Equal(t, sc, root.SpanContext)
+	assert.True(t, root.IsSampled())
+	assert.Equal(t, trace.SpanID{}, root.ParentSpanID, "the root span should have no parent")
+	assert.Equal(t, sc.SpanID, run.ParentSpanID, "the commit spans should be children of the exported root span")
+	assert.Equal(t, run.SpanID, read.ParentSpanID)
+	assert.Equal(t, sc.TraceID, read.TraceID)
+}
+
+func TestWithoutSpans(t *testing.T) {
+	e := registerFakeExporter(t)
+
+	_, span := StartSpan(WithoutSpans(context.Background()), "parse.read")
+	EndSpan(span, nil)
+
+	assert.Empty(t, e.spans)
+}
@@ -12,7 +12,9 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package controllers
+// Package jsonpath evaluates JSONPath expressions against unstructured
+// objects.
+package jsonpath
 
 import (
 	"encoding/json"
@@ -23,23 +25,33 @@ import (
 	"k8s.io/klog/v2"
 )
 
-// deleteFields evaluates the JSONPath expression to delete fields from the input map.
-func deleteFields(obj map[string]interface{}, expression string) error {
+// Nodes evaluates the JSONPath expression against the input map, and returns
+// the root node of the map and the nodes that match the expression.
+func Nodes(obj map[string]interface{}, expression string) (*ajson.Node, []*ajson.Node, error) {
 	// format input object as json for input into jsonpath library
 	jsonBytes, err := json.Marshal(obj)
 	if err != nil {
-		return fmt.Errorf("failed to marshal input to json: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal input to json: %w", err)
 	}
 	// parse json into an ajson node
 	root, err := ajson.Unmarshal(jsonBytes)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal input json: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal input json: %w", err)
 	}
 
 	// find nodes that match the expression
 	nodes, err := root.JSONPath(expression)
 	if err != nil {
-		return fmt.Errorf("failed to evaluate jsonpath expression (%s): %w", expression, err)
+		return nil, nil, fmt.Errorf("failed to evaluate jsonpath expression (%s): %w", expression, err)
+	}
+	return root, nodes, nil
+}
+
+// DeleteFields evaluates the JSONPath expression to delete fields from the input map.
+func DeleteFields(obj map[string]interface{}, expression string) error {
+	root, nodes, err := Nodes(obj, expression)
+	if err != nil {
+		return err
 	}
 
 	if len(nodes) == 0 {
@@ -53,7 +65,7 @@ func deleteFields(obj map[string]interface{}, expression string) error {
 		}
 	}
 
-	jsonBytes, err = ajson.Marshal(root)
+	jsonBytes, err := ajson.Marshal(root)
 	if err != nil {
 		return fmt.Errorf("failed to marshal jsonpath result to json: %w", err)
 	}
@@ -12,15 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package controllers
+package jsonpath
 
 import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
-	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/cli-utils/pkg/jsonpath"
+	clijsonpath "sigs.k8s.io/cli-utils/pkg/jsonpath"
 	"sigs.k8s.io/yaml"
 )
 
@@ -105,12 +104,12 @@ func TestDeleteFields(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			testCtx := []interface{}{"path: %s\nobject:\n%s", tc.path, toYaml(t, tc.obj.Object)}
-			values, err := jsonpath.Get(tc.obj.Object, tc.path)
+			values, err := clijsonpath.Get(tc.obj.Object, tc.path)
 			require.NoError(t, err, testCtx...)
 			require.Equal(t, tc.values, values, testCtx...)
-			err = deleteFields(tc.obj.Object, tc.path)
+			err = DeleteFields(tc.obj.Object, tc.path)
 			require.NoError(t, err, testCtx...)
-			values, err = jsonpath.Get(tc.obj.Object, tc.path)
+			values, err = clijsonpath.Get(tc.obj.Object, tc.path)
 			require.NoError(t, err, testCtx...)
 			require.Equal(t, []interface{}{}, values, testCtx...)
 		})
@@ -132,13 +131,3 @@ func yamlToUnstructured(t *testing.T, yml string) *unstructured.Unstructured {
 	}
 	return &unstructured.Unstructured{Object: m}
 }
-
-func yamlToDeployment(t *testing.T, yml string) *appsv1.Deployment {
-	d := &appsv1.Deployment{}
-	err := yaml.Unmarshal([]byte(yml), d)
-	if err != nil {
-		t.Fatalf("error parsing yaml: %v", err)
-		return nil
-	}
-	return d
-}
@@ -35,12 +35,16 @@ type ObjectVisitor func(obj ast.FileObject) status.Error
 // Git repo for a cluster.
 type Raw struct {
 	ClusterName       string
+	ClusterLabels     map[string]string
 	PolicyDir         cmpath.Relative
 	Objects           []ast.FileObject
 	PreviousCRDs      []*v1beta1.CustomResourceDefinition
 	BuildScoper       utildiscovery.BuildScoperFunc
 	Converter         *declared.ValueConverter
 	AllowUnknownKinds bool
+	// Validated returns true if the object already passed the ObjectVisitors
+	// of VisitAllRaw, which are skipped for it. It may be nil.
+	Validated func(obj ast.FileObject) bool
 }
 
 // Scoped builds a Scoped collection of objects from the Raw objects.
@@ -80,11 +84,15 @@ func (r *Raw) Scoped() (*Scoped, status.MultiError) {
 }
 
 // VisitAllRaw returns a RawVisitor which will call the given ObjectVisitor on
-// every FileObject in the Raw objects.
+// every FileObject in the Raw objects, except the ones which were already
+// validated.
 func VisitAllRaw(visit ObjectVisitor) RawVisitor {
 	return func(r *Raw) status.MultiError {
 		var errs status.MultiError
 		for _, obj := range r.Objects {
+			if r.Validated != nil && r.Validated(obj) {
+				continue
+			}
 			errs = status.Append(errs, visit(obj))
 		}
 		return errs
@@ -16,6 +16,7 @@ package objects
 
 import (
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
 	"kpt.dev/configsync/pkg/status"
 )
 
@@ -30,6 +31,7 @@ type Scoped struct {
 	Unknown               []ast.FileObject
 	DefaultNamespace      string
 	IsNamespaceReconciler bool
+	NSControllerState     *namespacecontroller.State
 }
 
 // Objects returns all FileObjects in the Scoped collection.
@@ -37,7 +37,7 @@ func ClusterSelectors(objs *objects.Raw) status.MultiError {
 	if errs != nil {
 		return errs
 	}
-	activeSelectors, errs := set.activeSelectors()
+	activeSelectors, errs := set.activeSelectors(objs.ClusterLabels)
 	if errs != nil {
 		return errs
 	}
@@ -184,11 +184,19 @@ func (h *hydratorSet) clusterSelectorObject(object ast.FileObject) status.Error
 	return nil
 }
 
-func (h *hydratorSet) activeSelectors() (map[string]bool, status.MultiError) {
+// activeSelectors evaluates the declared ClusterSelectors against the labels
+// of the current cluster. The labels read from the cluster itself are used as
+// the base, and the labels of the declared Cluster object take precedence.
+func (h *hydratorSet) activeSelectors(liveLabels map[string]string) (map[string]bool, status.MultiError) {
 	activeSels := make(map[string]bool)
 	clusterLabels := labels.Set{}
+	for k, v := range liveLabels {
+		clusterLabels[k] = v
+	}
 	if h.cluster != nil {
-		clusterLabels = h.cluster.Labels
+		for k, v := range h.cluster.Labels {
+			clusterLabels[k] = v
+		}
 	}
 
 	var errs status.MultiError
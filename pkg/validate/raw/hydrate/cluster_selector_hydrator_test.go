@@ -311,6 +311,42 @@ func TestClusterSelectors(t *testing.T) {
 			},
 			wantErrs: selectors.ObjectHasUnknownClusterSelector(fake.Role(), "stateUnknown"),
 		},
+		{
+			name: "Keep object with legacy cluster selector matching live cluster labels",
+			objs: &objects.Raw{
+				ClusterName:   unknownClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+				Objects: []ast.FileObject{
+					fake.Role(withProdLegacyClusterSelector),
+					prodSelector,
+					devSelector,
+				},
+			},
+			want: &objects.Raw{
+				ClusterName:   unknownClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+				Objects: []ast.FileObject{
+					fake.Role(withProdLegacyClusterSelector),
+				},
+			},
+		},
+		{
+			name: "Declared Cluster labels take precedence over live cluster labels",
+			objs: &objects.Raw{
+				ClusterName:   devClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+				Objects: []ast.FileObject{
+					fake.Role(withProdLegacyClusterSelector),
+					devCluster,
+					prodSelector,
+					devSelector,
+				},
+			},
+			want: &objects.Raw{
+				ClusterName:   devClusterName,
+				ClusterLabels: map[string]string{"environment": "prod"},
+			},
+		},
 		{
 			name: "Error if ClusterSelector is invalid",
 			objs: &objects.Raw{
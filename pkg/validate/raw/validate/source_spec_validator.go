@@ -15,6 +15,7 @@
 package validate
 
 import (
+	"net/url"
 	"strings"
 
 	"kpt.dev/configsync/pkg/api/configsync"
@@ -28,7 +29,7 @@ import (
 const gcpSASuffix = ".iam.gserviceaccount.com"
 
 // SourceSpec validates the Root Sync source specification for any obvious problems.
-func SourceSpec(sourceType string, git *v1beta1.Git, oci *v1beta1.Oci, helm *v1beta1.HelmBase, rs client.Object) status.Error {
+func SourceSpec(sourceType string, git *v1beta1.Git, oci *v1beta1.Oci, helm *v1beta1.HelmBase, local *v1beta1.Local, bucket *v1beta1.Bucket, rs client.Object) status.Error {
 	switch v1beta1.SourceType(sourceType) {
 	case v1beta1.GitSource:
 		return GitSpec(git, rs)
@@ -36,6 +37,10 @@ func SourceSpec(sourceType string, git *v1beta1.Git, oci *v1beta1.Oci, helm *v1b
 		return OciSpec(oci, rs)
 	case v1beta1.HelmSource:
 		return HelmSpec(helm, rs)
+	case v1beta1.LocalSource:
+		return LocalSpec(local, rs)
+	case v1beta1.BucketSource:
+		return BucketSpec(bucket, rs)
 	default:
 		return InvalidSourceType(rs)
 	}
@@ -161,6 +166,78 @@ func HelmSpec(helm *v1beta1.HelmBase, rs client.Object) status.Error {
 	return nil
 }
 
+// LocalSpec validates the local specification for any obvious problems.
+func LocalSpec(local *v1beta1.Local, rs client.Object) status.Error {
+	if local == nil {
+		return MissingLocalSpec(rs)
+	}
+
+	refs := 0
+	if local.ConfigMapRef != nil {
+		refs++
+		if local.ConfigMapRef.Name == "" || local.ConfigMapRef.Key == "" {
+			return MissingLocalKeyRef(rs, "configMapRef")
+		}
+	}
+	if local.SecretRef != nil {
+		refs++
+		if local.SecretRef.Name == "" || local.SecretRef.Key == "" {
+			return MissingLocalKeyRef(rs, "secretRef")
+		}
+	}
+	if local.PersistentVolumeClaimRef != nil {
+		refs++
+		if local.PersistentVolumeClaimRef.Name == "" {
+			return MissingLocalPersistentVolumeClaimName(rs)
+		}
+		// The PersistentVolumeClaim is mounted into the reconciler Pod, which
+		// runs in the config-management-system namespace.
+		if rs.GetNamespace() != configsync.ControllerNamespace {
+			return IllegalLocalPersistentVolumeClaim(rs)
+		}
+	}
+	if refs != 1 {
+		return InvalidLocalRefs(rs)
+	}
+	return nil
+}
+
+// BucketSpec validates the bucket specification for any obvious problems.
+func BucketSpec(bucket *v1beta1.Bucket, rs client.Object) status.Error {
+	if bucket == nil {
+		return MissingBucketSpec(rs)
+	}
+
+	// We can't connect to the bucket if we don't have the endpoint.
+	if bucket.Endpoint == "" {
+		return MissingBucketField(rs, "endpoint")
+	}
+	if u, err := url.Parse(bucket.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return InvalidBucketEndpoint(rs)
+	}
+
+	if bucket.Bucket == "" {
+		return MissingBucketField(rs, "bucket")
+	}
+
+	// Ensure auth is a valid value.
+	// Note that Auth is a case-sensitive field, so ones with arbitrary capitalization
+	// will fail to apply.
+	switch bucket.Auth {
+	case configsync.AuthNone:
+		if bucket.SecretRef != nil && bucket.SecretRef.Name != "" {
+			return IllegalBucketSecretRef(rs)
+		}
+	case configsync.AuthAccessKey:
+		if bucket.SecretRef == nil || bucket.SecretRef.Name == "" {
+			return MissingBucketSecretRef(rs)
+		}
+	default:
+		return InvalidBucketAuthType(rs)
+	}
+	return nil
+}
+
 // InvalidSyncCode is the code for an invalid declared RootSync/RepoSync.
 var InvalidSyncCode = "1061"
 
@@ -268,7 +345,7 @@ func validGCPServiceAccountEmail(email string) bool {
 func InvalidSourceType(o client.Object) status.Error {
 	kind := o.GetObjectKind().GroupVersionKind().Kind
 	return invalidSyncBuilder.
-		Sprintf("%ss must specify spec.sourceType to be one of %q, %q, %q", kind, v1beta1.GitSource, v1beta1.OciSource, v1beta1.HelmSource).
+		Sprintf("%ss must specify spec.sourceType to be one of %q, %q, %q, %q, %q", kind, v1beta1.GitSource, v1beta1.OciSource, v1beta1.HelmSource, v1beta1.LocalSource, v1beta1.BucketSource).
 		BuildWithResources(o)
 }
 
@@ -338,3 +415,108 @@ func InvalidHelmAuthType(o client.Object) status.Error {
 			strings.Join(types, ",")).
 		BuildWithResources(o)
 }
+
+// MissingLocalSpec reports that a RootSync/RepoSync doesn't declare the local
+// spec when spec.sourceType is set to `local`.
+func MissingLocalSpec(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.local when spec.sourceType is %q", kind, v1beta1.LocalSource).
+		BuildWithResources(o)
+}
+
+// InvalidLocalRefs reports that a RootSync/RepoSync doesn't declare exactly one
+// of the objects holding the local source.
+func InvalidLocalRefs(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify exactly one of spec.local.configMapRef, spec.local.secretRef and spec.local.persistentVolumeClaimRef when spec.sourceType is %q", kind, v1beta1.LocalSource).
+		BuildWithResources(o)
+}
+
+// MissingLocalKeyRef reports that a RootSync/RepoSync doesn't declare the name
+// or the key of the ConfigMap or Secret holding the local source.
+func MissingLocalKeyRef(o client.Object, field string) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify both spec.local.%[2]s.name and spec.local.%[2]s.key", kind, field).
+		BuildWithResources(o)
+}
+
+// MissingLocalPersistentVolumeClaimName reports that a RootSync/RepoSync
+// doesn't declare the name of the PersistentVolumeClaim holding the local
+// source.
+func MissingLocalPersistentVolumeClaimName(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.local.persistentVolumeClaimRef.name", kind).
+		BuildWithResources(o)
+}
+
+// IllegalLocalPersistentVolumeClaim reports that a RootSync/RepoSync declares a
+// PersistentVolumeClaim which can't be mounted into its reconciler Pod.
+func IllegalLocalPersistentVolumeClaim(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss outside of the %q namespace must not specify spec.local.persistentVolumeClaimRef, use spec.local.configMapRef or spec.local.secretRef instead",
+			kind, configsync.ControllerNamespace).
+		BuildWithResources(o)
+}
+
+// MissingBucketSpec reports that a RootSync/RepoSync doesn't declare the bucket
+// spec when spec.sourceType is set to `bucket`.
+func MissingBucketSpec(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.bucket when spec.sourceType is %q", kind, v1beta1.BucketSource).
+		BuildWithResources(o)
+}
+
+// MissingBucketField reports that a RootSync/RepoSync doesn't declare the
+// endpoint or the name of the bucket it is supposed to connect to.
+func MissingBucketField(o client.Object, field string) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.bucket.%s when spec.sourceType is %q", kind, field, v1beta1.BucketSource).
+		BuildWithResources(o)
+}
+
+// InvalidBucketEndpoint reports that a RootSync/RepoSync declares an endpoint
+// which isn't an HTTP or HTTPS URL.
+func InvalidBucketEndpoint(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.bucket.endpoint as an http or https URL, for example %q", kind, "https://s3.us-east-1.amazonaws.com").
+		BuildWithResources(o)
+}
+
+// InvalidBucketAuthType reports that a RootSync/RepoSync doesn't use one of the
+// known auth methods for buckets.
+func InvalidBucketAuthType(o client.Object) status.Error {
+	types := []string{string(configsync.AuthAccessKey), string(configsync.AuthNone)}
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss must specify spec.bucket.auth to be one of %s", kind,
+			strings.Join(types, ",")).
+		BuildWithResources(o)
+}
+
+// IllegalBucketSecretRef reports that a RootSync/RepoSync declares a
+// spec.bucket.secretRef without authenticating with an access key.
+func IllegalBucketSecretRef(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss which specify spec.bucket.auth as %q must not specify spec.bucket.secretRef",
+			kind, configsync.AuthNone).
+		BuildWithResources(o)
+}
+
+// MissingBucketSecretRef reports that a RootSync/RepoSync authenticates with
+// an access key, but doesn't declare the Secret holding it.
+func MissingBucketSecretRef(o client.Object) status.Error {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	return invalidSyncBuilder.
+		Sprintf("%ss which specify spec.bucket.auth as %q must also specify spec.bucket.secretRef",
+			kind, configsync.AuthAccessKey).
+		BuildWithResources(o)
+}
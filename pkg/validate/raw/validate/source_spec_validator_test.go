@@ -121,6 +121,20 @@ func repoSyncWithHelm(opts ...func(*v1beta1.RepoSync)) *v1beta1.RepoSync {
 	return rs
 }
 
+func repoSyncWithLocal(local *v1beta1.Local) *v1beta1.RepoSync {
+	rs := fake.RepoSyncObjectV1Beta1("test-ns", configsync.RepoSyncName)
+	rs.Spec.SourceType = string(v1beta1.LocalSource)
+	rs.Spec.Local = local
+	return rs
+}
+
+func repoSyncWithBucket(bucket *v1beta1.Bucket) *v1beta1.RepoSync {
+	rs := fake.RepoSyncObjectV1Beta1("test-ns", configsync.RepoSyncName)
+	rs.Spec.SourceType = string(v1beta1.BucketSource)
+	rs.Spec.Bucket = bucket
+	return rs
+}
+
 func withGit() func(*v1beta1.RepoSync) {
 	return func(sync *v1beta1.RepoSync) {
 		sync.Spec.Git = &v1beta1.Git{}
@@ -276,11 +290,118 @@ func TestValidateSourceSpec(t *testing.T) {
 			obj:     repoSyncWithGit(withHelm()),
 			wantErr: fake.Error(InvalidSyncCode),
 		},
+		// Validate local spec
+		{
+			name: "valid local ConfigMap",
+			obj: repoSyncWithLocal(&v1beta1.Local{
+				ConfigMapRef: &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+			}),
+		},
+		{
+			name: "valid local Secret",
+			obj: repoSyncWithLocal(&v1beta1.Local{
+				SecretRef: &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+			}),
+		},
+		{
+			name:    "missing local spec",
+			obj:     repoSyncWithLocal(nil),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name:    "missing local ref",
+			obj:     repoSyncWithLocal(&v1beta1.Local{}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "multiple local refs",
+			obj: repoSyncWithLocal(&v1beta1.Local{
+				ConfigMapRef: &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+				SecretRef:    &v1beta1.LocalObjectKeyReference{Name: "source", Key: "source.tar.gz"},
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "missing local ConfigMap key",
+			obj: repoSyncWithLocal(&v1beta1.Local{
+				ConfigMapRef: &v1beta1.LocalObjectKeyReference{Name: "source"},
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "local PersistentVolumeClaim outside of config-management-system",
+			obj: repoSyncWithLocal(&v1beta1.Local{
+				PersistentVolumeClaimRef: &v1beta1.PersistentVolumeClaimReference{Name: "source"},
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		// Validate bucket spec
+		{
+			name: "valid bucket with none auth",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "http://minio.minio:9000", Bucket: "configs", Auth: configsync.AuthNone,
+			}),
+		},
+		{
+			name: "valid bucket with accesskey auth",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "https://s3.us-east-1.amazonaws.com", Bucket: "configs", Prefix: "prod/", Auth: configsync.AuthAccessKey,
+				SecretRef: &v1beta1.SecretReference{Name: "bucket-creds"},
+			}),
+		},
+		{
+			name:    "missing bucket spec",
+			obj:     repoSyncWithBucket(nil),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "missing bucket endpoint",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Bucket: "configs", Auth: configsync.AuthNone,
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "invalid bucket endpoint",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "minio.minio:9000", Bucket: "configs", Auth: configsync.AuthNone,
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "missing bucket name",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "http://minio.minio:9000", Auth: configsync.AuthNone,
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "invalid bucket auth",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "http://minio.minio:9000", Bucket: "configs", Auth: configsync.AuthToken,
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "bucket accesskey auth without secretRef",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "http://minio.minio:9000", Bucket: "configs", Auth: configsync.AuthAccessKey,
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
+		{
+			name: "bucket none auth with secretRef",
+			obj: repoSyncWithBucket(&v1beta1.Bucket{
+				Endpoint: "http://minio.minio:9000", Bucket: "configs", Auth: configsync.AuthNone,
+				SecretRef: &v1beta1.SecretReference{Name: "bucket-creds"},
+			}),
+			wantErr: fake.Error(InvalidSyncCode),
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := SourceSpec(tc.obj.Spec.SourceType, tc.obj.Spec.Git, tc.obj.Spec.Oci, reposync.GetHelmBase(tc.obj.Spec.Helm), tc.obj)
+			err := SourceSpec(tc.obj.Spec.SourceType, tc.obj.Spec.Git, tc.obj.Spec.Oci, reposync.GetHelmBase(tc.obj.Spec.Helm), tc.obj.Spec.Local, tc.obj.Spec.Bucket, tc.obj)
 			if !errors.Is(err, tc.wantErr) {
 				t.Errorf("Got SourceSpec() error %v, want %v", err, tc.wantErr)
 			}
@@ -15,6 +15,10 @@
 package hydrate
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	v1 "kpt.dev/configsync/pkg/api/configmanagement/v1"
@@ -77,49 +81,100 @@ func buildSelectorMap(objs *objects.Scoped) (map[string][]string, status.MultiEr
 	}
 
 	var errs status.MultiError
-	selectorMap := make(map[string][]string)
-
+	selectorMap := make(map[string]labels.Selector)
+	dynamicSelectors := make(map[string]labels.Selector)
 	for _, obj := range nsSelectors {
-		var selected []string
-		selector, err := labelSelector(obj)
+		selector, mode, err := labelSelector(obj)
 		if err != nil {
 			errs = status.Append(errs, err)
 			continue
 		}
+		selectorMap[obj.GetName()] = selector
+		if mode == v1.NSSelectorDynamicMode && objs.NSControllerState != nil {
+			dynamicSelectors[obj.GetName()] = selector
+		}
+	}
+	if errs != nil {
+		return nil, errs
+	}
 
+	var live map[string]labels.Set
+	if objs.NSControllerState != nil {
+		var synced bool
+		live, synced = objs.NSControllerState.SetSelectorCache(dynamicSelectors)
+		if !synced {
+			// The parser retries the failed syncs, so the dynamic
+			// NamespaceSelectors are evaluated once the Namespaces are loaded.
+			return nil, status.APIServerError(errNamespacesNotSynced,
+				"unable to evaluate the dynamic NamespaceSelectors")
+		}
+	}
+
+	selected := make(map[string][]string, len(selectorMap))
+	for name, selector := range selectorMap {
+		var names []string
 		for _, namespace := range namespaces {
 			if selector.Matches(labels.Set(namespace.GetLabels())) {
-				selected = append(selected, namespace.GetName())
+				names = append(names, namespace.GetName())
 			}
 		}
-
-		selectorMap[obj.GetName()] = selected
-	}
-
-	if errs != nil {
-		return nil, errs
+		if _, dynamic := dynamicSelectors[name]; dynamic {
+			names = append(names, liveNamespaces(selector, namespaces, live)...)
+		}
+		selected[name] = names
 	}
 
 	// We are done with NamespaceSelectors so we can filter them out now.
 	objs.Cluster = append(namespaces, others...)
-	return selectorMap, nil
+	return selected, nil
+}
+
+// errNamespacesNotSynced is the error returned while the Namespaces on the
+// cluster have not been loaded yet.
+var errNamespacesNotSynced = errors.New("the Namespaces on the cluster have not been loaded yet")
+
+// liveNamespaces returns the names of the live Namespaces that are selected by
+// the given selector. Namespaces which are also declared in the source are
+// skipped, since their declared labels take precedence.
+func liveNamespaces(selector labels.Selector, declared []ast.FileObject, live map[string]labels.Set) []string {
+	isDeclared := make(map[string]bool, len(declared))
+	for _, namespace := range declared {
+		isDeclared[namespace.GetName()] = true
+	}
+
+	var selected []string
+	for name, nsLabels := range live {
+		if !isDeclared[name] && selector.Matches(nsLabels) {
+			selected = append(selected, name)
+		}
+	}
+	// Sort the result so that the hydrated objects are deterministic.
+	sort.Strings(selected)
+	return selected
 }
 
-func labelSelector(obj ast.FileObject) (labels.Selector, status.Error) {
+func labelSelector(obj ast.FileObject) (labels.Selector, string, status.Error) {
 	s, sErr := obj.Structured()
 	if sErr != nil {
-		return nil, sErr
+		return nil, "", sErr
 	}
 	nss := s.(*v1.NamespaceSelector)
 
+	switch nss.Spec.Mode {
+	case "", v1.NSSelectorStaticMode, v1.NSSelectorDynamicMode:
+	default:
+		return nil, "", selectors.InvalidSelectorError(obj,
+			fmt.Errorf("unknown mode %q, must be one of %q or %q", nss.Spec.Mode, v1.NSSelectorStaticMode, v1.NSSelectorDynamicMode))
+	}
+
 	selector, err := metav1.LabelSelectorAsSelector(&nss.Spec.Selector)
 	if err != nil {
-		return nil, selectors.InvalidSelectorError(obj, err)
+		return nil, "", selectors.InvalidSelectorError(obj, err)
 	}
 	if selector.Empty() {
-		return nil, selectors.EmptySelectorError(obj)
+		return nil, "", selectors.EmptySelectorError(obj)
 	}
-	return selector, nil
+	return selector, nss.Spec.Mode, nil
 }
 
 // makeNamespaceCopies uses the given object's namespace selector to make a copy
@@ -24,6 +24,7 @@ import (
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/analyzer/transform/selectors"
 	"kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/validate/objects"
@@ -227,3 +228,106 @@ func TestNamespaceSelectors(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicNamespaceSelectors(t *testing.T) {
+	dynamicNSS := fake.FileObject(fake.NamespaceSelectorObject(core.Name("dev-only"),
+		func(o client.Object) {
+			nss := o.(*v1.NamespaceSelector)
+			nss.Spec.Selector.MatchLabels = map[string]string{"environment": "dev"}
+			nss.Spec.Mode = v1.NSSelectorDynamicMode
+		}), "dev-only-nss.yaml")
+	unknownModeNSS := fake.FileObject(fake.NamespaceSelectorObject(core.Name("dev-only"),
+		func(o client.Object) {
+			nss := o.(*v1.NamespaceSelector)
+			nss.Spec.Selector.MatchLabels = map[string]string{"environment": "dev"}
+			nss.Spec.Mode = "sometimes"
+		}), "dev-only-nss.yaml")
+
+	testCases := []struct {
+		name           string
+		nss            ast.FileObject
+		nsState        bool
+		notSynced      bool
+		wantNamespaces []string
+		wantErrs       status.MultiError
+	}{
+		{
+			name:           "static selector only selects declared namespaces",
+			nss:            namespaceSelector,
+			nsState:        true,
+			wantNamespaces: []string{"dev"},
+		},
+		{
+			name:           "dynamic selector selects declared and live namespaces",
+			nss:            dynamicNSS,
+			nsState:        true,
+			wantNamespaces: []string{"dev", "dev-live-1", "dev-live-2"},
+		},
+		{
+			name:           "dynamic selector without live namespaces only selects declared namespaces",
+			nss:            dynamicNSS,
+			wantNamespaces: []string{"dev"},
+		},
+		{
+			name:      "dynamic selector before the live namespaces are loaded",
+			nss:       dynamicNSS,
+			nsState:   true,
+			notSynced: true,
+			wantErrs:  status.APIServerError(errors.New(""), ""),
+		},
+		{
+			name:           "static selector before the live namespaces are loaded",
+			nss:            namespaceSelector,
+			nsState:        true,
+			notSynced:      true,
+			wantNamespaces: []string{"dev"},
+		},
+		{
+			name:     "unknown mode",
+			nss:      unknownModeNSS,
+			nsState:  true,
+			wantErrs: selectors.InvalidSelectorError(unknownModeNSS, errors.New("")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := &objects.Scoped{
+				Cluster: []ast.FileObject{
+					tc.nss,
+					fake.Namespace("namespaces/dev", core.Label("environment", "dev")),
+				},
+				Namespace: []ast.FileObject{
+					fake.Role(core.Annotation(metadata.NamespaceSelectorAnnotationKey, "dev-only")),
+				},
+			}
+			if tc.nsState {
+				state := namespacecontroller.NewState()
+				// The declared labels take precedence over the live labels.
+				state.SetNamespace("dev", map[string]string{"environment": "prod"})
+				state.SetNamespace("dev-live-2", map[string]string{"environment": "dev"})
+				state.SetNamespace("dev-live-1", map[string]string{"environment": "dev"})
+				state.SetNamespace("prod-live", map[string]string{"environment": "prod"})
+				if !tc.notSynced {
+					state.MarkSynced(nil)
+				}
+				objs.NSControllerState = state
+			}
+
+			errs := NamespaceSelectors(objs)
+			if !errors.Is(errs, tc.wantErrs) {
+				t.Fatalf("Got NamespaceSelectors() error %v, want %v", errs, tc.wantErrs)
+			}
+			if errs != nil {
+				return
+			}
+			var gotNamespaces []string
+			for _, obj := range objs.Namespace {
+				gotNamespaces = append(gotNamespaces, obj.GetNamespace())
+			}
+			if diff := cmp.Diff(tc.wantNamespaces, gotNamespaces); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
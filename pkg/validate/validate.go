@@ -19,6 +19,7 @@ import (
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/importer/analyzer/ast"
 	"kpt.dev/configsync/pkg/importer/filesystem/cmpath"
+	"kpt.dev/configsync/pkg/reconciler/namespacecontroller"
 	"kpt.dev/configsync/pkg/status"
 	"kpt.dev/configsync/pkg/util/discovery"
 	"kpt.dev/configsync/pkg/validate/final"
@@ -39,6 +40,10 @@ type Options struct {
 	// ClusterName is the spec.clusterName of the cluster's ConfigManagement. This
 	// is used when hydrating cluster selectors.
 	ClusterName string
+	// ClusterLabels are the labels of the cluster read from the cluster itself.
+	// They are merged with the labels of the declared Cluster object (if any)
+	// when hydrating cluster selectors.
+	ClusterLabels map[string]string
 	// PolicyDir is the relative path of the root policy directory within the
 	// repo.
 	PolicyDir cmpath.Relative
@@ -66,6 +71,15 @@ type Options struct {
 	// IsNamespaceReconciler is a flag to indicate if the caller is a namespace
 	// reconciler which adds some additional validation logic.
 	IsNamespaceReconciler bool
+	// NSControllerState caches the Namespaces on the cluster. If set, dynamic
+	// NamespaceSelectors in an unstructured repo select live Namespaces in
+	// addition to the declared ones. Otherwise, they behave like static
+	// NamespaceSelectors.
+	NSControllerState *namespacecontroller.State
+	// Validated returns true if the object passed the validators which only
+	// depend on the object itself in a previous validation, and did not change
+	// since. These validators are skipped for it. It may be nil.
+	Validated func(obj ast.FileObject) bool
 	// Visitors is a list of optional visitor functions which can be used to
 	// inject additional validation or hydration steps on the final objects.
 	Visitors []VisitorFunc
@@ -83,12 +97,14 @@ func Hierarchical(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 	//   - adding metadata to resources (such as their filepath in the repo)
 	rawObjects := &objects.Raw{
 		ClusterName:       opts.ClusterName,
+		ClusterLabels:     opts.ClusterLabels,
 		PolicyDir:         opts.PolicyDir,
 		Objects:           objs,
 		PreviousCRDs:      opts.PreviousCRDs,
 		BuildScoper:       opts.BuildScoper,
 		Converter:         opts.Converter,
 		AllowUnknownKinds: opts.AllowUnknownKinds,
+		Validated:         opts.Validated,
 	}
 
 	// nonBlockingErrs tracks the errors which do not block the apply stage
@@ -162,12 +178,14 @@ func Unstructured(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 	//   - adding metadata to resources (such as their filepath in the repo)
 	rawObjects := &objects.Raw{
 		ClusterName:       opts.ClusterName,
+		ClusterLabels:     opts.ClusterLabels,
 		PolicyDir:         opts.PolicyDir,
 		Objects:           objs,
 		PreviousCRDs:      opts.PreviousCRDs,
 		BuildScoper:       opts.BuildScoper,
 		Converter:         opts.Converter,
 		AllowUnknownKinds: opts.AllowUnknownKinds,
+		Validated:         opts.Validated,
 	}
 
 	// nonBlockingErrs tracks the errors which do not block the apply stage
@@ -194,6 +212,7 @@ func Unstructured(objs []ast.FileObject, opts Options) ([]ast.FileObject, status
 
 	scopedObjects.DefaultNamespace = opts.DefaultNamespace
 	scopedObjects.IsNamespaceReconciler = opts.IsNamespaceReconciler
+	scopedObjects.NSControllerState = opts.NSControllerState
 	if errs := scoped.Unstructured(scopedObjects); errs != nil {
 		return nil, status.Append(nonBlockingErrs, errs)
 	}
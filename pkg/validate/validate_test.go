@@ -1167,6 +1167,37 @@ func TestUnstructured(t *testing.T) {
 			},
 			wantErrs: fake.Errors(nonhierarchical.UnsupportedCRDRemovalErrorCode),
 		},
+		{
+			name: "illegal annotation fails",
+			objs: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Name("role"),
+					core.Namespace("shipping"),
+					core.Annotation("configsync.gke.io/foo", "bar")),
+			},
+			wantErrs: fake.Errors(metadata.IllegalAnnotationDefinitionErrorCode),
+		},
+		{
+			name: "already validated objects skip the per-object validators",
+			options: Options{
+				Validated: func(ast.FileObject) bool { return true },
+			},
+			objs: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Name("role"),
+					core.Namespace("shipping"),
+					core.Annotation("configsync.gke.io/foo", "bar")),
+			},
+			want: []ast.FileObject{
+				fake.RoleAtPath("role.yaml",
+					core.Name("role"),
+					core.Namespace("shipping"),
+					core.Label(csmetadata.DeclaredVersionLabel, "v1"),
+					core.Annotation(csmetadata.DeclaredFieldsKey, `{"f:metadata":{"f:annotations":{"f:configsync.gke.io/foo":{}},"f:labels":{}},"f:rules":{}}`),
+					core.Annotation("configsync.gke.io/foo", "bar"),
+					core.Annotation(csmetadata.SourcePathAnnotationKey, dir+"/role.yaml")),
+			},
+		},
 	}
 
 	converter, err := openapitest.ValueConverterForTest()
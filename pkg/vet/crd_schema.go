@@ -0,0 +1,165 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// validateCustomResource validates the custom resource against the structural
+// schema of its CustomResourceDefinition version. The apiVersion, kind and
+// metadata fields are validated by the API server regardless of the schema.
+func validateCustomResource(kind string, obj map[string]interface{}, s *apiextensionsv1.JSONSchemaProps) []string {
+	var errs []string
+	keys := sortedKeys(obj)
+	for _, k := range keys {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			continue
+		}
+		errs = append(errs, validateField(kind, k, obj[k], s)...)
+	}
+	return errs
+}
+
+// validateField validates the value of the field of the parent object.
+func validateField(path, field string, value interface{}, parent *apiextensionsv1.JSONSchemaProps) []string {
+	if parent.XPreserveUnknownFields != nil && *parent.XPreserveUnknownFields {
+		if s, found := parent.Properties[field]; found {
+			return validateValue(path+"."+field, value, &s)
+		}
+		return nil
+	}
+	if s, found := parent.Properties[field]; found {
+		return validateValue(path+"."+field, value, &s)
+	}
+	if ap := parent.AdditionalProperties; ap != nil {
+		if ap.Schema != nil {
+			return validateValue(path+"."+field, value, ap.Schema)
+		}
+		if ap.Allows {
+			return nil
+		}
+	}
+	if len(parent.Properties) == 0 {
+		// Nothing is known about the fields of the object.
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: unknown field %q", path, field)}
+}
+
+// validateValue validates the type of the value, and the fields of objects and
+// the items of arrays.
+func validateValue(path string, value interface{}, s *apiextensionsv1.JSONSchemaProps) []string {
+	if value == nil || s.XEmbeddedResource {
+		return nil
+	}
+	if s.XIntOrString {
+		switch value.(type) {
+		case string, int64, float64:
+			return nil
+		default:
+			return []string{invalidType(path, value, "int-or-string")}
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{invalidType(path, value, s.Type)}
+		}
+		var errs []string
+		for _, k := range sortedKeys(obj) {
+			errs = append(errs, validateField(path, k, obj[k], s)...)
+		}
+		return errs
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{invalidType(path, value, s.Type)}
+		}
+		if s.Items == nil || s.Items.Schema == nil {
+			return nil
+		}
+		var errs []string
+		for i, item := range items {
+			errs = append(errs, validateValue(fmt.Sprintf("%s[%d]", path, i), item, s.Items.Schema)...)
+		}
+		return errs
+	case "string":
+		if _, ok := value.(string); !ok {
+			return []string{invalidType(path, value, s.Type)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{invalidType(path, value, s.Type)}
+		}
+	case "integer":
+		switch v := value.(type) {
+		case int64:
+		case float64:
+			if v != math.Trunc(v) {
+				return []string{invalidType(path, value, s.Type)}
+			}
+		default:
+			return []string{invalidType(path, value, s.Type)}
+		}
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+		default:
+			return []string{invalidType(path, value, s.Type)}
+		}
+	}
+	return nil
+}
+
+func invalidType(path string, value interface{}, expected string) string {
+	return fmt.Sprintf("%s: invalid type, got %q, expected %q", path, jsonType(value), expected)
+}
+
+// jsonType returns the JSON type of the decoded value.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
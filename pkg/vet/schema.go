@@ -0,0 +1,232 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vet
+
+import (
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	openapi_v2 "github.com/google/gnostic/openapiv2"
+	"google.golang.org/protobuf/proto"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeproto "k8s.io/kube-openapi/pkg/util/proto"
+	protovalidation "k8s.io/kube-openapi/pkg/util/proto/validation"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/status"
+	"kpt.dev/configsync/pkg/util/clusterconfig"
+	"kpt.dev/configsync/pkg/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultKubernetesVersion is the Kubernetes version of the bundled OpenAPI
+// schemas objects are validated against by default.
+const DefaultKubernetesVersion = "1.21"
+
+// schemaFiles holds the bundled OpenAPI schemas, generated by
+// cmd/gen-openapi-schemas.
+//
+//go:embed schemas/*.pb.gz
+var schemaFiles embed.FS
+
+// SupportedKubernetesVersions returns the Kubernetes versions with bundled
+// OpenAPI schemas.
+func SupportedKubernetesVersions() []string {
+	var versions []string
+	for v := range bundledSchemas {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// SchemaValidationErrorCode is the error code for SchemaValidationError.
+const SchemaValidationErrorCode = "1070"
+
+var schemaValidationErrorBuilder = status.NewErrorBuilder(SchemaValidationErrorCode)
+
+// SchemaValidationError reports that an object does not match the OpenAPI
+// schema of its kind.
+func SchemaValidationError(resource client.Object, errs []string) status.Error {
+	return schemaValidationErrorBuilder.
+		Sprintf("The object does not match the OpenAPI schema of %s:\n%s",
+			resource.GetObjectKind().GroupVersionKind().Kind, strings.Join(errs, "\n")).
+		BuildWithResources(resource)
+}
+
+var (
+	builtinMux       sync.Mutex
+	builtinResources = make(map[string]openapi.Resources)
+)
+
+// builtinSchemas returns the bundled OpenAPI schemas of the Kubernetes
+// version, parsing them on first use.
+func builtinSchemas(version string) (openapi.Resources, error) {
+	if err := ValidateKubernetesVersion(version); err != nil {
+		return nil, err
+	}
+	name := bundledSchemas[normalizeVersion(version)]
+
+	builtinMux.Lock()
+	defer builtinMux.Unlock()
+	if resources, found := builtinResources[name]; found {
+		return resources, nil
+	}
+	doc, err := readSchema(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OpenAPI schema of Kubernetes %s: %w", version, err)
+	}
+	resources, err := openapi.NewOpenAPIData(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI schema of Kubernetes %s: %w", version, err)
+	}
+	builtinResources[name] = resources
+	return resources, nil
+}
+
+// readSchema reads a bundled gzipped OpenAPI schema.
+func readSchema(name string) (*openapi_v2.Document, error) {
+	f, err := schemaFiles.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	doc := &openapi_v2.Document{}
+	if err := proto.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// normalizeVersion converts versions like v1.21.2 to 1.21.
+func normalizeVersion(version string) string {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
+
+// ValidateKubernetesVersion returns an error if there are no bundled OpenAPI
+// schemas for the Kubernetes version.
+func ValidateKubernetesVersion(version string) error {
+	if _, found := bundledSchemas[normalizeVersion(version)]; !found {
+		return fmt.Errorf("unsupported Kubernetes version %q: nomos only bundles the OpenAPI schemas of Kubernetes %s; "+
+			"validate against the API server of a %s cluster instead by omitting --schema-validation",
+			version, strings.Join(SupportedKubernetesVersions(), ", "), version)
+	}
+	return nil
+}
+
+// SchemaVisitor returns a visitor which validates the objects against the
+// bundled OpenAPI schemas of the Kubernetes version, and against the schemas
+// of the CustomResourceDefinitions declared among the objects. It reports
+// unknown fields and fields of the wrong type. Objects of unknown kinds are
+// not validated.
+func SchemaVisitor(version string) (validate.VisitorFunc, error) {
+	resources, err := builtinSchemas(version)
+	if err != nil {
+		return nil, err
+	}
+	return func(objs []ast.FileObject) ([]ast.FileObject, status.MultiError) {
+		crdSchemas := declaredSchemas(objs)
+		var errs status.MultiError
+		for i := range objs {
+			obj := &objs[i]
+			gvk := obj.GetObjectKind().GroupVersionKind()
+			var objErrs []string
+			if s, found := crdSchemas[gvk]; found {
+				objErrs = validateCustomResource(gvk.Kind, obj.Object, s)
+			} else if model := resources.LookupResource(gvk); model != nil {
+				objErrs = validateBuiltin(obj.Object, model, gvk.Kind)
+			}
+			if len(objErrs) > 0 {
+				errs = status.Append(errs, SchemaValidationError(obj, objErrs))
+			}
+		}
+		return objs, errs
+	}, nil
+}
+
+// declaredSchemas returns the schemas of the versions of the
+// CustomResourceDefinitions declared among the objects.
+// Malformed CustomResourceDefinitions are skipped, since they are reported by
+// the other validators.
+func declaredSchemas(objs []ast.FileObject) map[schema.GroupVersionKind]*apiextensionsv1.JSONSchemaProps {
+	schemas := make(map[schema.GroupVersionKind]*apiextensionsv1.JSONSchemaProps)
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().GroupKind() != kinds.CustomResourceDefinition() {
+			continue
+		}
+		var crd *apiextensionsv1.CustomResourceDefinition
+		var err status.Error
+		if obj.GetObjectKind().GroupVersionKind() == kinds.CustomResourceDefinitionV1Beta1() {
+			var v1beta1CRD *apiextensionsv1beta1.CustomResourceDefinition
+			if v1beta1CRD, err = clusterconfig.AsCRD(obj.Unstructured); err == nil {
+				crd, err = clusterconfig.V1Beta1ToV1CRD(v1beta1CRD)
+			}
+		} else {
+			crd, err = clusterconfig.AsV1CRD(obj.Unstructured)
+		}
+		if err != nil {
+			continue
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}
+			schemas[gvk] = v.Schema.OpenAPIV3Schema
+		}
+	}
+	return schemas
+}
+
+// validateBuiltin validates the object against the OpenAPI schema of a
+// built-in kind. Missing required fields are not reported, since they may be
+// defaulted or set by mutating webhooks.
+func validateBuiltin(obj map[string]interface{}, model kubeproto.Schema, kind string) []string {
+	var errs []string
+	for _, err := range protovalidation.ValidateModel(obj, model, kind) {
+		validationErr, ok := err.(protovalidation.ValidationError)
+		if !ok {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if _, missing := validationErr.Err.(protovalidation.MissingRequiredFieldError); missing {
+			continue
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", validationErr.Path, validationErr.Err))
+	}
+	return errs
+}
@@ -0,0 +1,196 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vet
+
+import (
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/importer/analyzer/ast"
+	"kpt.dev/configsync/pkg/kinds"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+var anvilGVK = schema.GroupVersionKind{Group: "acme.com", Version: "v1", Kind: "Anvil"}
+
+func anvilCRD() ast.FileObject {
+	crd := fake.CustomResourceDefinitionV1Object()
+	crd.Name = "anvils.acme.com"
+	crd.Spec.Group = anvilGVK.Group
+	crd.Spec.Names = apiextensionsv1.CustomResourceDefinitionNames{Kind: anvilGVK.Kind, Plural: "anvils"}
+	crd.Spec.Scope = apiextensionsv1.NamespaceScoped
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{{
+		Name:    anvilGVK.Version,
+		Served:  true,
+		Storage: true,
+		Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"lbs":    {Type: "integer"},
+						"labels": {Type: "object", AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+						"tags":   {Type: "array", Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}}},
+					},
+				},
+			},
+		}},
+	}}
+	return fake.FileObject(crd, "cluster/crd.yaml")
+}
+
+func withFields(obj ast.FileObject, fields map[string]interface{}) ast.FileObject {
+	for k, v := range fields {
+		obj.Object[k] = v
+	}
+	return obj
+}
+
+func TestSchemaVisitor(t *testing.T) {
+	testCases := []struct {
+		name string
+		objs []ast.FileObject
+		want []string
+	}{
+		{
+			name: "valid built-in object",
+			objs: []ast.FileObject{
+				withFields(fake.UnstructuredAtPath(kinds.Deployment(), "deployment.yaml"), map[string]interface{}{
+					"spec": map[string]interface{}{"replicas": int64(2)},
+				}),
+			},
+		},
+		{
+			name: "built-in object with unknown field and wrong type",
+			objs: []ast.FileObject{
+				withFields(fake.UnstructuredAtPath(kinds.Deployment(), "deployment.yaml"), map[string]interface{}{
+					"spec": map[string]interface{}{"replica": int64(2), "paused": "yes"},
+				}),
+			},
+			want: []string{
+				`Deployment.spec: unknown field "replica" in io.k8s.api.apps.v1.DeploymentSpec`,
+				`Deployment.spec.paused: invalid type for io.k8s.api.apps.v1.DeploymentSpec.paused: got "string", expected "boolean"`,
+			},
+		},
+		{
+			name: "valid custom resource",
+			objs: []ast.FileObject{
+				anvilCRD(),
+				withFields(fake.UnstructuredAtPath(anvilGVK, "anvil.yaml"), map[string]interface{}{
+					"spec": map[string]interface{}{
+						"lbs":    int64(100),
+						"labels": map[string]interface{}{"a": "b"},
+						"tags":   []interface{}{"heavy"},
+					},
+				}),
+			},
+		},
+		{
+			name: "invalid custom resource",
+			objs: []ast.FileObject{
+				anvilCRD(),
+				withFields(fake.UnstructuredAtPath(anvilGVK, "anvil.yaml"), map[string]interface{}{
+					"spec": map[string]interface{}{
+						"lbs":    "100",
+						"labels": map[string]interface{}{"a": int64(1)},
+						"tags":   []interface{}{true},
+						"weigth": int64(3),
+					},
+					"status": map[string]interface{}{},
+				}),
+			},
+			want: []string{
+				`Anvil.spec.labels.a: invalid type, got "integer", expected "string"`,
+				`Anvil.spec.lbs: invalid type, got "string", expected "integer"`,
+				`Anvil.spec.tags[0]: invalid type, got "boolean", expected "string"`,
+				`Anvil.spec: unknown field "weigth"`,
+				`Anvil: unknown field "status"`,
+			},
+		},
+		{
+			name: "custom resource without declared CRD",
+			objs: []ast.FileObject{
+				withFields(fake.UnstructuredAtPath(anvilGVK, "anvil.yaml"), map[string]interface{}{
+					"spec": map[string]interface{}{"weigth": int64(3)},
+				}),
+			},
+		},
+	}
+
+	visitor, err := SchemaVisitor(DefaultKubernetesVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := visitor(tc.objs)
+			if len(tc.want) == 0 {
+				if errs != nil {
+					t.Fatalf("got unexpected errors: %v", errs)
+				}
+				return
+			}
+			if errs == nil || len(errs.Errors()) != 1 {
+				t.Fatalf("got errors %v, want one %s error", errs, SchemaValidationErrorCode)
+			}
+			err := errs.Errors()[0]
+			if err.Code() != SchemaValidationErrorCode {
+				t.Fatalf("got error code %s, want %s", err.Code(), SchemaValidationErrorCode)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("got error %q, want it to contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKubernetesVersion(t *testing.T) {
+	for _, version := range []string{"1.21", "v1.21", "1.21.2", "v1.21.14"} {
+		if err := ValidateKubernetesVersion(version); err != nil {
+			t.Errorf("ValidateKubernetesVersion(%q) = %v, want nil", version, err)
+		}
+	}
+	for _, version := range []string{"", "1.2", "1.30"} {
+		if err := ValidateKubernetesVersion(version); err == nil {
+			t.Errorf("ValidateKubernetesVersion(%q) = nil, want error", version)
+		}
+	}
+}
+
+func TestBuiltinSchemas(t *testing.T) {
+	versions := SupportedKubernetesVersions()
+	if len(versions) < 2 {
+		t.Fatalf("got supported versions %v, want several minor versions", versions)
+	}
+	for _, version := range versions {
+		t.Run(version, func(t *testing.T) {
+			if err := ValidateKubernetesVersion(version); err != nil {
+				t.Fatalf("ValidateKubernetesVersion(%q) = %v, want nil", version, err)
+			}
+			resources, err := builtinSchemas(version)
+			if err != nil {
+				t.Fatalf("builtinSchemas(%q) = %v, want nil", version, err)
+			}
+			if resources.LookupResource(kinds.Deployment()) == nil {
+				t.Errorf("got no schema of %v for Kubernetes %s", kinds.Deployment(), version)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ import (
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/reconcilermanager"
 )
 
 const (
@@ -32,8 +34,12 @@ const (
 )
 
 // isConfigSyncSA returns true if the given UserInfo represents a Config Sync
-// service account.
+// service account, or a namespace reconciler impersonating the ServiceAccount
+// of its RepoSync.
 func isConfigSyncSA(userInfo authenticationv1.UserInfo) bool {
+	if _, found := impersonatingReconciler(userInfo); found {
+		return true
+	}
 	foundSA := false
 	foundNS := false
 	for _, group := range userInfo.Groups {
@@ -53,5 +59,21 @@ func isConfigSyncSA(userInfo authenticationv1.UserInfo) bool {
 // removed, assuming the prefix is present.
 // Run isConfigSyncSA first to detect the presense of the prefix.
 func configSyncSAName(userInfo authenticationv1.UserInfo) string {
+	if name, found := impersonatingReconciler(userInfo); found {
+		return name
+	}
 	return strings.TrimPrefix(userInfo.Username, saNamespaceGroupPrefix)
 }
+
+// impersonatingReconciler returns the name of the namespace reconciler, if the
+// given UserInfo represents a namespace reconciler impersonating the
+// ServiceAccount of its RepoSync. Only the reconciler is allowed to impersonate
+// the ServiceAccount with its name as user extra info.
+func impersonatingReconciler(userInfo authenticationv1.UserInfo) (string, bool) {
+	values := userInfo.Extra[reconcilermanager.ReconcilerUserExtraKey]
+	if len(values) != 1 || !strings.HasPrefix(values[0], core.NsReconcilerPrefix+"-") ||
+		!strings.HasPrefix(userInfo.Username, saGroupPrefix+":") {
+		return "", false
+	}
+	return values[0], true
+}
@@ -66,6 +66,39 @@ func TestIsConfigSyncSA(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "Namespace reconciler impersonating a service account",
+			userInfo: authenticationv1.UserInfo{
+				Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:bookstore"},
+				Username: "system:serviceaccount:bookstore:deployer",
+				Extra: map[string]authenticationv1.ExtraValue{
+					"configsync.gke.io/reconciler": {"ns-reconciler-bookstore"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "User with reconciler extra info",
+			userInfo: authenticationv1.UserInfo{
+				Groups:   []string{"system:authenticated"},
+				Username: "alice",
+				Extra: map[string]authenticationv1.ExtraValue{
+					"configsync.gke.io/reconciler": {"ns-reconciler-bookstore"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Service account with invalid reconciler extra info",
+			userInfo: authenticationv1.UserInfo{
+				Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:bookstore"},
+				Username: "system:serviceaccount:bookstore:deployer",
+				Extra: map[string]authenticationv1.ExtraValue{
+					"configsync.gke.io/reconciler": {"root-reconciler"},
+				},
+			},
+			want: false,
+		},
 		{
 			name: "Unauthenticated user",
 			userInfo: authenticationv1.UserInfo{
@@ -84,3 +117,39 @@ func TestIsConfigSyncSA(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigSyncSAName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		userInfo authenticationv1.UserInfo
+		want     string
+	}{
+		{
+			name: "Config Sync service account",
+			userInfo: authenticationv1.UserInfo{
+				Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:config-management-system"},
+				Username: "system:serviceaccount:config-management-system:ns-reconciler-bookstore",
+			},
+			want: "ns-reconciler-bookstore",
+		},
+		{
+			name: "Namespace reconciler impersonating a service account",
+			userInfo: authenticationv1.UserInfo{
+				Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:bookstore"},
+				Username: "system:serviceaccount:bookstore:deployer",
+				Extra: map[string]authenticationv1.ExtraValue{
+					"configsync.gke.io/reconciler": {"ns-reconciler-bookstore"},
+				},
+			},
+			want: "ns-reconciler-bookstore",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := configSyncSAName(tc.userInfo); got != tc.want {
+				t.Errorf("configSyncSAName got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
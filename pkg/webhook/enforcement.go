@@ -0,0 +1,136 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/api/configmanagement"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/kinds"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// EnforcementMode determines what the webhook does with requests which would
+// cause drift from the declared state.
+type EnforcementMode string
+
+const (
+	// DenyMode denies the requests.
+	DenyMode = EnforcementMode("deny")
+	// WarnMode allows the requests, returning admission warnings to the
+	// requester.
+	WarnMode = EnforcementMode("warn")
+	// AuditMode allows the requests, recording them in the webhook logs and
+	// the audit annotations of the API server audit log.
+	AuditMode = EnforcementMode("audit")
+)
+
+// violationAuditAnnotation is the key of the audit annotation recording the
+// requests allowed in warn and audit mode.
+const violationAuditAnnotation = "violation"
+
+// ParseEnforcementMode returns the EnforcementMode named by the string.
+func ParseEnforcementMode(mode string) (EnforcementMode, error) {
+	switch m := EnforcementMode(mode); m {
+	case DenyMode, WarnMode, AuditMode:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown enforcement mode %q, must be one of %s, %s or %s", mode, DenyMode, WarnMode, AuditMode)
+	}
+}
+
+// Enforcement is the enforcement mode of the webhook for the cluster, with
+// per-kind overrides.
+type Enforcement struct {
+	// Mode is the enforcement mode of the kinds without overrides. Defaults to
+	// DenyMode.
+	Mode EnforcementMode
+	// Overrides are the enforcement modes of specific kinds.
+	Overrides map[schema.GroupKind]EnforcementMode
+}
+
+// ParseEnforcementOverrides parses a comma-separated list of per-kind
+// enforcement modes formatted as `Kind.group=mode`, for example
+// `Deployment.apps=warn,ConfigMap=audit`.
+func ParseEnforcementOverrides(overrides string) (map[schema.GroupKind]EnforcementMode, error) {
+	result := make(map[schema.GroupKind]EnforcementMode)
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid enforcement mode override %q, must be formatted as Kind.group=mode", override)
+		}
+		mode, err := ParseEnforcementMode(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		result[schema.ParseGroupKind(parts[0])] = mode
+	}
+	return result, nil
+}
+
+// modeFor returns the enforcement mode of the kind.
+func (e Enforcement) modeFor(gk schema.GroupKind) EnforcementMode {
+	if mode, found := e.Overrides[gk]; found {
+		return mode
+	}
+	if e.Mode == "" {
+		return DenyMode
+	}
+	return e.Mode
+}
+
+// enforce applies the enforcement mode of the kind of the request to a
+// response which denies the request.
+func (e Enforcement) enforce(req admission.Request, manager string, resp admission.Response) admission.Response {
+	gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+	mode := e.modeFor(gk)
+	if resp.Allowed || mode == DenyMode {
+		return resp
+	}
+
+	message := resp.Result.Message
+	if owner := ownerOf(manager); owner != "" {
+		message = fmt.Sprintf("%s (managed by %s)", message, owner)
+	}
+	klog.Warningf("Allowed in %s mode: %s", mode, message)
+	allowed := allow()
+	allowed.AuditAnnotations = map[string]string{violationAuditAnnotation: message}
+	if mode == WarnMode {
+		allowed = allowed.WithWarnings(fmt.Sprintf("Config Sync drift: %s", message))
+	}
+	return allowed
+}
+
+// ownerOf returns the RootSync or RepoSync identified by the value of the
+// manager annotation of a managed object.
+func ownerOf(manager string) string {
+	scope, name := declared.ManagerScopeAndName(manager)
+	switch scope {
+	case "":
+		return ""
+	case declared.RootReconciler:
+		return fmt.Sprintf("%s %s/%s", kinds.RootSyncV1Beta1().Kind, configmanagement.ControllerNamespace, name)
+	default:
+		return fmt.Sprintf("%s %s/%s", kinds.RepoSyncV1Beta1().Kind, scope, name)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/declared"
+	"kpt.dev/configsync/pkg/kinds"
+	csmetadata "kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/testing/fake"
+)
+
+func TestValidator_Enforcement(t *testing.T) {
+	manager := declared.ResourceManager(declared.RootReconciler, rootSyncName)
+	oldObj := fake.RoleObject(
+		core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+		core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_default-name"),
+		core.Annotation(csmetadata.ResourceManagerKey, manager))
+	newObj := fake.RoleObject(
+		core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+		core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_default-name"),
+		core.Annotation(csmetadata.ResourceManagerKey, manager),
+		core.Annotation(csmetadata.LifecycleMutationAnnotation, csmetadata.IgnoreMutation))
+
+	testCases := []struct {
+		name         string
+		enforcement  Enforcement
+		wantAllowed  bool
+		wantWarnings int
+		wantAudit    bool
+	}{
+		{
+			name: "default mode denies",
+		},
+		{
+			name:        "deny mode denies",
+			enforcement: Enforcement{Mode: DenyMode},
+		},
+		{
+			name:         "warn mode allows with warning",
+			enforcement:  Enforcement{Mode: WarnMode},
+			wantAllowed:  true,
+			wantWarnings: 1,
+			wantAudit:    true,
+		},
+		{
+			name:        "audit mode allows and records",
+			enforcement: Enforcement{Mode: AuditMode},
+			wantAllowed: true,
+			wantAudit:   true,
+		},
+		{
+			name: "override of the kind takes precedence",
+			enforcement: Enforcement{Mode: DenyMode, Overrides: map[schema.GroupKind]EnforcementMode{
+				kinds.Role().GroupKind(): AuditMode,
+			}},
+			wantAllowed: true,
+			wantAudit:   true,
+		},
+		{
+			name: "override of another kind is ignored",
+			enforcement: Enforcement{Mode: DenyMode, Overrides: map[schema.GroupKind]EnforcementMode{
+				kinds.ConfigMap().GroupKind(): AuditMode,
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := validatorForTest(t)
+			v.enforcement = tc.enforcement
+			req := request(oldObj, newObj)
+			req.UserInfo = bob()
+
+			resp := v.Handle(context.Background(), req)
+			if resp.Allowed != tc.wantAllowed {
+				t.Fatalf("got Handle() response allowed %t, want %t", resp.Allowed, tc.wantAllowed)
+			}
+			if len(resp.Warnings) != tc.wantWarnings {
+				t.Errorf("got warnings %v, want %d warnings", resp.Warnings, tc.wantWarnings)
+			}
+			audit, found := resp.AuditAnnotations[violationAuditAnnotation]
+			if found != tc.wantAudit {
+				t.Errorf("got audit annotations %v, want audit annotation %t", resp.AuditAnnotations, tc.wantAudit)
+			}
+			for _, message := range append(resp.Warnings, audit) {
+				if message == "" {
+					continue
+				}
+				for _, want := range []string{"RootSync config-management-system/" + rootSyncName, csmetadata.LifecycleMutationAnnotation} {
+					if !strings.Contains(message, want) {
+						t.Errorf("got message %q, want it to contain %q", message, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseEnforcementOverrides(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides string
+		want      map[schema.GroupKind]EnforcementMode
+		wantErr   bool
+	}{
+		{
+			name: "empty",
+			want: map[schema.GroupKind]EnforcementMode{},
+		},
+		{
+			name:      "kinds with and without group",
+			overrides: "Deployment.apps=warn, ConfigMap=audit",
+			want: map[schema.GroupKind]EnforcementMode{
+				kinds.Deployment().GroupKind(): WarnMode,
+				kinds.ConfigMap().GroupKind():  AuditMode,
+			},
+		},
+		{
+			name:      "missing mode",
+			overrides: "Deployment.apps",
+			wantErr:   true,
+		},
+		{
+			name:      "unknown mode",
+			overrides: "Deployment.apps=block",
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseEnforcementOverrides(tc.overrides)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
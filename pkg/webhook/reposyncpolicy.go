@@ -0,0 +1,58 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/reposyncpolicy"
+	"kpt.dev/configsync/pkg/syncer/differ"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// handleRepoSyncPolicies denies the requests of namespace reconcilers which
+// create or update managed objects violating the RepoSyncPolicies of their
+// namespace. Namespace reconcilers already refuse to sync these objects, so
+// this only catches the requests of misbehaving reconcilers.
+func (v *Validator) handleRepoSyncPolicies(ctx context.Context, username string, op admissionv1.Operation, newObj client.Object) admission.Response {
+	if !strings.HasPrefix(username, core.NsReconcilerPrefix+"-") {
+		return allow()
+	}
+	if op != admissionv1.Create && op != admissionv1.Update {
+		return allow()
+	}
+	// Objects which are no longer managed are being abandoned.
+	if newObj == nil || !differ.ManagedByConfigSync(newObj) {
+		return allow()
+	}
+	policies := v.policies.ForNamespace(ctx, newObj.GetNamespace())
+	messages, err := reposyncpolicy.CheckObject(policies, newObj)
+	if err != nil {
+		messages = []string{err.Error()}
+	}
+	if len(messages) == 0 {
+		return allow()
+	}
+	message := fmt.Sprintf("%s is not allowed to sync %q: %s", username, core.GKNN(newObj), strings.Join(messages, "; "))
+	klog.Error(message)
+	return deny(metav1.StatusReasonForbidden, message)
+}
@@ -17,6 +17,7 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	admissionv1 "k8s.io/api/admission/v1"
@@ -28,7 +29,10 @@ import (
 	"kpt.dev/configsync/pkg/core"
 	"kpt.dev/configsync/pkg/declared"
 	"kpt.dev/configsync/pkg/diff"
+	"kpt.dev/configsync/pkg/events"
+	"kpt.dev/configsync/pkg/exemption"
 	csmetadata "kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reposyncpolicy"
 	"kpt.dev/configsync/pkg/syncer/differ"
 	"kpt.dev/configsync/pkg/webhook/configuration"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,11 +42,18 @@ import (
 )
 
 // AddValidator adds the admission webhook validator to the passed manager.
-func AddValidator(mgr manager.Manager) error {
+func AddValidator(mgr manager.Manager, enforcement Enforcement) error {
 	handler, err := handler(mgr.GetConfig())
 	if err != nil {
 		return err
 	}
+	handler.enforcement = enforcement
+	recorder, err := events.NewEventRecorder(mgr.GetConfig(), configuration.ShortName)
+	if err != nil {
+		return err
+	}
+	handler.exemptions = exemption.NewLister(mgr.GetAPIReader(), recorder)
+	handler.policies = reposyncpolicy.NewLister(mgr.GetAPIReader())
 	mgr.GetWebhookServer().Register(configuration.ServingPath, &webhook.Admission{
 		Handler: handler,
 	})
@@ -52,7 +63,10 @@ func AddValidator(mgr manager.Manager) error {
 // Validator is the part of the validating webhook which handles admission
 // requests and admits or denies them.
 type Validator struct {
-	differ *ObjectDiffer
+	differ      *ObjectDiffer
+	enforcement Enforcement
+	exemptions  *exemption.Lister
+	policies    *reposyncpolicy.Lister
 }
 
 var _ admission.Handler = &Validator{}
@@ -67,11 +81,11 @@ func handler(cfg *rest.Config) (*Validator, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Validator{&ObjectDiffer{vc}}, nil
+	return &Validator{differ: &ObjectDiffer{vc}}, nil
 }
 
 // Handle implements admission.Handler
-func (v *Validator) Handle(_ context.Context, req admission.Request) admission.Response {
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
 	// An admission request for a sub-resource (such as a Scale) will not include
 	// the full parent for us to validate until the admission chain is fixed:
 	// https://github.com/kubernetes/enhancements/pull/1600
@@ -100,9 +114,32 @@ func (v *Validator) Handle(_ context.Context, req admission.Request) admission.R
 			klog.Error(err.Error())
 			return deny(metav1.StatusReasonUnauthorized, err.Error())
 		}
-		return allow()
+		return v.handleRepoSyncPolicies(ctx, username, req.Operation, newObj)
+	}
+
+	// Requests from anyone else which would cause drift are allowed if a
+	// drift exemption grants them. Otherwise they are denied, or only
+	// reported, depending on the enforcement mode of their kind.
+	resp := v.handleUser(req, oldObj, newObj)
+	if !resp.Allowed {
+		if e, found := v.exemptions.ForUser(ctx, req.UserInfo, object(oldObj, newObj)); found {
+			return v.exempt(req, oldObj, newObj, e, resp)
+		}
 	}
+	return v.enforcement.enforce(req, objectManager(oldObj, newObj), resp)
+}
+
+// exempt allows a request which the response denies, recording the use of the
+// exemption.
+func (v *Validator) exempt(req admission.Request, oldObj, newObj client.Object, e exemption.Exemption, resp admission.Response) admission.Response {
+	gknn := core.GKNN(object(oldObj, newObj))
+	klog.Infof("Drift exemption %q allowed %s to %s %q: %s", e.Name, req.UserInfo.Username, req.Operation, gknn, resp.Result.Message)
+	v.exemptions.RecordUse(e, events.ReasonExemptionUsed, "allowed %s to %s %q: %s", req.UserInfo.Username, req.Operation, gknn, resp.Result.Message)
+	return allow().WithWarnings(fmt.Sprintf("Allowed by Config Sync drift exemption %q until %s",
+		e.Name, e.Expires().UTC().Format(time.RFC3339)))
+}
 
+func (v *Validator) handleUser(req admission.Request, oldObj, newObj client.Object) admission.Response {
 	// Handle the requests for ResourceGroup CRs.
 	if isResourceGroupRequest(req) {
 		return handleResourceGroupRequest(req)
@@ -237,6 +274,13 @@ func objectManager(oldObj, newObj client.Object) string {
 	return mgr
 }
 
+func object(oldObj, newObj client.Object) client.Object {
+	if oldObj != nil {
+		return oldObj
+	}
+	return newObj
+}
+
 func objectID(oldObj, newObj client.Object) core.ID {
 	if oldObj != nil {
 		return core.IDOf(oldObj)
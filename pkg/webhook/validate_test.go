@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
@@ -25,11 +26,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	"kpt.dev/configsync/pkg/api/configsync"
+	"kpt.dev/configsync/pkg/api/configsync/v1beta1"
 	"kpt.dev/configsync/pkg/applier"
 	"kpt.dev/configsync/pkg/core"
+	"kpt.dev/configsync/pkg/exemption"
 	"kpt.dev/configsync/pkg/importer"
 	csmetadata "kpt.dev/configsync/pkg/metadata"
+	"kpt.dev/configsync/pkg/reposyncpolicy"
+	syncertestfake "kpt.dev/configsync/pkg/syncer/syncertest/fake"
 	"kpt.dev/configsync/pkg/testing/fake"
 	"kpt.dev/configsync/pkg/testing/openapitest"
 	"sigs.k8s.io/cli-utils/pkg/common"
@@ -715,3 +721,132 @@ func request(oldObj, newObj client.Object) admission.Request {
 		},
 	}
 }
+
+func TestValidator_Exemptions(t *testing.T) {
+	oldObj := fake.RoleObject(core.Namespace("bookstore"),
+		core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+		core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_bookstore_default-name"))
+	newObj := fake.RoleObject(core.Namespace("bookstore"),
+		core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+		core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_bookstore_default-name"),
+		core.Annotation(csmetadata.LifecycleMutationAnnotation, csmetadata.IgnoreMutation))
+
+	future := metav1.NewTime(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+	roles := func(namespace string) []v1beta1.DriftExemptionSelector {
+		return []v1beta1.DriftExemptionSelector{{Kind: "Role", Namespace: namespace}}
+	}
+	testCases := []struct {
+		name        string
+		exemption   v1beta1.DriftExemptionSpec
+		wantAllowed bool
+		wantEvents  int
+	}{
+		{
+			name:        "exemption grants the user",
+			exemption:   v1beta1.DriftExemptionSpec{Users: []string{"bob@acme.com"}, Objects: roles("bookstore"), Expires: future},
+			wantAllowed: true,
+			wantEvents:  1,
+		},
+		{
+			name:      "exemption grants another user",
+			exemption: v1beta1.DriftExemptionSpec{Users: []string{"alice@acme.com"}, Objects: roles("bookstore"), Expires: future},
+		},
+		{
+			name:      "exemption selects other objects",
+			exemption: v1beta1.DriftExemptionSpec{Groups: []string{"devs@acme.com"}, Objects: roles("shipping"), Expires: future},
+		},
+		{
+			name: "exemption expired",
+			exemption: v1beta1.DriftExemptionSpec{Users: []string{"bob@acme.com"}, Objects: roles(""),
+				Expires: metav1.NewTime(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			de := &v1beta1.DriftExemption{Spec: tc.exemption}
+			de.Name = "break-glass"
+			recorder := record.NewFakeRecorder(10)
+			v := validatorForTest(t)
+			v.exemptions = exemption.NewLister(syncertestfake.NewClient(t, core.Scheme, de), recorder)
+
+			req := request(oldObj, newObj)
+			req.UserInfo = bob()
+			resp := v.Handle(context.Background(), req)
+			if resp.Allowed != tc.wantAllowed {
+				t.Errorf("got Handle() response allowed %t, want %t", resp.Allowed, tc.wantAllowed)
+			}
+			if len(recorder.Events) != tc.wantEvents {
+				t.Errorf("got %d Events, want %d", len(recorder.Events), tc.wantEvents)
+			}
+		})
+	}
+}
+
+func TestValidator_RepoSyncPolicies(t *testing.T) {
+	newObj := fake.RoleObject(core.Name("hello"), core.Namespace("bookstore"),
+		core.Annotation(csmetadata.ResourceManagementKey, csmetadata.ResourceManagementEnabled),
+		core.Annotation(csmetadata.ResourceIDKey, "rbac.authorization.k8s.io_role_bookstore_hello"),
+		core.Annotation(csmetadata.ResourceManagerKey, repoSyncManagerAnnotation("bookstore", repoSyncName)))
+
+	testCases := []struct {
+		name string
+		spec v1beta1.RepoSyncPolicySpec
+		user authenticationv1.UserInfo
+		deny metav1.StatusReason
+	}{
+		{
+			name: "namespace reconciler creates a denied kind",
+			spec: v1beta1.RepoSyncPolicySpec{
+				DeniedKinds: []v1beta1.RepoSyncPolicyKind{{Group: "rbac.authorization.k8s.io", Kind: "*"}},
+			},
+			user: configSyncNamespaceReconciler("bookstore", repoSyncName),
+			deny: metav1.StatusReasonForbidden,
+		},
+		{
+			name: "namespace reconciler creates a kind which is not allowed",
+			spec: v1beta1.RepoSyncPolicySpec{
+				AllowedKinds: []v1beta1.RepoSyncPolicyKind{{Kind: "ConfigMap"}},
+			},
+			user: configSyncNamespaceReconciler("bookstore", repoSyncName),
+			deny: metav1.StatusReasonForbidden,
+		},
+		{
+			name: "policy applies to other namespaces",
+			spec: v1beta1.RepoSyncPolicySpec{
+				Namespaces:  []string{"shipping"},
+				DeniedKinds: []v1beta1.RepoSyncPolicyKind{{Group: "rbac.authorization.k8s.io", Kind: "Role"}},
+			},
+			user: configSyncNamespaceReconciler("bookstore", repoSyncName),
+		},
+		{
+			name: "policy allows the kind",
+			spec: v1beta1.RepoSyncPolicySpec{
+				AllowedKinds: []v1beta1.RepoSyncPolicyKind{{Group: "rbac.authorization.k8s.io", Kind: "Role"}},
+			},
+			user: configSyncNamespaceReconciler("bookstore", repoSyncName),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &v1beta1.RepoSyncPolicy{Spec: tc.spec}
+			policy.Name = "restricted"
+			v := validatorForTest(t)
+			v.policies = reposyncpolicy.NewLister(syncertestfake.NewClient(t, core.Scheme, policy))
+
+			req := request(nil, newObj)
+			req.UserInfo = tc.user
+			resp := v.Handle(context.Background(), req)
+			if resp.Allowed {
+				if tc.deny != "" {
+					t.Errorf("got Handle() response allowed, want denied %q", tc.deny)
+				}
+			} else if tc.deny == "" {
+				t.Errorf("got Handle() response denied %q, want allowed", resp.Result.Message)
+			} else if tc.deny != resp.Result.Reason {
+				t.Errorf("got Handle() response denied %q, want denied %q", resp.Result.Reason, tc.deny)
+			}
+		})
+	}
+}